@@ -39,6 +39,19 @@ type ClusterColocationProfileSpec struct {
 	// +optional
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 
+	// WorkloadSelectors decides whether to mutate/validate Pods if the
+	// Pod is owned by any of the workloads, which are resolved through the owner references,
+	// e.g. a Pod created by a Deployment is owned by the Deployment via the ReplicaSet.
+	// Default to match all Pods.
+	// +optional
+	WorkloadSelectors []WorkloadSelector `json:"workloadSelectors,omitempty"`
+
+	// Order decides the precedence of the profile when a Pod matches multiple profiles.
+	// Only the matched profiles with the highest order take effect, and the profiles with
+	// the same order are applied in the order of their names.
+	// +optional
+	Order int32 `json:"order,omitempty"`
+
 	// Probability indicates profile will make effect with a probability.
 	// +optional
 	Probability *intstr.IntOrString `json:"probability,omitempty"`
@@ -83,10 +96,59 @@ type ClusterColocationProfileSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:Schemaless
 	Patch runtime.RawExtension `json:"patch,omitempty"`
+
+	// DryRun indicates the profile only records the would-be mutations in the status
+	// without mutating the Pod.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// WorkloadSelector selects the Pods by the workload owning them.
+type WorkloadSelector struct {
+	// APIVersion is the API version of the workload, e.g. apps/v1, argoproj.io/v1alpha1.
+	// Default to match any API version.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind is the kind of the workload, e.g. Deployment, StatefulSet, Job, CronJob, Workflow.
+	Kind string `json:"kind"`
+	// Names are the names of the workloads. Default to match all workloads of the kind.
+	// +optional
+	Names []string `json:"names,omitempty"`
 }
 
 // ClusterColocationProfileStatus represents information about the status of a ClusterColocationProfile.
 type ClusterColocationProfileStatus struct {
+	// MatchedPods is the number of the Pods matched the selectors of the profile.
+	// +optional
+	MatchedPods int64 `json:"matchedPods,omitempty"`
+	// MutatedPods is the number of the Pods mutated by the profile.
+	// +optional
+	MutatedPods int64 `json:"mutatedPods,omitempty"`
+	// SkippedPods is the number of the matched Pods skipped according to the Probability.
+	// +optional
+	SkippedPods int64 `json:"skippedPods,omitempty"`
+	// DryRunPods is the number of the Pods which would be mutated by the profile in dry-run mode.
+	// +optional
+	DryRunPods int64 `json:"dryRunPods,omitempty"`
+	// LastError is the last error when mutating Pods by the profile.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is the time of the last error.
+	// +optional
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+	// LastDryRunMutation is the would-be mutation of the last Pod in dry-run mode.
+	// +optional
+	LastDryRunMutation *ColocationProfileMutation `json:"lastDryRunMutation,omitempty"`
+}
+
+// ColocationProfileMutation describes the mutation of a Pod by the profile.
+type ColocationProfileMutation struct {
+	// Pod is the namespace/name of the Pod. The generateName is used if the name is empty.
+	Pod string `json:"pod,omitempty"`
+	// Patch is the strategic merge patch which would be applied to the Pod.
+	Patch string `json:"patch,omitempty"`
+	// Time is the time when the Pod was admitted.
+	Time metav1.Time `json:"time,omitempty"`
 }
 
 // +genclient
@@ -33,7 +33,7 @@ func (in *ClusterColocationProfile) DeepCopyInto(out *ClusterColocationProfile)
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterColocationProfile.
@@ -99,6 +99,13 @@ func (in *ClusterColocationProfileSpec) DeepCopyInto(out *ClusterColocationProfi
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WorkloadSelectors != nil {
+		in, out := &in.WorkloadSelectors, &out.WorkloadSelectors
+		*out = make([]WorkloadSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Probability != nil {
 		in, out := &in.Probability, &out.Probability
 		*out = new(intstr.IntOrString)
@@ -139,6 +146,15 @@ func (in *ClusterColocationProfileSpec) DeepCopy() *ClusterColocationProfileSpec
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterColocationProfileStatus) DeepCopyInto(out *ClusterColocationProfileStatus) {
 	*out = *in
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDryRunMutation != nil {
+		in, out := &in.LastDryRunMutation, &out.LastDryRunMutation
+		*out = new(ColocationProfileMutation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterColocationProfileStatus.
@@ -150,3 +166,39 @@ func (in *ClusterColocationProfileStatus) DeepCopy() *ClusterColocationProfileSt
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationProfileMutation) DeepCopyInto(out *ColocationProfileMutation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationProfileMutation.
+func (in *ColocationProfileMutation) DeepCopy() *ColocationProfileMutation {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationProfileMutation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSelector) DeepCopyInto(out *WorkloadSelector) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSelector.
+func (in *WorkloadSelector) DeepCopy() *WorkloadSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSelector)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -32,6 +32,7 @@ const (
 	SystemConfigKey            = "system-config"
 	HostApplicationConfigKey   = "host-application-config"
 	CPUNormalizationConfigKey  = "cpu-normalization-config"
+	RolloutConfigKey           = "rollout-config"
 )
 
 // +k8s:deepcopy-gen=true
@@ -293,6 +294,33 @@ type ModelRatioCfg struct {
 	HyperThreadTurboEnabledRatio *float64 `json:"hyperThreadTurboEnabledRatio,omitempty"`
 }
 
+// RolloutCfg is the configuration of the staged rollout of the NodeSLO strategies.
+// A new config revision is firstly applied to the canary nodes, and progresses to all nodes if the canary nodes keep
+// healthy during the bake time. Otherwise, the revision is reverted.
+// +k8s:deepcopy-gen=true
+type RolloutCfg struct {
+	// Enable defines whether the config changes are rolled out with canary.
+	Enable *bool `json:"enable,omitempty"`
+	// CanaryNodeSelector selects the candidate nodes of the canary. A nil selector matches all nodes.
+	CanaryNodeSelector *metav1.LabelSelector `json:"canaryNodeSelector,omitempty"`
+	// CanaryPercent is the percentage of the candidate nodes to apply the new revision firstly.
+	CanaryPercent *int64 `json:"canaryPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// BakeTimeSeconds is the duration to watch the health of the canary nodes before the revision progresses.
+	BakeTimeSeconds *int64 `json:"bakeTimeSeconds,omitempty" validate:"omitempty,min=0"`
+	// NodeMetricExpiredSeconds is the duration after which a canary node without NodeMetric updates is unhealthy.
+	NodeMetricExpiredSeconds *int64 `json:"nodeMetricExpiredSeconds,omitempty" validate:"omitempty,min=1"`
+	// MaxUnhealthyPercent is the max percentage of the unhealthy canary nodes before the revision is reverted.
+	MaxUnhealthyPercent *int64 `json:"maxUnhealthyPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// MaxEvictionCount is the max number of pods evicted by koordlet on the canary nodes during the bake time.
+	// The eviction count is not checked if it is not set.
+	MaxEvictionCount *int64 `json:"maxEvictionCount,omitempty" validate:"omitempty,min=0"`
+	// MaxLSCPUPressurePercent is the max cpu pressure (some avg10) of the LS pods on a canary node, which is reported
+	// in the NodeMetric. The canary node exceeding it is unhealthy. The pressure is not checked if it is not set.
+	MaxLSCPUPressurePercent *int64 `json:"maxLSCPUPressurePercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// RevisionHistoryLimit is the number of the config revisions to retain.
+	RevisionHistoryLimit *int64 `json:"revisionHistoryLimit,omitempty" validate:"omitempty,min=1"`
+}
+
 /*
 Koordinator uses configmap to manage the configuration of SLO, the configmap is stored in
  <ConfigNameSpace>/<SLOCtrlConfigMap>, with the following keys respectively:
@@ -529,6 +529,66 @@ func (in *ResourceThresholdCfg) DeepCopy() *ResourceThresholdCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutCfg) DeepCopyInto(out *RolloutCfg) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CanaryNodeSelector != nil {
+		in, out := &in.CanaryNodeSelector, &out.CanaryNodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CanaryPercent != nil {
+		in, out := &in.CanaryPercent, &out.CanaryPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BakeTimeSeconds != nil {
+		in, out := &in.BakeTimeSeconds, &out.BakeTimeSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NodeMetricExpiredSeconds != nil {
+		in, out := &in.NodeMetricExpiredSeconds, &out.NodeMetricExpiredSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxUnhealthyPercent != nil {
+		in, out := &in.MaxUnhealthyPercent, &out.MaxUnhealthyPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxEvictionCount != nil {
+		in, out := &in.MaxEvictionCount, &out.MaxEvictionCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxLSCPUPressurePercent != nil {
+		in, out := &in.MaxLSCPUPressurePercent, &out.MaxLSCPUPressurePercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutCfg.
+func (in *RolloutCfg) DeepCopy() *RolloutCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemCfg) DeepCopyInto(out *SystemCfg) {
 	*out = *in
@@ -52,8 +52,16 @@ const (
 	AnnotationAllocated             = QuotaKoordinatorPrefix + "/allocated"
 	AnnotationNonPreemptibleRequest = QuotaKoordinatorPrefix + "/non-preemptible-request"
 	AnnotationNonPreemptibleUsed    = QuotaKoordinatorPrefix + "/non-preemptible-used"
+	// AnnotationBatchResourceQuota is the upper limit of the batch resources (e.g. kubernetes.io/batch-cpu)
+	// requested by the Pods of the Namespace or the ElasticQuota.
+	AnnotationBatchResourceQuota = QuotaKoordinatorPrefix + "/batch-resource-quota"
+	// AnnotationBatchResourceUsed is the batch resources requested by the Pods of the Namespace or the ElasticQuota.
+	AnnotationBatchResourceUsed = QuotaKoordinatorPrefix + "/batch-resource-used"
 )
 
+// BatchResourceNames are the resources limited by AnnotationBatchResourceQuota.
+var BatchResourceNames = []corev1.ResourceName{BatchCPU, BatchMemory}
+
 func GetParentQuotaName(quota *v1alpha1.ElasticQuota) string {
 	parentName := quota.Labels[LabelQuotaParent]
 	if parentName == "" && quota.Name != RootQuotaName {
@@ -195,3 +203,26 @@ func GetChildRequest(quota *v1alpha1.ElasticQuota) (corev1.ResourceList, error)
 	}
 	return request, nil
 }
+
+// GetBatchResourceQuota parses the batch resource quota of the Namespace or the ElasticQuota.
+// It returns nil if the quota is not set.
+func GetBatchResourceQuota(annotations map[string]string) (corev1.ResourceList, error) {
+	return getBatchResourceList(annotations, AnnotationBatchResourceQuota)
+}
+
+// GetBatchResourceUsed parses the batch resources used of the Namespace or the ElasticQuota.
+func GetBatchResourceUsed(annotations map[string]string) (corev1.ResourceList, error) {
+	return getBatchResourceList(annotations, AnnotationBatchResourceUsed)
+}
+
+func getBatchResourceList(annotations map[string]string, key string) (corev1.ResourceList, error) {
+	data, ok := annotations[key]
+	if !ok || data == "" {
+		return nil, nil
+	}
+	resourceList := corev1.ResourceList{}
+	if err := json.Unmarshal([]byte(data), &resourceList); err != nil {
+		return nil, err
+	}
+	return v1.Mask(resourceList, BatchResourceNames), nil
+}
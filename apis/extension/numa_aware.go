@@ -19,6 +19,7 @@ package extension
 import (
 	"encoding/json"
 
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -163,6 +164,8 @@ type CPUInfo struct {
 	Core   int32 `json:"core"`
 	Socket int32 `json:"socket"`
 	Node   int32 `json:"node"`
+	// L3 is the ID of the L3 Cache (e.g. the CCD of AMD EPYC) which the CPU belongs to
+	L3 int32 `json:"l3,omitempty"`
 }
 
 type PodCPUAlloc struct {
@@ -337,3 +340,19 @@ func SetNodeNUMATopologyPolicy(obj metav1.Object, policy NUMATopologyPolicy) {
 	obj.SetLabels(labels)
 	return
 }
+
+// GetNUMATopologyPolicyFromNRT converts the kubelet topology manager policy reported in the NodeResourceTopology
+// into the NUMATopologyPolicy.
+func GetNUMATopologyPolicyFromNRT(nrt *nrtv1alpha1.NodeResourceTopology) NUMATopologyPolicy {
+	for _, policy := range nrt.TopologyPolicies {
+		switch nrtv1alpha1.TopologyManagerPolicy(policy) {
+		case nrtv1alpha1.BestEffort:
+			return NUMATopologyPolicyBestEffort
+		case nrtv1alpha1.Restricted:
+			return NUMATopologyPolicyRestricted
+		case nrtv1alpha1.SingleNUMANodePodLevel:
+			return NUMATopologyPolicySingleNUMANode
+		}
+	}
+	return NUMATopologyPolicyNone
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetNUMATopologyPolicyFromNRT(t *testing.T) {
+	tests := []struct {
+		name             string
+		topologyPolicies []string
+		want             NUMATopologyPolicy
+	}{
+		{
+			name:             "none policy",
+			topologyPolicies: []string{string(nrtv1alpha1.None)},
+			want:             NUMATopologyPolicyNone,
+		},
+		{
+			name:             "best-effort policy",
+			topologyPolicies: []string{string(nrtv1alpha1.BestEffort)},
+			want:             NUMATopologyPolicyBestEffort,
+		},
+		{
+			name:             "restricted policy",
+			topologyPolicies: []string{string(nrtv1alpha1.Restricted)},
+			want:             NUMATopologyPolicyRestricted,
+		},
+		{
+			name:             "single numa node policy with the pod scope",
+			topologyPolicies: []string{string(nrtv1alpha1.SingleNUMANodePodLevel)},
+			want:             NUMATopologyPolicySingleNUMANode,
+		},
+		{
+			name:             "single numa node policy with the container scope is not supported",
+			topologyPolicies: []string{string(nrtv1alpha1.SingleNUMANodeContainerLevel)},
+			want:             NUMATopologyPolicyNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nrt := &nrtv1alpha1.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+				},
+				TopologyPolicies: tt.topologyPolicies,
+			}
+			got := GetNUMATopologyPolicyFromNRT(nrt)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -38,6 +38,13 @@ const (
 
 	// AnnotationReservationAffinity represents the constraints of Pod selection Reservation
 	AnnotationReservationAffinity = SchedulingDomainPrefix + "/reservation-affinity"
+
+	// LabelReservationSetName represents the name of the ReservationSet which creates the Reservation.
+	LabelReservationSetName = SchedulingDomainPrefix + "/reservation-set"
+
+	// AnnotationReservationResizeSpec represents the desired resources of an Available Reservation.
+	// The scheduler shrinks or grows the Reservation in place according to it.
+	AnnotationReservationResizeSpec = SchedulingDomainPrefix + "/reservation-resize-spec"
 )
 
 type ReservationAllocated struct {
@@ -67,6 +74,14 @@ type ReservationAffinitySelector struct {
 	ReservationSelectorTerms []corev1.NodeSelectorTerm `json:"reservationSelectorTerms,omitempty"`
 }
 
+// ReservationResizeSpec describes the desired reserved resources of an Available Reservation.
+type ReservationResizeSpec struct {
+	// Resources are the desired reserved resources. The resources not specified keep the current allocatable.
+	// The resources can not be shrunk below the resources allocated by the owner Pods,
+	// and the increased resources must fit in the free resources of the node.
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+}
+
 func GetReservationAllocated(pod *corev1.Pod) (*ReservationAllocated, error) {
 	if pod.Annotations == nil {
 		return nil, nil
@@ -122,3 +137,15 @@ func SetReservationAffinity(obj metav1.Object, affinity *ReservationAffinity) er
 	obj.SetAnnotations(annotations)
 	return nil
 }
+
+func GetReservationResizeSpec(annotations map[string]string) (*ReservationResizeSpec, error) {
+	s := annotations[AnnotationReservationResizeSpec]
+	if s == "" {
+		return nil, nil
+	}
+	var resize ReservationResizeSpec
+	if err := json.Unmarshal([]byte(s), &resize); err != nil {
+		return nil, err
+	}
+	return &resize, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NodeDrainSpec struct {
+	// NodeName is the name of the node to be drained.
+	// +kubebuilder:validation:Required
+	NodeName string `json:"nodeName"`
+
+	// PodSelector if specified, only the pods matching the selector are migrated.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// TTL controls the timeout duration of the PodMigrationJobs created by the NodeDrain.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Abort indicates whether the NodeDrain should be aborted. The aborted NodeDrain uncordons the node
+	// if the node was cordoned by it, and aborts the PodMigrationJobs which have not been started.
+	// The running PodMigrationJobs are not interrupted.
+	// +optional
+	Abort bool `json:"abort,omitempty"`
+}
+
+type NodeDrainStatus struct {
+	// Phase represents the phase of the NodeDrain.
+	Phase NodeDrainPhase `json:"phase,omitempty"`
+	// Message represents a human-readable message indicating details about why the NodeDrain is in this state.
+	Message string `json:"message,omitempty"`
+	// TotalPods is the number of pods to be migrated from the node.
+	TotalPods int32 `json:"totalPods,omitempty"`
+	// MigratingPods is the number of pods whose PodMigrationJobs are not completed.
+	MigratingPods int32 `json:"migratingPods,omitempty"`
+	// SucceededPods is the number of pods whose PodMigrationJobs are Succeeded.
+	SucceededPods int32 `json:"succeededPods,omitempty"`
+	// FailedPods is the number of pods whose PodMigrationJobs are Failed or Aborted.
+	FailedPods int32 `json:"failedPods,omitempty"`
+}
+
+type NodeDrainPhase string
+
+const (
+	// NodeDrainPending represents the initial status
+	NodeDrainPending NodeDrainPhase = "Pending"
+	// NodeDrainRunning represents the node is cordoned and the pods are being migrated
+	NodeDrainRunning NodeDrainPhase = "Running"
+	// NodeDrainSucceeded represents all the pods are migrated successfully
+	NodeDrainSucceeded NodeDrainPhase = "Succeeded"
+	// NodeDrainFailed represents some pods are failed to migrate, or the node is missing
+	NodeDrainFailed NodeDrainPhase = "Failed"
+	// NodeDrainAborted represents the user aborted the NodeDrain
+	NodeDrainAborted NodeDrainPhase = "Aborted"
+)
+
+// NodeDrain is the Schema for the NodeDrain API.
+// A NodeDrain cordons the node and migrates the pods on the node by ReservationFirst PodMigrationJobs.
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.totalPods"
+// +kubebuilder:printcolumn:name="Succeeded",type="integer",JSONPath=".status.succeededPods"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedPods"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type NodeDrain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeDrainSpec   `json:"spec,omitempty"`
+	Status NodeDrainStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeDrainList contains a list of NodeDrain
+type NodeDrainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeDrain `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeDrain{}, &NodeDrainList{})
+}
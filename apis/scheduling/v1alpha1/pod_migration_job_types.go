@@ -50,6 +50,40 @@ type PodMigrationJobSpec struct {
 	// DeleteOptions defines the deleting options for the migrated Pod and preempted Pods
 	// +optional
 	DeleteOptions *metav1.DeleteOptions `json:"deleteOptions,omitempty"`
+
+	// ExecutionPolicy overrides the maintenance windows and batch policy of the MigrationController for the job
+	// +optional
+	ExecutionPolicy *PodMigrationJobExecutionPolicy `json:"executionPolicy,omitempty"`
+}
+
+type PodMigrationJobExecutionPolicy struct {
+	// MaintenanceWindows if specified, the job only starts within these windows
+	// instead of the maintenance windows of the MigrationController.
+	// +optional
+	MaintenanceWindows []PodMigrationJobMaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// IgnoreMaintenanceWindows allows the job to start at any time, e.g. urgent migrations.
+	// +optional
+	IgnoreMaintenanceWindows bool `json:"ignoreMaintenanceWindows,omitempty"`
+
+	// IgnoreBatch allows the job to start without waiting for or occupying a batch of the MigrationController.
+	// +optional
+	IgnoreBatch bool `json:"ignoreBatch,omitempty"`
+}
+
+// PodMigrationJobMaintenanceWindow represents a periodic time window in which PodMigrationJobs can start.
+type PodMigrationJobMaintenanceWindow struct {
+	// StartTime is the start time of the window in the format "15:04", e.g. "02:00"
+	StartTime string `json:"startTime"`
+	// Duration is the length of the window, it must be greater than 0 and not more than 24h
+	Duration metav1.Duration `json:"duration"`
+	// Weekdays are the days of the week when the window starts, e.g. "Saturday".
+	// The window starts every day if not specified.
+	// +optional
+	Weekdays []string `json:"weekdays,omitempty"`
+	// TimeZone is the IANA time zone name of the StartTime, e.g. "Asia/Shanghai". Default is UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 type PodMigrationJobMode string
@@ -183,6 +217,7 @@ const (
 	PodMigrationJobReasonEvictComplete             = "EvictComplete"
 	PodMigrationJobReasonWaitForPodBindReservation = "WaitForPodBindReservation"
 	PodMigrationJobReasonWaitForBoundPodReady      = "WaitForBoundPodReady"
+	PodMigrationJobReasonInvalidExecutionPolicy    = "InvalidExecutionPolicy"
 )
 
 type PodMigrationJobConditionStatus string
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ReservationSetSpec struct {
+	// Replicas is the number of desired active Reservations. Defaults to 1.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Template is the object that describes the Reservations that will be created.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Required
+	Template ReservationTemplateSpec `json:"template"`
+	// Owners specify the owners who can allocate the reserved resources of every Reservation created by the set.
+	// If specified, it overrides the `template.spec.owners`.
+	// +optional
+	Owners []ReservationOwner `json:"owners,omitempty"`
+	// TopologySpreadConstraints describes how the Reservations of the set ought to spread across topology domains.
+	// The constraints are appended to the pod template of each Reservation. If the `labelSelector` of a constraint
+	// is not specified, it is defaulted to select the Reservations of the set.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+type ReservationSetStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Replicas is the number of active Reservations (Pending, Waiting or Available) created by the set.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// AvailableReplicas is the number of Reservations created by the set that are Available.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+	// AllocatedReplicas is the number of Reservations created by the set which have been allocated by owners.
+	// The consumed Reservations of `allocateOnce` are counted until they are garbage collected.
+	// +optional
+	AllocatedReplicas int32 `json:"allocatedReplicas,omitempty"`
+}
+
+// ReservationSet is the Schema for the ReservationSet API.
+// A ReservationSet ensures that a specified number of Reservations created by the template are active at any
+// given time. The failed or expired Reservations are replaced automatically.
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".spec.replicas"
+// +kubebuilder:printcolumn:name="Current",type="integer",JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.availableReplicas"
+// +kubebuilder:printcolumn:name="Allocated",type="integer",JSONPath=".status.allocatedReplicas"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ReservationSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReservationSetSpec   `json:"spec,omitempty"`
+	Status ReservationSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReservationSetList contains a list of ReservationSet
+type ReservationSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReservationSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ReservationSet{}, &ReservationSetList{})
+}
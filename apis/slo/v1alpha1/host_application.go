@@ -33,7 +33,15 @@ type HostApplicationSpec struct {
 	Strategy *HostApplicationStrategy `json:"strategy,omitempty"`
 }
 
+// HostApplicationStrategy describes the QoS strategy of a host application. The strategy of the QoS class which the
+// application declares is applied to its cgroup by default, and the fields here overrides it.
+// NOTE: host applications are never evicted by koordlet; BE host applications are suppressible like the BE pods,
+// while the others are regarded as non-BE usage and exempt from suppression.
 type HostApplicationStrategy struct {
+	// ResourceQOS overrides the resource qos strategy of the QoS class, e.g. memory qos, resctrl qos and blkio qos
+	ResourceQOS *ResourceQOS `json:"resourceQOS,omitempty"`
+	// CPUBurstConfig overrides the cpu burst config of the node, only works for LS host applications
+	CPUBurstConfig *CPUBurstConfig `json:"cpuBurstConfig,omitempty"`
 }
 
 // CgroupPath decribes the cgroup path for out-of-band applications
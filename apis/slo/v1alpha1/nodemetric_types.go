@@ -45,6 +45,9 @@ type NodeMetricInfo struct {
 	// AggregatedSystemUsages will report only if there are enough samples
 	// Deleted pods will be excluded during aggregation
 	AggregatedSystemUsages []AggregatedUsage `json:"aggregatedSystemUsages,omitempty"`
+	// LSCPUPressurePercent is the max cpu pressure (some avg10, in percentage) of the LS pods on the node.
+	// It will report only if the PSI collector is enabled.
+	LSCPUPressurePercent *int64 `json:"lsCPUPressurePercent,omitempty"`
 }
 
 type AggregatedUsage struct {
@@ -31,7 +31,8 @@ type CPUQOS struct {
 	// cpu.idle value for pods, default = 0.
 	// `1` means using SCHED_IDLE.
 	// CGroup Idle (introduced since mainline Linux 5.15): https://lore.kernel.org/lkml/162971078674.25758.15464079371945307825.tip-bot2@tip-bot2/#r
-	// NOTE: It takes effect if cpuPolicy = "coreSched".
+	// NOTE: It takes effect if cpuPolicy = "coreSched", or if cpuPolicy = "groupIdentity" on the cgroups-v2 kernels
+	// without the group identity, where the cpu.idle is used as the fallback.
 	SchedIdle *int64 `json:"schedIdle,omitempty" validate:"omitempty,min=0,max=1"`
 	// whether pods of the QoS class can expel the cgroup idle pods at the SMT-level. default = false
 	// If set to true, pods of this QoS will use a dedicated core sched group for noise clean with the SchedIdle pods.
@@ -180,6 +181,38 @@ type IOCfg struct {
 	ReadLatency *int64 `json:"readLatency,omitempty"`
 	// the write latency threshold. Unit: microseconds.
 	WriteLatency *int64 `json:"writeLatency,omitempty"`
+	// Configure the dynamic throttling of the read and write BPS
+	// Only used for BEClass
+	Dynamic *BlkIODynamicCfg `json:"dynamic,omitempty"`
+}
+
+// BlkIODynamicCfg configures the dynamic throttling of the read and write BPS of the BE pods on a block.
+// The BE limits are tightened when the device is busy and the IO latency exceeds the target or the LS pods suffer
+// from the IO pressure, and they are relaxed otherwise. The ReadBPS and WriteBPS of the IOCfg are used as the upper
+// bounds, and the direction whose upper bound is unlimited is not throttled dynamically.
+type BlkIODynamicCfg struct {
+	// Enable indicates whether the dynamic throttling is enabled.
+	Enable *bool `json:"enable,omitempty"`
+	// the target of the average IO latency of the device. Unit: microseconds.
+	// +kubebuilder:validation:Minimum=1
+	TargetLatency *int64 `json:"targetLatency,omitempty" validate:"omitempty,min=1"`
+	// the BE limits are tightened only if the utilization of the device exceeds the threshold
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	UtilizationThresholdPercent *int64 `json:"utilizationThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the LS pods are considered interfered when the IO pressure (some avg10) of any LS pod exceeds the threshold.
+	// The value is set to 0, which indicates that the IO pressure is not checked.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	LSIOPressureThresholdPercent *int64 `json:"lsIOPressureThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the lower bound of the BPS limits by the percentage of the upper bounds
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MinBPSPercent *int64 `json:"minBPSPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// the BPS limits are tightened by StepPercent of the current limits, and relaxed by StepPercent of the upper bounds
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	StepPercent *int64 `json:"stepPercent,omitempty" validate:"omitempty,min=1,max=100"`
 }
 
 type BlockCfg struct {
@@ -234,8 +267,36 @@ type CPUSuppressPolicy string
 const (
 	CPUSetPolicy      CPUSuppressPolicy = "cpuset"
 	CPUCfsQuotaPolicy CPUSuppressPolicy = "cfsQuota"
+	// CPULSLatencyPolicy adjusts the cfs quota of the BE pods by the cpu contention of the LS pods with a PID
+	// controller, instead of the node utilization. The BE pods can use the cpu not used by the others as long as
+	// the LS pods are not contended.
+	CPULSLatencyPolicy CPUSuppressPolicy = "lsLatency"
 )
 
+// CPUSuppressLatencyConfig configures the lsLatency CPUSuppressPolicy.
+// The control error is the max relative deviation of the LS signals from their targets, and the cpu of BE pods is
+// adjusted by the gains in percentage of the node capacity per unit error.
+type CPUSuppressLatencyConfig struct {
+	// the target of the max cpu pressure (some avg10) of the LS pods. The value is set to 0, which indicates that the
+	// cpu pressure is not checked.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	LSCPUPressureTargetPercent *int64 `json:"lsCPUPressureTargetPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the target of the average run queue latency per timeslice of the LS tasks. Unit: microseconds. The value is set
+	// to 0, which indicates that the run queue latency is not checked.
+	// +kubebuilder:validation:Minimum=0
+	LSRunQueueLatencyTargetMicroSeconds *int64 `json:"lsRunQueueLatencyTargetMicroSeconds,omitempty" validate:"omitempty,min=0"`
+	// the proportional gain of the PID controller
+	// +kubebuilder:validation:Minimum=0
+	ProportionalGainPercent *int64 `json:"proportionalGainPercent,omitempty" validate:"omitempty,min=0"`
+	// the integral gain of the PID controller
+	// +kubebuilder:validation:Minimum=0
+	IntegralGainPercent *int64 `json:"integralGainPercent,omitempty" validate:"omitempty,min=0"`
+	// the derivative gain of the PID controller
+	// +kubebuilder:validation:Minimum=0
+	DerivativeGainPercent *int64 `json:"derivativeGainPercent,omitempty" validate:"omitempty,min=0"`
+}
+
 type CPUEvictPolicy string
 
 const (
@@ -253,6 +314,8 @@ type ResourceThresholdStrategy struct {
 	CPUSuppressThresholdPercent *int64 `json:"cpuSuppressThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
 	// CPUSuppressPolicy
 	CPUSuppressPolicy CPUSuppressPolicy `json:"cpuSuppressPolicy,omitempty"`
+	// CPUSuppressLatencyConfig is used when the CPUSuppressPolicy is lsLatency
+	CPUSuppressLatencyConfig *CPUSuppressLatencyConfig `json:"cpuSuppressLatencyConfig,omitempty"`
 
 	// upper: memory evict threshold percentage (0,100), default = 70
 	// +kubebuilder:validation:Maximum=100
@@ -278,6 +341,18 @@ type ResourceThresholdStrategy struct {
 	// CPUEvictPolicy defines the policy for the BECPUEvict feature.
 	// Default: `evictByRealLimit`.
 	CPUEvictPolicy CPUEvictPolicy `json:"cpuEvictPolicy,omitempty"`
+
+	// when the cpu usage of the non-BE pods and the system exceeds MidCPUEvictThresholdPercent of the node capacity,
+	// the koord-mid pods will be evicted until the usage is under the threshold. The BE pods are not counted, so
+	// they are suppressed and evicted before the koord-mid pods. Disabled if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MidCPUEvictThresholdPercent *int64 `json:"midCPUEvictThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// when the memory usage of the non-BE pods and the system exceeds MidMemoryEvictThresholdPercent of the node
+	// capacity, the koord-mid pods will be evicted until the usage is under the threshold. Disabled if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MidMemoryEvictThresholdPercent *int64 `json:"midMemoryEvictThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
 }
 
 // ResctrlQOSCfg stores node-level config of resctrl qos
@@ -285,6 +360,45 @@ type ResctrlQOSCfg struct {
 	// Enable indicates whether the resctrl qos is enabled.
 	Enable     *bool `json:"enable,omitempty"`
 	ResctrlQOS `json:",inline"`
+	// Adaptive configures the adaptive policy which adjusts the LLC ways and memory bandwidth of the class according
+	// to the interference on the LS pods. It only takes effect on the BE class.
+	Adaptive *ResctrlAdaptiveCfg `json:"adaptive,omitempty"`
+}
+
+// ResctrlAdaptiveCfg stores the config of the adaptive resctrl policy.
+// When the CPI or the memory bandwidth of the LS pods degrades, the CAT range end and the MBA percent of the class
+// are shrunk step by step, and they are relaxed when the node is quiet. The CATRangeEndPercent and MBAPercent of the
+// class are used as the upper bounds.
+type ResctrlAdaptiveCfg struct {
+	// Enable indicates whether the adaptive policy is enabled.
+	Enable *bool `json:"enable,omitempty"`
+	// the lower bound of the LLC available range end by percentage
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	CATRangeEndMinPercent *int64 `json:"catRangeEndMinPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the lower bound of the MBA percent
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MBAMinPercent *int64 `json:"mbaMinPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the percent to shrink or relax the CAT range end and the MBA percent in each adjustment
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	StepPercent *int64 `json:"stepPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// the LS pods are considered interfered when the average CPI of the LS pods in the recent window exceeds the
+	// baseline by CPIDegradationThresholdPercent
+	// +kubebuilder:validation:Minimum=0
+	CPIDegradationThresholdPercent *int64 `json:"cpiDegradationThresholdPercent,omitempty" validate:"omitempty,min=0"`
+	// the LS pods are considered interfered when the memory bandwidth of the LS groups in the recent window drops
+	// below the baseline by MemoryBandwidthDegradationThresholdPercent, while the bandwidth of the BE group does not drop
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MemoryBandwidthDegradationThresholdPercent *int64 `json:"memoryBandwidthDegradationThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the recent window to check the interference, which is also the minimal interval between two adjustments
+	// +kubebuilder:validation:Minimum=1
+	WindowSeconds *int64 `json:"windowSeconds,omitempty" validate:"omitempty,gt=0"`
+	// the window to calculate the baseline, which ends at the start of the recent window
+	// +kubebuilder:validation:Minimum=1
+	BaselineWindowSeconds *int64 `json:"baselineWindowSeconds,omitempty" validate:"omitempty,gt=0"`
 }
 
 type ResctrlQOS struct {
@@ -313,6 +427,9 @@ const (
 	CFSQuotaBurstOnly CPUBurstPolicy = "cfsQuotaBurstOnly"
 	// CPUBurstAuto enables both
 	CPUBurstAuto CPUBurstPolicy = "auto"
+	// CPUBurstAdaptive enables both, and sizes cpu.cfs_burst_us and the cfs quota scaling of each container by its
+	// recent throttled ratio and cpu usage
+	CPUBurstAdaptive CPUBurstPolicy = "adaptive"
 )
 
 type CPUBurstConfig struct {
@@ -325,6 +442,14 @@ type CPUBurstConfig struct {
 	CFSQuotaBurstPercent *int64 `json:"cfsQuotaBurstPercent,omitempty" validate:"omitempty,min=100"`
 	// specifies a period of time for pod can use at burst, default = -1 (unlimited)
 	CFSQuotaBurstPeriodSeconds *int64 `json:"cfsQuotaBurstPeriodSeconds,omitempty" validate:"omitempty,min=-1"`
+	// for the adaptive policy, the average throttled ratio of the container at which the cpu burst and the cfs quota
+	// scaling reach the max, default = 10 (10%)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	ThrottledRatioThresholdPercent *int64 `json:"throttledRatioThresholdPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// for the adaptive policy, the time window of the throttled ratio and the cpu usage history, default = 300
+	// +kubebuilder:validation:Minimum=1
+	HistoryWindowSeconds *int64 `json:"historyWindowSeconds,omitempty" validate:"omitempty,min=1"`
 }
 
 type CPUBurstStrategy struct {
@@ -340,6 +465,9 @@ type SystemStrategy struct {
 	WatermarkScaleFactor *int64 `json:"watermarkScaleFactor,omitempty" validate:"omitempty,gt=0,max=400"`
 	// /sys/kernel/mm/memcg_reaper/reap_background
 	MemcgReapBackGround *int64 `json:"memcgReapBackGround,omitempty" validate:"omitempty,min=0,max=1"`
+	// ResourceExecutorDryRun indicates koordlet to record the resource updates of the node instead of applying them,
+	// which takes effect when the koordlet feature-gate ResourceExecutorDryRun is enabled
+	ResourceExecutorDryRun *bool `json:"resourceExecutorDryRun,omitempty"`
 }
 
 // NodeSLOSpec defines the desired state of NodeSLO
@@ -358,10 +486,49 @@ type NodeSLOSpec struct {
 	HostApplications []HostApplicationSpec `json:"hostApplications,omitempty"`
 }
 
+type StrategyConditionType string
+
+const (
+	// StrategyConditionApplied means the strategy has been applied on the node successfully.
+	StrategyConditionApplied StrategyConditionType = "Applied"
+	// StrategyConditionUnsupported means the strategy cannot be applied since the node does not support it.
+	StrategyConditionUnsupported StrategyConditionType = "Unsupported"
+	// StrategyConditionError means the strategy failed to apply in the last reconciliation.
+	StrategyConditionError StrategyConditionType = "Error"
+)
+
+// StrategyCondition describes the enforcement state of a strategy on the node.
+type StrategyCondition struct {
+	// Strategy is the name of the strategy, e.g. CPUSuppress, ResourceQOS
+	Strategy string `json:"strategy"`
+	// Type is the enforcement state of the strategy
+	Type StrategyConditionType `json:"type"`
+	// Message is a human-readable explanation of the state
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one type to another
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// KernelFeature describes whether a kernel feature which the strategies depend on is supported by the node.
+type KernelFeature struct {
+	// Name is the name of the kernel feature, e.g. CPUBurst, Resctrl
+	Name string `json:"name"`
+	// Supported indicates if the kernel feature is supported
+	Supported bool `json:"supported"`
+	// Message is the reason when the kernel feature is unsupported
+	Message string `json:"message,omitempty"`
+}
+
 // NodeSLOStatus defines the observed state of NodeSLO
 type NodeSLOStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ObservedSpecHash is the hash of the effective spec which koordlet merges with the default config
+	ObservedSpecHash string `json:"observedSpecHash,omitempty"`
+	// LastReconcileTime is the last time koordlet reported the status
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+	// Conditions are the enforcement states of the strategies on the node
+	Conditions []StrategyCondition `json:"conditions,omitempty"`
+	// KernelFeatures are the kernel features detected on the node
+	KernelFeatures []KernelFeature `json:"kernelFeatures,omitempty"`
 }
 
 // +genclient
@@ -72,6 +72,51 @@ func (in *AggregatedUsage) DeepCopy() *AggregatedUsage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlkIODynamicCfg) DeepCopyInto(out *BlkIODynamicCfg) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TargetLatency != nil {
+		in, out := &in.TargetLatency, &out.TargetLatency
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UtilizationThresholdPercent != nil {
+		in, out := &in.UtilizationThresholdPercent, &out.UtilizationThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LSIOPressureThresholdPercent != nil {
+		in, out := &in.LSIOPressureThresholdPercent, &out.LSIOPressureThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinBPSPercent != nil {
+		in, out := &in.MinBPSPercent, &out.MinBPSPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StepPercent != nil {
+		in, out := &in.StepPercent, &out.StepPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlkIODynamicCfg.
+func (in *BlkIODynamicCfg) DeepCopy() *BlkIODynamicCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(BlkIODynamicCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlkIOQOS) DeepCopyInto(out *BlkIOQOS) {
 	*out = *in
@@ -153,6 +198,16 @@ func (in *CPUBurstConfig) DeepCopyInto(out *CPUBurstConfig) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ThrottledRatioThresholdPercent != nil {
+		in, out := &in.ThrottledRatioThresholdPercent, &out.ThrottledRatioThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HistoryWindowSeconds != nil {
+		in, out := &in.HistoryWindowSeconds, &out.HistoryWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUBurstConfig.
@@ -237,6 +292,46 @@ func (in *CPUQOSCfg) DeepCopy() *CPUQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUSuppressLatencyConfig) DeepCopyInto(out *CPUSuppressLatencyConfig) {
+	*out = *in
+	if in.LSCPUPressureTargetPercent != nil {
+		in, out := &in.LSCPUPressureTargetPercent, &out.LSCPUPressureTargetPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LSRunQueueLatencyTargetMicroSeconds != nil {
+		in, out := &in.LSRunQueueLatencyTargetMicroSeconds, &out.LSRunQueueLatencyTargetMicroSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ProportionalGainPercent != nil {
+		in, out := &in.ProportionalGainPercent, &out.ProportionalGainPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IntegralGainPercent != nil {
+		in, out := &in.IntegralGainPercent, &out.IntegralGainPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DerivativeGainPercent != nil {
+		in, out := &in.DerivativeGainPercent, &out.DerivativeGainPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUSuppressLatencyConfig.
+func (in *CPUSuppressLatencyConfig) DeepCopy() *CPUSuppressLatencyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUSuppressLatencyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CgroupPath) DeepCopyInto(out *CgroupPath) {
 	*out = *in
@@ -279,7 +374,7 @@ func (in *HostApplicationSpec) DeepCopyInto(out *HostApplicationSpec) {
 	if in.Strategy != nil {
 		in, out := &in.Strategy, &out.Strategy
 		*out = new(HostApplicationStrategy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -296,6 +391,16 @@ func (in *HostApplicationSpec) DeepCopy() *HostApplicationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostApplicationStrategy) DeepCopyInto(out *HostApplicationStrategy) {
 	*out = *in
+	if in.ResourceQOS != nil {
+		in, out := &in.ResourceQOS, &out.ResourceQOS
+		*out = new(ResourceQOS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUBurstConfig != nil {
+		in, out := &in.CPUBurstConfig, &out.CPUBurstConfig
+		*out = new(CPUBurstConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostApplicationStrategy.
@@ -346,6 +451,11 @@ func (in *IOCfg) DeepCopyInto(out *IOCfg) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Dynamic != nil {
+		in, out := &in.Dynamic, &out.Dynamic
+		*out = new(BlkIODynamicCfg)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOCfg.
@@ -358,6 +468,21 @@ func (in *IOCfg) DeepCopy() *IOCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelFeature) DeepCopyInto(out *KernelFeature) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelFeature.
+func (in *KernelFeature) DeepCopy() *KernelFeature {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelFeature)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemoryQOS) DeepCopyInto(out *MemoryQOS) {
 	*out = *in
@@ -520,6 +645,11 @@ func (in *NodeMetricInfo) DeepCopyInto(out *NodeMetricInfo) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LSCPUPressurePercent != nil {
+		in, out := &in.LSCPUPressurePercent, &out.LSCPUPressurePercent
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricInfo.
@@ -641,7 +771,7 @@ func (in *NodeSLO) DeepCopyInto(out *NodeSLO) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSLO.
@@ -743,6 +873,22 @@ func (in *NodeSLOSpec) DeepCopy() *NodeSLOSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSLOStatus) DeepCopyInto(out *NodeSLOStatus) {
 	*out = *in
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]StrategyCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KernelFeatures != nil {
+		in, out := &in.KernelFeatures, &out.KernelFeatures
+		*out = make([]KernelFeature, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSLOStatus.
@@ -829,6 +975,61 @@ func (in *ReclaimableMetric) DeepCopy() *ReclaimableMetric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResctrlAdaptiveCfg) DeepCopyInto(out *ResctrlAdaptiveCfg) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CATRangeEndMinPercent != nil {
+		in, out := &in.CATRangeEndMinPercent, &out.CATRangeEndMinPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MBAMinPercent != nil {
+		in, out := &in.MBAMinPercent, &out.MBAMinPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StepPercent != nil {
+		in, out := &in.StepPercent, &out.StepPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CPIDegradationThresholdPercent != nil {
+		in, out := &in.CPIDegradationThresholdPercent, &out.CPIDegradationThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryBandwidthDegradationThresholdPercent != nil {
+		in, out := &in.MemoryBandwidthDegradationThresholdPercent, &out.MemoryBandwidthDegradationThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WindowSeconds != nil {
+		in, out := &in.WindowSeconds, &out.WindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BaselineWindowSeconds != nil {
+		in, out := &in.BaselineWindowSeconds, &out.BaselineWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResctrlAdaptiveCfg.
+func (in *ResctrlAdaptiveCfg) DeepCopy() *ResctrlAdaptiveCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(ResctrlAdaptiveCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResctrlQOS) DeepCopyInto(out *ResctrlQOS) {
 	*out = *in
@@ -868,6 +1069,11 @@ func (in *ResctrlQOSCfg) DeepCopyInto(out *ResctrlQOSCfg) {
 		**out = **in
 	}
 	in.ResctrlQOS.DeepCopyInto(&out.ResctrlQOS)
+	if in.Adaptive != nil {
+		in, out := &in.Adaptive, &out.Adaptive
+		*out = new(ResctrlAdaptiveCfg)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResctrlQOSCfg.
@@ -1022,6 +1228,11 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CPUSuppressLatencyConfig != nil {
+		in, out := &in.CPUSuppressLatencyConfig, &out.CPUSuppressLatencyConfig
+		*out = new(CPUSuppressLatencyConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MemoryEvictThresholdPercent != nil {
 		in, out := &in.MemoryEvictThresholdPercent, &out.MemoryEvictThresholdPercent
 		*out = new(int64)
@@ -1052,6 +1263,16 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MidCPUEvictThresholdPercent != nil {
+		in, out := &in.MidCPUEvictThresholdPercent, &out.MidCPUEvictThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MidMemoryEvictThresholdPercent != nil {
+		in, out := &in.MidMemoryEvictThresholdPercent, &out.MidMemoryEvictThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceThresholdStrategy.
@@ -1064,6 +1285,22 @@ func (in *ResourceThresholdStrategy) DeepCopy() *ResourceThresholdStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrategyCondition) DeepCopyInto(out *StrategyCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrategyCondition.
+func (in *StrategyCondition) DeepCopy() *StrategyCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(StrategyCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemStrategy) DeepCopyInto(out *SystemStrategy) {
 	*out = *in
@@ -1082,6 +1319,11 @@ func (in *SystemStrategy) DeepCopyInto(out *SystemStrategy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ResourceExecutorDryRun != nil {
+		in, out := &in.ResourceExecutorDryRun, &out.ResourceExecutorDryRun
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemStrategy.
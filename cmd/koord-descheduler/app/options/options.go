@@ -51,6 +51,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/informers"
 	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
 	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
 )
 
 func newDefaultComponentConfig() (*deschedulerconfig.DeschedulerConfiguration, error) {
@@ -164,6 +165,7 @@ func (o *Options) initFlags() {
 	fs := nfs.FlagSet("misc")
 	fs.StringVar(&o.ConfigFile, "config", o.ConfigFile, "The path to the configuration file.")
 	fs.StringVar(&o.WriteConfigTo, "write-config-to", o.WriteConfigTo, "If set, write the configuration values to this file and exit.")
+	victim.AddFlags(fs)
 
 	o.SecureServing.AddFlags(nfs.FlagSet("secure serving"))
 	o.CombinedInsecureServing.AddFlags(nfs.FlagSet("insecure serving"))
@@ -21,6 +21,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/koordinator-sh/koordinator/pkg/quota-controller/batchresource"
 	"github.com/koordinator-sh/koordinator/pkg/quota-controller/profile"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodemetric"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource"
@@ -32,8 +33,9 @@ var controllerInitFlags = map[string]func(*flag.FlagSet){
 }
 
 var controllerAddFuncs = map[string]func(manager.Manager) error{
-	nodemetric.Name:   nodemetric.Add,
-	noderesource.Name: noderesource.Add,
-	nodeslo.Name:      nodeslo.Add,
-	profile.Name:      profile.Add,
+	nodemetric.Name:    nodemetric.Add,
+	noderesource.Name:  noderesource.Add,
+	nodeslo.Name:       nodeslo.Add,
+	profile.Name:       profile.Add,
+	batchresource.Name: batchresource.Add,
 }
@@ -67,6 +67,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/util/asynclog"
 	utilroutes "github.com/koordinator-sh/koordinator/pkg/util/routes"
 	"github.com/koordinator-sh/koordinator/pkg/util/transformer"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
 )
 
 func init() {
@@ -109,6 +110,7 @@ for cost reduction and efficiency enhancement.
 	verflag.AddFlags(nfs.FlagSet("global"))
 	globalflag.AddGlobalFlags(nfs.FlagSet("global"), cmd.Name(), logs.SkipLoggingConfigurationFlags())
 	frameworkext.AddFlags(nfs.FlagSet("extend"))
+	victim.AddFlags(nfs.FlagSet("extend"))
 	fs := cmd.Flags()
 	for _, f := range nfs.FlagSets {
 		fs.AddFlagSet(f)
@@ -415,7 +417,10 @@ func Setup(ctx context.Context, opts *options.Options, outOfTreeRegistryOptions
 	}
 
 	frameworkExtenderFactory.InterceptSchedulerError(sched)
-	frameworkExtenderFactory.InitScheduler(&frameworkext.SchedulerAdapter{Scheduler: sched})
+	frameworkExtenderFactory.InitScheduler(&frameworkext.SchedulerAdapter{
+		Scheduler:  sched,
+		NodeLister: cc.InformerFactory.Core().V1().Nodes().Lister(),
+	})
 	schedAdapter := frameworkExtenderFactory.Scheduler()
 
 	eventhandlers.AddScheduleEventHandler(sched, schedAdapter, frameworkExtenderFactory.KoordinatorSharedInformerFactory())
@@ -33,6 +33,7 @@ import (
 	agent "github.com/koordinator-sh/koordinator/pkg/koordlet"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/config"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 )
 
 func main() {
@@ -84,6 +85,12 @@ func main() {
 		if features.DefaultKoordletFeatureGate.Enabled(features.AuditEventsHTTPHandler) {
 			mux.HandleFunc("/events", audit.HttpHandler())
 		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.ResourceExecutorDryRun) {
+			mux.HandleFunc("/resource-updates/dry-run", resourceexecutor.DryRunHttpHandler())
+		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.ResourceJournal) {
+			mux.HandleFunc("/resource-updates/journal", resourceexecutor.JournalHttpHandler())
+		}
 		// http.HandleFunc("/healthz", d.HealthzHandler())
 		klog.Fatalf("Prometheus monitoring failed: %v", http.ListenAndServe(*options.ServerAddr, mux))
 	}()
@@ -29,8 +29,10 @@ import (
 type SchedulingV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	DevicesGetter
+	NodeDrainsGetter
 	PodMigrationJobsGetter
 	ReservationsGetter
+	ReservationSetsGetter
 }
 
 // SchedulingV1alpha1Client is used to interact with features provided by the scheduling group.
@@ -42,6 +44,10 @@ func (c *SchedulingV1alpha1Client) Devices() DeviceInterface {
 	return newDevices(c)
 }
 
+func (c *SchedulingV1alpha1Client) NodeDrains() NodeDrainInterface {
+	return newNodeDrains(c)
+}
+
 func (c *SchedulingV1alpha1Client) PodMigrationJobs() PodMigrationJobInterface {
 	return newPodMigrationJobs(c)
 }
@@ -50,6 +56,10 @@ func (c *SchedulingV1alpha1Client) Reservations() ReservationInterface {
 	return newReservations(c)
 }
 
+func (c *SchedulingV1alpha1Client) ReservationSets() ReservationSetInterface {
+	return newReservationSets(c)
+}
+
 // NewForConfig creates a new SchedulingV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
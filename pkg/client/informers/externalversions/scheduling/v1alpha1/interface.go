@@ -26,10 +26,14 @@ import (
 type Interface interface {
 	// Devices returns a DeviceInformer.
 	Devices() DeviceInformer
+	// NodeDrains returns a NodeDrainInformer.
+	NodeDrains() NodeDrainInformer
 	// PodMigrationJobs returns a PodMigrationJobInformer.
 	PodMigrationJobs() PodMigrationJobInformer
 	// Reservations returns a ReservationInformer.
 	Reservations() ReservationInformer
+	// ReservationSets returns a ReservationSetInformer.
+	ReservationSets() ReservationSetInformer
 }
 
 type version struct {
@@ -48,6 +52,11 @@ func (v *version) Devices() DeviceInformer {
 	return &deviceInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// NodeDrains returns a NodeDrainInformer.
+func (v *version) NodeDrains() NodeDrainInformer {
+	return &nodeDrainInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // PodMigrationJobs returns a PodMigrationJobInformer.
 func (v *version) PodMigrationJobs() PodMigrationJobInformer {
 	return &podMigrationJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -57,3 +66,8 @@ func (v *version) PodMigrationJobs() PodMigrationJobInformer {
 func (v *version) Reservations() ReservationInformer {
 	return &reservationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// ReservationSets returns a ReservationSetInformer.
+func (v *version) ReservationSets() ReservationSetInformer {
+	return &reservationSetInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
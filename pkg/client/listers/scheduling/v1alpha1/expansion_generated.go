@@ -22,6 +22,10 @@ package v1alpha1
 // DeviceLister.
 type DeviceListerExpansion interface{}
 
+// NodeDrainListerExpansion allows custom methods to be added to
+// NodeDrainLister.
+type NodeDrainListerExpansion interface{}
+
 // PodMigrationJobListerExpansion allows custom methods to be added to
 // PodMigrationJobLister.
 type PodMigrationJobListerExpansion interface{}
@@ -29,3 +33,7 @@ type PodMigrationJobListerExpansion interface{}
 // ReservationListerExpansion allows custom methods to be added to
 // ReservationLister.
 type ReservationListerExpansion interface{}
+
+// ReservationSetListerExpansion allows custom methods to be added to
+// ReservationSetLister.
+type ReservationSetListerExpansion interface{}
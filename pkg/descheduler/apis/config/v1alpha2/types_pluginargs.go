@@ -112,6 +112,14 @@ type MigrationControllerArgs struct {
 
 	// ArbitrationArgs defines the control parameters of the Arbitration Mechanism.
 	ArbitrationArgs *ArbitrationArgs `json:"arbitrationArgs,omitempty"`
+
+	// MaintenanceWindows if specified, PodMigrationJobs only start within these windows.
+	// The running PodMigrationJobs are not interrupted when the windows end.
+	// PodMigrationJobs can override the windows by spec.executionPolicy.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// BatchPolicy if specified, PodMigrationJobs are executed in batches.
+	BatchPolicy *MigrationBatchPolicy `json:"batchPolicy,omitempty"`
 }
 
 type MigrationLimitObjectType string
@@ -142,3 +150,32 @@ type ArbitrationArgs struct {
 	// Default is 500 ms
 	Interval *metav1.Duration `json:"interval,omitempty"`
 }
+
+// MaintenanceWindow represents a periodic time window in which PodMigrationJobs can start.
+type MaintenanceWindow struct {
+	// StartTime is the start time of the window in the format "15:04", e.g. "02:00"
+	StartTime string `json:"startTime"`
+	// Duration is the length of the window, it must be greater than 0 and not more than 24h
+	Duration metav1.Duration `json:"duration"`
+	// Weekdays are the days of the week when the window starts, e.g. "Saturday".
+	// The window starts every day if not specified.
+	Weekdays []string `json:"weekdays,omitempty"`
+	// TimeZone is the IANA time zone name of the StartTime, e.g. "Asia/Shanghai". Default is UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MigrationBatchPolicy groups PodMigrationJobs into batches. The next batch starts after all jobs
+// of the previous batch are completed and BatchInterval has elapsed.
+type MigrationBatchPolicy struct {
+	// BatchSize is the maximum number of PodMigrationJobs in a batch.
+	BatchSize int32 `json:"batchSize,omitempty"`
+	// BatchInterval is the pause between the completion of a batch and the start of the next batch.
+	BatchInterval metav1.Duration `json:"batchInterval,omitempty"`
+	// MaxFailedPerBatch is the maximum number of Failed or Aborted PodMigrationJobs in a batch.
+	// Value can be an absolute number (ex: 5) or a percentage of the batch (ex: 10%).
+	// The migration halts if a completed batch exceeds it. No limit if not specified.
+	MaxFailedPerBatch *intstr.IntOrString `json:"maxFailedPerBatch,omitempty"`
+	// HaltDuration is how long the migration halts after a batch exceeded MaxFailedPerBatch.
+	// Zero means halting until the descheduler restarts.
+	HaltDuration metav1.Duration `json:"haltDuration,omitempty"`
+}
@@ -95,6 +95,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MaintenanceWindow)(nil), (*config.MaintenanceWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MaintenanceWindow_To_config_MaintenanceWindow(a.(*MaintenanceWindow), b.(*config.MaintenanceWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MaintenanceWindow)(nil), (*MaintenanceWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MaintenanceWindow_To_v1alpha2_MaintenanceWindow(a.(*config.MaintenanceWindow), b.(*MaintenanceWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MigrationBatchPolicy)(nil), (*config.MigrationBatchPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MigrationBatchPolicy_To_config_MigrationBatchPolicy(a.(*MigrationBatchPolicy), b.(*config.MigrationBatchPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MigrationBatchPolicy)(nil), (*MigrationBatchPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MigrationBatchPolicy_To_v1alpha2_MigrationBatchPolicy(a.(*config.MigrationBatchPolicy), b.(*MigrationBatchPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MigrationControllerArgs)(nil), (*config.MigrationControllerArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerArgs(a.(*MigrationControllerArgs), b.(*config.MigrationControllerArgs), scope)
 	}); err != nil {
@@ -524,6 +544,58 @@ func Convert_config_LowNodeLoadPodSelector_To_v1alpha2_LowNodeLoadPodSelector(in
 	return autoConvert_config_LowNodeLoadPodSelector_To_v1alpha2_LowNodeLoadPodSelector(in, out, s)
 }
 
+func autoConvert_v1alpha2_MaintenanceWindow_To_config_MaintenanceWindow(in *MaintenanceWindow, out *config.MaintenanceWindow, s conversion.Scope) error {
+	out.StartTime = in.StartTime
+	out.Duration = in.Duration
+	out.Weekdays = *(*[]string)(unsafe.Pointer(&in.Weekdays))
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_v1alpha2_MaintenanceWindow_To_config_MaintenanceWindow is an autogenerated conversion function.
+func Convert_v1alpha2_MaintenanceWindow_To_config_MaintenanceWindow(in *MaintenanceWindow, out *config.MaintenanceWindow, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MaintenanceWindow_To_config_MaintenanceWindow(in, out, s)
+}
+
+func autoConvert_config_MaintenanceWindow_To_v1alpha2_MaintenanceWindow(in *config.MaintenanceWindow, out *MaintenanceWindow, s conversion.Scope) error {
+	out.StartTime = in.StartTime
+	out.Duration = in.Duration
+	out.Weekdays = *(*[]string)(unsafe.Pointer(&in.Weekdays))
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_config_MaintenanceWindow_To_v1alpha2_MaintenanceWindow is an autogenerated conversion function.
+func Convert_config_MaintenanceWindow_To_v1alpha2_MaintenanceWindow(in *config.MaintenanceWindow, out *MaintenanceWindow, s conversion.Scope) error {
+	return autoConvert_config_MaintenanceWindow_To_v1alpha2_MaintenanceWindow(in, out, s)
+}
+
+func autoConvert_v1alpha2_MigrationBatchPolicy_To_config_MigrationBatchPolicy(in *MigrationBatchPolicy, out *config.MigrationBatchPolicy, s conversion.Scope) error {
+	out.BatchSize = in.BatchSize
+	out.BatchInterval = in.BatchInterval
+	out.MaxFailedPerBatch = (*intstr.IntOrString)(unsafe.Pointer(in.MaxFailedPerBatch))
+	out.HaltDuration = in.HaltDuration
+	return nil
+}
+
+// Convert_v1alpha2_MigrationBatchPolicy_To_config_MigrationBatchPolicy is an autogenerated conversion function.
+func Convert_v1alpha2_MigrationBatchPolicy_To_config_MigrationBatchPolicy(in *MigrationBatchPolicy, out *config.MigrationBatchPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MigrationBatchPolicy_To_config_MigrationBatchPolicy(in, out, s)
+}
+
+func autoConvert_config_MigrationBatchPolicy_To_v1alpha2_MigrationBatchPolicy(in *config.MigrationBatchPolicy, out *MigrationBatchPolicy, s conversion.Scope) error {
+	out.BatchSize = in.BatchSize
+	out.BatchInterval = in.BatchInterval
+	out.MaxFailedPerBatch = (*intstr.IntOrString)(unsafe.Pointer(in.MaxFailedPerBatch))
+	out.HaltDuration = in.HaltDuration
+	return nil
+}
+
+// Convert_config_MigrationBatchPolicy_To_v1alpha2_MigrationBatchPolicy is an autogenerated conversion function.
+func Convert_config_MigrationBatchPolicy_To_v1alpha2_MigrationBatchPolicy(in *config.MigrationBatchPolicy, out *MigrationBatchPolicy, s conversion.Scope) error {
+	return autoConvert_config_MigrationBatchPolicy_To_v1alpha2_MigrationBatchPolicy(in, out, s)
+}
+
 func autoConvert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerArgs(in *MigrationControllerArgs, out *config.MigrationControllerArgs, s conversion.Scope) error {
 	out.DryRun = in.DryRun
 	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxConcurrentReconciles, &out.MaxConcurrentReconciles, s); err != nil {
@@ -556,6 +628,8 @@ func autoConvert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerA
 	out.EvictionPolicy = in.EvictionPolicy
 	out.DefaultDeleteOptions = (*v1.DeleteOptions)(unsafe.Pointer(in.DefaultDeleteOptions))
 	out.ArbitrationArgs = (*config.ArbitrationArgs)(unsafe.Pointer(in.ArbitrationArgs))
+	out.MaintenanceWindows = *(*[]config.MaintenanceWindow)(unsafe.Pointer(&in.MaintenanceWindows))
+	out.BatchPolicy = (*config.MigrationBatchPolicy)(unsafe.Pointer(in.BatchPolicy))
 	return nil
 }
 
@@ -596,6 +670,8 @@ func autoConvert_config_MigrationControllerArgs_To_v1alpha2_MigrationControllerA
 	out.DefaultDeleteOptions = (*v1.DeleteOptions)(unsafe.Pointer(in.DefaultDeleteOptions))
 	out.SchedulerNames = *(*[]string)(unsafe.Pointer(&in.SchedulerNames))
 	out.ArbitrationArgs = (*ArbitrationArgs)(unsafe.Pointer(in.ArbitrationArgs))
+	out.MaintenanceWindows = *(*[]MaintenanceWindow)(unsafe.Pointer(&in.MaintenanceWindows))
+	out.BatchPolicy = (*MigrationBatchPolicy)(unsafe.Pointer(in.BatchPolicy))
 	return nil
 }
 
@@ -18,6 +18,7 @@ package validation
 
 import (
 	"fmt"
+	"time"
 
 	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -79,8 +80,63 @@ func ValidateMigrationControllerArgs(path *field.Path, args *deschedulerconfig.M
 		allErrs = append(allErrs, field.Invalid(path.Child("defaultJobTTL"), args.DefaultJobTTL, "defaultJobTTL should be positive or zero"))
 	}
 
+	for i := range args.MaintenanceWindows {
+		allErrs = append(allErrs, validateMaintenanceWindow(path.Child("maintenanceWindows").Index(i), &args.MaintenanceWindows[i])...)
+	}
+
+	if args.BatchPolicy != nil {
+		allErrs = append(allErrs, validateMigrationBatchPolicy(path.Child("batchPolicy"), args.BatchPolicy)...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return allErrs.ToAggregate()
 }
+
+func validateMaintenanceWindow(path *field.Path, window *deschedulerconfig.MaintenanceWindow) field.ErrorList {
+	var allErrs field.ErrorList
+	if _, err := time.Parse("15:04", window.StartTime); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("startTime"), window.StartTime, "startTime must be in the format 15:04"))
+	}
+	if window.Duration.Duration <= 0 || window.Duration.Duration > 24*time.Hour {
+		allErrs = append(allErrs, field.Invalid(path.Child("duration"), window.Duration, "duration must be greater than 0 and not more than 24h"))
+	}
+	for i, weekday := range window.Weekdays {
+		if !isValidWeekday(weekday) {
+			allErrs = append(allErrs, field.Invalid(path.Child("weekdays").Index(i), weekday, "weekday must be one of Sunday, Monday, ..., Saturday"))
+		}
+	}
+	if _, err := time.LoadLocation(window.TimeZone); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), window.TimeZone, fmt.Sprintf("timeZone is invalid, err: %v", err)))
+	}
+	return allErrs
+}
+
+func isValidWeekday(weekday string) bool {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if d.String() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func validateMigrationBatchPolicy(path *field.Path, policy *deschedulerconfig.MigrationBatchPolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	if policy.BatchSize <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("batchSize"), policy.BatchSize, "batchSize must be greater than 0"))
+	}
+	if policy.BatchInterval.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("batchInterval"), policy.BatchInterval, "batchInterval should be positive or zero"))
+	}
+	if policy.MaxFailedPerBatch != nil {
+		if _, err := intstr.GetScaledValueFromIntOrPercent(policy.MaxFailedPerBatch, 100, false); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxFailedPerBatch"), *policy.MaxFailedPerBatch, fmt.Sprintf("maxFailedPerBatch is invalid, err: %v ", err)))
+		}
+	}
+	if policy.HaltDuration.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("haltDuration"), policy.HaltDuration, "haltDuration should be positive or zero"))
+	}
+	return allErrs
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
@@ -92,6 +93,47 @@ func TestValidateMigrationControllerArgs(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid maintenanceWindows and batchPolicy",
+			args: &v1alpha2.MigrationControllerArgs{
+				MaintenanceWindows: []v1alpha2.MaintenanceWindow{
+					{
+						StartTime: "02:00",
+						Duration:  metav1.Duration{Duration: 4 * time.Hour},
+						Weekdays:  []string{"Saturday", "Sunday"},
+					},
+				},
+				BatchPolicy: &v1alpha2.MigrationBatchPolicy{
+					BatchSize:         10,
+					BatchInterval:     metav1.Duration{Duration: 5 * time.Minute},
+					MaxFailedPerBatch: &intstr.IntOrString{Type: intstr.String, StrVal: "20%"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid maintenanceWindows",
+			args: &v1alpha2.MigrationControllerArgs{
+				MaintenanceWindows: []v1alpha2.MaintenanceWindow{
+					{
+						StartTime: "2am",
+						Duration:  metav1.Duration{Duration: 25 * time.Hour},
+						Weekdays:  []string{"Sat"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid batchPolicy",
+			args: &v1alpha2.MigrationControllerArgs{
+				BatchPolicy: &v1alpha2.MigrationBatchPolicy{
+					BatchSize:         0,
+					MaxFailedPerBatch: &intstr.IntOrString{Type: intstr.String, StrVal: "xx"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
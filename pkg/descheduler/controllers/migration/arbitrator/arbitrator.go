@@ -34,6 +34,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/sorter"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
 )
 
 const (
@@ -45,6 +46,7 @@ var enqueueLog = klog.Background().WithName("eventHandler").WithName("arbitrator
 
 type MigrationFilter interface {
 	Filter(pod *corev1.Pod) bool
+	EvictableFilter(pod *corev1.Pod) bool
 	PreEvictionFilter(pod *corev1.Pod) bool
 	TrackEvictedPod(pod *corev1.Pod)
 }
@@ -63,8 +65,9 @@ type arbitratorImpl struct {
 	waitingCollection map[types.UID]*v1alpha1.PodMigrationJob
 	interval          time.Duration
 
-	sorts  []SortFn
-	filter *filter
+	sorts   []SortFn
+	filter  *filter
+	batcher *batcher
 
 	client        client.Client
 	eventRecorder events.EventRecorder
@@ -77,17 +80,28 @@ func New(args *config.MigrationControllerArgs, options Options) (Arbitrator, err
 	if err != nil {
 		return nil, err
 	}
+	b, err := newBatcher(args, options.EventRecorder)
+	if err != nil {
+		return nil, err
+	}
+
+	var rankerOpts []victim.Option
+	if options.Handle != nil && options.Handle.SharedInformerFactory() != nil {
+		pdbLister := options.Handle.SharedInformerFactory().Policy().V1().PodDisruptionBudgets().Lister()
+		rankerOpts = append(rankerOpts, victim.WithDisruptionsAllowed(victim.PDBDisruptionsAllowed(pdbLister)))
+	}
 
 	arbitrator := &arbitratorImpl{
 		waitingCollection: map[types.UID]*v1alpha1.PodMigrationJob{},
 		interval:          args.ArbitrationArgs.Interval.Duration,
 		sorts: []SortFn{
 			SortJobsByCreationTime(),
-			SortJobsByPod(sorter.PodSorter().Sort),
+			SortJobsByPod(sorter.RankerSorter(nil, rankerOpts...).Sort),
 			SortJobsByController(),
 			SortJobsByMigratingNum(options.Client),
 		},
 		filter:        f,
+		batcher:       b,
 		client:        options.Client,
 		eventRecorder: options.EventRecorder,
 		mu:            sync.Mutex{},
@@ -112,6 +126,7 @@ func (a *arbitratorImpl) AddPodMigrationJob(job *v1alpha1.PodMigrationJob) {
 // It is safe to be called concurrently by multiple goroutines.
 func (a *arbitratorImpl) DeletePodMigrationJob(job *v1alpha1.PodMigrationJob) {
 	a.filter.removeJobPassedArbitration(job.UID)
+	a.batcher.complete(job)
 }
 
 // Start starts the goroutine to arbitrate jobs periodically.
@@ -140,6 +155,12 @@ func (a *arbitratorImpl) Filter(pod *corev1.Pod) bool {
 	return true
 }
 
+// EvictableFilter checks if a pod can be evicted regardless of the retryable limits
+// such as MaxMigratingPerNode and MaxUnavailablePerWorkload.
+func (a *arbitratorImpl) EvictableFilter(pod *corev1.Pod) bool {
+	return a.filter.nonRetryablePodFilter == nil || a.filter.nonRetryablePodFilter(pod)
+}
+
 func (a *arbitratorImpl) PreEvictionFilter(pod *corev1.Pod) bool {
 	return a.filter.defaultFilterPlugin.PreEvictionFilter(pod)
 }
@@ -180,8 +201,11 @@ func (a *arbitratorImpl) updatePassedJob(job *v1alpha1.PodMigrationJob) {
 		job.Annotations = map[string]string{}
 	}
 	job.Annotations[AnnotationPassedArbitration] = "true"
+	// admit the job into the batch before updating, since the job may complete before the update returns.
+	a.batcher.admit(job)
 	err := a.client.Update(context.TODO(), job)
 	if err != nil {
+		a.batcher.unadmit(job)
 		klog.ErrorS(err, "failed to update job", "job", klog.KObj(job))
 	} else {
 		a.filter.markJobPassedArbitration(job.UID)
@@ -207,6 +231,14 @@ func (a *arbitratorImpl) doOnceArbitrate() {
 
 	// filter
 	for _, job := range jobs {
+		inWindow, err := a.batcher.inMaintenanceWindow(job)
+		if err != nil {
+			a.abortJob(job, v1alpha1.PodMigrationJobReasonInvalidExecutionPolicy, fmt.Sprintf("Invalid maintenance windows: %v", err))
+			continue
+		}
+		if !inWindow || !a.batcher.allowed(job) {
+			continue
+		}
 		pod := podOfJob[job]
 		isFailed, isPassed := a.filtering(pod)
 		if isFailed {
@@ -231,13 +263,17 @@ func (a *arbitratorImpl) copyJobs() []*v1alpha1.PodMigrationJob {
 }
 
 func (a *arbitratorImpl) updateFailedJob(job *v1alpha1.PodMigrationJob, pod *corev1.Pod) {
-	// change phase to Failed
+	a.abortJob(job, v1alpha1.PodMigrationJobReasonForbiddenMigratePod, fmt.Sprintf("Pod %q is forbidden to migrate because it does not meet the requirements", klog.KObj(pod)))
+}
+
+// abortJob changes the phase of the job to Failed and removes it from the waitingCollection.
+func (a *arbitratorImpl) abortJob(job *v1alpha1.PodMigrationJob, reason, message string) {
 	job.Status.Phase = v1alpha1.PodMigrationJobFailed
-	job.Status.Reason = v1alpha1.PodMigrationJobReasonForbiddenMigratePod
-	job.Status.Message = fmt.Sprintf("Pod %q is forbidden to migrate because it does not meet the requirements", klog.KObj(pod))
+	job.Status.Reason = reason
+	job.Status.Message = message
 	err := a.client.Status().Update(context.TODO(), job)
 	if err == nil {
-		a.eventRecorder.Eventf(job, nil, corev1.EventTypeWarning, v1alpha1.PodMigrationJobReasonForbiddenMigratePod, "Migrating", job.Status.Message)
+		a.eventRecorder.Eventf(job, nil, corev1.EventTypeWarning, reason, "Migrating", job.Status.Message)
 	}
 
 	// delete from waitingCollection
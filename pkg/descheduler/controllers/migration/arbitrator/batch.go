@@ -0,0 +1,279 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitrator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+)
+
+const (
+	ReasonMigrationHalted = "MigrationHalted"
+)
+
+type maintenanceWindow struct {
+	start    time.Duration
+	duration time.Duration
+	weekdays map[time.Weekday]bool
+	location *time.Location
+}
+
+func newMaintenanceWindow(startTime string, duration time.Duration, weekdays []string, timeZone string) (*maintenanceWindow, error) {
+	start, err := time.Parse("15:04", startTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startTime %q: %v", startTime, err)
+	}
+	if duration <= 0 || duration > 24*time.Hour {
+		return nil, fmt.Errorf("invalid duration %v, it must be greater than 0 and not more than 24h", duration)
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeZone %q: %v", timeZone, err)
+	}
+	w := &maintenanceWindow{
+		start:    time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		duration: duration,
+		location: location,
+	}
+	for _, weekday := range weekdays {
+		d, ok := parseWeekday(weekday)
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", weekday)
+		}
+		if w.weekdays == nil {
+			w.weekdays = map[time.Weekday]bool{}
+		}
+		w.weekdays[d] = true
+	}
+	return w, nil
+}
+
+func parseWeekday(weekday string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if d.String() == weekday {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// contains checks whether now is in the window. Since the window lasts at most 24h,
+// only the windows started today and yesterday need to be checked.
+func (w *maintenanceWindow) contains(now time.Time) bool {
+	now = now.In(w.location)
+	for _, offset := range []int{0, -1} {
+		day := now.AddDate(0, 0, offset)
+		if len(w.weekdays) > 0 && !w.weekdays[day.Weekday()] {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, w.location).Add(w.start)
+		if !now.Before(start) && now.Before(start.Add(w.duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+func inMaintenanceWindows(windows []*maintenanceWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, w := range windows {
+		if w.contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// batcher decides when PodMigrationJobs can start according to the maintenance windows and the batch policy.
+// A nil batcher allows all PodMigrationJobs to start at any time.
+type batcher struct {
+	windows       []*maintenanceWindow
+	policy        *config.MigrationBatchPolicy
+	clock         clock.Clock
+	eventRecorder events.EventRecorder
+
+	lock sync.Mutex
+	// running records the jobs of the current batch which are not completed.
+	running map[types.UID]*v1alpha1.PodMigrationJob
+	// admitted is the number of jobs in the current batch.
+	admitted int
+	// failed is the number of Failed or Aborted jobs in the current batch.
+	failed            int
+	lastBatchFinished time.Time
+	halted            bool
+	haltedAt          time.Time
+}
+
+func newBatcher(args *config.MigrationControllerArgs, eventRecorder events.EventRecorder) (*batcher, error) {
+	if len(args.MaintenanceWindows) == 0 && args.BatchPolicy == nil {
+		return nil, nil
+	}
+	b := &batcher{
+		policy:        args.BatchPolicy,
+		clock:         clock.RealClock{},
+		eventRecorder: eventRecorder,
+		running:       map[types.UID]*v1alpha1.PodMigrationJob{},
+	}
+	for _, window := range args.MaintenanceWindows {
+		w, err := newMaintenanceWindow(window.StartTime, window.Duration.Duration, window.Weekdays, window.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		b.windows = append(b.windows, w)
+	}
+	return b, nil
+}
+
+// inMaintenanceWindow checks whether the job can start now according to the maintenance windows.
+// The windows of the job override the windows of the MigrationController.
+func (b *batcher) inMaintenanceWindow(job *v1alpha1.PodMigrationJob) (bool, error) {
+	policy := job.Spec.ExecutionPolicy
+	if policy != nil && policy.IgnoreMaintenanceWindows {
+		return true, nil
+	}
+	if policy != nil && len(policy.MaintenanceWindows) > 0 {
+		var windows []*maintenanceWindow
+		for _, window := range policy.MaintenanceWindows {
+			w, err := newMaintenanceWindow(window.StartTime, window.Duration.Duration, window.Weekdays, window.TimeZone)
+			if err != nil {
+				return false, err
+			}
+			windows = append(windows, w)
+		}
+		return inMaintenanceWindows(windows, b.now()), nil
+	}
+	if b == nil {
+		return true, nil
+	}
+	return inMaintenanceWindows(b.windows, b.now()), nil
+}
+
+func (b *batcher) now() time.Time {
+	if b == nil {
+		return time.Now()
+	}
+	return b.clock.Now()
+}
+
+func ignoreBatch(job *v1alpha1.PodMigrationJob) bool {
+	return job.Spec.ExecutionPolicy != nil && job.Spec.ExecutionPolicy.IgnoreBatch
+}
+
+// allowed checks whether the job can join the current batch.
+func (b *batcher) allowed(job *v1alpha1.PodMigrationJob) bool {
+	if b == nil || b.policy == nil || ignoreBatch(job) {
+		return true
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.clock.Now()
+	if b.halted {
+		if b.policy.HaltDuration.Duration <= 0 || now.Before(b.haltedAt.Add(b.policy.HaltDuration.Duration)) {
+			return false
+		}
+		b.halted = false
+		klog.InfoS("Migration resumed after halting", "haltDuration", b.policy.HaltDuration.Duration)
+	}
+	if b.admitted >= int(b.policy.BatchSize) {
+		return false
+	}
+	if b.admitted == 0 && now.Before(b.lastBatchFinished.Add(b.policy.BatchInterval.Duration)) {
+		return false
+	}
+	return true
+}
+
+// admit adds the job into the current batch.
+func (b *batcher) admit(job *v1alpha1.PodMigrationJob) {
+	if b == nil || b.policy == nil || ignoreBatch(job) {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.running[job.UID]; ok {
+		return
+	}
+	b.running[job.UID] = job
+	b.admitted++
+}
+
+// unadmit removes the job from the current batch if the job failed to start.
+func (b *batcher) unadmit(job *v1alpha1.PodMigrationJob) {
+	if b == nil || b.policy == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.running[job.UID]; !ok {
+		return
+	}
+	delete(b.running, job.UID)
+	b.admitted--
+}
+
+// complete marks the job of the current batch completed, and finishes the batch if all jobs are completed.
+// The migration halts if the Failed or Aborted jobs of the finished batch exceed MaxFailedPerBatch.
+func (b *batcher) complete(job *v1alpha1.PodMigrationJob) {
+	if b == nil || b.policy == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.running[job.UID]; !ok {
+		return
+	}
+	delete(b.running, job.UID)
+	if job.Status.Phase == v1alpha1.PodMigrationJobFailed || job.Status.Phase == v1alpha1.PodMigrationJobAborted {
+		b.failed++
+	}
+	if len(b.running) > 0 {
+		return
+	}
+
+	now := b.clock.Now()
+	if b.policy.MaxFailedPerBatch != nil {
+		maxFailed, err := intstr.GetScaledValueFromIntOrPercent(b.policy.MaxFailedPerBatch, b.admitted, false)
+		if err == nil && b.failed > maxFailed {
+			b.halted = true
+			b.haltedAt = now
+			klog.InfoS("Migration halted because too many PodMigrationJobs failed in the batch",
+				"batchSize", b.admitted, "failed", b.failed, "maxFailedPerBatch", maxFailed)
+			if b.eventRecorder != nil {
+				b.eventRecorder.Eventf(job, nil, corev1.EventTypeWarning, ReasonMigrationHalted, "Migrating",
+					"Migration halted because %d of %d PodMigrationJobs failed in the batch, exceeding maxFailedPerBatch %d", b.failed, b.admitted, maxFailed)
+			}
+		}
+	}
+	klog.V(4).InfoS("Migration batch finished", "batchSize", b.admitted, "failed", b.failed)
+	b.admitted = 0
+	b.failed = 0
+	b.lastBatchFinished = now
+}
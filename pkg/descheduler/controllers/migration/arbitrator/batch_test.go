@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+)
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	// 2023-01-07 is Saturday
+	saturday := time.Date(2023, 1, 7, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		startTime string
+		duration  time.Duration
+		weekdays  []string
+		now       time.Time
+		want      bool
+	}{
+		{
+			name:      "in daily window",
+			startTime: "02:00",
+			duration:  2 * time.Hour,
+			now:       saturday.Add(3 * time.Hour),
+			want:      true,
+		},
+		{
+			name:      "end of daily window",
+			startTime: "02:00",
+			duration:  2 * time.Hour,
+			now:       saturday.Add(4 * time.Hour),
+			want:      false,
+		},
+		{
+			name:      "in window crossing midnight",
+			startTime: "22:00",
+			duration:  4 * time.Hour,
+			weekdays:  []string{"Friday"},
+			now:       saturday.Add(1 * time.Hour),
+			want:      true,
+		},
+		{
+			name:      "not in weekdays",
+			startTime: "02:00",
+			duration:  2 * time.Hour,
+			weekdays:  []string{"Sunday"},
+			now:       saturday.Add(3 * time.Hour),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := newMaintenanceWindow(tt.startTime, tt.duration, tt.weekdays, "")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, w.contains(tt.now))
+		})
+	}
+}
+
+func TestNewMaintenanceWindowFailed(t *testing.T) {
+	_, err := newMaintenanceWindow("2am", time.Hour, nil, "")
+	assert.Error(t, err)
+	_, err = newMaintenanceWindow("02:00", 25*time.Hour, nil, "")
+	assert.Error(t, err)
+	_, err = newMaintenanceWindow("02:00", time.Hour, []string{"Sat"}, "")
+	assert.Error(t, err)
+}
+
+func TestBatcherInMaintenanceWindow(t *testing.T) {
+	now := time.Date(2023, 1, 7, 3, 0, 0, 0, time.UTC)
+	b, err := newBatcher(&config.MigrationControllerArgs{
+		MaintenanceWindows: []config.MaintenanceWindow{
+			{StartTime: "22:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+		},
+	}, nil)
+	assert.NoError(t, err)
+	b.clock = clock.NewFakeClock(now)
+
+	job := &v1alpha1.PodMigrationJob{}
+	inWindow, err := b.inMaintenanceWindow(job)
+	assert.NoError(t, err)
+	assert.False(t, inWindow)
+
+	job.Spec.ExecutionPolicy = &v1alpha1.PodMigrationJobExecutionPolicy{
+		MaintenanceWindows: []v1alpha1.PodMigrationJobMaintenanceWindow{
+			{StartTime: "02:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+		},
+	}
+	inWindow, err = b.inMaintenanceWindow(job)
+	assert.NoError(t, err)
+	assert.True(t, inWindow)
+
+	job.Spec.ExecutionPolicy = &v1alpha1.PodMigrationJobExecutionPolicy{IgnoreMaintenanceWindows: true}
+	inWindow, err = b.inMaintenanceWindow(job)
+	assert.NoError(t, err)
+	assert.True(t, inWindow)
+
+	job.Spec.ExecutionPolicy = &v1alpha1.PodMigrationJobExecutionPolicy{
+		MaintenanceWindows: []v1alpha1.PodMigrationJobMaintenanceWindow{{StartTime: "invalid"}},
+	}
+	_, err = b.inMaintenanceWindow(job)
+	assert.Error(t, err)
+
+	var nilBatcher *batcher
+	inWindow, err = nilBatcher.inMaintenanceWindow(&v1alpha1.PodMigrationJob{})
+	assert.NoError(t, err)
+	assert.True(t, inWindow)
+}
+
+func TestBatcher(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	b, err := newBatcher(&config.MigrationControllerArgs{
+		BatchPolicy: &config.MigrationBatchPolicy{
+			BatchSize:         2,
+			BatchInterval:     metav1.Duration{Duration: time.Minute},
+			MaxFailedPerBatch: &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
+			HaltDuration:      metav1.Duration{Duration: time.Hour},
+		},
+	}, nil)
+	assert.NoError(t, err)
+	b.clock = fakeClock
+
+	newJob := func() *v1alpha1.PodMigrationJob {
+		return &v1alpha1.PodMigrationJob{ObjectMeta: metav1.ObjectMeta{UID: uuid.NewUUID()}}
+	}
+	completeJob := func(job *v1alpha1.PodMigrationJob, phase v1alpha1.PodMigrationJobPhase) {
+		job = job.DeepCopy()
+		job.Status.Phase = phase
+		b.complete(job)
+	}
+	admitBatch := func() []*v1alpha1.PodMigrationJob {
+		var jobs []*v1alpha1.PodMigrationJob
+		for i := 0; i < 2; i++ {
+			job := newJob()
+			assert.True(t, b.allowed(job))
+			b.admit(job)
+			jobs = append(jobs, job)
+		}
+		assert.False(t, b.allowed(newJob()), "batch is full")
+		return jobs
+	}
+
+	// the first batch succeeds with one failed job
+	jobs := admitBatch()
+	ignored := newJob()
+	ignored.Spec.ExecutionPolicy = &v1alpha1.PodMigrationJobExecutionPolicy{IgnoreBatch: true}
+	assert.True(t, b.allowed(ignored))
+	completeJob(jobs[0], v1alpha1.PodMigrationJobSucceeded)
+	assert.False(t, b.allowed(newJob()), "batch is not finished")
+	completeJob(jobs[1], v1alpha1.PodMigrationJobFailed)
+	assert.False(t, b.allowed(newJob()), "waiting for batch interval")
+	assert.False(t, b.halted)
+
+	// the second batch fails and halts the migration
+	fakeClock.Step(time.Minute)
+	jobs = admitBatch()
+	completeJob(jobs[0], v1alpha1.PodMigrationJobFailed)
+	completeJob(jobs[1], v1alpha1.PodMigrationJobAborted)
+	assert.True(t, b.halted)
+	fakeClock.Step(time.Minute)
+	assert.False(t, b.allowed(newJob()), "migration is halted")
+	assert.True(t, b.allowed(ignored))
+
+	// resume after the halt duration
+	fakeClock.Step(time.Hour)
+	job := newJob()
+	assert.True(t, b.allowed(job))
+	b.admit(job)
+	b.unadmit(job)
+	assert.Equal(t, 0, b.admitted)
+	assert.Equal(t, map[types.UID]*v1alpha1.PodMigrationJob{}, b.running)
+}
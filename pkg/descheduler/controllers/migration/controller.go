@@ -51,7 +51,9 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/names"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/options"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 )
 
 const (
@@ -125,6 +127,11 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	if err = c.Watch(&source.Kind{Type: r.reservationInterpreter.GetReservationType()}, &handler.Funcs{}); err != nil {
 		return nil, err
 	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.NodeDrain) {
+		if err = addNodeDrainController(options.Manager, controllerArgs, handle, a); err != nil {
+			return nil, err
+		}
+	}
 	return r, nil
 }
 
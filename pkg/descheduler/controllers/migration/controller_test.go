@@ -1604,6 +1604,7 @@ func TestPreEvictionFilter(t *testing.T) {
 
 type fakeArbitrator struct {
 	filter            framework.FilterFunc
+	evictableFilter   framework.FilterFunc
 	preEvictionFilter framework.FilterFunc
 	trackEvictedPod   func(*corev1.Pod)
 	add               func(*sev1alpha1.PodMigrationJob)
@@ -1618,6 +1619,13 @@ func (f *fakeArbitrator) Filter(pod *corev1.Pod) bool {
 	return f.filter(pod)
 }
 
+func (f *fakeArbitrator) EvictableFilter(pod *corev1.Pod) bool {
+	if f.evictableFilter == nil {
+		return true
+	}
+	return f.evictableFilter(pod)
+}
+
 func (f *fakeArbitrator) PreEvictionFilter(pod *corev1.Pod) bool {
 	return f.preEvictionFilter(pod)
 }
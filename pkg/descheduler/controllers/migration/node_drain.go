@@ -0,0 +1,346 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmanager "sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/arbitrator"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+const (
+	NodeDrainControllerName = "NodeDrainController"
+
+	// LabelNodeDrain records the NodeDrain that created the PodMigrationJob.
+	LabelNodeDrain = "descheduler.koordinator.sh/node-drain"
+	// AnnotationCordonedByNodeDrain records the NodeDrain that cordoned the node.
+	AnnotationCordonedByNodeDrain = "descheduler.koordinator.sh/cordoned-by-node-drain"
+
+	PodMigrationJobReasonNodeDrainAborted = "NodeDrainAborted"
+)
+
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=nodedrains,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=nodedrains/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
+
+// nodeDrainReconciler cordons the node of the NodeDrain and creates ReservationFirst PodMigrationJobs for
+// the evictable pods on the node. The PodMigrationJobs are arbitrated by the MigrationController as usual,
+// so that the limits such as MaxUnavailablePerWorkload are respected, and the PDBs are respected by the Eviction API.
+type nodeDrainReconciler struct {
+	client.Client
+	args                  *deschedulerconfig.MigrationControllerArgs
+	eventRecorder         events.EventRecorder
+	filter                arbitrator.MigrationFilter
+	getPodsAssignedToNode framework.GetPodsAssignedToNodeFunc
+}
+
+func addNodeDrainController(manager ctrlmanager.Manager, args *deschedulerconfig.MigrationControllerArgs, handle framework.Handle, filter arbitrator.MigrationFilter) error {
+	r := &nodeDrainReconciler{
+		Client:                manager.GetClient(),
+		args:                  args,
+		eventRecorder:         handle.EventRecorder(),
+		filter:                filter,
+		getPodsAssignedToNode: handle.GetPodsAssignedToNodeFunc(),
+	}
+	c, err := controller.New(NodeDrainControllerName, manager, controller.Options{Reconciler: r, MaxConcurrentReconciles: 1})
+	if err != nil {
+		return err
+	}
+	if err = c.Watch(&source.Kind{Type: &sev1alpha1.NodeDrain{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &sev1alpha1.PodMigrationJob{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		name := obj.GetLabels()[LabelNodeDrain]
+		if name == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+	}))
+}
+
+func (r *nodeDrainReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	drain := &sev1alpha1.NodeDrain{}
+	if err := r.Client.Get(ctx, request.NamespacedName, drain); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if drain.Status.Phase == sev1alpha1.NodeDrainAborted {
+		return reconcile.Result{}, nil
+	}
+	if drain.Spec.Abort {
+		return reconcile.Result{}, r.abort(ctx, drain)
+	}
+	if drain.Status.Phase == sev1alpha1.NodeDrainSucceeded || drain.Status.Phase == sev1alpha1.NodeDrainFailed {
+		return reconcile.Result{}, nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: drain.Spec.NodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			status := drain.Status.DeepCopy()
+			status.Phase = sev1alpha1.NodeDrainFailed
+			status.Message = fmt.Sprintf("node %s is not found", drain.Spec.NodeName)
+			return reconcile.Result{}, r.updateStatus(ctx, drain, status)
+		}
+		return reconcile.Result{}, err
+	}
+	if err := r.cordon(ctx, drain, node); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	jobs, err := r.listJobs(ctx, drain)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	podsWithJob := map[types.UID]bool{}
+	for _, job := range jobs {
+		if job.Spec.PodRef != nil {
+			podsWithJob[job.Spec.PodRef.UID] = true
+		}
+	}
+	pods, err := r.getPodsAssignedToNode(node.Name, r.podFilter(drain))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	for _, pod := range pods {
+		if podsWithJob[pod.UID] {
+			continue
+		}
+		job, err := r.createJob(ctx, drain, pod)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	status := summarizeNodeDrainJobs(jobs)
+	switch {
+	case status.MigratingPods > 0:
+		status.Phase = sev1alpha1.NodeDrainRunning
+	case status.FailedPods > 0:
+		status.Phase = sev1alpha1.NodeDrainFailed
+		status.Message = fmt.Sprintf("%d pods failed to migrate", status.FailedPods)
+	default:
+		status.Phase = sev1alpha1.NodeDrainSucceeded
+	}
+	if err = r.updateStatus(ctx, drain, status); err != nil {
+		return reconcile.Result{}, err
+	}
+	if status.Phase == sev1alpha1.NodeDrainRunning {
+		// requeue to pick up the pods assigned to the node before it was cordoned
+		return reconcile.Result{RequeueAfter: defaultRequeueAfter}, nil
+	}
+	r.eventRecorder.Eventf(drain, nil, corev1.EventTypeNormal, string(status.Phase), "Draining",
+		"NodeDrain %s, %d pods succeeded and %d pods failed", status.Phase, status.SucceededPods, status.FailedPods)
+	return reconcile.Result{}, nil
+}
+
+func (r *nodeDrainReconciler) podFilter(drain *sev1alpha1.NodeDrain) framework.FilterFunc {
+	selector := labels.Everything()
+	if drain.Spec.PodSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(drain.Spec.PodSelector)
+		if err != nil {
+			klog.Errorf("Invalid podSelector of NodeDrain %s, err: %v", drain.Name, err)
+			selector = labels.Nothing()
+		}
+	}
+	return func(pod *corev1.Pod) bool {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return false
+		}
+		return selector.Matches(labels.Set(pod.Labels)) && r.filter.EvictableFilter(pod)
+	}
+}
+
+func (r *nodeDrainReconciler) cordon(ctx context.Context, drain *sev1alpha1.NodeDrain, node *corev1.Node) error {
+	if node.Spec.Unschedulable {
+		return nil
+	}
+	newNode := node.DeepCopy()
+	newNode.Spec.Unschedulable = true
+	if newNode.Annotations == nil {
+		newNode.Annotations = map[string]string{}
+	}
+	newNode.Annotations[AnnotationCordonedByNodeDrain] = drain.Name
+	if err := r.Client.Patch(ctx, newNode, client.MergeFrom(node)); err != nil {
+		klog.Errorf("Failed to cordon node %s for NodeDrain %s, err: %v", node.Name, drain.Name, err)
+		return err
+	}
+	r.eventRecorder.Eventf(drain, nil, corev1.EventTypeNormal, "Cordoned", "Draining", "Node %s is cordoned", node.Name)
+	return nil
+}
+
+// uncordon uncordons the node only if it was cordoned by the NodeDrain.
+func (r *nodeDrainReconciler) uncordon(ctx context.Context, drain *sev1alpha1.NodeDrain) error {
+	node := &corev1.Node{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: drain.Spec.NodeName}, node); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if node.Annotations[AnnotationCordonedByNodeDrain] != drain.Name {
+		return nil
+	}
+	newNode := node.DeepCopy()
+	newNode.Spec.Unschedulable = false
+	delete(newNode.Annotations, AnnotationCordonedByNodeDrain)
+	if err := r.Client.Patch(ctx, newNode, client.MergeFrom(node)); err != nil {
+		klog.Errorf("Failed to uncordon node %s for NodeDrain %s, err: %v", node.Name, drain.Name, err)
+		return err
+	}
+	r.eventRecorder.Eventf(drain, nil, corev1.EventTypeNormal, "Uncordoned", "Aborting", "Node %s is uncordoned", node.Name)
+	return nil
+}
+
+// abort aborts the PodMigrationJobs which have not been started and uncordons the node.
+func (r *nodeDrainReconciler) abort(ctx context.Context, drain *sev1alpha1.NodeDrain) error {
+	jobs, err := r.listJobs(ctx, drain)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Status.Phase != "" && job.Status.Phase != sev1alpha1.PodMigrationJobPending {
+			continue
+		}
+		job = job.DeepCopy()
+		job.Status.Phase = sev1alpha1.PodMigrationJobAborted
+		job.Status.Reason = PodMigrationJobReasonNodeDrainAborted
+		job.Status.Message = fmt.Sprintf("NodeDrain %s is aborted", drain.Name)
+		if err = r.Client.Status().Update(ctx, job); err != nil {
+			klog.Errorf("Failed to abort PodMigrationJob %s of NodeDrain %s, err: %v", job.Name, drain.Name, err)
+			return err
+		}
+	}
+	if err = r.uncordon(ctx, drain); err != nil {
+		return err
+	}
+
+	jobs, err = r.listJobs(ctx, drain)
+	if err != nil {
+		return err
+	}
+	status := summarizeNodeDrainJobs(jobs)
+	status.Phase = sev1alpha1.NodeDrainAborted
+	status.Message = "NodeDrain is aborted"
+	return r.updateStatus(ctx, drain, status)
+}
+
+func (r *nodeDrainReconciler) listJobs(ctx context.Context, drain *sev1alpha1.NodeDrain) ([]*sev1alpha1.PodMigrationJob, error) {
+	jobList := &sev1alpha1.PodMigrationJobList{}
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{LabelNodeDrain: drain.Name}),
+	}
+	if err := r.Client.List(ctx, jobList, opts, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	jobs := make([]*sev1alpha1.PodMigrationJob, 0, len(jobList.Items))
+	for i := range jobList.Items {
+		jobs = append(jobs, &jobList.Items[i])
+	}
+	return jobs, nil
+}
+
+// createJob creates the PodMigrationJob with a deterministic name, so that the pod is not migrated repeatedly
+// even if the created PodMigrationJob has not been observed by the cache.
+func (r *nodeDrainReconciler) createJob(ctx context.Context, drain *sev1alpha1.NodeDrain, pod *corev1.Pod) (*sev1alpha1.PodMigrationJob, error) {
+	ttl := drain.Spec.TTL
+	if ttl == nil {
+		ttl = r.args.DefaultJobTTL.DeepCopy()
+	}
+	job := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-%s", drain.Name, pod.UID),
+			Labels: map[string]string{
+				LabelNodeDrain: drain.Name,
+			},
+			Annotations: map[string]string{
+				evictor.AnnotationEvictReason:  fmt.Sprintf("node %s is drained by NodeDrain %s", drain.Spec.NodeName, drain.Name),
+				evictor.AnnotationEvictTrigger: NodeDrainControllerName,
+			},
+		},
+		Spec: sev1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				UID:       pod.UID,
+			},
+			Mode:          sev1alpha1.PodMigrationJobModeReservationFirst,
+			TTL:           ttl,
+			DeleteOptions: r.args.DefaultDeleteOptions,
+		},
+		Status: sev1alpha1.PodMigrationJobStatus{
+			Phase: sev1alpha1.PodMigrationJobPending,
+		},
+	}
+	err := r.Client.Create(ctx, job)
+	if errors.IsAlreadyExists(err) {
+		err = r.Client.Get(ctx, types.NamespacedName{Name: job.Name}, job)
+	}
+	if err != nil {
+		klog.Errorf("Failed to create PodMigrationJob for Pod %s/%s of NodeDrain %s, err: %v", pod.Namespace, pod.Name, drain.Name, err)
+		return nil, err
+	}
+	return job, nil
+}
+
+func summarizeNodeDrainJobs(jobs []*sev1alpha1.PodMigrationJob) *sev1alpha1.NodeDrainStatus {
+	status := &sev1alpha1.NodeDrainStatus{
+		TotalPods: int32(len(jobs)),
+	}
+	for _, job := range jobs {
+		switch job.Status.Phase {
+		case sev1alpha1.PodMigrationJobSucceeded:
+			status.SucceededPods++
+		case sev1alpha1.PodMigrationJobFailed, sev1alpha1.PodMigrationJobAborted:
+			status.FailedPods++
+		default:
+			status.MigratingPods++
+		}
+	}
+	return status
+}
+
+func (r *nodeDrainReconciler) updateStatus(ctx context.Context, drain *sev1alpha1.NodeDrain, status *sev1alpha1.NodeDrainStatus) error {
+	if drain.Status == *status {
+		return nil
+	}
+	drain = drain.DeepCopy()
+	drain.Status = *status
+	if err := r.Client.Status().Update(ctx, drain); err != nil {
+		klog.Errorf("Failed to update status of NodeDrain %s, err: %v", drain.Name, err)
+		return err
+	}
+	return nil
+}
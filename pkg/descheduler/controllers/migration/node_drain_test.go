@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/v1alpha2"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+)
+
+func newTestNodeDrainReconciler(objs ...client.Object) *nodeDrainReconciler {
+	scheme := runtime.NewScheme()
+	_ = sev1alpha1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	var v1beta2args v1alpha2.MigrationControllerArgs
+	v1alpha2.SetDefaults_MigrationControllerArgs(&v1beta2args)
+	var args deschedulerconfig.MigrationControllerArgs
+	if err := v1alpha2.Convert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerArgs(&v1beta2args, &args, nil); err != nil {
+		panic(err)
+	}
+
+	runtimeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	recorder := record.NewBroadcaster().NewRecorder(scheme, corev1.EventSource{Component: NodeDrainControllerName})
+	return &nodeDrainReconciler{
+		Client:        runtimeClient,
+		args:          &args,
+		eventRecorder: record.NewEventRecorderAdapter(recorder),
+		filter: &fakeArbitrator{
+			evictableFilter: func(pod *corev1.Pod) bool {
+				return pod.Labels["evictable"] != "false"
+			},
+		},
+		getPodsAssignedToNode: func(nodeName string, filter framework.FilterFunc) ([]*corev1.Pod, error) {
+			podList := &corev1.PodList{}
+			if err := runtimeClient.List(context.TODO(), podList); err != nil {
+				return nil, err
+			}
+			var pods []*corev1.Pod
+			for i := range podList.Items {
+				pod := &podList.Items[i]
+				if pod.Spec.NodeName == nodeName && filter(pod) {
+					pods = append(pods, pod)
+				}
+			}
+			return pods, nil
+		},
+	}
+}
+
+func newTestDrainPod(name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       uuid.NewUUID(),
+			Labels:    labels,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+func TestNodeDrainReconcile(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+	drain := &sev1alpha1.NodeDrain{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-drain"},
+		Spec:       sev1alpha1.NodeDrainSpec{NodeName: node.Name},
+	}
+	pods := []*corev1.Pod{
+		newTestDrainPod("pod-1", nil),
+		newTestDrainPod("pod-2", map[string]string{"app": "test"}),
+		newTestDrainPod("pod-3", map[string]string{"evictable": "false"}),
+	}
+	r := newTestNodeDrainReconciler(node, drain, pods[0], pods[1], pods[2])
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: drain.Name}}
+
+	result, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultRequeueAfter, result.RequeueAfter)
+
+	gotNode := &corev1.Node{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, gotNode))
+	assert.True(t, gotNode.Spec.Unschedulable)
+	assert.Equal(t, drain.Name, gotNode.Annotations[AnnotationCordonedByNodeDrain])
+
+	jobs, err := r.listJobs(context.TODO(), drain)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	for _, job := range jobs {
+		assert.Equal(t, sev1alpha1.PodMigrationJobModeReservationFirst, job.Spec.Mode)
+		assert.NotEqual(t, pods[2].UID, job.Spec.PodRef.UID)
+	}
+
+	gotDrain := &sev1alpha1.NodeDrain{}
+	assert.NoError(t, r.Client.Get(context.TODO(), request.NamespacedName, gotDrain))
+	assert.Equal(t, sev1alpha1.NodeDrainStatus{Phase: sev1alpha1.NodeDrainRunning, TotalPods: 2, MigratingPods: 2}, gotDrain.Status)
+
+	// reconcile again does not create duplicated jobs
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	jobs, err = r.listJobs(context.TODO(), drain)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 2)
+
+	// complete the jobs
+	for i, phase := range []sev1alpha1.PodMigrationJobPhase{sev1alpha1.PodMigrationJobSucceeded, sev1alpha1.PodMigrationJobFailed} {
+		job := jobs[i].DeepCopy()
+		job.Status.Phase = phase
+		assert.NoError(t, r.Client.Status().Update(context.TODO(), job))
+	}
+	result, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.True(t, result.IsZero())
+	assert.NoError(t, r.Client.Get(context.TODO(), request.NamespacedName, gotDrain))
+	assert.Equal(t, sev1alpha1.NodeDrainFailed, gotDrain.Status.Phase)
+	assert.Equal(t, int32(1), gotDrain.Status.SucceededPods)
+	assert.Equal(t, int32(1), gotDrain.Status.FailedPods)
+}
+
+func TestNodeDrainReconcileWithPodSelector(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+	drain := &sev1alpha1.NodeDrain{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-drain"},
+		Spec: sev1alpha1.NodeDrainSpec{
+			NodeName:    node.Name,
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+		},
+	}
+	pod := newTestDrainPod("pod-2", map[string]string{"app": "test"})
+	r := newTestNodeDrainReconciler(node, drain, newTestDrainPod("pod-1", nil), pod)
+
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: drain.Name}})
+	assert.NoError(t, err)
+	jobs, err := r.listJobs(context.TODO(), drain)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, pod.UID, jobs[0].Spec.PodRef.UID)
+}
+
+func TestNodeDrainReconcileMissingNode(t *testing.T) {
+	drain := &sev1alpha1.NodeDrain{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-drain"},
+		Spec:       sev1alpha1.NodeDrainSpec{NodeName: "missing-node"},
+	}
+	r := newTestNodeDrainReconciler(drain)
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: drain.Name}})
+	assert.NoError(t, err)
+	gotDrain := &sev1alpha1.NodeDrain{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: drain.Name}, gotDrain))
+	assert.Equal(t, sev1alpha1.NodeDrainFailed, gotDrain.Status.Phase)
+}
+
+func TestNodeDrainAbort(t *testing.T) {
+	tests := []struct {
+		name           string
+		unschedulable  bool
+		wantUncordoned bool
+	}{
+		{
+			name:           "abort and uncordon the node",
+			wantUncordoned: true,
+		},
+		{
+			name:           "abort and keep the node cordoned by others",
+			unschedulable:  true,
+			wantUncordoned: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec:       corev1.NodeSpec{Unschedulable: tt.unschedulable},
+			}
+			drain := &sev1alpha1.NodeDrain{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-drain"},
+				Spec:       sev1alpha1.NodeDrainSpec{NodeName: node.Name},
+			}
+			r := newTestNodeDrainReconciler(node, drain, newTestDrainPod("pod-1", nil), newTestDrainPod("pod-2", nil))
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: drain.Name}}
+			_, err := r.Reconcile(context.TODO(), request)
+			assert.NoError(t, err)
+
+			jobs, err := r.listJobs(context.TODO(), drain)
+			assert.NoError(t, err)
+			assert.Len(t, jobs, 2)
+			runningJob := jobs[0].DeepCopy()
+			runningJob.Status.Phase = sev1alpha1.PodMigrationJobRunning
+			assert.NoError(t, r.Client.Status().Update(context.TODO(), runningJob))
+
+			gotDrain := &sev1alpha1.NodeDrain{}
+			assert.NoError(t, r.Client.Get(context.TODO(), request.NamespacedName, gotDrain))
+			gotDrain.Spec.Abort = true
+			assert.NoError(t, r.Client.Update(context.TODO(), gotDrain))
+			_, err = r.Reconcile(context.TODO(), request)
+			assert.NoError(t, err)
+
+			jobs, err = r.listJobs(context.TODO(), drain)
+			assert.NoError(t, err)
+			for _, job := range jobs {
+				if job.Name == runningJob.Name {
+					assert.Equal(t, sev1alpha1.PodMigrationJobRunning, job.Status.Phase)
+				} else {
+					assert.Equal(t, sev1alpha1.PodMigrationJobAborted, job.Status.Phase)
+					assert.Equal(t, PodMigrationJobReasonNodeDrainAborted, job.Status.Reason)
+				}
+			}
+
+			gotNode := &corev1.Node{}
+			assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, gotNode))
+			assert.Equal(t, !tt.wantUncordoned, gotNode.Spec.Unschedulable)
+			assert.Empty(t, gotNode.Annotations[AnnotationCordonedByNodeDrain])
+
+			assert.NoError(t, r.Client.Get(context.TODO(), request.NamespacedName, gotDrain))
+			assert.Equal(t, sev1alpha1.NodeDrainAborted, gotDrain.Status.Phase)
+			assert.Equal(t, int32(1), gotDrain.Status.MigratingPods)
+			assert.Equal(t, int32(1), gotDrain.Status.FailedPods)
+		})
+	}
+}
@@ -19,87 +19,29 @@ package sorter
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
-	schedulingcorev1helper "k8s.io/component-helpers/scheduling/corev1"
-	apiscorehelper "k8s.io/kubernetes/pkg/apis/core/helper"
 
-	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
-	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
 )
 
-var koordPriorityClassOrder = map[extension.PriorityClass]int{
-	extension.PriorityNone:  5,
-	extension.PriorityProd:  4,
-	extension.PriorityMid:   3,
-	extension.PriorityBatch: 2,
-	extension.PriorityFree:  1,
-}
-
-var koordQoSClassOrder = map[extension.QoSClass]int{
-	extension.QoSNone:   5,
-	extension.QoSSystem: 4,
-	extension.QoSLSE:    4,
-	extension.QoSLSR:    3,
-	extension.QoSLS:     2,
-	extension.QoSBE:     1,
-}
-
-var k8sQoSClassOrder = map[corev1.PodQOSClass]int{
-	corev1.PodQOSGuaranteed: 3,
-	corev1.PodQOSBurstable:  2,
-	corev1.PodQOSBestEffort: 1,
-}
-
 // Priority compares pods by Priority
 func Priority(p1, p2 *corev1.Pod) int {
-	priority1 := schedulingcorev1helper.PodPriority(p1)
-	priority2 := schedulingcorev1helper.PodPriority(p2)
-	if priority1 == priority2 {
-		return 0
-	}
-	if priority1 > priority2 {
-		return 1
-	}
-	return -1
+	return victim.Priority(p1, p2)
 }
 
 // KubernetesQoSClass compares pods by Kubernetes QosClass
 func KubernetesQoSClass(p1, p2 *corev1.Pod) int {
-	qos1 := k8sQoSClassOrder[util.GetKubeQosClass(p1)]
-	qos2 := k8sQoSClassOrder[util.GetKubeQosClass(p2)]
-	if qos1 == qos2 {
-		return 0
-	}
-	if qos1 > qos2 {
-		return 1
-	}
-	return -1
+	return victim.KubernetesQoSClass(p1, p2)
 }
 
 // KoordinatorQoSClass compares pods by the Koordinator QoSClass
 func KoordinatorQoSClass(p1, p2 *corev1.Pod) int {
-	qos1 := koordQoSClassOrder[extension.GetPodQoSClassWithDefault(p1)]
-	qos2 := koordQoSClassOrder[extension.GetPodQoSClassWithDefault(p2)]
-	if qos1 == qos2 {
-		return 0
-	}
-	if qos1 > qos2 {
-		return 1
-	}
-	return -1
+	return victim.KoordinatorQoSClass(p1, p2)
 }
 
 // KoordinatorPriorityClass compares pods by the Koordinator PriorityClass
 func KoordinatorPriorityClass(p1, p2 *corev1.Pod) int {
-	priorityClass1 := koordPriorityClassOrder[extension.GetPodPriorityClassWithDefault(p1)]
-	priorityClass2 := koordPriorityClassOrder[extension.GetPodPriorityClassWithDefault(p2)]
-	if priorityClass1 == priorityClass2 {
-		return 0
-	}
-	if priorityClass1 > priorityClass2 {
-		return 1
-	}
-	return -1
+	return victim.KoordinatorPriorityClass(p1, p2)
 }
 
 // PodUsage compares pods by the actual usage
@@ -125,50 +67,33 @@ func PodUsage(podMetrics map[types.NamespacedName]*slov1alpha1.ResourceMap, node
 
 // PodCreationTimestamp compares the pods by the creation timestamp
 func PodCreationTimestamp(p1, p2 *corev1.Pod) int {
-	if p1.CreationTimestamp.Equal(&p2.CreationTimestamp) {
-		return 0
-	}
-	if p1.CreationTimestamp.Before(&p2.CreationTimestamp) {
-		return 1
-	}
-	return -1
+	return victim.Age(p1, p2)
 }
 
 func PodDeletionCost(p1, p2 *corev1.Pod) int {
-	p1DeletionCost, _ := apiscorehelper.GetDeletionCostFromPodAnnotations(p1.Annotations)
-	p2DeletionCost, _ := apiscorehelper.GetDeletionCostFromPodAnnotations(p2.Annotations)
-	if p1DeletionCost == p2DeletionCost {
-		return 0
-	}
-	if p1DeletionCost > p2DeletionCost {
-		return 1
-	}
-	return -1
+	return victim.DeletionCost(p1, p2)
 }
 
 func EvictionCost(p1, p2 *corev1.Pod) int {
-	p1EvictionCost, _ := extension.GetEvictionCost(p1.Annotations)
-	p2EvictionCost, _ := extension.GetEvictionCost(p2.Annotations)
-	if p1EvictionCost == p2EvictionCost {
-		return 0
-	}
-	if p1EvictionCost > p2EvictionCost {
-		return 1
-	}
-	return -1
+	return victim.EvictionCost(p1, p2)
 }
 
+// PodSorter sorts the pods by the victim ranking factors, and the cmp functions are used as the Usage factor.
 func PodSorter(cmp ...CompareFn) *MultiSorter {
-	comparators := []CompareFn{
-		KoordinatorPriorityClass,
-		Priority,
-		KubernetesQoSClass,
-		KoordinatorQoSClass,
-		PodDeletionCost,
-		EvictionCost,
+	return RankerSorter(cmp)
+}
+
+// RankerSorter sorts the pods by the victim ranking factors with options, and the cmp functions are used as the Usage factor.
+func RankerSorter(cmp []CompareFn, opts ...victim.Option) *MultiSorter {
+	usage := make([]victim.CompareFn, 0, len(cmp))
+	for _, fn := range cmp {
+		usage = append(usage, victim.CompareFn(fn))
+	}
+	opts = append([]victim.Option{victim.WithUsage(usage...)}, opts...)
+	var comparators []CompareFn
+	for _, fn := range victim.NewRanker(opts...).Comparators() {
+		comparators = append(comparators, CompareFn(fn))
 	}
-	comparators = append(comparators, cmp...)
-	comparators = append(comparators, PodCreationTimestamp)
 	return OrderedBy(comparators...)
 }
 
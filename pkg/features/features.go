@@ -80,10 +80,16 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 
 const (
 	DisablePVCReservation featuregate.Feature = "DisablePVCReservation"
+
+	// alpha: v1.4
+	//
+	// NodeDrain enables the NodeDrain controller in the MigrationController to drain nodes by PodMigrationJobs.
+	NodeDrain featuregate.Feature = "NodeDrain"
 )
 
 var defaultDeschedulerFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	DisablePVCReservation: {Default: false, PreRelease: featuregate.Beta},
+	NodeDrain:             {Default: false, PreRelease: featuregate.Alpha},
 }
 
 func init() {
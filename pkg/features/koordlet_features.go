@@ -133,6 +133,28 @@ const (
 	//
 	// ColdPageCollector enables coldPageCollector feature of koordlet.
 	ColdPageCollector featuregate.Feature = "ColdPageCollector"
+
+	// alpha: v1.4
+	//
+	// MidEvict evicts koord-mid pods based on the node cpu and memory usage of the non-BE pods.
+	MidEvict featuregate.Feature = "MidEvict"
+
+	// alpha: v1.4
+	//
+	// ResourceExecutorDryRun allows the NodeSLO to make the resource executor of koordlet record the resource updates
+	// instead of applying them, and serves the records via the http endpoint.
+	ResourceExecutorDryRun featuregate.Feature = "ResourceExecutorDryRun"
+
+	// alpha: v1.4
+	//
+	// ResourceJournal records the resource updates of koordlet in an on-disk journal, and supports rolling back
+	// the cgroups to the values before koordlet updates them.
+	ResourceJournal featuregate.Feature = "ResourceJournal"
+
+	// alpha: v1.4
+	//
+	// ResctrlCollector enables the collector of resctrl monitoring data (CMT/MBM) in koordlet.
+	ResctrlCollector featuregate.Feature = "ResctrlCollector"
 )
 
 func init() {
@@ -161,6 +183,10 @@ var (
 		PSICollector:           {Default: false, PreRelease: featuregate.Alpha},
 		BlkIOReconcile:         {Default: false, PreRelease: featuregate.Alpha},
 		ColdPageCollector:      {Default: false, PreRelease: featuregate.Alpha},
+		MidEvict:               {Default: false, PreRelease: featuregate.Alpha},
+		ResourceExecutorDryRun: {Default: false, PreRelease: featuregate.Alpha},
+		ResourceJournal:        {Default: false, PreRelease: featuregate.Alpha},
+		ResctrlCollector:       {Default: false, PreRelease: featuregate.Alpha},
 	}
 )
 
@@ -172,7 +198,7 @@ func IsFeatureDisabled(nodeSLO *slov1alpha1.NodeSLO, feature featuregate.Feature
 
 	spec := nodeSLO.Spec
 	switch feature {
-	case BECPUSuppress, BEMemoryEvict, BECPUEvict:
+	case BECPUSuppress, BEMemoryEvict, BECPUEvict, MidEvict:
 		if spec.ResourceUsedThresholdWithBE == nil || spec.ResourceUsedThresholdWithBE.Enable == nil {
 			return true, fmt.Errorf("cannot parse feature config for invalid nodeSLO %v", nodeSLO)
 		}
@@ -57,6 +57,17 @@ const (
 	//
 	// ResizePod is used to enable resize pod feature
 	ResizePod featuregate.Feature = "ResizePod"
+
+	// alpha: v1.4
+	//
+	// ReservationSet is used to enable the ReservationSet controller which maintains a number of Reservations.
+	// The ReservationSet CRD must be installed before enabling the FeatureGate.
+	ReservationSet featuregate.Feature = "ReservationSet"
+
+	// alpha: v1.4
+	//
+	// ReservationResize is used to enable shrinking or growing the Available Reservations in place.
+	ReservationResize featuregate.Feature = "ReservationResize"
 )
 
 var defaultSchedulerFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
@@ -65,6 +76,8 @@ var defaultSchedulerFeatureGates = map[featuregate.Feature]featuregate.FeatureSp
 	CompatiblePodDisruptionBudget:      {Default: false, PreRelease: featuregate.Alpha},
 	DisablePodDisruptionBudgetInformer: {Default: false, PreRelease: featuregate.Alpha},
 	ResizePod:                          {Default: false, PreRelease: featuregate.Alpha},
+	ReservationSet:                     {Default: false, PreRelease: featuregate.Alpha},
+	ReservationResize:                  {Default: false, PreRelease: featuregate.Alpha},
 	MultiQuotaTree:                     {Default: false, PreRelease: featuregate.Alpha},
 	ElasticQuotaIgnorePodOverhead:      {Default: false, PreRelease: featuregate.Alpha},
 	ElasticQuotaGuaranteeUsage:         {Default: false, PreRelease: featuregate.Alpha},
@@ -34,6 +34,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks"
 	statesinformerimpl "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/impl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
 )
 
 const (
@@ -84,6 +85,7 @@ func (c *Configuration) InitFlags(fs *flag.FlagSet) {
 	c.AuditConf.InitFlags(fs)
 	c.PredictionConf.InitFlags(fs)
 	resourceexecutor.Conf.InitFlags(fs)
+	victim.AddGoFlags(fs)
 	fs.Var(cliflag.NewMapStringBool(&c.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for alpha/experimental features. "+
 		"Options are:\n"+strings.Join(features.DefaultKoordletFeatureGate.KnownFeatures(), "\n"))
 }
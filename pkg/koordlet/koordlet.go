@@ -29,14 +29,17 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	clientsetbeta1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	"github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/config"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	statesinformerimpl "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/impl"
@@ -90,6 +93,16 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 	predictorFactory := prediction.NewPredictorFactory(predictServer, config.PredictionConf.ColdStartDuration, config.PredictionConf.SafetyMarginPercent)
 
 	statesInformer := statesinformerimpl.NewStatesInformer(config.StatesInformerConf, kubeClient, crdClient, topologyClient, metricCache, nodeName, schedulingClient, predictorFactory)
+	// the NodeSLO decides whether the resource updates on the node are recorded instead of applied
+	if features.DefaultKoordletFeatureGate.Enabled(features.ResourceExecutorDryRun) {
+		executor := resourceexecutor.NewResourceUpdateExecutor()
+		statesInformer.RegisterCallbacks(statesinformer.RegisterTypeNodeSLOSpec, "resource-executor-dry-run",
+			"Set the dry-run mode of resource executor if NodeSLO updated",
+			func(t statesinformer.RegisterType, obj interface{}, target *statesinformer.CallbackTarget) {
+				nodeSLOSpec, _ := obj.(*slov1alpha1.NodeSLOSpec)
+				executor.SetDryRun(resourceexecutor.IsDryRunByNodeSLO(nodeSLOSpec))
+			})
+	}
 
 	cgroupDriver := system.GetCgroupDriver()
 	system.SetupCgroupPathFormatter(cgroupDriver)
@@ -179,4 +192,5 @@ func (d *daemon) Run(stopCh <-chan struct{}) {
 	klog.Info("Start daemon successfully")
 	<-stopCh
 	klog.Info("Shutting down daemon")
+	resourceexecutor.RollbackOnExit()
 }
@@ -59,6 +59,14 @@ var (
 	// CPI
 	ContainerCPI = defaultMetricFactory.New(ContainerMetricCPI).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertyCPIResource)
 
+	// Resctrl, the llc occupancy is in bytes and the memory bandwidth is in bytes per second
+	ResctrlGroupLLCOccupancyMetric = defaultMetricFactory.New(ResctrlGroupMetricLLCOccupancy).withPropertySchema(MetricPropertyResctrlGroup)
+	ResctrlGroupMBMTotalMetric     = defaultMetricFactory.New(ResctrlGroupMetricMBMTotal).withPropertySchema(MetricPropertyResctrlGroup)
+	ResctrlGroupMBMLocalMetric     = defaultMetricFactory.New(ResctrlGroupMetricMBMLocal).withPropertySchema(MetricPropertyResctrlGroup)
+	PodResctrlLLCOccupancyMetric   = defaultMetricFactory.New(PodMetricResctrlLLCOccupancy).withPropertySchema(MetricPropertyPodUID)
+	PodResctrlMBMTotalMetric       = defaultMetricFactory.New(PodMetricResctrlMBMTotal).withPropertySchema(MetricPropertyPodUID)
+	PodResctrlMBMLocalMetric       = defaultMetricFactory.New(PodMetricResctrlMBMLocal).withPropertySchema(MetricPropertyPodUID)
+
 	// PSI
 	ContainerPSIMetric                 = defaultMetricFactory.New(ContainerMetricPSI).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertyPSIResource, MetricPropertyPSIPrecision, MetricPropertyPSIDegree)
 	ContainerPSICPUFullSupportedMetric = defaultMetricFactory.New(ContainerMetricPSICPUFullSupported).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID)
@@ -73,6 +73,14 @@ const (
 	// CPI
 	ContainerMetricCPI MetricKind = "container_cpi"
 
+	// Resctrl
+	ResctrlGroupMetricLLCOccupancy MetricKind = "resctrl_group_llc_occupancy"
+	ResctrlGroupMetricMBMTotal     MetricKind = "resctrl_group_mbm_total_bandwidth"
+	ResctrlGroupMetricMBMLocal     MetricKind = "resctrl_group_mbm_local_bandwidth"
+	PodMetricResctrlLLCOccupancy   MetricKind = "pod_resctrl_llc_occupancy"
+	PodMetricResctrlMBMTotal       MetricKind = "pod_resctrl_mbm_total_bandwidth"
+	PodMetricResctrlMBMLocal       MetricKind = "pod_resctrl_mbm_local_bandwidth"
+
 	// PSI
 	ContainerMetricPSI                 MetricKind = "container_psi"
 	ContainerMetricPSICPUFullSupported MetricKind = "container_psi_cpu_full_supported"
@@ -110,6 +118,8 @@ const (
 	MetricPropertyBEAllocation MetricProperty = "be_allocation"
 
 	MetricPropertyHostAppName MetricProperty = "host_app_name"
+
+	MetricPropertyResctrlGroup MetricProperty = "resctrl_group"
 )
 
 // MetricPropertyValue is the property value
@@ -147,6 +157,7 @@ var MetricPropertiesFunc = struct {
 	ContainerGPU        func(string, string, string) map[MetricProperty]string
 	NodeBE              func(string, string) map[MetricProperty]string
 	HostApplication     func(string) map[MetricProperty]string
+	ResctrlGroup        func(string) map[MetricProperty]string
 }{
 	Pod: func(podUID string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID}
@@ -181,6 +192,9 @@ var MetricPropertiesFunc = struct {
 	HostApplication: func(appName string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyHostAppName: appName}
 	},
+	ResctrlGroup: func(group string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyResctrlGroup: group}
+	},
 }
 
 // point is the struct to describe metric
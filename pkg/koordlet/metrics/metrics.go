@@ -29,6 +29,8 @@ func init() {
 	prometheus.MustRegister(ResourceSummaryCollectors...)
 	prometheus.MustRegister(CPICollectors...)
 	prometheus.MustRegister(PSICollectors...)
+	prometheus.MustRegister(ResctrlCollectors...)
+	prometheus.MustRegister(NUMACollectors...)
 	prometheus.MustRegister(CPUSuppressCollector...)
 	prometheus.MustRegister(CPUBurstCollector...)
 	prometheus.MustRegister(PredictionCollectors...)
@@ -147,6 +147,15 @@ func TestCommonCollectors(t *testing.T) {
 		RecordContainerPSI(testingContainer, testingPod, testingPSI)
 		ResetPodPSI()
 		RecordPodPSI(testingPod, testingPSI)
+		RecordResctrlLLCOccupancy("BE", 1048576)
+		RecordResctrlMemoryBandwidth("BE", MBMTypeTotal, 1000)
+		ResetPodResctrl()
+		RecordPodResctrlLLCOccupancy(testingPod, 1048576)
+		RecordPodResctrlMemoryBandwidth(testingPod, MBMTypeLocal, 1000)
+		RecordContainerNUMAMemory(testingContainer, testingPod, 0, true, 1048576)
+		RecordContainerNUMAMemory(testingContainer, testingPod, 1, false, 4096)
+		RecordContainerCrossNUMAMemoryRatio(testingContainer, testingPod, 0.1)
+		DeleteContainerNUMAMemory(testingContainer.ContainerID)
 	})
 }
 
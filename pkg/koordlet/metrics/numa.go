@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	NUMANode  = "numa_node"
+	NUMALocal = "numa_local"
+)
+
+var (
+	ContainerNUMAMemory = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "container_numa_memory",
+		Help:      "Number of bytes of the container memory on each NUMA node collected by koordlet, numa_local indicates whether the NUMA node is allocated to the pod",
+	}, []string{NodeKey, ContainerID, ContainerName, PodUID, PodName, PodNamespace, NUMANode, NUMALocal})
+
+	ContainerCrossNUMAMemoryRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "container_cross_numa_memory_ratio",
+		Help:      "Ratio of the container memory on the NUMA nodes not allocated to the pod collected by koordlet",
+	}, []string{NodeKey, ContainerID, ContainerName, PodUID, PodName, PodNamespace})
+
+	NUMACollectors = []prometheus.Collector{
+		ContainerNUMAMemory,
+		ContainerCrossNUMAMemoryRatio,
+	}
+)
+
+func RecordContainerNUMAMemory(status *corev1.ContainerStatus, pod *corev1.Pod, numaNode int, isLocal bool, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ContainerID] = status.ContainerID
+	labels[ContainerName] = status.Name
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	labels[NUMANode] = strconv.Itoa(numaNode)
+	labels[NUMALocal] = strconv.FormatBool(isLocal)
+	ContainerNUMAMemory.With(labels).Set(value)
+}
+
+func RecordContainerCrossNUMAMemoryRatio(status *corev1.ContainerStatus, pod *corev1.Pod, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ContainerID] = status.ContainerID
+	labels[ContainerName] = status.Name
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	ContainerCrossNUMAMemoryRatio.With(labels).Set(value)
+}
+
+// DeleteContainerNUMAMemory deletes the NUMA memory metrics of the container.
+func DeleteContainerNUMAMemory(containerID string) {
+	ContainerNUMAMemory.DeletePartialMatch(prometheus.Labels{ContainerID: containerID})
+	ContainerCrossNUMAMemoryRatio.DeletePartialMatch(prometheus.Labels{ContainerID: containerID})
+}
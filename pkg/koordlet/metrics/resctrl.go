@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	ResctrlGroup = "resctrl_group"
+	MBMType      = "mbm_type"
+
+	MBMTypeTotal = "total"
+	MBMTypeLocal = "local"
+)
+
+var (
+	ResctrlLLCOccupancy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_llc_occupancy",
+		Help:      "Number of bytes of the last level cache occupied by the resctrl group collected by koordlet",
+	}, []string{NodeKey, ResctrlGroup})
+
+	ResctrlMemoryBandwidth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_memory_bandwidth",
+		Help:      "Memory bandwidth in bytes per second of the resctrl group collected by koordlet",
+	}, []string{NodeKey, ResctrlGroup, MBMType})
+
+	PodResctrlLLCOccupancy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_resctrl_llc_occupancy",
+		Help:      "Number of bytes of the last level cache occupied by the pod collected by koordlet",
+	}, []string{NodeKey, PodUID, PodName, PodNamespace})
+
+	PodResctrlMemoryBandwidth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_resctrl_memory_bandwidth",
+		Help:      "Memory bandwidth in bytes per second of the pod collected by koordlet",
+	}, []string{NodeKey, PodUID, PodName, PodNamespace, MBMType})
+
+	ResctrlCollectors = []prometheus.Collector{
+		ResctrlLLCOccupancy,
+		ResctrlMemoryBandwidth,
+		PodResctrlLLCOccupancy,
+		PodResctrlMemoryBandwidth,
+	}
+)
+
+func RecordResctrlLLCOccupancy(group string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResctrlGroup] = group
+	ResctrlLLCOccupancy.With(labels).Set(value)
+}
+
+func RecordResctrlMemoryBandwidth(group string, mbmType string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResctrlGroup] = group
+	labels[MBMType] = mbmType
+	ResctrlMemoryBandwidth.With(labels).Set(value)
+}
+
+func RecordPodResctrlLLCOccupancy(pod *corev1.Pod, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	PodResctrlLLCOccupancy.With(labels).Set(value)
+}
+
+func RecordPodResctrlMemoryBandwidth(pod *corev1.Pod, mbmType string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	labels[MBMType] = mbmType
+	PodResctrlMemoryBandwidth.With(labels).Set(value)
+}
+
+func ResetPodResctrl() {
+	PodResctrlLLCOccupancy.Reset()
+	PodResctrlMemoryBandwidth.Reset()
+}
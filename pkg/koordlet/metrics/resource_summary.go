@@ -113,3 +113,9 @@ func RecordContainerResourceLimits(resourceName string, unit string, status *cor
 func ResetContainerResourceLimits() {
 	ContainerResourceLimits.Reset()
 }
+
+// DeletePodResourceMetrics deletes the container requests and limits of the pod.
+func DeletePodResourceMetrics(podUID string) {
+	ContainerResourceRequests.DeletePartialMatch(prometheus.Labels{PodUID: podUID})
+	ContainerResourceLimits.DeletePartialMatch(prometheus.Labels{PodUID: podUID})
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podnumastat
+
+import (
+	"time"
+
+	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	CollectorName = "PodNUMAStatCollector"
+)
+
+// podNUMAStatCollector collects the memory distribution on the NUMA nodes of the containers whose pods are allocated
+// with the NUMA nodes by the scheduler, and reports the memory on the NUMA nodes not allocated as the cross-NUMA memory.
+type podNUMAStatCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	statesInformer  statesinformer.StatesInformer
+	cgroupReader    resourceexecutor.CgroupReader
+	podFilter       framework.PodFilter
+	// recordedContainers is the IDs of the containers whose metrics are recorded in the last collection
+	recordedContainers sets.String
+}
+
+func New(opt *framework.Options) framework.Collector {
+	podFilter := framework.DefaultPodFilter
+	if filter, ok := opt.PodFilters[CollectorName]; ok {
+		podFilter = filter
+	}
+	return &podNUMAStatCollector{
+		collectInterval:    opt.Config.NUMAStatCollectorInterval,
+		started:            atomic.NewBool(false),
+		statesInformer:     opt.StatesInformer,
+		cgroupReader:       opt.CgroupReader,
+		podFilter:          podFilter,
+		recordedContainers: sets.NewString(),
+	}
+}
+
+var _ framework.PodCollector = &podNUMAStatCollector{}
+
+func (c *podNUMAStatCollector) Enabled() bool {
+	return c.collectInterval > 0
+}
+
+func (c *podNUMAStatCollector) Setup(ctx *framework.Context) {}
+
+func (c *podNUMAStatCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go wait.Until(c.collectPodNUMAStat, c.collectInterval, stopCh)
+}
+
+func (c *podNUMAStatCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *podNUMAStatCollector) FilterPod(meta *statesinformer.PodMeta) (bool, string) {
+	return c.podFilter.FilterPod(meta)
+}
+
+func (c *podNUMAStatCollector) collectPodNUMAStat() {
+	klog.V(6).Info("start collectPodNUMAStat")
+	// the collector only reports the prometheus metrics, it should not block the metrics advisor
+	defer c.started.Store(true)
+
+	podMetas := c.statesInformer.GetAllPods()
+	recordedContainers := sets.NewString()
+	for _, meta := range podMetas {
+		pod := meta.Pod
+		if filtered, msg := c.FilterPod(meta); filtered {
+			klog.V(5).Infof("skip collect pod %s/%s, reason: %s", pod.Namespace, pod.Name, msg)
+			continue
+		}
+		numaNodes, err := getAllocatedNUMANodes(pod.Annotations)
+		if err != nil {
+			klog.V(4).Infof("failed to get allocated NUMA nodes of pod %s, err: %s", util.GetPodKey(pod), err)
+			continue
+		}
+		if numaNodes.Len() <= 0 { // not NUMA-aware allocated
+			continue
+		}
+		c.collectContainerNUMAStat(meta, numaNodes, recordedContainers)
+	}
+	// only delete the metrics of the containers which are gone or no longer collected, so the series of the running
+	// containers are kept between the collections
+	for containerID := range c.recordedContainers {
+		if !recordedContainers.Has(containerID) {
+			metrics.DeleteContainerNUMAMemory(containerID)
+		}
+	}
+	c.recordedContainers = recordedContainers
+	klog.V(5).Infof("collectPodNUMAStat finished, pod num %d, container num %d", len(podMetas), recordedContainers.Len())
+}
+
+func (c *podNUMAStatCollector) collectContainerNUMAStat(podMeta *statesinformer.PodMeta, numaNodes sets.Int, recordedContainers sets.String) {
+	pod := podMeta.Pod
+	for i := range pod.Status.ContainerStatuses {
+		containerStat := &pod.Status.ContainerStatuses[i]
+		if len(containerStat.ContainerID) == 0 || containerStat.State.Running == nil {
+			klog.V(6).Infof("container %s/%s/%s is not running, skip this round",
+				pod.Namespace, pod.Name, containerStat.Name)
+			continue
+		}
+		containerCgroupDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+		if err != nil {
+			klog.V(4).Infof("collect container %s/%s/%s numa stat failed, cannot get container cgroup, err: %s",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		numaStats, err := c.cgroupReader.ReadMemoryNumaStat(containerCgroupDir)
+		if err != nil {
+			klog.V(4).Infof("collect container %s/%s/%s numa stat failed, err: %s",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+
+		var totalBytes, crossBytes float64
+		for _, numaStat := range numaStats {
+			bytes := float64(numaStat.PagesNum) * float64(system.PageSize)
+			isLocal := numaNodes.Has(numaStat.NumaId)
+			metrics.RecordContainerNUMAMemory(containerStat, pod, numaStat.NumaId, isLocal, bytes)
+			totalBytes += bytes
+			if !isLocal {
+				crossBytes += bytes
+			}
+		}
+		crossRatio := float64(0)
+		if totalBytes > 0 {
+			crossRatio = crossBytes / totalBytes
+		}
+		metrics.RecordContainerCrossNUMAMemoryRatio(containerStat, pod, crossRatio)
+		recordedContainers.Insert(containerStat.ContainerID)
+		klog.V(6).Infof("collect container %s/%s/%s numa stat finished, allocated NUMA nodes %v, cross-NUMA memory %v/%v",
+			pod.Namespace, pod.Name, containerStat.Name, numaNodes.List(), crossBytes, totalBytes)
+	}
+}
+
+// getAllocatedNUMANodes returns the NUMA nodes allocated by the scheduler according to the pod resource status.
+func getAllocatedNUMANodes(podAnnotations map[string]string) (sets.Int, error) {
+	numaNodes := sets.NewInt()
+	resourceStatus, err := apiext.GetResourceStatus(podAnnotations)
+	if err != nil {
+		return numaNodes, err
+	}
+	for _, numaNodeRes := range resourceStatus.NUMANodeResources {
+		numaNodes.Insert(int(numaNodeRes.Node))
+	}
+	return numaNodes, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podnumastat
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+func Test_getAllocatedNUMANodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     map[string]string
+		want    sets.Int
+		wantErr bool
+	}{
+		{
+			name:    "no resource status",
+			arg:     nil,
+			want:    sets.NewInt(),
+			wantErr: false,
+		},
+		{
+			name: "bad resource status",
+			arg: map[string]string{
+				apiext.AnnotationResourceStatus: "bad-format",
+			},
+			want:    sets.NewInt(),
+			wantErr: true,
+		},
+		{
+			name: "cpuset allocated without NUMA nodes",
+			arg: map[string]string{
+				apiext.AnnotationResourceStatus: util.DumpJSON(&apiext.ResourceStatus{
+					CPUSet: "0-3",
+				}),
+			},
+			want:    sets.NewInt(),
+			wantErr: false,
+		},
+		{
+			name: "NUMA nodes allocated",
+			arg: map[string]string{
+				apiext.AnnotationResourceStatus: util.DumpJSON(&apiext.ResourceStatus{
+					CPUSet: "0-3,32-35",
+					NUMANodeResources: []apiext.NUMANodeResource{
+						{
+							Node: 0,
+						},
+						{
+							Node: 1,
+						},
+					},
+				}),
+			},
+			want:    sets.NewInt(0, 1),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := getAllocatedNUMANodes(tt.arg)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_podNUMAStatCollector_collectPodNUMAStat(t *testing.T) {
+	testNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}
+	metrics.Register(testNode)
+	defer metrics.Register(nil)
+
+	testPodMetaDir := "kubepods.slice/kubepods-podtest_pod_uid.slice"
+	testContainerParentDir := "/kubepods.slice/kubepods-podtest_pod_uid.slice/cri-containerd-testContainerUID.scope"
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "test",
+			UID:       "test_pod_uid",
+			Annotations: map[string]string{
+				apiext.AnnotationResourceStatus: util.DumpJSON(&apiext.ResourceStatus{
+					CPUSet: "0-3",
+					NUMANodeResources: []apiext.NUMANodeResource{
+						{
+							Node: 0,
+						},
+					},
+				}),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: "containerd://testContainerUID",
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+	testLSPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ls-pod",
+			Namespace: "test",
+			UID:       "test_ls_pod_uid",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: "containerd://testLSContainerUID",
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(false)
+	helper.WriteCgroupFileContents(testContainerParentDir, system.MemoryNumaStat,
+		"total=1000 N0=750 N1=250\nfile=500 N0=400 N1=100\nanon=500 N0=350 N1=150\n")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+	testLSPodMeta := &statesinformer.PodMeta{
+		Pod:       testLSPod,
+		CgroupDir: "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podtest_ls_pod_uid.slice",
+	}
+	gomock.InOrder(
+		statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+			{
+				Pod:       testPod,
+				CgroupDir: testPodMetaDir,
+			},
+			testLSPodMeta,
+		}).Times(1),
+		statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testLSPodMeta}).Times(1),
+	)
+
+	collector := New(&framework.Options{
+		Config: &framework.Config{
+			NUMAStatCollectorInterval: time.Second,
+		},
+		StatesInformer: statesInformer,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	})
+	c := collector.(*podNUMAStatCollector)
+	assert.True(t, c.Enabled())
+	assert.NotPanics(t, func() {
+		c.collectPodNUMAStat()
+	})
+	assert.True(t, c.Started())
+	// the pod not allocated with the NUMA nodes is skipped
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.ContainerNUMAMemory))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.ContainerCrossNUMAMemoryRatio))
+
+	containerLabels := func(numaNode int, isLocal bool) prometheus.Labels {
+		return prometheus.Labels{
+			metrics.NodeKey:       testNode.Name,
+			metrics.ContainerID:   "containerd://testContainerUID",
+			metrics.ContainerName: "test-container",
+			metrics.PodUID:        string(testPod.UID),
+			metrics.PodName:       testPod.Name,
+			metrics.PodNamespace:  testPod.Namespace,
+			metrics.NUMANode:      strconv.Itoa(numaNode),
+			metrics.NUMALocal:     strconv.FormatBool(isLocal),
+		}
+	}
+	assert.Equal(t, float64(750*system.PageSize), testutil.ToFloat64(metrics.ContainerNUMAMemory.With(containerLabels(0, true))))
+	assert.Equal(t, float64(250*system.PageSize), testutil.ToFloat64(metrics.ContainerNUMAMemory.With(containerLabels(1, false))))
+	ratioLabels := containerLabels(0, true)
+	delete(ratioLabels, metrics.NUMANode)
+	delete(ratioLabels, metrics.NUMALocal)
+	assert.Equal(t, 0.25, testutil.ToFloat64(metrics.ContainerCrossNUMAMemoryRatio.With(ratioLabels)))
+
+	// the metrics of the deleted pod are removed
+	c.collectPodNUMAStat()
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics.ContainerNUMAMemory))
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics.ContainerCrossNUMAMemoryRatio))
+}
@@ -0,0 +1,393 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	CollectorName = "ResctrlCollector"
+
+	// podMonGroupPrefix is the prefix of the resctrl mon groups created for pods
+	podMonGroupPrefix = "koord-pod-"
+)
+
+var (
+	timeNow = time.Now
+
+	// reservedResctrlDirs are the dirs under the resctrl root which are not ctrl groups
+	reservedResctrlDirs = sets.NewString(system.RdtInfoDir, system.ResctrlMonDataDir, system.ResctrlMonGroupsDir)
+)
+
+type monDataStat struct {
+	monData   *system.ResctrlMonData
+	timestamp time.Time
+}
+
+type resctrlCollector struct {
+	collectInterval  time.Duration
+	enablePodMonitor bool
+	started          *atomic.Bool
+	appendableDB     metriccache.Appendable
+	statesInformer   statesinformer.StatesInformer
+	cgroupReader     resourceexecutor.CgroupReader
+	podFilter        framework.PodFilter
+	lastMonDataStat  *gocache.Cache
+}
+
+func New(opt *framework.Options) framework.Collector {
+	collectInterval := opt.Config.ResctrlCollectorInterval
+	podFilter := framework.DefaultPodFilter
+	if filter, ok := opt.PodFilters[CollectorName]; ok {
+		podFilter = filter
+	}
+	return &resctrlCollector{
+		collectInterval:  collectInterval,
+		enablePodMonitor: opt.Config.EnableResctrlPodMonitor,
+		started:          atomic.NewBool(false),
+		appendableDB:     opt.MetricCache,
+		statesInformer:   opt.StatesInformer,
+		cgroupReader:     opt.CgroupReader,
+		podFilter:        podFilter,
+		lastMonDataStat:  gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+	}
+}
+
+var _ framework.PodCollector = &resctrlCollector{}
+
+func (c *resctrlCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.ResctrlCollector) && c.collectInterval > 0
+}
+
+func (c *resctrlCollector) Setup(ctx *framework.Context) {}
+
+func (c *resctrlCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go wait.Until(c.collectResctrlMonData, c.collectInterval, stopCh)
+}
+
+func (c *resctrlCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *resctrlCollector) FilterPod(meta *statesinformer.PodMeta) (bool, string) {
+	return c.podFilter.FilterPod(meta)
+}
+
+func (c *resctrlCollector) collectResctrlMonData() {
+	klog.V(6).Info("start collectResctrlMonData")
+	// the collector should not block the metrics advisor when the resctrl monitoring is unsupported
+	defer c.started.Store(true)
+	if !system.IsResctrlMonitorSupported() {
+		klog.V(5).Infof("skip collect resctrl mon data, resctrl monitoring is not supported")
+		return
+	}
+
+	ctrlGroups, err := listResctrlCtrlGroups()
+	if err != nil {
+		klog.V(4).Infof("failed to list resctrl ctrl groups, err: %s", err)
+		return
+	}
+
+	var monMetrics []metriccache.MetricSample
+	for _, group := range ctrlGroups {
+		monMetrics = append(monMetrics, c.collectCtrlGroup(group)...)
+	}
+	if c.enablePodMonitor {
+		monMetrics = append(monMetrics, c.collectPodMonGroups(ctrlGroups)...)
+	}
+
+	appender := c.appendableDB.Appender()
+	if err := appender.Append(monMetrics); err != nil {
+		klog.Warningf("append resctrl mon metrics failed, reason: %v", err)
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("commit resctrl mon metrics failed, reason: %v", err)
+		return
+	}
+	klog.V(5).Infof("collectResctrlMonData finished, ctrl group num %d, metric num %d", len(ctrlGroups), len(monMetrics))
+}
+
+func (c *resctrlCollector) collectCtrlGroup(group string) []metriccache.MetricSample {
+	llcOccupancy, mbmTotal, mbmLocal, ok := c.readMonData(group)
+	if !ok {
+		return nil
+	}
+	metrics.RecordResctrlLLCOccupancy(group, llcOccupancy)
+
+	collectTime := timeNow()
+	properties := metriccache.MetricPropertiesFunc.ResctrlGroup(group)
+	var samples []metriccache.MetricSample
+	if sample, err := metriccache.ResctrlGroupLLCOccupancyMetric.GenerateSample(properties, collectTime, llcOccupancy); err != nil {
+		klog.Warningf("generate resctrl group %s llc occupancy metric failed, err %v", group, err)
+	} else {
+		samples = append(samples, sample)
+	}
+	if mbmTotal == nil {
+		// the memory bandwidth needs at least two points
+		return samples
+	}
+	metrics.RecordResctrlMemoryBandwidth(group, metrics.MBMTypeTotal, *mbmTotal)
+	metrics.RecordResctrlMemoryBandwidth(group, metrics.MBMTypeLocal, *mbmLocal)
+	if sample, err := metriccache.ResctrlGroupMBMTotalMetric.GenerateSample(properties, collectTime, *mbmTotal); err != nil {
+		klog.Warningf("generate resctrl group %s mbm total metric failed, err %v", group, err)
+	} else {
+		samples = append(samples, sample)
+	}
+	if sample, err := metriccache.ResctrlGroupMBMLocalMetric.GenerateSample(properties, collectTime, *mbmLocal); err != nil {
+		klog.Warningf("generate resctrl group %s mbm local metric failed, err %v", group, err)
+	} else {
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// collectPodMonGroups maintains a mon group for each pod under the ctrl group which the pod tasks belong to, and
+// collects the mon data of the pods. The mon groups of the deleted pods are removed to release the RMIDs.
+func (c *resctrlCollector) collectPodMonGroups(ctrlGroups []string) []metriccache.MetricSample {
+	taskGroups := map[int32]string{}
+	for _, group := range ctrlGroups {
+		tasksMap, err := system.ReadResctrlTasksMap(group)
+		if err != nil {
+			klog.V(4).Infof("failed to read tasks of resctrl group %s, err: %s", group, err)
+			continue
+		}
+		for task := range tasksMap {
+			taskGroups[task] = group
+		}
+	}
+
+	metrics.ResetPodResctrl()
+	expectedMonGroups := map[string]sets.String{}
+	var samples []metriccache.MetricSample
+	for _, meta := range c.statesInformer.GetAllPods() {
+		pod := meta.Pod
+		if filtered, msg := c.FilterPod(meta); filtered {
+			klog.V(5).Infof("skip collect pod %s resctrl mon data, reason: %s", util.GetPodKey(pod), msg)
+			continue
+		}
+		taskIDs := c.getPodTaskIDs(meta)
+		group := getTasksCtrlGroup(taskIDs, taskGroups)
+		if len(group) <= 0 {
+			klog.V(6).Infof("skip collect pod %s resctrl mon data, tasks not in any ctrl group", util.GetPodKey(pod))
+			continue
+		}
+
+		monGroup := podMonGroupPrefix + string(pod.UID)
+		if expectedMonGroups[group] == nil {
+			expectedMonGroups[group] = sets.NewString()
+		}
+		expectedMonGroups[group].Insert(monGroup)
+		if _, err := system.InitResctrlMonGroupIfNotExist(group, monGroup); err != nil {
+			klog.V(4).Infof("failed to init resctrl mon group for pod %s, err: %s", util.GetPodKey(pod), err)
+			continue
+		}
+		monGroupPath := filepath.Join(group, system.ResctrlMonGroupsDir, monGroup)
+		c.updateMonGroupTasks(monGroupPath, group, taskIDs, taskGroups)
+
+		samples = append(samples, c.collectPodMonGroup(pod, monGroupPath)...)
+	}
+
+	c.cleanupPodMonGroups(ctrlGroups, expectedMonGroups)
+	return samples
+}
+
+func (c *resctrlCollector) collectPodMonGroup(pod *corev1.Pod, monGroupPath string) []metriccache.MetricSample {
+	llcOccupancy, mbmTotal, mbmLocal, ok := c.readMonData(monGroupPath)
+	if !ok {
+		return nil
+	}
+	metrics.RecordPodResctrlLLCOccupancy(pod, llcOccupancy)
+
+	collectTime := timeNow()
+	properties := metriccache.MetricPropertiesFunc.Pod(string(pod.UID))
+	var samples []metriccache.MetricSample
+	if sample, err := metriccache.PodResctrlLLCOccupancyMetric.GenerateSample(properties, collectTime, llcOccupancy); err != nil {
+		klog.Warningf("generate pod %s resctrl llc occupancy metric failed, err %v", util.GetPodKey(pod), err)
+	} else {
+		samples = append(samples, sample)
+	}
+	if mbmTotal == nil {
+		return samples
+	}
+	metrics.RecordPodResctrlMemoryBandwidth(pod, metrics.MBMTypeTotal, *mbmTotal)
+	metrics.RecordPodResctrlMemoryBandwidth(pod, metrics.MBMTypeLocal, *mbmLocal)
+	if sample, err := metriccache.PodResctrlMBMTotalMetric.GenerateSample(properties, collectTime, *mbmTotal); err != nil {
+		klog.Warningf("generate pod %s resctrl mbm total metric failed, err %v", util.GetPodKey(pod), err)
+	} else {
+		samples = append(samples, sample)
+	}
+	if sample, err := metriccache.PodResctrlMBMLocalMetric.GenerateSample(properties, collectTime, *mbmLocal); err != nil {
+		klog.Warningf("generate pod %s resctrl mbm local metric failed, err %v", util.GetPodKey(pod), err)
+	} else {
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// readMonData reads the llc occupancy and calculates the memory bandwidth in bytes per second of the resctrl group.
+// The bandwidths are nil for the first point.
+func (c *resctrlCollector) readMonData(groupPath string) (float64, *float64, *float64, bool) {
+	monData, err := system.ReadResctrlMonData(groupPath)
+	if err != nil {
+		klog.V(4).Infof("failed to read resctrl mon data of group %s, err: %s", groupPath, err)
+		return 0, nil, nil, false
+	}
+	current := &monDataStat{
+		monData:   monData,
+		timestamp: timeNow(),
+	}
+	lastValue, ok := c.lastMonDataStat.Get(groupPath)
+	c.lastMonDataStat.Set(groupPath, current, gocache.DefaultExpiration)
+	llcOccupancy := float64(monData.LLCOccupancy)
+	if !ok {
+		klog.V(6).Infof("collect resctrl group %s mon data first point", groupPath)
+		return llcOccupancy, nil, nil, true
+	}
+	last := lastValue.(*monDataStat)
+	duration := current.timestamp.Sub(last.timestamp).Seconds()
+	// the counters may be reset when the group is recreated
+	if duration <= 0 || monData.MBMTotalBytes < last.monData.MBMTotalBytes || monData.MBMLocalBytes < last.monData.MBMLocalBytes {
+		klog.V(5).Infof("collect resctrl group %s mon data abnormal, reset the point", groupPath)
+		return llcOccupancy, nil, nil, true
+	}
+	mbmTotal := float64(monData.MBMTotalBytes-last.monData.MBMTotalBytes) / duration
+	mbmLocal := float64(monData.MBMLocalBytes-last.monData.MBMLocalBytes) / duration
+	return llcOccupancy, &mbmTotal, &mbmLocal, true
+}
+
+func (c *resctrlCollector) getPodTaskIDs(podMeta *statesinformer.PodMeta) []int32 {
+	var taskIDs []int32
+	pod := podMeta.Pod
+	for i := range pod.Status.ContainerStatuses {
+		containerStat := &pod.Status.ContainerStatuses[i]
+		containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+		if err != nil {
+			klog.V(5).Infof("failed to get container cgroup path for container %s/%s/%s, err: %s",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		ids, err := c.cgroupReader.ReadCPUTasks(containerDir)
+		if err != nil {
+			klog.V(5).Infof("failed to read task ids of container %s/%s/%s, err: %s",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		taskIDs = append(taskIDs, ids...)
+	}
+	return taskIDs
+}
+
+func (c *resctrlCollector) updateMonGroupTasks(monGroupPath, group string, taskIDs []int32, taskGroups map[int32]string) {
+	monTasksMap, err := system.ReadResctrlTasksMap(monGroupPath)
+	if err != nil {
+		klog.V(4).Infof("failed to read tasks of resctrl mon group %s, err: %s", monGroupPath, err)
+		return
+	}
+	// a task can only be moved into the mon group of the ctrl group it belongs to
+	var newTaskIDs []int32
+	for _, id := range taskIDs {
+		if _, ok := monTasksMap[id]; ok || taskGroups[id] != group {
+			continue
+		}
+		newTaskIDs = append(newTaskIDs, id)
+	}
+	if len(newTaskIDs) <= 0 {
+		return
+	}
+	updater, err := resourceexecutor.CalculateResctrlL3TasksResource(monGroupPath, newTaskIDs)
+	if err != nil {
+		klog.V(4).Infof("failed to get tasks updater for resctrl mon group %s, err: %s", monGroupPath, err)
+		return
+	}
+	if err = resourceexecutor.UpdateResctrlTasksFunc(updater); err != nil {
+		klog.V(4).Infof("failed to update tasks for resctrl mon group %s, err: %s", monGroupPath, err)
+	}
+}
+
+func (c *resctrlCollector) cleanupPodMonGroups(ctrlGroups []string, expectedMonGroups map[string]sets.String) {
+	for _, group := range ctrlGroups {
+		monGroups, err := system.ListResctrlMonGroups(group)
+		if err != nil {
+			klog.V(5).Infof("failed to list mon groups of resctrl group %s, err: %s", group, err)
+			continue
+		}
+		for _, monGroup := range monGroups {
+			// only clean up the mon groups created by the collector
+			if !strings.HasPrefix(monGroup, podMonGroupPrefix) || expectedMonGroups[group].Has(monGroup) {
+				continue
+			}
+			if err = system.RemoveResctrlMonGroup(group, monGroup); err != nil {
+				klog.V(4).Infof("failed to remove resctrl mon group %s/%s, err: %s", group, monGroup, err)
+				continue
+			}
+			c.lastMonDataStat.Delete(filepath.Join(group, system.ResctrlMonGroupsDir, monGroup))
+			klog.V(5).Infof("remove resctrl mon group %s/%s successfully", group, monGroup)
+		}
+	}
+}
+
+func getTasksCtrlGroup(taskIDs []int32, taskGroups map[int32]string) string {
+	for _, id := range taskIDs {
+		if group, ok := taskGroups[id]; ok {
+			return group
+		}
+	}
+	return ""
+}
+
+// listResctrlCtrlGroups returns the ctrl groups under the resctrl root, e.g. LSR, LS, BE.
+func listResctrlCtrlGroups() ([]string, error) {
+	entries, err := os.ReadDir(system.GetResctrlSubsystemDirPath())
+	if err != nil {
+		return nil, err
+	}
+	var groups []string
+	for _, entry := range entries {
+		if !entry.IsDir() || reservedResctrlDirs.Has(entry.Name()) {
+			continue
+		}
+		groups = append(groups, entry.Name())
+	}
+	return groups, nil
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_resctrlCollector_collectResctrlMonData(t *testing.T) {
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	testPodMetaDir := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-podtest-pod-uid.slice"
+	testContainerParentDir := "/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-podtest-pod-uid.slice/cri-containerd-testContainerUID.scope"
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "test",
+			UID:       "test-pod-uid",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: "containerd://testContainerUID",
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+	testPodMonGroup := filepath.Join("BE", system.ResctrlMonGroupsDir, podMonGroupPrefix+"test-pod-uid")
+	prepareMonData := func(helper *system.FileTestUtil, groupPath string, llcOccupancy, mbmTotal, mbmLocal string) {
+		domainDir := filepath.Join(system.GetResctrlMonDataDirPath(groupPath), "mon_L3_00")
+		helper.WriteFileContents(filepath.Join(domainDir, system.ResctrlLLCOccupancyName), llcOccupancy)
+		helper.WriteFileContents(filepath.Join(domainDir, system.ResctrlMBMTotalBytesName), mbmTotal)
+		helper.WriteFileContents(filepath.Join(domainDir, system.ResctrlMBMLocalBytesName), mbmLocal)
+	}
+	type fields struct {
+		enablePodMonitor bool
+		prepareFn        func(helper *system.FileTestUtil)
+		initLastStat     func(c *resctrlCollector)
+	}
+	type wants struct {
+		groupMetrics    map[string][]float64
+		podMetrics      []float64
+		podMonGroupLeft bool
+		podMonTasks     string
+		staleGroupLeft  bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		wants  wants
+	}{
+		{
+			name: "skip when resctrl monitoring unsupported",
+			fields: fields{
+				prepareFn: func(helper *system.FileTestUtil) {
+					prepareMonData(helper, "BE", "1048576", "1000", "800")
+				},
+			},
+			wants: wants{},
+		},
+		{
+			name: "collect llc occupancy for the first point",
+			fields: fields{
+				prepareFn: func(helper *system.FileTestUtil) {
+					helper.MkDirAll(filepath.Join(system.GetResctrlSubsystemDirPath(), system.RdtInfoDir, system.L3MonDir))
+					prepareMonData(helper, "BE", "1048576", "1000", "800")
+					prepareMonData(helper, "LS", "2097152", "1000", "800")
+				},
+			},
+			wants: wants{
+				groupMetrics: map[string][]float64{
+					"BE": {1048576},
+					"LS": {2097152},
+				},
+			},
+		},
+		{
+			name: "collect llc occupancy and memory bandwidth",
+			fields: fields{
+				prepareFn: func(helper *system.FileTestUtil) {
+					helper.MkDirAll(filepath.Join(system.GetResctrlSubsystemDirPath(), system.RdtInfoDir, system.L3MonDir))
+					prepareMonData(helper, "BE", "1048576", "11000", "8800")
+				},
+				initLastStat: func(c *resctrlCollector) {
+					c.lastMonDataStat.Set("BE", &monDataStat{
+						monData: &system.ResctrlMonData{
+							LLCOccupancy:  1048576,
+							MBMTotalBytes: 1000,
+							MBMLocalBytes: 800,
+						},
+						timestamp: testNow.Add(-10 * time.Second),
+					}, 0)
+				},
+			},
+			wants: wants{
+				groupMetrics: map[string][]float64{
+					"BE": {1048576, 1000, 800},
+				},
+			},
+		},
+		{
+			name: "skip memory bandwidth when counters reset",
+			fields: fields{
+				prepareFn: func(helper *system.FileTestUtil) {
+					helper.MkDirAll(filepath.Join(system.GetResctrlSubsystemDirPath(), system.RdtInfoDir, system.L3MonDir))
+					prepareMonData(helper, "BE", "1048576", "100", "80")
+				},
+				initLastStat: func(c *resctrlCollector) {
+					c.lastMonDataStat.Set("BE", &monDataStat{
+						monData: &system.ResctrlMonData{
+							MBMTotalBytes: 1000,
+							MBMLocalBytes: 800,
+						},
+						timestamp: testNow.Add(-10 * time.Second),
+					}, 0)
+				},
+			},
+			wants: wants{
+				groupMetrics: map[string][]float64{
+					"BE": {1048576},
+				},
+			},
+		},
+		{
+			name: "collect pod mon group and remove stale ones",
+			fields: fields{
+				enablePodMonitor: true,
+				prepareFn: func(helper *system.FileTestUtil) {
+					helper.MkDirAll(filepath.Join(system.GetResctrlSubsystemDirPath(), system.RdtInfoDir, system.L3MonDir))
+					prepareMonData(helper, "BE", "1048576", "11000", "8800")
+					helper.WriteFileContents(system.GetResctrlTasksFilePath("BE"), "100\n101\n")
+					prepareMonData(helper, testPodMonGroup, "524288", "6000", "5000")
+					helper.WriteFileContents(system.GetResctrlTasksFilePath(testPodMonGroup), "")
+					staleMonGroup := filepath.Join("BE", system.ResctrlMonGroupsDir, podMonGroupPrefix+"stale-pod-uid")
+					helper.WriteFileContents(system.GetResctrlTasksFilePath(staleMonGroup), "")
+					tasks, err := system.GetCgroupResource(system.CPUTasksName)
+					assert.NoError(t, err)
+					helper.WriteCgroupFileContents(testContainerParentDir, tasks, "100\n")
+				},
+				initLastStat: func(c *resctrlCollector) {
+					c.lastMonDataStat.Set(testPodMonGroup, &monDataStat{
+						monData: &system.ResctrlMonData{
+							MBMTotalBytes: 1000,
+							MBMLocalBytes: 1000,
+						},
+						timestamp: testNow.Add(-10 * time.Second),
+					}, 0)
+				},
+			},
+			wants: wants{
+				groupMetrics: map[string][]float64{
+					"BE": {1048576},
+				},
+				podMetrics:      []float64{524288, 500, 400},
+				podMonGroupLeft: true,
+				podMonTasks:     "100",
+				staleGroupLeft:  false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.fields.prepareFn != nil {
+				tt.fields.prepareFn(helper)
+			}
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              t.TempDir(),
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer func() {
+				metricCache.Close()
+			}()
+			statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+			statesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+			statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+				{
+					Pod:       testPod,
+					CgroupDir: testPodMetaDir,
+				},
+			}).AnyTimes()
+
+			collector := New(&framework.Options{
+				Config: &framework.Config{
+					ResctrlCollectorInterval: time.Second,
+					EnableResctrlPodMonitor:  tt.fields.enablePodMonitor,
+				},
+				StatesInformer: statesInformer,
+				MetricCache:    metricCache,
+				CgroupReader:   resourceexecutor.NewCgroupReader(),
+			})
+			c := collector.(*resctrlCollector)
+			if tt.fields.initLastStat != nil {
+				tt.fields.initLastStat(c)
+			}
+
+			assert.NotPanics(t, func() {
+				c.collectResctrlMonData()
+			})
+			assert.True(t, c.Started())
+
+			querier, err := metricCache.Querier(testNow.Add(-time.Minute), testNow)
+			assert.NoError(t, err)
+			queryLast := func(resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string) (float64, int) {
+				queryMeta, err := resource.BuildQueryMeta(properties)
+				assert.NoError(t, err)
+				aggregateResult := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+				assert.NoError(t, querier.Query(queryMeta, nil, aggregateResult))
+				if aggregateResult.Count() <= 0 {
+					return 0, 0
+				}
+				got, err := aggregateResult.Value(metriccache.AggregationTypeLast)
+				assert.NoError(t, err)
+				return got, aggregateResult.Count()
+			}
+
+			groupResources := []metriccache.MetricResource{
+				metriccache.ResctrlGroupLLCOccupancyMetric,
+				metriccache.ResctrlGroupMBMTotalMetric,
+				metriccache.ResctrlGroupMBMLocalMetric,
+			}
+			for _, group := range []string{"BE", "LS"} {
+				properties := metriccache.MetricPropertiesFunc.ResctrlGroup(group)
+				wantValues := tt.wants.groupMetrics[group]
+				for i, resource := range groupResources {
+					got, count := queryLast(resource, properties)
+					if i >= len(wantValues) {
+						assert.Equal(t, 0, count, "group %s, metric %d", group, i)
+						continue
+					}
+					assert.Equal(t, wantValues[i], got, "group %s, metric %d", group, i)
+				}
+			}
+
+			podResources := []metriccache.MetricResource{
+				metriccache.PodResctrlLLCOccupancyMetric,
+				metriccache.PodResctrlMBMTotalMetric,
+				metriccache.PodResctrlMBMLocalMetric,
+			}
+			podProperties := metriccache.MetricPropertiesFunc.Pod(string(testPod.UID))
+			for i, resource := range podResources {
+				got, count := queryLast(resource, podProperties)
+				if i >= len(tt.wants.podMetrics) {
+					assert.Equal(t, 0, count, "pod metric %d", i)
+					continue
+				}
+				assert.Equal(t, tt.wants.podMetrics[i], got, "pod metric %d", i)
+			}
+
+			if tt.fields.enablePodMonitor {
+				monGroups, err := system.ListResctrlMonGroups("BE")
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wants.podMonGroupLeft, len(monGroups) > 0 && monGroups[0] == podMonGroupPrefix+"test-pod-uid")
+				assert.Equal(t, tt.wants.staleGroupLeft, len(monGroups) > 1)
+				assert.Equal(t, tt.wants.podMonTasks, helper.ReadFileContents(system.GetResctrlTasksFilePath(testPodMonGroup)))
+			}
+		})
+	}
+}
+
+func Test_resctrlCollector_Enabled(t *testing.T) {
+	tests := []struct {
+		name            string
+		featureEnabled  bool
+		collectInterval time.Duration
+		want            bool
+	}{
+		{
+			name:            "disabled by feature gate",
+			featureEnabled:  false,
+			collectInterval: time.Second,
+			want:            false,
+		},
+		{
+			name:            "disabled by invalid interval",
+			featureEnabled:  true,
+			collectInterval: 0,
+			want:            false,
+		},
+		{
+			name:            "enabled",
+			featureEnabled:  true,
+			collectInterval: time.Second,
+			want:            true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{
+				string(features.ResctrlCollector): tt.featureEnabled,
+			}))
+			defer func() {
+				assert.NoError(t, features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{
+					string(features.ResctrlCollector): false,
+				}))
+			}()
+			c := &resctrlCollector{
+				collectInterval: tt.collectInterval,
+			}
+			assert.Equal(t, tt.want, c.Enabled())
+		})
+	}
+}
+
+func Test_resctrlCollector_Started(t *testing.T) {
+	c := &resctrlCollector{
+		started: atomic.NewBool(true),
+	}
+	assert.True(t, c.Started())
+}
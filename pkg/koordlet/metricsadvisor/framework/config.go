@@ -36,6 +36,9 @@ type Config struct {
 	CPICollectorTimeWindow           time.Duration
 	ColdPageCollectorInterval        time.Duration
 	EnablePageCacheCollector         bool
+	ResctrlCollectorInterval         time.Duration
+	EnableResctrlPodMonitor          bool
+	NUMAStatCollectorInterval        time.Duration
 }
 
 func NewDefaultConfig() *Config {
@@ -49,6 +52,9 @@ func NewDefaultConfig() *Config {
 		CPICollectorTimeWindow:           10 * time.Second,
 		ColdPageCollectorInterval:        5 * time.Second,
 		EnablePageCacheCollector:         false,
+		ResctrlCollectorInterval:         10 * time.Second,
+		EnableResctrlPodMonitor:          false,
+		NUMAStatCollectorInterval:        10 * time.Second,
 	}
 }
 
@@ -62,4 +68,7 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.CPICollectorTimeWindow, "collect-cpi-timewindow", c.CPICollectorTimeWindow, "Collect cpi time window. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 	fs.DurationVar(&c.ColdPageCollectorInterval, "coldpage-collector-interval", c.ColdPageCollectorInterval, "Collect cold page interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 	fs.BoolVar(&c.EnablePageCacheCollector, "enable-pagecache-collector", c.EnablePageCacheCollector, "Enable cache collector of node, pods and containers")
+	fs.DurationVar(&c.ResctrlCollectorInterval, "resctrl-collector-interval", c.ResctrlCollectorInterval, "Collect resctrl monitoring data interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.BoolVar(&c.EnableResctrlPodMonitor, "enable-resctrl-pod-monitor", c.EnableResctrlPodMonitor, "Enable the resctrl monitoring groups of pods, which consume the limited RMIDs of the node")
+	fs.DurationVar(&c.NUMAStatCollectorInterval, "numastat-collector-interval", c.NUMAStatCollectorInterval, "Collect the NUMA memory stat of the NUMA-aware allocated pods interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 }
@@ -35,6 +35,9 @@ func Test_NewDefaultConfig(t *testing.T) {
 		CPICollectorTimeWindow:           10 * time.Second,
 		ColdPageCollectorInterval:        5 * time.Second,
 		EnablePageCacheCollector:         false,
+		ResctrlCollectorInterval:         10 * time.Second,
+		EnableResctrlPodMonitor:          false,
+		NUMAStatCollectorInterval:        10 * time.Second,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -51,6 +54,9 @@ func Test_InitFlags(t *testing.T) {
 		"--psi-collector-interval=5s",
 		"--collect-cpi-timewindow=15s",
 		"--coldpage-collector-interval=15s",
+		"--resctrl-collector-interval=30s",
+		"--enable-resctrl-pod-monitor=true",
+		"--numastat-collector-interval=30s",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -63,6 +69,9 @@ func Test_InitFlags(t *testing.T) {
 		PSICollectorInterval             time.Duration
 		CPICollectorTimeWindow           time.Duration
 		ColdPageCollectorInterval        time.Duration
+		ResctrlCollectorInterval         time.Duration
+		EnableResctrlPodMonitor          bool
+		NUMAStatCollectorInterval        time.Duration
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -83,6 +92,9 @@ func Test_InitFlags(t *testing.T) {
 				PSICollectorInterval:             5 * time.Second,
 				CPICollectorTimeWindow:           15 * time.Second,
 				ColdPageCollectorInterval:        15 * time.Second,
+				ResctrlCollectorInterval:         30 * time.Second,
+				EnableResctrlPodMonitor:          true,
+				NUMAStatCollectorInterval:        30 * time.Second,
 			},
 			args: args{fs: fs},
 		},
@@ -98,6 +110,9 @@ func Test_InitFlags(t *testing.T) {
 				PSICollectorInterval:             tt.fields.PSICollectorInterval,
 				CPICollectorTimeWindow:           tt.fields.CPICollectorTimeWindow,
 				ColdPageCollectorInterval:        tt.fields.ColdPageCollectorInterval,
+				ResctrlCollectorInterval:         tt.fields.ResctrlCollectorInterval,
+				EnableResctrlPodMonitor:          tt.fields.EnableResctrlPodMonitor,
+				NUMAStatCollectorInterval:        tt.fields.NUMAStatCollectorInterval,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
@@ -25,8 +25,10 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/nodestorageinfo"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/pagecache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/performance"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/podnumastat"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/podresource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/podthrottled"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/sysresource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/gpu"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
@@ -51,10 +53,14 @@ var (
 		coldmemoryresource.CollectorName: coldmemoryresource.New,
 		pagecache.CollectorName:          pagecache.New,
 		hostapplication.CollectorName:    hostapplication.New,
+		resctrl.CollectorName:            resctrl.New,
+		podnumastat.CollectorName:        podnumastat.New,
 	}
 
 	podFilters = map[string]framework.PodFilter{
 		podresource.CollectorName:  framework.DefaultPodFilter,
 		podthrottled.CollectorName: framework.DefaultPodFilter,
+		resctrl.CollectorName:      framework.DefaultPodFilter,
+		podnumastat.CollectorName:  framework.DefaultPodFilter,
 	}
 )
@@ -16,6 +16,12 @@ limitations under the License.
 
 package framework
 
+import (
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+)
+
 type QOSStrategyFactory = func(opt *Options) QOSStrategy
 
 type QOSStrategy interface {
@@ -23,3 +29,42 @@ type QOSStrategy interface {
 	Setup(*Context)
 	Run(stopCh <-chan struct{})
 }
+
+// RollbackStrategy is a QOSStrategy whose resource updates are restored by the resource journal when it is disabled.
+type RollbackStrategy interface {
+	// RollbackReasons returns the reasons of the resource updates made by the strategy.
+	RollbackReasons() []string
+}
+
+// RollbackStrategyResources restores the resources updated by the strategy, e.g. when it is disabled by the
+// feature-gate or the NodeSLO.
+func RollbackStrategyResources(name string, strategy QOSStrategy) {
+	s, ok := strategy.(RollbackStrategy)
+	if !ok {
+		return
+	}
+	restored, err := resourceexecutor.RollbackByStrategies(s.RollbackReasons())
+	if err != nil {
+		klog.Warningf("failed to rollback resources of disabled qos strategy %v, restored %v, err: %v", name, restored, err)
+		return
+	}
+	if restored > 0 {
+		klog.V(4).Infof("rollback %v resources of disabled qos strategy %v", restored, name)
+	}
+}
+
+// NodeSLOEnabledState tracks whether the NodeSLO enables a strategy, so that the strategy rolls back its resource
+// updates once it turns from enabled to disabled. The first observed disabled state is also taken as a transition,
+// since the strategy can be enabled in the previous runs of koordlet.
+type NodeSLOEnabledState struct {
+	enabled *bool
+}
+
+// TurnDisabled records whether the strategy is enabled by the NodeSLO, and returns true if it turns to disabled.
+func (s *NodeSLOEnabledState) TurnDisabled(enabled bool) bool {
+	if s.enabled != nil && *s.enabled == enabled {
+		return false
+	}
+	s.enabled = &enabled
+	return !enabled
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeSLOEnabledState(t *testing.T) {
+	s := NodeSLOEnabledState{}
+	// the first disabled state is a transition
+	assert.True(t, s.TurnDisabled(false))
+	assert.False(t, s.TurnDisabled(false))
+	assert.False(t, s.TurnDisabled(true))
+	assert.False(t, s.TurnDisabled(true))
+	assert.True(t, s.TurnDisabled(false))
+	assert.False(t, s.TurnDisabled(false))
+
+	s = NodeSLOEnabledState{}
+	assert.False(t, s.TurnDisabled(true))
+	assert.True(t, s.TurnDisabled(false))
+}
@@ -94,9 +94,24 @@ func NonBEPodFilter(pod *corev1.Pod) bool {
 	return apiext.GetPodQoSClassRaw(pod) != apiext.QoSBE && util.GetKubeQosClass(pod) != corev1.PodQOSBestEffort
 }
 
+// MidTierPodFilter returns true for the koord-mid pods of QoS LS or None, which are suppressed after the BE pods.
+func MidTierPodFilter(pod *corev1.Pod) bool {
+	if apiext.GetPodPriorityClassRaw(pod) != apiext.PriorityMid || !NonBEPodFilter(pod) {
+		return false
+	}
+	qosClass := apiext.GetPodQoSClassRaw(pod)
+	return qosClass == apiext.QoSLS || qosClass == apiext.QoSNone
+}
+
+// NonBENonMidPodFilter returns true for the pods which neither the BE suppression nor the Mid suppression limits.
+func NonBENonMidPodFilter(pod *corev1.Pod) bool {
+	return NonBEPodFilter(pod) && !MidTierPodFilter(pod)
+}
+
 func NonBEHostAppFilter(hostAppSpec *slov1alpha1.HostApplicationSpec) bool {
-	// host app qos must be BE and also run under best-effort dir
-	return hostAppSpec.QoS != apiext.QoSBE || hostAppSpec.CgroupPath == nil || hostAppSpec.CgroupPath.Base != slov1alpha1.CgroupBaseTypeKubeBesteffort
+	// BE host apps are suppressed along with the BE pods, no matter which cgroup dir they run under, and the ones out of
+	// the besteffort dir share the BE cfs quota with it
+	return hostAppSpec.QoS != apiext.QoSBE
 }
 
 func NonePodHighPriority(_ *corev1.Pod) bool {
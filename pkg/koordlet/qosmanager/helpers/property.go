@@ -18,9 +18,11 @@ package helpers
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 func GetPodResourceQoSByQoSClass(pod *corev1.Pod, strategy *slov1alpha1.ResourceQOSStrategy) *slov1alpha1.ResourceQOS {
@@ -66,3 +68,35 @@ func GetKubeQoSResourceQoSByQoSClass(qosClass corev1.PodQOSClass, strategy *slov
 	}
 	return resourceQoS
 }
+
+// GetHostAppResourceQoS gets the host application config by its declared qos class, and merges the strategy of the
+// host application if specified.
+func GetHostAppResourceQoS(hostApp *slov1alpha1.HostApplicationSpec, strategy *slov1alpha1.ResourceQOSStrategy) *slov1alpha1.ResourceQOS {
+	if hostApp == nil || strategy == nil {
+		return nil
+	}
+	var resourceQoS *slov1alpha1.ResourceQOS
+	switch hostApp.QoS {
+	case apiext.QoSLSE, apiext.QoSLSR:
+		resourceQoS = strategy.LSRClass
+	case apiext.QoSLS:
+		resourceQoS = strategy.LSClass
+	case apiext.QoSBE:
+		resourceQoS = strategy.BEClass
+	case apiext.QoSSystem:
+		resourceQoS = strategy.SystemClass
+	default:
+		// host application with qos none is not managed
+	}
+	if resourceQoS == nil || hostApp.Strategy == nil || hostApp.Strategy.ResourceQOS == nil {
+		return resourceQoS
+	}
+
+	merged, err := util.MergeCfg(resourceQoS.DeepCopy(), hostApp.Strategy.ResourceQOS)
+	if err != nil {
+		klog.Warningf("failed to merge resource qos of host application %s, use the qos class config, err: %v",
+			hostApp.Name, err)
+		return resourceQoS
+	}
+	return merged.(*slov1alpha1.ResourceQOS)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
@@ -82,3 +83,62 @@ func Test_getPodResourceQoSByQoSClass(t *testing.T) {
 		})
 	}
 }
+
+func TestGetHostAppResourceQoS(t *testing.T) {
+	lsOverridden := testutil.DefaultQOSStrategy().LSClass
+	lsOverridden.MemoryQOS.WmarkRatio = pointer.Int64(80)
+	tests := []struct {
+		name     string
+		hostApp  *slov1alpha1.HostApplicationSpec
+		strategy *slov1alpha1.ResourceQOSStrategy
+		want     *slov1alpha1.ResourceQOS
+	}{
+		{
+			name: "return nil",
+			want: nil,
+		},
+		{
+			name: "get qos=BE config",
+			hostApp: &slov1alpha1.HostApplicationSpec{
+				Name: "test-app",
+				QoS:  apiext.QoSBE,
+			},
+			strategy: testutil.DefaultQOSStrategy(),
+			want:     testutil.DefaultQOSStrategy().BEClass,
+		},
+		{
+			name: "get nil for qos=None",
+			hostApp: &slov1alpha1.HostApplicationSpec{
+				Name: "test-app",
+			},
+			strategy: testutil.DefaultQOSStrategy(),
+			want:     nil,
+		},
+		{
+			name: "get qos=LS config merged with host app strategy",
+			hostApp: &slov1alpha1.HostApplicationSpec{
+				Name: "test-app",
+				QoS:  apiext.QoSLS,
+				Strategy: &slov1alpha1.HostApplicationStrategy{
+					ResourceQOS: &slov1alpha1.ResourceQOS{
+						MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+							MemoryQOS: slov1alpha1.MemoryQOS{
+								WmarkRatio: pointer.Int64(80),
+							},
+						},
+					},
+				},
+			},
+			strategy: testutil.DefaultQOSStrategy(),
+			want:     lsOverridden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategyCopy := tt.strategy.DeepCopy()
+			got := GetHostAppResourceQoS(tt.hostApp, tt.strategy)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, strategyCopy, tt.strategy, "node strategy should not be changed")
+		})
+	}
+}
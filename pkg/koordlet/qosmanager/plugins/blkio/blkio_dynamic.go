@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blkio
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+var timeNow = time.Now
+
+type blkIOLimit struct {
+	readBPS  int64
+	writeBPS int64
+}
+
+// blkIODynamicState records the disk stats of the last round and the current dynamic BPS limits of the BE class.
+type blkIODynamicState struct {
+	lastDiskStats map[string]*system.DiskStat
+	lastTime      time.Time
+	// key is the disk number
+	limits map[string]*blkIOLimit
+}
+
+// getDynamicBlocks returns the BE blocks whose read and write BPS are replaced with the dynamic limits.
+// The blocks without the dynamic throttling enabled are returned as they are.
+func (b *blkIOReconcile) getDynamicBlocks(blocks []*slov1alpha1.BlockCfg) []*slov1alpha1.BlockCfg {
+	now := timeNow()
+	diskStats, err := system.ReadDiskStats()
+	if err != nil {
+		klog.V(4).Infof("%s: failed to read disk stats, err: %s", BlkIOReconcileName, err)
+	}
+	if b.dynamicState == nil {
+		b.dynamicState = &blkIODynamicState{limits: map[string]*blkIOLimit{}}
+	}
+	state := b.dynamicState
+	durationMilliSeconds := float64(now.Sub(state.lastTime).Milliseconds())
+
+	lsIOPressure := float64(-1)
+	newLimits := map[string]*blkIOLimit{}
+	dynamicBlocks := make([]*slov1alpha1.BlockCfg, 0, len(blocks))
+	for _, block := range blocks {
+		dynamicCfg := mergeBlkIODynamicCfg(block.IOCfg.Dynamic)
+		if dynamicCfg.Enable == nil || !*dynamicCfg.Enable {
+			dynamicBlocks = append(dynamicBlocks, block)
+			continue
+		}
+		var upper blkIOLimit
+		if block.IOCfg.ReadBPS != nil {
+			upper.readBPS = *block.IOCfg.ReadBPS
+		}
+		if block.IOCfg.WriteBPS != nil {
+			upper.writeBPS = *block.IOCfg.WriteBPS
+		}
+		if upper.readBPS <= 0 && upper.writeBPS <= 0 {
+			dynamicBlocks = append(dynamicBlocks, block)
+			continue
+		}
+		diskNumber, err := b.getDiskNumberFromBlockCfg(block, nil)
+		if err != nil { // the error is reported by the static updating
+			dynamicBlocks = append(dynamicBlocks, block)
+			continue
+		}
+
+		limit, ok := state.limits[diskNumber]
+		if !ok {
+			limit = &blkIOLimit{readBPS: upper.readBPS, writeBPS: upper.writeBPS}
+		}
+		latency, utilization, err := system.DiskIOLatencyAndUtil(state.lastDiskStats[diskNumber], diskStats[diskNumber], durationMilliSeconds)
+		if err == nil {
+			if utilization >= float64(*dynamicCfg.UtilizationThresholdPercent) && *dynamicCfg.LSIOPressureThresholdPercent > 0 && lsIOPressure < 0 {
+				lsIOPressure = b.getLSIOPressure()
+			}
+			contended, reason := isBlkIOContended(dynamicCfg, latency, utilization, lsIOPressure)
+			newLimit := adjustBlkIOLimit(limit, &upper, dynamicCfg, contended)
+			if *newLimit != *limit {
+				msg := fmt.Sprintf("adjust be dynamic blkio limit of disk %s from (rbps=%d, wbps=%d) to (rbps=%d, wbps=%d), %s",
+					diskNumber, limit.readBPS, limit.writeBPS, newLimit.readBPS, newLimit.writeBPS, reason)
+				klog.V(4).Infof("%s: %s", BlkIOReconcileName, msg)
+				_ = audit.V(3).Group("blkio").Reason("DynamicBlkIO").Message(msg).Do()
+			}
+			limit = newLimit
+		} else {
+			klog.V(5).Infof("%s: skip adjusting be dynamic blkio limit of disk %s, err: %s", BlkIOReconcileName, diskNumber, err)
+		}
+		limit = clampBlkIOLimit(limit, &upper, dynamicCfg)
+		newLimits[diskNumber] = limit
+
+		dynamicBlock := block.DeepCopy()
+		if upper.readBPS > 0 {
+			dynamicBlock.IOCfg.ReadBPS = &limit.readBPS
+		}
+		if upper.writeBPS > 0 {
+			dynamicBlock.IOCfg.WriteBPS = &limit.writeBPS
+		}
+		dynamicBlocks = append(dynamicBlocks, dynamicBlock)
+	}
+
+	state.limits = newLimits
+	state.lastDiskStats = diskStats
+	state.lastTime = now
+	return dynamicBlocks
+}
+
+// getLSIOPressure returns the max IO pressure (some avg10) of the running LS and LSR pods.
+func (b *blkIOReconcile) getLSIOPressure() float64 {
+	var pressure float64
+	for _, podMeta := range b.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || podMeta.Pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		qosClass := extension.GetPodQoSClassWithDefault(podMeta.Pod)
+		if qosClass != extension.QoSLS && qosClass != extension.QoSLSR {
+			continue
+		}
+		queryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodPSI(string(podMeta.Pod.UID),
+			string(metriccache.PSIResourceIO), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)))
+		if err != nil {
+			klog.V(5).Infof("%s: failed to build io pressure query for pod %s, err: %s", BlkIOReconcileName, util.GetPodKey(podMeta.Pod), err)
+			continue
+		}
+		value, err := helpers.CollectPodMetricLast(b.metricCache, queryMeta, b.reconcileInterval)
+		if err != nil {
+			klog.V(5).Infof("%s: failed to get io pressure of pod %s, err: %s", BlkIOReconcileName, util.GetPodKey(podMeta.Pod), err)
+			continue
+		}
+		pressure = math.Max(pressure, value)
+	}
+	return pressure
+}
+
+func mergeBlkIODynamicCfg(cfg *slov1alpha1.BlkIODynamicCfg) *slov1alpha1.BlkIODynamicCfg {
+	defaultCfg := sloconfig.DefaultBlkIODynamicCfg()
+	if cfg == nil {
+		return defaultCfg
+	}
+	merged, err := util.MergeCfg(defaultCfg, cfg.DeepCopy())
+	if err != nil {
+		klog.V(4).Infof("%s: failed to merge dynamic blkio config, use the default, err: %s", BlkIOReconcileName, err)
+		return defaultCfg
+	}
+	return merged.(*slov1alpha1.BlkIODynamicCfg)
+}
+
+// isBlkIOContended checks if the device is busy and either the IO latency exceeds the target or the LS pods suffer
+// from the IO pressure. The lsIOPressure is negative if it is not checked.
+func isBlkIOContended(cfg *slov1alpha1.BlkIODynamicCfg, latency, utilization, lsIOPressure float64) (bool, string) {
+	if utilization < float64(*cfg.UtilizationThresholdPercent) {
+		return false, fmt.Sprintf("utilization %.2f%% is below the threshold %d%%", utilization, *cfg.UtilizationThresholdPercent)
+	}
+	if latency > float64(*cfg.TargetLatency) {
+		return true, fmt.Sprintf("utilization %.2f%%, latency %.0fus exceeds the target %dus", utilization, latency, *cfg.TargetLatency)
+	}
+	if *cfg.LSIOPressureThresholdPercent > 0 && lsIOPressure > float64(*cfg.LSIOPressureThresholdPercent) {
+		return true, fmt.Sprintf("utilization %.2f%%, ls io pressure %.2f%% exceeds the threshold %d%%", utilization, lsIOPressure, *cfg.LSIOPressureThresholdPercent)
+	}
+	return false, fmt.Sprintf("utilization %.2f%%, latency %.0fus and ls io pressure are under the targets", utilization, latency)
+}
+
+// adjustBlkIOLimit tightens the limit by StepPercent of the current limit when contended, and relaxes it by StepPercent
+// of the upper bound otherwise.
+func adjustBlkIOLimit(limit, upper *blkIOLimit, cfg *slov1alpha1.BlkIODynamicCfg, contended bool) *blkIOLimit {
+	step := *cfg.StepPercent
+	adjust := func(cur, upperBound int64) int64 {
+		if upperBound <= 0 {
+			return cur
+		}
+		if contended {
+			return cur * (100 - step) / 100
+		}
+		return cur + upperBound*step/100
+	}
+	return clampBlkIOLimit(&blkIOLimit{
+		readBPS:  adjust(limit.readBPS, upper.readBPS),
+		writeBPS: adjust(limit.writeBPS, upper.writeBPS),
+	}, upper, cfg)
+}
+
+// clampBlkIOLimit keeps the limit between MinBPSPercent of the upper bound and the upper bound.
+func clampBlkIOLimit(limit, upper *blkIOLimit, cfg *slov1alpha1.BlkIODynamicCfg) *blkIOLimit {
+	clamp := func(cur, upperBound int64) int64 {
+		if upperBound <= 0 {
+			return upperBound
+		}
+		lowerBound := upperBound * *cfg.MinBPSPercent / 100
+		if lowerBound < 1 {
+			lowerBound = 1
+		}
+		if cur < lowerBound {
+			return lowerBound
+		}
+		if cur > upperBound {
+			return upperBound
+		}
+		return cur
+	}
+	return &blkIOLimit{
+		readBPS:  clamp(limit.readBPS, upper.readBPS),
+		writeBPS: clamp(limit.writeBPS, upper.writeBPS),
+	}
+}
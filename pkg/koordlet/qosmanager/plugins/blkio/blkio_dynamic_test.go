@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blkio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestBlkIOReconcile_getDynamicBlocks(t *testing.T) {
+	now := time.Now()
+	testLastDiskStats := "253 16 vdb 1000 0 8000 1000 1000 0 8000 1000 0 1000 2000 0 0 0 0"
+	testLSPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ls-pod",
+			Namespace: "default",
+			UID:       "xxxxxx",
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSLS),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+	testBlock := &slov1alpha1.BlockCfg{
+		Name:      "/dev/vdb",
+		BlockType: slov1alpha1.BlockTypeDevice,
+		IOCfg: slov1alpha1.IOCfg{
+			ReadIOPS: pointer.Int64(2048),
+			ReadBPS:  pointer.Int64(1000),
+			Dynamic: &slov1alpha1.BlkIODynamicCfg{
+				Enable: pointer.Bool(true),
+			},
+		},
+	}
+	testStaticBlock := &slov1alpha1.BlockCfg{
+		Name:      "/dev/vda",
+		BlockType: slov1alpha1.BlockTypeDevice,
+		IOCfg: slov1alpha1.IOCfg{
+			ReadBPS: pointer.Int64(1000),
+		},
+	}
+	dynamicBlock := func(readBPS int64) *slov1alpha1.BlockCfg {
+		b := testBlock.DeepCopy()
+		b.IOCfg.ReadBPS = pointer.Int64(readBPS)
+		return b
+	}
+	type fields struct {
+		lastLimit    *blkIOLimit
+		diskStats    string
+		lsIOPressure float64
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		arg       []*slov1alpha1.BlockCfg
+		want      []*slov1alpha1.BlockCfg
+		wantLimit *blkIOLimit
+	}{
+		{
+			name: "no dynamic block",
+			fields: fields{
+				diskStats: "253 16 vdb 2000 0 16000 21000 1000 0 8000 1000 0 1900 2000 0 0 0 0",
+			},
+			arg:  []*slov1alpha1.BlockCfg{testStaticBlock},
+			want: []*slov1alpha1.BlockCfg{testStaticBlock},
+		},
+		{
+			name: "initialize the limit with the upper bound",
+			fields: fields{
+				diskStats: "253 16 vdb 2000 0 16000 21000 1000 0 8000 1000 0 1100 2000 0 0 0 0",
+			},
+			arg:       []*slov1alpha1.BlockCfg{testBlock, testStaticBlock},
+			want:      []*slov1alpha1.BlockCfg{dynamicBlock(1000), testStaticBlock},
+			wantLimit: &blkIOLimit{readBPS: 1000},
+		},
+		{
+			name: "tighten the limit when the latency exceeds the target",
+			fields: fields{
+				lastLimit: &blkIOLimit{readBPS: 1000},
+				diskStats: "253 16 vdb 2000 0 16000 21000 1000 0 8000 1000 0 1900 2000 0 0 0 0",
+			},
+			arg:       []*slov1alpha1.BlockCfg{testBlock},
+			want:      []*slov1alpha1.BlockCfg{dynamicBlock(800)},
+			wantLimit: &blkIOLimit{readBPS: 800},
+		},
+		{
+			name: "tighten the limit no lower than the min",
+			fields: fields{
+				lastLimit: &blkIOLimit{readBPS: 110},
+				diskStats: "253 16 vdb 2000 0 16000 21000 1000 0 8000 1000 0 1900 2000 0 0 0 0",
+			},
+			arg:       []*slov1alpha1.BlockCfg{testBlock},
+			want:      []*slov1alpha1.BlockCfg{dynamicBlock(100)},
+			wantLimit: &blkIOLimit{readBPS: 100},
+		},
+		{
+			name: "tighten the limit when the ls pods suffer from the io pressure",
+			fields: fields{
+				lastLimit:    &blkIOLimit{readBPS: 1000},
+				diskStats:    "253 16 vdb 2000 0 16000 2000 1000 0 8000 1000 0 1900 2000 0 0 0 0",
+				lsIOPressure: 20,
+			},
+			arg:       []*slov1alpha1.BlockCfg{testBlock},
+			want:      []*slov1alpha1.BlockCfg{dynamicBlock(800)},
+			wantLimit: &blkIOLimit{readBPS: 800},
+		},
+		{
+			name: "relax the limit when the latency is under the target",
+			fields: fields{
+				lastLimit:    &blkIOLimit{readBPS: 500},
+				diskStats:    "253 16 vdb 2000 0 16000 2000 1000 0 8000 1000 0 1900 2000 0 0 0 0",
+				lsIOPressure: 5,
+			},
+			arg:       []*slov1alpha1.BlockCfg{testBlock},
+			want:      []*slov1alpha1.BlockCfg{dynamicBlock(700)},
+			wantLimit: &blkIOLimit{readBPS: 700},
+		},
+		{
+			name: "relax the limit no higher than the upper bound when the device is idle",
+			fields: fields{
+				lastLimit: &blkIOLimit{readBPS: 900},
+				diskStats: "253 16 vdb 2000 0 16000 21000 1000 0 8000 1000 0 1100 2000 0 0 0 0",
+			},
+			arg:       []*slov1alpha1.BlockCfg{testBlock},
+			want:      []*slov1alpha1.BlockCfg{dynamicBlock(1000)},
+			wantLimit: &blkIOLimit{readBPS: 1000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.WriteProcSubFileContents(system.ProcDiskStatsName, tt.fields.diskStats)
+			lastDiskStats, err := system.ParseDiskStats([]byte(testLastDiskStats))
+			assert.NoError(t, err)
+
+			oldTimeNow := timeNow
+			timeNow = func() time.Time {
+				return now
+			}
+			defer func() {
+				timeNow = oldTimeNow
+			}()
+
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              t.TempDir(),
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer func() {
+				metricCache.Close()
+			}()
+			if tt.fields.lsIOPressure > 0 {
+				sample, err := metriccache.PodPSIMetric.GenerateSample(metriccache.MetricPropertiesFunc.PodPSI(string(testLSPod.UID),
+					string(metriccache.PSIResourceIO), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), now, tt.fields.lsIOPressure)
+				assert.NoError(t, err)
+				appender := metricCache.Appender()
+				assert.NoError(t, appender.Append([]metriccache.MetricSample{sample}))
+				assert.NoError(t, appender.Commit())
+			}
+			statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+			statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+				{
+					Pod: testLSPod,
+				},
+			}).AnyTimes()
+
+			state := &blkIODynamicState{
+				lastDiskStats: lastDiskStats,
+				lastTime:      now.Add(-time.Second),
+				limits:        map[string]*blkIOLimit{},
+			}
+			if tt.fields.lastLimit != nil {
+				state.limits["253:16"] = tt.fields.lastLimit
+			}
+			b := &blkIOReconcile{
+				reconcileInterval: time.Minute,
+				statesInformer:    statesInformer,
+				metricCache:       metricCache,
+				storageInfo: &metriccache.NodeLocalStorageInfo{
+					DiskNumberMap: map[string]string{
+						"/dev/vda": "253:0",
+						"/dev/vdb": "253:16",
+					},
+				},
+				dynamicState: state,
+			}
+			got := b.getDynamicBlocks(tt.arg)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantLimit, b.dynamicState.limits["253:16"])
+			assert.Equal(t, now, b.dynamicState.lastTime)
+		})
+	}
+}
+
+func Test_getIOMaxUpdaterFromBlockCfg(t *testing.T) {
+	block := &slov1alpha1.BlockCfg{
+		Name:      "/dev/vdb",
+		BlockType: slov1alpha1.BlockTypeDevice,
+		IOCfg: slov1alpha1.IOCfg{
+			ReadIOPS: pointer.Int64(2048),
+			WriteBPS: pointer.Int64(1048576),
+		},
+	}
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	updaters := getIOMaxUpdaterFromBlockCfg(block, "253:16", "kubepods.slice/kubepods-besteffort.slice")
+	assert.Equal(t, 1, len(updaters))
+	assert.Equal(t, "253:16 rbps=max wbps=1048576 riops=2048 wiops=max", updaters[0].Value())
+
+	removers := getIOMaxRemoverFromDiskNumber("253:16", "kubepods.slice/kubepods-besteffort.slice")
+	assert.Equal(t, 1, len(removers))
+	assert.Equal(t, "253:16 rbps=max wbps=max riops=max wiops=max", removers[0].Value())
+}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
@@ -46,6 +48,8 @@ import (
 const (
 	BlkIOReconcileName = "BlkioReconcile"
 
+	reasonUpdateBlkIO = "UpdateBlkIO"
+
 	DefaultReadIOPS           = 0
 	DefaultWriteIOPS          = 0
 	DefaultReadBPS            = 0
@@ -62,12 +66,19 @@ type blkIOReconcile struct {
 	metricCache       metriccache.MetricCache
 	executor          resourceexecutor.ResourceUpdateExecutor
 	storageInfo       *metriccache.NodeLocalStorageInfo
+	dynamicState      *blkIODynamicState
+	// nodeSLOEnabled rolls back the resource updates once the NodeSLO disables the blkio qos of the node
+	nodeSLOEnabled framework.NodeSLOEnabledState
 }
 
 func (b *blkIOReconcile) Enabled() bool {
 	return features.DefaultKoordletFeatureGate.Enabled(features.BlkIOReconcile) && b.reconcileInterval > 0
 }
 
+func (b *blkIOReconcile) RollbackReasons() []string {
+	return []string{reasonUpdateBlkIO}
+}
+
 func (b *blkIOReconcile) Setup(context *framework.Context) {
 }
 
@@ -108,6 +119,8 @@ func (b *blkIOReconcile) reconcile() {
 	storageInfoRaw, exist := b.metricCache.Get(metriccache.NodeLocalStorageInfoKey)
 	if !exist {
 		klog.Errorf("%s: fail to get node local storage info not exist", BlkIOReconcileName)
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(BlkIOReconcileName, slov1alpha1.StrategyConditionError,
+			"node local storage info not exist")
 		return
 	}
 	storageInfo, ok := storageInfoRaw.(*metriccache.NodeLocalStorageInfo)
@@ -124,6 +137,11 @@ func (b *blkIOReconcile) reconcile() {
 
 	// update node blk qos by strategy defined in nodeslo
 	strategy := nodeSLO.Spec.ResourceQOSStrategy
+	// the pods specifying the blkio qos by their annotations are configured again after the rollback
+	if b.nodeSLOEnabled.TurnDisabled(isNodeBlkIOQOSEnabled(strategy)) {
+		framework.RollbackStrategyResources(BlkIOReconcileName, b)
+	}
+	var classErrs []string
 	// lsr
 	if strategy.LSRClass != nil && strategy.LSRClass.BlkIOQOS != nil && *strategy.LSRClass.BlkIOQOS.Enable && len(strategy.LSRClass.BlkIOQOS.Blocks) != 0 {
 		klog.Warningf("%s: configuring blkio of LSRClass is not supported!", BlkIOReconcileName)
@@ -137,23 +155,32 @@ func (b *blkIOReconcile) reconcile() {
 		klog.V(4).Infof("%s: start to reconcile be class blkio config", BlkIOReconcileName)
 		blocks := []*slov1alpha1.BlockCfg{}
 		if *strategy.BEClass.BlkIOQOS.Enable {
-			blocks = strategy.BEClass.BlkIOQOS.Blocks
+			blocks = b.getDynamicBlocks(strategy.BEClass.BlkIOQOS.Blocks)
+		} else {
+			b.dynamicState = nil
 		}
 		beClassRelativeDir := util.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
-		beClassPath := util.GetPodCgroupBlkIOAbsoluteDir(corev1.PodQOSBestEffort)
-		err := b.updateBlkIOConfig(
-			blocks,
-			nil,
-			blkioUpdater{
-				absolutePath:    beClassPath,
-				getDiskRecorder: getBlkIORecorder,
+		beClassUpdater := blkioUpdater{
+			absolutePath:    util.GetPodCgroupBlkIOAbsoluteDir(corev1.PodQOSBestEffort),
+			getDiskRecorder: getBlkIORecorder,
+			dynamicPath:     beClassRelativeDir,
+			getUpdaterFunc:  getBlkIOUpdaterFromBlockCfg,
+			getRemoverFunc:  getBlkIORemoverFromDiskNumber,
+		}
+		if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+			// cgroups-v2 throttles the IOPS and BPS in the io.max
+			beClassUpdater = blkioUpdater{
+				absolutePath:    filepath.Join(system.GetRootCgroupSubfsDir(system.CgroupBlkioDir), beClassRelativeDir),
+				getDiskRecorder: getIOMaxRecorder,
 				dynamicPath:     beClassRelativeDir,
-				getUpdaterFunc:  getBlkIOUpdaterFromBlockCfg,
-				getRemoverFunc:  getBlkIORemoverFromDiskNumber,
-			},
-		)
+				getUpdaterFunc:  getIOMaxUpdaterFromBlockCfg,
+				getRemoverFunc:  getIOMaxRemoverFromDiskNumber,
+			}
+		}
+		err := b.updateBlkIOConfig(blocks, nil, beClassUpdater)
 		if err != nil {
 			klog.Errorf("%s: fail to update be class blkio config: %s", BlkIOReconcileName, err.Error())
+			classErrs = append(classErrs, fmt.Sprintf("be class: %s", err))
 		} else {
 			klog.V(4).Infof("%s: reconcile be class blkio config finished", BlkIOReconcileName)
 		}
@@ -180,11 +207,21 @@ func (b *blkIOReconcile) reconcile() {
 		)
 		if err != nil {
 			klog.Errorf("%s: fail to update root class blkio config: %s", BlkIOReconcileName, err.Error())
+			classErrs = append(classErrs, fmt.Sprintf("root class: %s", err))
 		} else {
 			klog.V(4).Infof("%s: reconcile root class blkio config finished", BlkIOReconcileName)
 		}
 	}
 
+	// host applications
+	for i := range nodeSLO.Spec.HostApplications {
+		hostApp := &nodeSLO.Spec.HostApplications[i]
+		if err := b.reconcileHostApp(hostApp, strategy); err != nil {
+			klog.Errorf("%s: fail to update host app %s blkio config: %s", BlkIOReconcileName, hostApp.Name, err.Error())
+			classErrs = append(classErrs, fmt.Sprintf("host app %s: %s", hostApp.Name, err))
+		}
+	}
+
 	// pods
 	podsMeta := b.statesInformer.GetAllPods()
 	for _, podMeta := range podsMeta {
@@ -226,6 +263,53 @@ func (b *blkIOReconcile) reconcile() {
 			klog.V(4).Infof("%s: reconcile pod %s/%s blkio config finished", BlkIOReconcileName, podMeta.Pod.Namespace, podMeta.Pod.Name)
 		}
 	}
+
+	if len(classErrs) > 0 {
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(BlkIOReconcileName, slov1alpha1.StrategyConditionError,
+			strings.Join(classErrs, "; "))
+	} else {
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(BlkIOReconcileName, slov1alpha1.StrategyConditionApplied, "")
+	}
+}
+
+// isNodeBlkIOQOSEnabled returns whether the blkio qos of the BE class or the cgroup root is enabled.
+func isNodeBlkIOQOSEnabled(strategy *slov1alpha1.ResourceQOSStrategy) bool {
+	for _, qos := range []*slov1alpha1.ResourceQOS{strategy.BEClass, strategy.CgroupRoot} {
+		if qos != nil && qos.BlkIOQOS != nil && qos.BlkIOQOS.Enable != nil && *qos.BlkIOQOS.Enable {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileHostApp updates the blkio config of the host application. BE host apps follow the BE class config, while
+// the others are configured only if the blkio qos is specified in the strategy of the host app.
+func (b *blkIOReconcile) reconcileHostApp(hostApp *slov1alpha1.HostApplicationSpec, strategy *slov1alpha1.ResourceQOSStrategy) error {
+	hostAppSpecified := hostApp.Strategy != nil && hostApp.Strategy.ResourceQOS != nil && hostApp.Strategy.ResourceQOS.BlkIOQOS != nil
+	if hostApp.QoS != extension.QoSBE && !hostAppSpecified {
+		return nil
+	}
+	hostAppCfg := helpers.GetHostAppResourceQoS(hostApp, strategy)
+	if hostAppCfg == nil || hostAppCfg.BlkIOQOS == nil {
+		return nil
+	}
+	klog.V(4).Infof("%s: start to reconcile host app %s blkio config", BlkIOReconcileName, hostApp.Name)
+	blocks := []*slov1alpha1.BlockCfg{}
+	if hostAppCfg.BlkIOQOS.Enable != nil && *hostAppCfg.BlkIOQOS.Enable {
+		blocks = hostAppCfg.BlkIOQOS.Blocks
+	}
+	hostAppDir := util.GetHostAppCgroupRelativePath(hostApp)
+	return b.updateBlkIOConfig(
+		blocks,
+		nil,
+		blkioUpdater{
+			absolutePath:    util.GetPodCgroupBlkIOAbsolutePath(hostAppDir),
+			dynamicPath:     hostAppDir,
+			getDiskRecorder: getBlkIORecorder,
+			getUpdaterFunc:  getBlkIOUpdaterFromBlockCfg,
+			getRemoverFunc:  getBlkIORemoverFromDiskNumber,
+		},
+	)
 }
 
 type blkioUpdater struct {
@@ -333,31 +417,31 @@ func getBlkIOUpdaterFromBlockCfg(block *slov1alpha1.BlockCfg, diskNumber string,
 		system.BlkioTRIopsName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, readIOPS),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioTRIopsName, fmt.Sprintf("%s %d", diskNumber, readIOPS)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioTRIopsName, fmt.Sprintf("%s %d", diskNumber, readIOPS)),
 	)
 	readBPSUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
 		system.BlkioTRBpsName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, readBPS),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioTRBpsName, fmt.Sprintf("%s %d", diskNumber, readBPS)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioTRBpsName, fmt.Sprintf("%s %d", diskNumber, readBPS)),
 	)
 	writeIOPSUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
 		system.BlkioTWIopsName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, writeIOPS),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioTWIopsName, fmt.Sprintf("%s %d", diskNumber, writeIOPS)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioTWIopsName, fmt.Sprintf("%s %d", diskNumber, writeIOPS)),
 	)
 	writeBPSUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
 		system.BlkioTWBpsName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, writeBPS),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioTWBpsName, fmt.Sprintf("%s %d", diskNumber, writeBPS)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioTWBpsName, fmt.Sprintf("%s %d", diskNumber, writeBPS)),
 	)
 	ioWeightUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
 		system.BlkioIOWeightName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, ioweight),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioIOWeightName, fmt.Sprintf("%s %d", diskNumber, ioweight)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioIOWeightName, fmt.Sprintf("%s %d", diskNumber, ioweight)),
 	)
 
 	resources = append(resources,
@@ -432,7 +516,7 @@ func getDiskConfigUpdaterFromBlockCfg(block *slov1alpha1.BlockCfg, diskNumber st
 		system.BlkioIOQoSName,
 		dynamicPath,
 		fmt.Sprintf("%s enable=1 ctrl=user rlat=%d wlat=%d", diskNumber, readlat, writelat),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioIOQoSName, fmt.Sprintf("%s enable=1 ctrl=user rlat=%d wlat=%d", diskNumber, readlat, writelat)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioIOQoSName, fmt.Sprintf("%s enable=1 ctrl=user rlat=%d wlat=%d", diskNumber, readlat, writelat)),
 	)
 
 	resources = append(resources, ioQoSUpdater)
@@ -440,6 +524,54 @@ func getDiskConfigUpdaterFromBlockCfg(block *slov1alpha1.BlockCfg, diskNumber st
 	return
 }
 
+// configure io.max on cgroups-v2, the value 0 means no limit
+// eg. 253:16 rbps=1048576 wbps=max riops=max wiops=max
+func getIOMaxUpdaterFromBlockCfg(block *slov1alpha1.BlockCfg, diskNumber string, dynamicPath string) (resources []resourceexecutor.ResourceUpdater) {
+	var readIOPS, writeIOPS, readBPS, writeBPS int64 = DefaultReadIOPS, DefaultWriteIOPS, DefaultReadBPS, DefaultWriteBPS
+	if value := block.IOCfg.ReadIOPS; value != nil {
+		readIOPS = *value
+	}
+	if value := block.IOCfg.WriteIOPS; value != nil {
+		writeIOPS = *value
+	}
+	if value := block.IOCfg.ReadBPS; value != nil {
+		readBPS = *value
+	}
+	if value := block.IOCfg.WriteBPS; value != nil {
+		writeBPS = *value
+	}
+
+	value := fmt.Sprintf("%s rbps=%s wbps=%s riops=%s wiops=%s", diskNumber,
+		ioMaxValue(readBPS), ioMaxValue(writeBPS), ioMaxValue(readIOPS), ioMaxValue(writeIOPS))
+	ioMaxUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
+		system.BlkioIOMaxName,
+		dynamicPath,
+		value,
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioIOMaxName, value),
+	)
+	resources = append(resources, ioMaxUpdater)
+	return
+}
+
+func getIOMaxRemoverFromDiskNumber(diskNumber string, dynamicPath string) (resources []resourceexecutor.ResourceUpdater) {
+	value := fmt.Sprintf("%s rbps=max wbps=max riops=max wiops=max", diskNumber)
+	ioMaxUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
+		system.BlkioIOMaxName,
+		dynamicPath,
+		value,
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioIOMaxName, value),
+	)
+	resources = append(resources, ioMaxUpdater)
+	return
+}
+
+func ioMaxValue(value int64) string {
+	if value <= 0 {
+		return "max"
+	}
+	return strconv.FormatInt(value, 10)
+}
+
 func parseBlkIOResult(blkioResult string) (*slov1alpha1.BlkIOQOS, error) {
 	podBlkIOQoS := &slov1alpha1.BlkIOQOS{}
 	if err := json.Unmarshal([]byte(blkioResult), podBlkIOQoS); err != nil {
@@ -499,6 +631,17 @@ func getBlkIORecorder(path string) (map[string]bool, error) {
 	return recorder, nil
 }
 
+func getIOMaxRecorder(path string) (map[string]bool, error) {
+	fileNames := []string{
+		system.BlkioIOMaxName,
+	}
+	recorder, err := getDiskRecorder(path, fileNames)
+	if err != nil {
+		return nil, err
+	}
+	return recorder, nil
+}
+
 func getDiskConfigRecorder(path string) (map[string]bool, error) {
 	fileNames := []string{
 		system.BlkioIOQoSName,
@@ -516,31 +659,31 @@ func getBlkIORemoverFromDiskNumber(diskNumber string, dynamicPath string) (resou
 		system.BlkioTRIopsName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, DefaultReadIOPS),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioTRIopsName, fmt.Sprintf("%s %d", diskNumber, DefaultReadIOPS)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioTRIopsName, fmt.Sprintf("%s %d", diskNumber, DefaultReadIOPS)),
 	)
 	readBPSUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
 		system.BlkioTRBpsName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, DefaultReadBPS),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioTRBpsName, fmt.Sprintf("%s %d", diskNumber, DefaultReadBPS)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioTRBpsName, fmt.Sprintf("%s %d", diskNumber, DefaultReadBPS)),
 	)
 	writeIOPSUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
 		system.BlkioTWIopsName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, DefaultWriteIOPS),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioTWIopsName, fmt.Sprintf("%s %d", diskNumber, DefaultWriteIOPS)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioTWIopsName, fmt.Sprintf("%s %d", diskNumber, DefaultWriteIOPS)),
 	)
 	writeBPSUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
 		system.BlkioTWBpsName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, DefaultWriteBPS),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioTWBpsName, fmt.Sprintf("%s %d", diskNumber, DefaultWriteBPS)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioTWBpsName, fmt.Sprintf("%s %d", diskNumber, DefaultWriteBPS)),
 	)
 	ioWeightUpdater, _ := resourceexecutor.NewBlkIOResourceUpdater(
 		system.BlkioIOWeightName,
 		dynamicPath,
 		fmt.Sprintf("%s %d", diskNumber, DefaultIOWeightPercentage),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioIOWeightName, fmt.Sprintf("%s %d", diskNumber, DefaultIOWeightPercentage)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioIOWeightName, fmt.Sprintf("%s %d", diskNumber, DefaultIOWeightPercentage)),
 	)
 
 	resources = append(resources,
@@ -559,7 +702,7 @@ func getDiskConfigRemoverFromDiskNumber(diskNumber string, dynamicPath string) (
 		system.BlkioIOQoSName,
 		dynamicPath,
 		fmt.Sprintf("%s enable=0", diskNumber),
-		audit.V(3).Group("blkio").Reason("UpdateBlkIO").Message("update %s/%s to %s", dynamicPath, system.BlkioIOQoSName, fmt.Sprintf("%s enable=0", diskNumber)),
+		audit.V(3).Group("blkio").Reason(reasonUpdateBlkIO).Message("update %s/%s to %s", dynamicPath, system.BlkioIOQoSName, fmt.Sprintf("%s enable=0", diskNumber)),
 	)
 	resources = append(resources, ioQoSUpdater)
 	return
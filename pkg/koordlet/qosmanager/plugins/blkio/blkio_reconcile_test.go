@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
@@ -36,6 +37,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util/cache"
 )
@@ -53,6 +55,11 @@ func TestBlkIOReconcile_reconcile(t *testing.T) {
 	sysFSRootDirName := BlkIOReconcileName
 
 	testingNodeSLO := newNodeSLO()
+	testingHostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-be-host-app",
+		QoS:  extension.QoSBE,
+	}
+	testingNodeSLO.Spec.HostApplications = []slov1alpha1.HostApplicationSpec{testingHostApp}
 	pod0 := newPodWithPVC(PodName0, PVCName)
 	pod1 := newPodWithEphemeralVolume(PodName1)
 	pod2 := newPodWithEphemeralVolume(PodName2)
@@ -160,6 +167,13 @@ func TestBlkIOReconcile_reconcile(t *testing.T) {
 		helper.WriteCgroupFileContents(pod2Dir, system.BlkioWriteIops, "253:16 2048")
 		helper.WriteCgroupFileContents(pod2Dir, system.BlkioReadBps, "253:16 10485760")
 		helper.WriteCgroupFileContents(pod2Dir, system.BlkioWriteBps, "253:16 10485760")
+		// host app
+		hostAppDir := util.GetHostAppCgroupRelativePath(&testingHostApp)
+		helper.WriteCgroupFileContents(hostAppDir, system.BlkioIOWeight, "253:16 100")
+		helper.WriteCgroupFileContents(hostAppDir, system.BlkioReadIops, "253:16 2048")
+		helper.WriteCgroupFileContents(hostAppDir, system.BlkioWriteIops, "253:16 2048")
+		helper.WriteCgroupFileContents(hostAppDir, system.BlkioReadBps, "253:16 10485760")
+		helper.WriteCgroupFileContents(hostAppDir, system.BlkioWriteBps, "253:16 10485760")
 		defer helper.Cleanup()
 
 		bi := New(opt)
@@ -176,6 +190,9 @@ func TestBlkIOReconcile_reconcile(t *testing.T) {
 			b.executor.Run(stop)
 		}
 		b.reconcile()
+		// be host app follows the be class config
+		assert.Equal(t, "253:16 1024", helper.ReadCgroupFileContents(hostAppDir, system.BlkioReadIops))
+		assert.Equal(t, "253:16 1048576", helper.ReadCgroupFileContents(hostAppDir, system.BlkioWriteBps))
 	})
 }
 
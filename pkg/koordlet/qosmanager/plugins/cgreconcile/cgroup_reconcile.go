@@ -17,11 +17,13 @@ limitations under the License.
 package cgreconcile
 
 import (
+	"fmt"
 	"math"
 	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
@@ -123,18 +125,37 @@ func (m *cgroupResourcesReconcile) calculateAndUpdateResources(nodeSLO *slov1alp
 	node := m.statesInformer.GetNode()
 	if node == nil || node.Status.Allocatable == nil {
 		klog.Errorf("failed to calculate resources, err: node is invalid: %v", util.DumpJSON(node))
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(CgroupReconcileName, slov1alpha1.StrategyConditionError,
+			"node is invalid")
 		return
 	}
 	podMetas := m.statesInformer.GetAllPods()
 
 	// calculate qos-level, pod-level and container-level resources
 	qosResources, podResources, containerResources := m.calculateResources(nodeSLO.Spec.ResourceQOSStrategy, node, podMetas)
+	// host applications are regarded as the same level as pods
+	podResources = append(podResources, m.calculateHostAppsResources(nodeSLO.Spec.ResourceQOSStrategy, nodeSLO.Spec.HostApplications)...)
 
 	// to make sure the hierarchical cgroup resources are correctly updated, we simply update the resources by
 	// cgroup-level order.
 	// e.g. /kubepods.slice/memory.min, /kubepods.slice-podxxx/memory.min, /kubepods.slice-podxxx/docker-yyy/memory.min
 	leveledResources := [][]resourceexecutor.ResourceUpdater{qosResources, podResources, containerResources}
-	m.executor.LeveledUpdateBatch(leveledResources)
+	if err := m.executor.LeveledUpdateBatch(leveledResources); err != nil {
+		klog.V(4).Infof("failed to update cgroup resources, err: %v", err)
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(CgroupReconcileName, slov1alpha1.StrategyConditionError,
+			getUpdateFailureMessage(err))
+		return
+	}
+	statesinformer.DefaultStrategyStatusRecorder.SetCondition(CgroupReconcileName, slov1alpha1.StrategyConditionApplied, "")
+}
+
+// getUpdateFailureMessage summarizes the failed updates with the first one to keep the condition message short.
+func getUpdateFailureMessage(err error) string {
+	agg, ok := err.(utilerrors.Aggregate)
+	if !ok || len(agg.Errors()) <= 0 {
+		return err.Error()
+	}
+	return fmt.Sprintf("failed to update %v cgroup resources, e.g. %v", len(agg.Errors()), agg.Errors()[0])
 }
 
 // calculateResources calculates qos-level, pod-level and container-level resources with nodeCfg and podMetas
@@ -207,6 +228,33 @@ func (m *cgroupResourcesReconcile) calculateQoSResources(summary *cgroupResource
 	return makeCgroupResources(qosDir, summary)
 }
 
+// calculateHostAppsResources calculates the resources of host applications according to their declared qos.
+// Only the statically configured values are applied since host applications do not declare resource requests.
+func (m *cgroupResourcesReconcile) calculateHostAppsResources(nodeCfg *slov1alpha1.ResourceQOSStrategy,
+	hostApps []slov1alpha1.HostApplicationSpec) []resourceexecutor.ResourceUpdater {
+	var hostAppResources []resourceexecutor.ResourceUpdater
+	for i := range hostApps {
+		hostApp := &hostApps[i]
+		hostAppCfg := helpers.GetHostAppResourceQoS(hostApp, nodeCfg)
+		if hostAppCfg == nil || hostAppCfg.MemoryQOS == nil {
+			klog.V(5).Infof("skip calculate cgroup resources for host app %s since config is empty", hostApp.Name)
+			continue
+		}
+
+		summary := &cgroupResourceSummary{
+			memoryWmarkRatio:       hostAppCfg.MemoryQOS.WmarkRatio,
+			memoryWmarkScaleFactor: hostAppCfg.MemoryQOS.WmarkScalePermill,
+			memoryWmarkMinAdj:      hostAppCfg.MemoryQOS.WmarkMinAdj,
+			memoryUsePriorityOom:   hostAppCfg.MemoryQOS.PriorityEnable,
+			memoryPriority:         hostAppCfg.MemoryQOS.Priority,
+			memoryOomKillGroup:     hostAppCfg.MemoryQOS.OomKillGroup,
+		}
+		hostAppDir := koordletutil.GetHostAppCgroupRelativePath(hostApp)
+		hostAppResources = append(hostAppResources, makeCgroupResources(hostAppDir, summary)...)
+	}
+	return hostAppResources
+}
+
 func (m *cgroupResourcesReconcile) calculatePodAndContainerResources(podMeta *statesinformer.PodMeta, node *corev1.Node,
 	podCfg *slov1alpha1.ResourceQOS) (podResources, containerResources []resourceexecutor.ResourceUpdater) {
 	pod := podMeta.Pod
@@ -1182,6 +1182,66 @@ func Test_makeCgroupResources(t *testing.T) {
 	}
 }
 
+func TestCgroupResourcesReconcile_calculateHostAppsResources(t *testing.T) {
+	lsHostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-ls-host-app",
+		QoS:  apiext.QoSLS,
+	}
+	beHostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-be-host-app",
+		QoS:  apiext.QoSBE,
+		Strategy: &slov1alpha1.HostApplicationStrategy{
+			ResourceQOS: &slov1alpha1.ResourceQOS{
+				MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+					MemoryQOS: slov1alpha1.MemoryQOS{
+						WmarkRatio: pointer.Int64(80),
+					},
+				},
+			},
+		},
+	}
+	noneHostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-none-host-app",
+	}
+	nodeCfg := &slov1alpha1.ResourceQOSStrategy{
+		LSClass: &slov1alpha1.ResourceQOS{
+			MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+				MemoryQOS: slov1alpha1.MemoryQOS{
+					MinLimitPercent: pointer.Int64(100),
+					WmarkRatio:      pointer.Int64(95),
+				},
+			},
+		},
+		BEClass: &slov1alpha1.ResourceQOS{
+			MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+				MemoryQOS: slov1alpha1.MemoryQOS{
+					WmarkRatio:  pointer.Int64(90),
+					WmarkMinAdj: pointer.Int64(50),
+				},
+			},
+		},
+	}
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldIsAnolisOS := system.HostSystemInfo.IsAnolisOS
+	system.HostSystemInfo.IsAnolisOS = true
+	defer func() {
+		system.HostSystemInfo.IsAnolisOS = oldIsAnolisOS
+	}()
+
+	m := newTestCgroupResourcesReconcile(&framework.Options{Config: framework.NewDefaultConfig()})
+	got := m.calculateHostAppsResources(nodeCfg, []slov1alpha1.HostApplicationSpec{lsHostApp, beHostApp, noneHostApp})
+	lsHostAppDir := koordletutil.GetHostAppCgroupRelativePath(&lsHostApp)
+	beHostAppDir := koordletutil.GetHostAppCgroupRelativePath(&beHostApp)
+	want := []resourceexecutor.ResourceUpdater{
+		createCgroupResourceUpdater(t, system.MemoryWmarkRatioName, lsHostAppDir, "95", false),
+		createCgroupResourceUpdater(t, system.MemoryWmarkRatioName, beHostAppDir, "80", false),
+		createCgroupResourceUpdater(t, system.MemoryWmarkMinAdjName, beHostAppDir, "50", false),
+	}
+	assertCgroupResourceEqual(t, want, got)
+}
+
 func newTestCgroupResourcesReconcile(opt *framework.Options) *cgroupResourcesReconcile {
 	return &cgroupResourcesReconcile{
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
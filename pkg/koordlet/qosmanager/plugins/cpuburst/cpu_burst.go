@@ -46,6 +46,9 @@ import (
 const (
 	CPUBurstName = "CPUBurst"
 
+	reasonCPUBurst      = "CPUBurst"
+	reasonCFSQuotaBurst = "CFSQuotaBurst"
+
 	cfsIncreaseStep = 1.2
 	cfsDecreaseStep = 0.8
 
@@ -173,6 +176,8 @@ type cpuBurst struct {
 	cgroupReader          resourceexecutor.CgroupReader
 	nodeCPUBurstStrategy  *slov1alpha1.CPUBurstStrategy
 	containerLimiter      map[string]*burstLimiter
+	// nodeSLOEnabled rolls back the resource updates once the NodeSLO disables the cpu burst of the node
+	nodeSLOEnabled framework.NodeSLOEnabledState
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -191,6 +196,10 @@ func (b *cpuBurst) Enabled() bool {
 	return features.DefaultKoordletFeatureGate.Enabled(features.CPUBurst) && b.reconcileInterval > 0
 }
 
+func (b *cpuBurst) RollbackReasons() []string {
+	return []string{reasonCPUBurst, reasonCFSQuotaBurst}
+}
+
 func (b *cpuBurst) Setup(ctx *framework.Context) {
 
 }
@@ -215,6 +224,11 @@ func (b *cpuBurst) start() {
 		return
 	}
 	b.nodeCPUBurstStrategy = nodeSLO.Spec.CPUBurstStrategy
+	// the pods enabling the burst by their annotations are burst again after the rollback
+	nodeBurstPolicy := b.nodeCPUBurstStrategy.Policy
+	if b.nodeSLOEnabled.TurnDisabled(cpuBurstEnabled(nodeBurstPolicy) || cfsQuotaBurstEnabled(nodeBurstPolicy)) {
+		framework.RollbackStrategyResources(CPUBurstName, b)
+	}
 	podsMeta := b.statesInformer.GetAllPods()
 
 	// get node state by node share pool usage
@@ -251,6 +265,7 @@ func (b *cpuBurst) start() {
 		// scale cpu.cfs_quota_us for pod and containers
 		b.applyCFSQuotaBurst(cpuBurstCfg, podMeta, nodeState)
 	}
+	b.applyHostAppsCPUBurst(nodeSLO.Spec.HostApplications)
 	b.Recycle()
 }
 
@@ -380,26 +395,16 @@ func (b *cpuBurst) applyCFSQuotaBurst(burstCfg *slov1alpha1.CPUBurstConfig, podM
 			containerCeilCFS = int64(float64(containerBaseCFS) * float64(*burstCfg.CFSQuotaBurstPercent) / 100)
 		}
 
-		originOperation := b.genOperationByContainer(burstCfg, pod, container, containerStat)
-		klog.V(6).Infof("cfs burst operation for container %v/%v/%v is %v",
-			pod.Namespace, pod.Name, containerStat.Name, originOperation)
-
-		changed, finalOperation := changeOperationByNode(nodeState, originOperation)
-		if changed {
-			klog.Infof("node is in %v state, switch origin scale operation %v to %v",
-				nodeState, originOperation.String(), finalOperation.String())
+		var containerTargetCFS int64
+		var finalOperation cfsOperation
+		if burstCfg.Policy == slov1alpha1.CPUBurstAdaptive {
+			containerTargetCFS, finalOperation = b.genAdaptiveCFSQuota(burstCfg, container, containerStat,
+				containerBaseCFS, containerCurCFS, containerCeilCFS, nodeState)
+			klog.V(6).Infof("adaptive cfs burst operation for container %v/%v/%v is %v, node state %v",
+				pod.Namespace, pod.Name, containerStat.Name, finalOperation, nodeState)
 		} else {
-			klog.V(5).Infof("node is in %v state, operation %v is same as before %v",
-				nodeState, finalOperation.String(), originOperation.String())
-		}
-
-		containerTargetCFS := containerCurCFS
-		if finalOperation == cfsScaleUp {
-			containerTargetCFS = int64(float64(containerCurCFS) * cfsIncreaseStep)
-		} else if finalOperation == cfsScaleDown {
-			containerTargetCFS = int64(float64(containerCurCFS) * cfsDecreaseStep)
-		} else if finalOperation == cfsReset {
-			containerTargetCFS = containerBaseCFS
+			containerTargetCFS, finalOperation = b.genCFSQuotaByOperation(burstCfg, pod, container, containerStat,
+				containerBaseCFS, containerCurCFS, nodeState)
 		}
 		containerTargetCFS = util.MaxInt64(containerBaseCFS, util.MinInt64(containerTargetCFS, containerCeilCFS))
 
@@ -421,6 +426,34 @@ func (b *cpuBurst) applyCFSQuotaBurst(burstCfg *slov1alpha1.CPUBurstConfig, podM
 	} // end for containers
 }
 
+// genCFSQuotaByOperation scales the current cfs quota of the container step by step according to the operation
+// generated by container throttled state and node state
+func (b *cpuBurst) genCFSQuotaByOperation(burstCfg *slov1alpha1.CPUBurstConfig, pod *corev1.Pod, container *corev1.Container,
+	containerStat *corev1.ContainerStatus, containerBaseCFS, containerCurCFS int64, nodeState nodeStateForBurst) (int64, cfsOperation) {
+	originOperation := b.genOperationByContainer(burstCfg, pod, container, containerStat)
+	klog.V(6).Infof("cfs burst operation for container %v/%v/%v is %v",
+		pod.Namespace, pod.Name, containerStat.Name, originOperation)
+
+	changed, finalOperation := changeOperationByNode(nodeState, originOperation)
+	if changed {
+		klog.Infof("node is in %v state, switch origin scale operation %v to %v",
+			nodeState, originOperation.String(), finalOperation.String())
+	} else {
+		klog.V(5).Infof("node is in %v state, operation %v is same as before %v",
+			nodeState, finalOperation.String(), originOperation.String())
+	}
+
+	containerTargetCFS := containerCurCFS
+	if finalOperation == cfsScaleUp {
+		containerTargetCFS = int64(float64(containerCurCFS) * cfsIncreaseStep)
+	} else if finalOperation == cfsScaleDown {
+		containerTargetCFS = int64(float64(containerCurCFS) * cfsDecreaseStep)
+	} else if finalOperation == cfsReset {
+		containerTargetCFS = containerBaseCFS
+	}
+	return containerTargetCFS, finalOperation
+}
+
 // check if cfs burst for container is allowed by limiter config, return true if allowed
 func (b *cpuBurst) cfsBurstAllowedByLimiter(burstCfg *slov1alpha1.CPUBurstConfig, container *corev1.Container,
 	containerID *string) bool {
@@ -521,7 +554,7 @@ func (b *cpuBurst) applyContainerCFSQuota(podMeta *statesinformer.PodMeta, conta
 
 		targetPodCFS := curPodCFS + deltaContainerCFS
 		podCFSValStr := strconv.FormatInt(targetPodCFS, 10)
-		eventHelper := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason("CFSQuotaBurst").Message("update pod CFSQuota: %v", podCFSValStr)
+		eventHelper := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason(reasonCFSQuotaBurst).Message("update pod CFSQuota: %v", podCFSValStr)
 		updater, _ := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, podDir, podCFSValStr, eventHelper)
 		if _, err := b.executor.Update(true, updater); err != nil {
 			return fmt.Errorf("update pod cgroup %v failed, error %v", podMeta.CgroupDir, err)
@@ -533,7 +566,7 @@ func (b *cpuBurst) applyContainerCFSQuota(podMeta *statesinformer.PodMeta, conta
 	updateContainerCFSQuota := func() error {
 		targetContainerCFS := curContaienrCFS + deltaContainerCFS
 		containerCFSValStr := strconv.FormatInt(targetContainerCFS, 10)
-		eventHelper := audit.V(3).Container(containerStat.Name).Reason("CFSQuotaBurst").Message("update container CFSQuota: %v", containerCFSValStr)
+		eventHelper := audit.V(3).Container(containerStat.Name).Reason(reasonCFSQuotaBurst).Message("update container CFSQuota: %v", containerCFSValStr)
 		updater, _ := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, containerDir, containerCFSValStr, eventHelper)
 		if _, err := b.executor.Update(true, updater); err != nil {
 			return fmt.Errorf("update container cgroup %v failed, reason %v", containerDir, err)
@@ -582,7 +615,12 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 			continue
 		}
 
-		containerCFSBurstVal := calcStaticCPUBurstVal(container, burstCfg)
+		var containerCFSBurstVal int64
+		if burstCfg.Policy == slov1alpha1.CPUBurstAdaptive {
+			containerCFSBurstVal = b.calcAdaptiveCPUBurstVal(container, containerStat, burstCfg)
+		} else {
+			containerCFSBurstVal = calcStaticCPUBurstVal(container, burstCfg)
+		}
 		containerDir, burstPathErr := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
 		if burstPathErr != nil {
 			klog.Warningf("get container dir %s/%s/%s failed, dir %v, error %v",
@@ -592,7 +630,7 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 
 		podCFSBurstVal += containerCFSBurstVal
 		containerCFSBurstValStr := strconv.FormatInt(containerCFSBurstVal, 10)
-		eventHelper := audit.V(3).Container(containerStat.Name).Reason("CPUBurst").Message("update container CPUBurst: %v", containerCFSBurstValStr)
+		eventHelper := audit.V(3).Container(containerStat.Name).Reason(reasonCPUBurst).Message("update container CPUBurst: %v", containerCFSBurstValStr)
 		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUBurstName, containerDir, containerCFSBurstValStr, eventHelper)
 		if err != nil { // normally cpu burst resource not supported on current system
 			klog.V(5).Infof("get cpu burst updater for container %s/%s/%s failed, maybe system unsupported, err: %v",
@@ -615,7 +653,7 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 
 	podDir := podMeta.CgroupDir
 	podCFSBurstValStr := strconv.FormatInt(podCFSBurstVal, 10)
-	eventHelper := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason("CPUBurst").Message("update pod CFSQuota: %v", podCFSBurstValStr)
+	eventHelper := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason(reasonCPUBurst).Message("update pod CFSQuota: %v", podCFSBurstValStr)
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUBurstName, podDir, podCFSBurstValStr, eventHelper)
 	if err != nil { // normally cpu burst resource not supported on current system
 		klog.V(5).Infof("get cpu burst updater for pod %s/%s failed, maybe system unsupported, err: %v",
@@ -635,6 +673,48 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 	}
 }
 
+// set cpu.cfs_burst_us for LS host applications, which is statically calculated with the cfs quota of the host app
+func (b *cpuBurst) applyHostAppsCPUBurst(hostApps []slov1alpha1.HostApplicationSpec) {
+	for i := range hostApps {
+		hostApp := &hostApps[i]
+		if hostApp.QoS != apiext.QoSLS {
+			// ignore non-burstable host app, e.g. LSR, BE host apps
+			continue
+		}
+		burstCfg := genHostAppBurstConfig(hostApp, &b.nodeCPUBurstStrategy.CPUBurstConfig)
+		hostAppDir := koordletutil.GetHostAppCgroupRelativePath(hostApp)
+
+		cfsBurstVal := int64(0)
+		if cpuBurstEnabled(burstCfg.Policy) && burstCfg.CPUBurstPercent != nil {
+			cfsQuota, err := b.cgroupReader.ReadCPUQuota(hostAppDir)
+			if err != nil {
+				klog.V(4).Infof("get cfs quota of host app %s failed, dir %v, error %v", hostApp.Name, hostAppDir, err)
+				continue
+			}
+			if cfsQuota > 0 {
+				cfsBurstVal = cfsQuota * (*burstCfg.CPUBurstPercent) / 100
+			}
+		}
+
+		cfsBurstValStr := strconv.FormatInt(cfsBurstVal, 10)
+		eventHelper := audit.V(3).Reason(reasonCPUBurst).Message("update host app %s CPUBurst: %v", hostApp.Name, cfsBurstValStr)
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUBurstName, hostAppDir, cfsBurstValStr, eventHelper)
+		if err != nil { // normally cpu burst resource not supported on current system
+			klog.V(5).Infof("get cpu burst updater for host app %s failed, maybe system unsupported, err: %v",
+				hostApp.Name, err)
+			continue
+		}
+		updated, err := b.executor.Update(true, updater)
+		if err != nil {
+			klog.V(4).Infof("update host app %v cpu burst failed, dir %v, updated %v, err %v",
+				hostApp.Name, hostAppDir, updated, err)
+		} else {
+			klog.V(5).Infof("apply host app %v cpu burst value successfully, dir %v, value %v",
+				hostApp.Name, hostAppDir, cfsBurstValStr)
+		}
+	}
+}
+
 func (b *cpuBurst) Recycle() {
 	for key, limiter := range b.containerLimiter {
 		if limiter.Expire() {
@@ -690,12 +770,25 @@ func genPodBurstConfig(pod *corev1.Pod, nodeCfg *slov1alpha1.CPUBurstConfig) *sl
 	return out
 }
 
+// use node config by default, overlap if host app specify config
+func genHostAppBurstConfig(hostApp *slov1alpha1.HostApplicationSpec, nodeCfg *slov1alpha1.CPUBurstConfig) *slov1alpha1.CPUBurstConfig {
+	if hostApp.Strategy == nil || hostApp.Strategy.CPUBurstConfig == nil {
+		return nodeCfg
+	}
+	hostAppCfgData, _ := json.Marshal(hostApp.Strategy.CPUBurstConfig)
+	out := nodeCfg.DeepCopy()
+	_ = json.Unmarshal(hostAppCfgData, &out)
+	return out
+}
+
 func cpuBurstEnabled(burstPolicy slov1alpha1.CPUBurstPolicy) bool {
-	return burstPolicy == slov1alpha1.CPUBurstAuto || burstPolicy == slov1alpha1.CPUBurstOnly
+	return burstPolicy == slov1alpha1.CPUBurstAuto || burstPolicy == slov1alpha1.CPUBurstOnly ||
+		burstPolicy == slov1alpha1.CPUBurstAdaptive
 }
 
 func cfsQuotaBurstEnabled(burstPolicy slov1alpha1.CPUBurstPolicy) bool {
-	return burstPolicy == slov1alpha1.CPUBurstAuto || burstPolicy == slov1alpha1.CFSQuotaBurstOnly
+	return burstPolicy == slov1alpha1.CPUBurstAuto || burstPolicy == slov1alpha1.CFSQuotaBurstOnly ||
+		burstPolicy == slov1alpha1.CPUBurstAdaptive
 }
 
 func changeOperationByNode(nodeState nodeStateForBurst, originOperation cfsOperation) (bool, cfsOperation) {
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuburst
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// containerBurstProfile is the recent throttled ratio and cpu usage of a container, used for CPUBurstAdaptive policy
+type containerBurstProfile struct {
+	// average throttled ratio in the history window, in range [0, 1]
+	throttledRatio float64
+	// p99 cpu usage in the history window, unit: cores
+	usageP99 float64
+}
+
+// burstFactor returns the burst demand of the container in range [0, 1], which grows linearly with the throttled ratio
+// and reaches 1 when the throttled ratio exceeds the threshold
+func (p *containerBurstProfile) burstFactor(throttledRatioThresholdPercent *int64) float64 {
+	if p == nil || p.throttledRatio <= 0 || throttledRatioThresholdPercent == nil || *throttledRatioThresholdPercent <= 0 {
+		return 0
+	}
+	return math.Min(1, p.throttledRatio*100/float64(*throttledRatioThresholdPercent))
+}
+
+func (b *cpuBurst) getContainerBurstProfile(containerID string, burstCfg *slov1alpha1.CPUBurstConfig) (*containerBurstProfile, error) {
+	if burstCfg.HistoryWindowSeconds == nil || *burstCfg.HistoryWindowSeconds <= 0 {
+		return nil, fmt.Errorf("illegal history window %v", burstCfg.HistoryWindowSeconds)
+	}
+	window := time.Duration(*burstCfg.HistoryWindowSeconds) * time.Second
+
+	throttledResult, err := helpers.CollectContainerThrottledMetric(b.metricCache, &containerID, window)
+	if err != nil {
+		return nil, fmt.Errorf("query throttled metric failed, error: %v", err)
+	}
+	if throttledResult.Count() == 0 {
+		return nil, fmt.Errorf("throttled metric is empty")
+	}
+	throttledRatio, err := throttledResult.Value(metriccache.AggregationTypeAVG)
+	if err != nil {
+		return nil, fmt.Errorf("get throttled ratio failed, error: %v", err)
+	}
+
+	queryMeta, err := metriccache.ContainerCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Container(containerID))
+	if err != nil {
+		return nil, fmt.Errorf("build cpu usage queryMeta failed, error: %v", err)
+	}
+	end := time.Now()
+	querier, err := b.metricCache.Querier(end.Add(-window), end)
+	if err != nil {
+		return nil, fmt.Errorf("get querier failed, error: %v", err)
+	}
+	usageResult := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+	if err = querier.Query(queryMeta, nil, usageResult); err != nil {
+		return nil, fmt.Errorf("query cpu usage failed, error: %v", err)
+	}
+	if usageResult.Count() == 0 {
+		return nil, fmt.Errorf("cpu usage metric is empty")
+	}
+	usageP99, err := usageResult.Value(metriccache.AggregationTypeP99)
+	if err != nil {
+		return nil, fmt.Errorf("get cpu usage p99 failed, error: %v", err)
+	}
+
+	return &containerBurstProfile{
+		throttledRatio: throttledRatio,
+		usageP99:       usageP99,
+	}, nil
+}
+
+// container cpu.cfs_burst_us = container.limit * burstCfg.CPUBurstPercent * burstFactor * cfs_period_us,
+// so that the containers frequently throttled get more burst while the idle ones get none
+func (b *cpuBurst) calcAdaptiveCPUBurstVal(container *corev1.Container, containerStat *corev1.ContainerStatus,
+	burstCfg *slov1alpha1.CPUBurstConfig) int64 {
+	staticBurstVal := calcStaticCPUBurstVal(container, burstCfg)
+	if staticBurstVal <= 0 {
+		return 0
+	}
+	profile, err := b.getContainerBurstProfile(containerStat.ContainerID, burstCfg)
+	if err != nil {
+		klog.V(5).Infof("get container %s burst profile failed, set cpu burst as 0, reason %v", containerStat.Name, err)
+		return 0
+	}
+	factor := profile.burstFactor(burstCfg.ThrottledRatioThresholdPercent)
+	klog.V(6).Infof("container %s burst profile %+v, factor %v", containerStat.Name, *profile, factor)
+	return int64(float64(staticBurstVal) * factor)
+}
+
+// genAdaptiveCFSQuota calculates the target cfs quota of the container for CPUBurstAdaptive policy.
+// The desired quota = base quota * (usage p99 / limit) * (1 + burstFactor), which is in range [base, ceil].
+// The quota is only scaled up when the share pool is idle, keeps no higher than current when cooling,
+// and scales down step by step when overload.
+func (b *cpuBurst) genAdaptiveCFSQuota(burstCfg *slov1alpha1.CPUBurstConfig, container *corev1.Container,
+	containerStat *corev1.ContainerStatus, baseCFS, curCFS, ceilCFS int64, nodeState nodeStateForBurst) (int64, cfsOperation) {
+	if !b.cfsBurstAllowedByLimiter(burstCfg, container, &containerStat.ContainerID) {
+		return util.MaxInt64(baseCFS, int64(float64(curCFS)*cfsDecreaseStep)), cfsScaleDown
+	}
+	if nodeState == nodeBurstOverload {
+		return util.MaxInt64(baseCFS, int64(float64(curCFS)*cfsDecreaseStep)), cfsScaleDown
+	}
+
+	profile, err := b.getContainerBurstProfile(containerStat.ContainerID, burstCfg)
+	if err != nil {
+		klog.V(4).Infof("get container %s burst profile failed, skip this round, reason %v", containerStat.Name, err)
+		return curCFS, cfsRemain
+	}
+	factor := profile.burstFactor(burstCfg.ThrottledRatioThresholdPercent)
+	limitCores := float64(baseCFS) / float64(system.CFSBasePeriodValue)
+	desiredCFS := int64(float64(baseCFS) * (profile.usageP99 / limitCores) * (1 + factor))
+	desiredCFS = util.MaxInt64(baseCFS, util.MinInt64(desiredCFS, ceilCFS))
+	klog.V(6).Infof("container %s burst profile %+v, factor %v, desired cfs quota %v",
+		containerStat.Name, *profile, factor, desiredCFS)
+
+	if nodeState != nodeBurstIdle {
+		// cooling or unknown, scale up is not allowed
+		desiredCFS = util.MinInt64(desiredCFS, curCFS)
+	}
+	if desiredCFS > curCFS {
+		return desiredCFS, cfsScaleUp
+	} else if desiredCFS < curCFS {
+		return desiredCFS, cfsScaleDown
+	}
+	return desiredCFS, cfsRemain
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuburst
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+)
+
+func newTestAdaptiveCPUBurst(t *testing.T, throttledRatio, usage []float64) (*cpuBurst, func()) {
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	now := time.Now()
+	var samples []metriccache.MetricSample
+	for i, v := range throttledRatio {
+		sample, err := metriccache.ContainerCPUThrottledMetric.GenerateSample(metriccache.MetricPropertiesFunc.Container("test-container-id"),
+			now.Add(-time.Duration(len(throttledRatio)-i)*time.Second), v)
+		assert.NoError(t, err)
+		samples = append(samples, sample)
+	}
+	for i, v := range usage {
+		sample, err := metriccache.ContainerCPUUsageMetric.GenerateSample(metriccache.MetricPropertiesFunc.Container("test-container-id"),
+			now.Add(-time.Duration(len(usage)-i)*time.Second), v)
+		assert.NoError(t, err)
+		samples = append(samples, sample)
+	}
+	if len(samples) > 0 {
+		appender := metricCache.Appender()
+		assert.NoError(t, appender.Append(samples))
+		assert.NoError(t, appender.Commit())
+	}
+	b := &cpuBurst{
+		metricCache:      metricCache,
+		containerLimiter: make(map[string]*burstLimiter),
+	}
+	return b, func() {
+		metricCache.Close()
+	}
+}
+
+func Test_containerBurstProfile_burstFactor(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   *containerBurstProfile
+		threshold *int64
+		want      float64
+	}{
+		{
+			name:      "nil profile",
+			profile:   nil,
+			threshold: pointer.Int64(10),
+			want:      0,
+		},
+		{
+			name:      "nil threshold",
+			profile:   &containerBurstProfile{throttledRatio: 0.05},
+			threshold: nil,
+			want:      0,
+		},
+		{
+			name:      "not throttled",
+			profile:   &containerBurstProfile{throttledRatio: 0},
+			threshold: pointer.Int64(10),
+			want:      0,
+		},
+		{
+			name:      "throttled below the threshold",
+			profile:   &containerBurstProfile{throttledRatio: 0.05},
+			threshold: pointer.Int64(10),
+			want:      0.5,
+		},
+		{
+			name:      "throttled above the threshold",
+			profile:   &containerBurstProfile{throttledRatio: 0.3},
+			threshold: pointer.Int64(10),
+			want:      1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.profile.burstFactor(tt.threshold), 1e-6)
+		})
+	}
+}
+
+func TestCPUBurst_calcAdaptiveCPUBurstVal(t *testing.T) {
+	container := &corev1.Container{
+		Name: "test-container",
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("2"),
+			},
+		},
+	}
+	containerStat := &corev1.ContainerStatus{
+		Name:        "test-container",
+		ContainerID: "test-container-id",
+	}
+	burstCfg := defaultAutoBurstCfg.DeepCopy()
+	burstCfg.Policy = slov1alpha1.CPUBurstAdaptive
+	burstCfg.ThrottledRatioThresholdPercent = pointer.Int64(10)
+	burstCfg.HistoryWindowSeconds = pointer.Int64(300)
+	tests := []struct {
+		name           string
+		throttledRatio []float64
+		usage          []float64
+		want           int64
+	}{
+		{
+			name: "no metrics",
+			want: 0,
+		},
+		{
+			name:           "not throttled",
+			throttledRatio: []float64{0, 0, 0},
+			usage:          []float64{0.5, 0.6, 0.4},
+			want:           0,
+		},
+		{
+			name:           "throttled partially",
+			throttledRatio: []float64{0.04, 0.05, 0.06},
+			usage:          []float64{1.5, 2, 1.8},
+			want:           1000000,
+		},
+		{
+			name:           "throttled heavily",
+			throttledRatio: []float64{0.2, 0.3, 0.1},
+			usage:          []float64{1.9, 2, 2},
+			want:           2000000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, cleanup := newTestAdaptiveCPUBurst(t, tt.throttledRatio, tt.usage)
+			defer cleanup()
+			got := b.calcAdaptiveCPUBurstVal(container, containerStat, burstCfg)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCPUBurst_genAdaptiveCFSQuota(t *testing.T) {
+	container := &corev1.Container{
+		Name: "test-container",
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("2"),
+			},
+		},
+	}
+	containerStat := &corev1.ContainerStatus{
+		Name:        "test-container",
+		ContainerID: "test-container-id",
+	}
+	burstCfg := defaultAutoBurstCfg.DeepCopy()
+	burstCfg.Policy = slov1alpha1.CPUBurstAdaptive
+	burstCfg.ThrottledRatioThresholdPercent = pointer.Int64(10)
+	burstCfg.HistoryWindowSeconds = pointer.Int64(300)
+	type args struct {
+		curCFS    int64
+		nodeState nodeStateForBurst
+	}
+	tests := []struct {
+		name           string
+		throttledRatio []float64
+		usage          []float64
+		args           args
+		wantCFS        int64
+		wantOperation  cfsOperation
+	}{
+		{
+			name: "no metrics, keep current",
+			args: args{
+				curCFS:    300000,
+				nodeState: nodeBurstIdle,
+			},
+			wantCFS:       300000,
+			wantOperation: cfsRemain,
+		},
+		{
+			name:           "heavily throttled and node idle, scale up",
+			throttledRatio: []float64{0.1, 0.2, 0.1},
+			usage:          []float64{2, 2, 2},
+			args: args{
+				curCFS:    200000,
+				nodeState: nodeBurstIdle,
+			},
+			wantCFS:       400000,
+			wantOperation: cfsScaleUp,
+		},
+		{
+			name:           "slightly throttled with low usage, keep base",
+			throttledRatio: []float64{0.05, 0.05, 0.05},
+			usage:          []float64{1, 1, 1},
+			args: args{
+				curCFS:    200000,
+				nodeState: nodeBurstIdle,
+			},
+			wantCFS:       200000,
+			wantOperation: cfsRemain,
+		},
+		{
+			name:           "not throttled any more, scale down to the usage",
+			throttledRatio: []float64{0, 0, 0},
+			usage:          []float64{3, 3, 3},
+			args: args{
+				curCFS:    400000,
+				nodeState: nodeBurstIdle,
+			},
+			wantCFS:       300000,
+			wantOperation: cfsScaleDown,
+		},
+		{
+			name:           "heavily throttled but node cooling, not scale up",
+			throttledRatio: []float64{0.1, 0.2, 0.1},
+			usage:          []float64{2, 2, 2},
+			args: args{
+				curCFS:    300000,
+				nodeState: nodeBurstCooling,
+			},
+			wantCFS:       300000,
+			wantOperation: cfsRemain,
+		},
+		{
+			name:           "heavily throttled but node overload, scale down",
+			throttledRatio: []float64{0.1, 0.2, 0.1},
+			usage:          []float64{2, 2, 2},
+			args: args{
+				curCFS:    400000,
+				nodeState: nodeBurstOverload,
+			},
+			wantCFS:       320000,
+			wantOperation: cfsScaleDown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, cleanup := newTestAdaptiveCPUBurst(t, tt.throttledRatio, tt.usage)
+			defer cleanup()
+			gotCFS, gotOperation := b.genAdaptiveCFSQuota(burstCfg, container, containerStat, 200000, tt.args.curCFS, 600000, tt.args.nodeState)
+			assert.Equal(t, tt.wantCFS, gotCFS)
+			assert.Equal(t, tt.wantOperation, gotOperation)
+		})
+	}
+}
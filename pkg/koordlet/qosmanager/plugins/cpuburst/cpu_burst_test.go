@@ -700,6 +700,94 @@ func TestCPUBurst_applyCPUBurst(t *testing.T) {
 	}
 }
 
+func TestCPUBurst_applyHostAppsCPUBurst(t *testing.T) {
+	lsHostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-ls-host-app",
+		QoS:  apiext.QoSLS,
+	}
+	lsHostAppWithStrategy := slov1alpha1.HostApplicationSpec{
+		Name: "test-ls-host-app-with-strategy",
+		QoS:  apiext.QoSLS,
+		Strategy: &slov1alpha1.HostApplicationStrategy{
+			CPUBurstConfig: &slov1alpha1.CPUBurstConfig{
+				CPUBurstPercent: pointer.Int64(200),
+			},
+		},
+	}
+	lsrHostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-lsr-host-app",
+		QoS:  apiext.QoSLSR,
+	}
+	tests := []struct {
+		name          string
+		hostApps      []slov1alpha1.HostApplicationSpec
+		nodeBurstCfg  slov1alpha1.CPUBurstConfig
+		curCFSQuota   int64
+		wantBurstVals map[string]int64
+	}{
+		{
+			name:         "apply burst for ls host apps with node config and strategy",
+			hostApps:     []slov1alpha1.HostApplicationSpec{lsHostApp, lsHostAppWithStrategy, lsrHostApp},
+			nodeBurstCfg: defaultAutoBurstCfg,
+			curCFSQuota:  200000,
+			wantBurstVals: map[string]int64{
+				lsHostApp.Name:             2000000,
+				lsHostAppWithStrategy.Name: 400000,
+				lsrHostApp.Name:            0,
+			},
+		},
+		{
+			name:         "reset burst for unlimited host app",
+			hostApps:     []slov1alpha1.HostApplicationSpec{lsHostApp},
+			nodeBurstCfg: defaultAutoBurstCfg,
+			curCFSQuota:  -1,
+			wantBurstVals: map[string]int64{
+				lsHostApp.Name: 0,
+			},
+		},
+		{
+			name:     "reset burst when cpu burst is disabled",
+			hostApps: []slov1alpha1.HostApplicationSpec{lsHostApp},
+			nodeBurstCfg: slov1alpha1.CPUBurstConfig{
+				Policy:          slov1alpha1.CPUBurstNone,
+				CPUBurstPercent: pointer.Int64(100),
+			},
+			curCFSQuota: 200000,
+			wantBurstVals: map[string]int64{
+				lsHostApp.Name: 0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHelper := system.NewFileTestUtil(t)
+			defer testHelper.Cleanup()
+			for i := range tt.hostApps {
+				hostAppDir := util.GetHostAppCgroupRelativePath(&tt.hostApps[i])
+				testHelper.WriteCgroupFileContents(hostAppDir, system.CPUCFSQuota, strconv.FormatInt(tt.curCFSQuota, 10))
+				testHelper.WriteCgroupFileContents(hostAppDir, system.CPUBurst, "0")
+			}
+
+			b := &cpuBurst{
+				executor:             newTestExecutor(),
+				cgroupReader:         resourceexecutor.NewCgroupReader(),
+				nodeCPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{CPUBurstConfig: tt.nodeBurstCfg},
+			}
+			stop := make(chan struct{})
+			b.init(stop)
+			defer func() { stop <- struct{}{} }()
+
+			b.applyHostAppsCPUBurst(tt.hostApps)
+
+			for i := range tt.hostApps {
+				hostAppDir := util.GetHostAppCgroupRelativePath(&tt.hostApps[i])
+				got := testHelper.ReadCgroupFileContents(hostAppDir, system.CPUBurst)
+				assert.Equal(t, strconv.FormatInt(tt.wantBurstVals[tt.hostApps[i].Name], 10), got, tt.hostApps[i].Name)
+			}
+		})
+	}
+}
+
 func TestCPUBurst_applyCFSQuotaBurst(t *testing.T) {
 	testPodName1 := "test-pod-1"
 	testContainerName1 := "test-container-1"
@@ -1768,10 +1856,12 @@ func Test_genPodBurstConfigWithPlugin(t *testing.T) {
 				nodeCfg:        sloconfig.DefaultCPUBurstConfig(),
 			},
 			want: &slov1alpha1.CPUBurstConfig{
-				Policy:                     slov1alpha1.CPUBurstAuto,
-				CPUBurstPercent:            pointer.Int64(1000),
-				CFSQuotaBurstPercent:       pointer.Int64(300),
-				CFSQuotaBurstPeriodSeconds: pointer.Int64(-1),
+				Policy:                         slov1alpha1.CPUBurstAuto,
+				CPUBurstPercent:                pointer.Int64(1000),
+				CFSQuotaBurstPercent:           pointer.Int64(300),
+				CFSQuotaBurstPeriodSeconds:     pointer.Int64(-1),
+				ThrottledRatioThresholdPercent: pointer.Int64(10),
+				HistoryWindowSeconds:           pointer.Int64(300),
 			},
 		},
 	}
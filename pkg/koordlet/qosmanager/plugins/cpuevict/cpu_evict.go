@@ -36,6 +36,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
 )
 
 const (
@@ -349,12 +350,22 @@ func (c *cpuEvictor) getPodEvictInfoAndSort() []*podEvictCPUInfo {
 		}
 	}
 
-	sort.Slice(bePodInfos, func(i, j int) bool {
-		if bePodInfos[i].pod.Spec.Priority == nil || bePodInfos[j].pod.Spec.Priority == nil ||
-			*bePodInfos[i].pod.Spec.Priority == *bePodInfos[j].pod.Spec.Priority {
-			return bePodInfos[i].cpuUsage > bePodInfos[j].cpuUsage
+	cpuUsages := make(map[*corev1.Pod]float64, len(bePodInfos))
+	for _, info := range bePodInfos {
+		cpuUsages[info.pod] = info.cpuUsage
+	}
+	// the pods with higher cpu usage are preferred under the same priority
+	ranker := victim.NewRanker(victim.WithUsage(func(p1, p2 *corev1.Pod) int {
+		if cpuUsages[p1] == cpuUsages[p2] {
+			return 0
+		}
+		if cpuUsages[p1] > cpuUsages[p2] {
+			return -1
 		}
-		return *bePodInfos[i].pod.Spec.Priority < *bePodInfos[j].pod.Spec.Priority
+		return 1
+	}))
+	sort.SliceStable(bePodInfos, func(i, j int) bool {
+		return ranker.Compare(bePodInfos[i].pod, bePodInfos[j].pod) < 0
 	})
 	return bePodInfos
 }
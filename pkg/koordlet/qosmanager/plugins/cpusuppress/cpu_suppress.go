@@ -47,6 +47,8 @@ import (
 
 const (
 	CPUSuppressName = "CPUSuppresss"
+
+	reasonSuppressBECPU = "suppressBECPU"
 )
 
 var (
@@ -56,6 +58,8 @@ var (
 	cfsPeriod               int64 = 100000
 	beMinQuota              int64 = 2000
 	beMaxIncreaseCPUPercent       = 0.1 // scale up slow
+	// the ratio of the BE cfs quota split evenly across the BE cgroup dirs, the rest is split by their cpu usages
+	beQuotaEvenSplitRatio = 0.5
 )
 
 type suppressPolicyStatus string
@@ -75,6 +79,16 @@ type CPUSuppress struct {
 	executor               resourceexecutor.ResourceUpdateExecutor
 	cgroupReader           resourceexecutor.CgroupReader
 	suppressPolicyStatuses map[string]suppressPolicyStatus
+	// hostApps are the host applications of the latest NodeSLO, BE ones are suppressed along with the BE pods
+	hostApps []slov1alpha1.HostApplicationSpec
+	// beCPUUsages are the latest cpu usages in cores of the besteffort dir and the BE host app dirs out of it
+	beCPUUsages map[string]float64
+	// beHostAppDirs are the BE host app dirs limited by the cfs quota, reset once they are removed from the NodeSLO
+	beHostAppDirs map[string]bool
+	// latencyState is the state of the lsLatency policy
+	latencyState *lsLatencyState
+	// nodeSLOEnabled rolls back the resource updates once the NodeSLO disables the strategy
+	nodeSLOEnabled framework.NodeSLOEnabledState
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -93,6 +107,10 @@ func (r *CPUSuppress) Enabled() bool {
 	return features.DefaultKoordletFeatureGate.Enabled(features.BECPUSuppress) && r.interval > 0
 }
 
+func (r *CPUSuppress) RollbackReasons() []string {
+	return []string{resourceexecutor.AdjustBEByNodeCPUUsage, resourceexecutor.AdjustMidByNodeCPUUsage, reasonSuppressBECPU}
+}
+
 func (r *CPUSuppress) Setup(*framework.Context) {
 
 }
@@ -200,6 +218,7 @@ func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32)
 		klog.Warningf("applyCPUSetWithNonePolicy failed to get be cgroup cpuset paths, err: %s", err)
 		return fmt.Errorf("apply be suppress policy failed, err: %s", err)
 	}
+	cpusetCgroupPaths = append(cpusetCgroupPaths, r.getBEHostAppCgroupPaths()...)
 
 	// write a loose cpuset for all be cgroups before applying the real policy
 	mergedCPUSet := cpuset.MergeCPUSet(oldCPUSet, cpus)
@@ -228,6 +247,7 @@ func (r *CPUSuppress) applyCPUSetWithStaticPolicy(cpus []int32) error {
 		klog.Warningf("applyCPUSetWithStaticPolicy failed to get be cgroup cpuset paths, err: %s", err)
 		return fmt.Errorf("apply be suppress policy failed, err: %s", err)
 	}
+	containerPaths = append(containerPaths, r.getBEHostAppCgroupPaths()...)
 
 	cpusetStr := cpuset.GenerateCPUSetStr(cpus)
 	klog.V(6).Infof("applyCPUSetWithStaticPolicy writes suppressed cpuset to containers, cpuset %v", cpus)
@@ -248,8 +268,13 @@ func (r *CPUSuppress) suppressBECPU() {
 
 	// Step 0.
 	nodeSLO := r.statesInformer.GetNodeSLO()
+	if nodeSLO != nil {
+		r.hostApps = nodeSLO.Spec.HostApplications
+	}
 	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BECPUSuppress); err != nil {
 		klog.Warningf("suppressBECPU failed, cannot check the featuregate, err: %s", err)
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(CPUSuppressName, slov1alpha1.StrategyConditionError,
+			fmt.Sprintf("cannot check the featuregate, err: %s", err))
 		return
 	} else if features.DefaultKoordletFeatureGate.Enabled(features.BECPUSuppress) &&
 		features.DefaultKoordletFeatureGate.Enabled(features.BECPUManager) {
@@ -261,10 +286,18 @@ func (r *CPUSuppress) suppressBECPU() {
 	} else if disabled {
 		r.recoverCFSQuotaIfNeed()
 		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
+		// the batchresource runtime hook limits the koord-mid pods by the mid-cpu when the CPUSuppress is disabled
+		r.recoverMidCFSQuotaIfNeed(getMidTierPods(r.statesInformer.GetAllPods()), r.statesInformer.GetNode(), "")
+		if r.nodeSLOEnabled.TurnDisabled(false) {
+			framework.RollbackStrategyResources(CPUSuppressName, r)
+		}
 		klog.V(5).Infof("suppressBECPU skipped, nodeSLO disable the featuregate")
+		statesinformer.DefaultStrategyStatusRecorder.RemoveCondition(CPUSuppressName)
 		return
 	}
 
+	r.nodeSLOEnabled.TurnDisabled(true)
+
 	// Step 1.
 	node := r.statesInformer.GetNode()
 	if node == nil {
@@ -294,10 +327,18 @@ func (r *CPUSuppress) suppressBECPU() {
 	}
 	hostAppMetrics := helpers.CollectAllHostAppMetricsLast(nodeSLO.Spec.HostApplications, r.metricCache,
 		metriccache.HostAppCPUUsageMetric, r.metricCollectInterval)
+	r.beCPUUsages = getBECPUUsages(podMetas, podMetrics, nodeSLO.Spec.HostApplications, hostAppMetrics)
 
 	suppressCPUQuantity := r.calculateBESuppressCPU(node, nodeCPUUsage, podMetrics, podMetas,
 		nodeSLO.Spec.HostApplications, hostAppMetrics,
 		*nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent)
+	suppressPolicy := nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy
+	var suppressCPUUpperQuantity *resource.Quantity
+	if suppressPolicy == slov1alpha1.CPULSLatencyPolicy {
+		// the BE pods can use all the cpu not used by the others if the LS pods are not contended
+		suppressCPUUpperQuantity = r.calculateBESuppressCPU(node, nodeCPUUsage, podMetrics, podMetas,
+			nodeSLO.Spec.HostApplications, hostAppMetrics, 100)
+	}
 
 	// Step 2.
 	nodeCPUInfoRaw, exist := r.metricCache.Get(metriccache.NodeCPUInfoKey)
@@ -309,15 +350,30 @@ func (r *CPUSuppress) suppressBECPU() {
 	if !ok {
 		klog.Fatalf("type error, expect %T， but got %T", metriccache.NodeCPUInfo{}, nodeCPUInfoRaw)
 	}
-	if nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
+	if suppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
 		r.adjustByCfsQuota(suppressCPUQuantity, node)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyUsing
 		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
+	} else if suppressPolicy == slov1alpha1.CPULSLatencyPolicy {
+		r.adjustByLSLatency(suppressCPUQuantity, suppressCPUUpperQuantity, node, podMetas,
+			nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressLatencyConfig)
+		r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyUsing
+		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
 	} else {
 		r.adjustByCPUSet(suppressCPUQuantity, nodeCPUInfo)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyUsing
 		r.recoverCFSQuotaIfNeed()
 	}
+	if suppressPolicy != slov1alpha1.CPULSLatencyPolicy {
+		r.latencyState = nil
+	}
+	// the koord-mid pods are suppressed after the BE pods
+	midSuppressCPUQuantity := r.calculateMidSuppressCPU(node, nodeCPUUsage, podMetrics, podMetas,
+		nodeSLO.Spec.HostApplications, hostAppMetrics,
+		*nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent)
+	r.suppressMidCPU(suppressCPUQuantity, midSuppressCPUQuantity, podMetas, node, suppressPolicy)
+	statesinformer.DefaultStrategyStatusRecorder.SetCondition(CPUSuppressName, slov1alpha1.StrategyConditionApplied,
+		fmt.Sprintf("suppress policy %s", suppressPolicy))
 }
 
 func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUInfo *metriccache.NodeCPUInfo) {
@@ -486,6 +542,7 @@ func (r *CPUSuppress) recoverCPUSetForBECPUManager() {
 	cpusetToRecover := make([]string, 0, len(cpusetPathOfContainerWithoutSpecified)+len(cpusetPathOfAllBEPods))
 	cpusetToRecover = append(cpusetToRecover, cpusetPathOfAllBEPods...)
 	cpusetToRecover = append(cpusetToRecover, cpusetPathOfContainerWithoutSpecified...)
+	cpusetToRecover = append(cpusetToRecover, r.getBEHostAppCgroupPaths()...)
 
 	cpusetStr := beCPUSet.String()
 	klog.V(5).Infof("recover bestEffort cpuset with be cpu manager, cpuset %v", cpusetStr)
@@ -508,6 +565,7 @@ func (r *CPUSuppress) recoverCPUSetIfNeed(maxDepth int) {
 		klog.Warningf("recover bestEffort cpuset failed, get be cgroup cpuset paths  err: %s", err)
 		return
 	}
+	cpusetCgroupPaths = append(cpusetCgroupPaths, r.getBEHostAppCgroupPaths()...)
 
 	cpusetStr := beCPUSet.String()
 	klog.V(6).Infof("recover bestEffort cpuset, cpuset %v", cpusetStr)
@@ -597,10 +655,25 @@ func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *cor
 		klog.Warningf("suppressBECPU fail:get currentBeQuota fail,error: %v", err)
 		return
 	}
+	// the BE host apps out of the besteffort dir share the quota with the BE pods
+	hostAppDirs := r.getBEHostAppCgroupPaths()
+	if currentBeQuota > 0 {
+		for _, hostAppDir := range hostAppDirs {
+			hostAppQuota, err := r.cgroupReader.ReadCPUQuota(hostAppDir)
+			if err != nil {
+				klog.V(4).Infof("suppressBECPU: failed to read cfs quota of BE host app %s, err: %v", hostAppDir, err)
+				continue
+			}
+			if hostAppQuota > 0 {
+				currentBeQuota += hostAppQuota
+			}
+		}
+	}
 
 	minQuotaDelta := float64(node.Status.Capacity.Cpu().Value()) * float64(cfsPeriod) * suppressBypassQuotaDeltaRatio
-	//  delta is large enough
-	if math.Abs(float64(newBeQuota)-float64(currentBeQuota)) < minQuotaDelta && newBeQuota != beMinQuota {
+	//  delta is large enough, or the BE host apps are changed
+	if math.Abs(float64(newBeQuota)-float64(currentBeQuota)) < minQuotaDelta && newBeQuota != beMinQuota &&
+		!r.isBEHostAppsChanged(hostAppDirs) {
 		klog.Infof("suppressBECPU: quota delta is too small, bypass suppress.reason: current quota: %d, target quota: %d, min quota delta: %f",
 			currentBeQuota, newBeQuota, minQuotaDelta)
 		return
@@ -611,8 +684,9 @@ func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *cor
 		newBeQuota = currentBeQuota + int64(beMaxIncreaseCPUQuota)
 	}
 
-	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", newBeQuota)
-	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, beCgroupPath, strconv.FormatInt(newBeQuota, 10), eventHelper)
+	quotas := splitBECFSQuota(newBeQuota, append([]string{beCgroupPath}, hostAppDirs...), r.beCPUUsages)
+	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", quotas[beCgroupPath])
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, beCgroupPath, strconv.FormatInt(quotas[beCgroupPath], 10), eventHelper)
 	if err != nil {
 		klog.V(4).Infof("failed to get be cfs quota updater, err: %v", err)
 		return
@@ -622,6 +696,8 @@ func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *cor
 		klog.Errorf("suppressBECPU: failed to write cfs_quota_us for be pods, error: %v", err)
 		return
 	}
+	delete(quotas, beCgroupPath)
+	r.updateBEHostAppCFSQuota(quotas)
 	metrics.RecordBESuppressCores(string(slov1alpha1.CPUCfsQuotaPolicy), float64(newBeQuota)/float64(cfsPeriod))
 	_ = audit.V(1).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", newBeQuota).Do()
 	klog.Infof("suppressBECPU: succeeded to write cfs_quota_us for offline pods, isUpdated %v, new value: %d", isUpdated, newBeQuota)
@@ -634,7 +710,7 @@ func (r *CPUSuppress) recoverCFSQuotaIfNeed() {
 	}
 
 	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
-	eventHelper := audit.V(3).Reason("suppressBECPU").Message("recover bestEffort cfsQuota, isUpdated %v", "-1")
+	eventHelper := audit.V(3).Reason(reasonSuppressBECPU).Message("recover bestEffort cfsQuota, isUpdated %v", "-1")
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, beCgroupPath, "-1", eventHelper)
 	if err != nil {
 		klog.V(4).Infof("failed to get be cfs quota updater, err: %v", err)
@@ -645,10 +721,131 @@ func (r *CPUSuppress) recoverCFSQuotaIfNeed() {
 		klog.Errorf("recover bestEffort cfsQuota err: %v", err)
 		return
 	}
+	hostAppQuotas := map[string]int64{}
+	for _, hostAppDir := range r.getBEHostAppCgroupPaths() {
+		hostAppQuotas[hostAppDir] = -1
+	}
+	r.updateBEHostAppCFSQuota(hostAppQuotas)
 	klog.V(5).Infof("successfully recover bestEffort cfsQuota, isUpdated %v", isUpdated)
 	r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyRecovered
 }
 
+// getBEHostAppCgroupPaths returns the cgroup dirs of the BE host applications which are not under the besteffort dir,
+// since the suppression on the besteffort dir does not cover them.
+func (r *CPUSuppress) getBEHostAppCgroupPaths() []string {
+	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	var paths []string
+	for i := range r.hostApps {
+		hostApp := &r.hostApps[i]
+		if hostApp.QoS != apiext.QoSBE {
+			continue
+		}
+		hostAppDir := koordletutil.GetHostAppCgroupRelativePath(hostApp)
+		if strings.HasPrefix(hostAppDir, beCgroupPath) {
+			continue
+		}
+		paths = append(paths, hostAppDir)
+	}
+	return paths
+}
+
+// updateBEHostAppCFSQuota writes the cfs quotas of the BE host applications out of the besteffort dir, and resets the
+// ones limited before but no longer BE host applications in the NodeSLO.
+func (r *CPUSuppress) updateBEHostAppCFSQuota(quotas map[string]int64) {
+	if r.beHostAppDirs == nil {
+		r.beHostAppDirs = map[string]bool{}
+	}
+	for hostAppDir := range r.beHostAppDirs {
+		if _, ok := quotas[hostAppDir]; !ok {
+			quotas[hostAppDir] = -1
+		}
+	}
+	for hostAppDir, quota := range quotas {
+		cfsQuota := strconv.FormatInt(quota, 10)
+		eventHelper := audit.V(3).Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE host app %v to cfs_quota: %v", hostAppDir, cfsQuota)
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, hostAppDir, cfsQuota, eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to get cfs quota updater for BE host app %s, err: %v", hostAppDir, err)
+			continue
+		}
+		if _, err = r.executor.Update(false, updater); err != nil {
+			klog.Warningf("failed to write cfs_quota_us for BE host app %s, error: %v", hostAppDir, err)
+		}
+		// a removed host app is not retried since its cgroup dir can be gone
+		if quota > 0 {
+			r.beHostAppDirs[hostAppDir] = true
+		} else {
+			delete(r.beHostAppDirs, hostAppDir)
+		}
+	}
+}
+
+// isBEHostAppsChanged checks if the given BE host app dirs differ from the ones limited by the cfs quota.
+func (r *CPUSuppress) isBEHostAppsChanged(hostAppDirs []string) bool {
+	if len(hostAppDirs) != len(r.beHostAppDirs) {
+		return true
+	}
+	for _, hostAppDir := range hostAppDirs {
+		if !r.beHostAppDirs[hostAppDir] {
+			return true
+		}
+	}
+	return false
+}
+
+// getBECPUUsages returns the cpu usages of the besteffort dir and the BE host app dirs out of it, where the usage of
+// the besteffort dir includes the BE pods and the BE host apps under it.
+func getBECPUUsages(podMetas []*statesinformer.PodMeta, podMetrics map[string]float64,
+	hostApps []slov1alpha1.HostApplicationSpec, hostAppMetrics map[string]float64) map[string]float64 {
+	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	usages := map[string]float64{beCgroupPath: 0}
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil || helpers.NonBEPodFilter(podMeta.Pod) {
+			continue
+		}
+		usages[beCgroupPath] += podMetrics[string(podMeta.Pod.UID)]
+	}
+	for i := range hostApps {
+		hostApp := &hostApps[i]
+		if helpers.NonBEHostAppFilter(hostApp) {
+			continue
+		}
+		hostAppDir := koordletutil.GetHostAppCgroupRelativePath(hostApp)
+		if strings.HasPrefix(hostAppDir, beCgroupPath) {
+			hostAppDir = beCgroupPath
+		}
+		usages[hostAppDir] += hostAppMetrics[hostApp.Name]
+	}
+	return usages
+}
+
+// splitBECFSQuota splits the cfs quota of the BE suppression across the BE cgroup dirs, so the BE pods and the BE host
+// apps cannot use more cpu than the quota in total. A part of the quota is split evenly to keep room for the idle
+// dirs to grow, and the rest is split by the cpu usages. Each dir gets no less than the min quota.
+func splitBECFSQuota(quota int64, dirs []string, usages map[string]float64) map[string]int64 {
+	quotas := make(map[string]int64, len(dirs))
+	if len(dirs) == 1 {
+		quotas[dirs[0]] = quota
+		return quotas
+	}
+	totalUsage := 0.0
+	for _, dir := range dirs {
+		totalUsage += usages[dir]
+	}
+	evenRatio := beQuotaEvenSplitRatio
+	if totalUsage <= 0 {
+		evenRatio = 1
+	}
+	for _, dir := range dirs {
+		share := evenRatio / float64(len(dirs))
+		if totalUsage > 0 {
+			share += (1 - evenRatio) * usages[dir] / totalUsage
+		}
+		quotas[dir] = int64(math.Max(float64(quota)*share, float64(beMinQuota)))
+	}
+	return quotas
+}
+
 // calculateBESuppressPolicy calculates the be cpu suppress policy with cpuset cpus number and node cpu info
 func calculateBESuppressCPUSetPolicy(cpus int32, processorInfos []koordletutil.ProcessorInfo) []int32 {
 	var CPUSets []int32
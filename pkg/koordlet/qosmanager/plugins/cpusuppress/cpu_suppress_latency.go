@@ -0,0 +1,263 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"fmt"
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+// lsLatencyMaxSampledTasks is the max number of the LS tasks whose schedstat is read in one round, which bounds the
+// cost of the run queue latency signal on the nodes running many LS threads
+var lsLatencyMaxSampledTasks = 256
+
+// lsLatencyState records the signals and the control errors of the last rounds for the lsLatency policy
+type lsLatencyState struct {
+	// key is the task id of the LS containers
+	lastTaskSchedStats map[int32]*system.TaskSchedStat
+	// the control errors of the last two rounds, lastErrors[0] is the latest
+	lastErrors []float64
+}
+
+// adjustByLSLatency adjusts the cfs quota of the BE pods with a PID controller on the LS contention signals.
+// The cpu of BE pods is bounded by the upper quantity, i.e. the cpu not used by the others. It falls back to the
+// suppression by node utilization when the LS signals are unavailable.
+func (r *CPUSuppress) adjustByLSLatency(fallbackQuantity, upperQuantity *resource.Quantity, node *corev1.Node,
+	podMetas []*statesinformer.PodMeta, latencyCfg *slov1alpha1.CPUSuppressLatencyConfig) {
+	if r.latencyState == nil {
+		r.latencyState = &lsLatencyState{}
+	}
+	cfg := mergeCPUSuppressLatencyConfig(latencyCfg)
+	controlErr, err := r.getLSLatencyError(podMetas, cfg)
+	if err != nil {
+		klog.V(4).Infof("suppressBECPU: failed to get ls latency signals, fallback to suppress by node utilization, err: %v", err)
+		r.latencyState.lastErrors = nil
+		r.adjustByCfsQuota(fallbackQuantity, node)
+		return
+	}
+	r.adjustByCfsQuota(r.calculateBESuppressCPUByPID(controlErr, upperQuantity, node, cfg), node)
+}
+
+// calculateBESuppressCPUByPID calculates the cpu of BE pods with an incremental PID controller:
+// delta = -(Kp * (e[k] - e[k-1]) + Ki * e[k] + Kd * (e[k] - 2 * e[k-1] + e[k-2])) * node.Capacity,
+// where the positive error means the LS pods are contended.
+func (r *CPUSuppress) calculateBESuppressCPUByPID(controlErr float64, upperQuantity *resource.Quantity,
+	node *corev1.Node, cfg *slov1alpha1.CPUSuppressLatencyConfig) *resource.Quantity {
+	upperCPU := float64(upperQuantity.MilliValue()) / 1000
+	minCPU := float64(beMinQuota) / float64(cfsPeriod)
+	capacityCPU := float64(node.Status.Capacity.Cpu().MilliValue()) / 1000
+
+	currentCPU := upperCPU
+	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	if currentBeQuota, err := r.cgroupReader.ReadCPUQuota(beCgroupPath); err != nil {
+		klog.V(4).Infof("suppressBECPU: failed to get current be cfs quota, use the upper, err: %v", err)
+	} else if currentBeQuota > 0 {
+		currentCPU = float64(currentBeQuota) / float64(cfsPeriod)
+	}
+
+	lastErr, lastLastErr := controlErr, controlErr
+	if len(r.latencyState.lastErrors) > 0 {
+		lastErr, lastLastErr = r.latencyState.lastErrors[0], r.latencyState.lastErrors[0]
+	}
+	if len(r.latencyState.lastErrors) > 1 {
+		lastLastErr = r.latencyState.lastErrors[1]
+	}
+	r.latencyState.lastErrors = []float64{controlErr, lastErr}
+
+	output := float64(*cfg.ProportionalGainPercent)*(controlErr-lastErr) +
+		float64(*cfg.IntegralGainPercent)*controlErr +
+		float64(*cfg.DerivativeGainPercent)*(controlErr-2*lastErr+lastLastErr)
+	targetCPU := currentCPU - output/100*capacityCPU
+	targetCPU = math.Max(minCPU, math.Min(targetCPU, upperCPU))
+	klog.V(5).Infof("suppressBECPU: ls latency control error %v, pid output %v%%, be cpu %v -> %v, upper %v",
+		controlErr, output, currentCPU, targetCPU, upperCPU)
+
+	return resource.NewMilliQuantity(int64(targetCPU*1000), resource.DecimalSI)
+}
+
+// getLSLatencyError returns the control error in range [-1, 1], which is the max relative deviation of the LS
+// signals from their targets. It returns -1 if there is no LS pod.
+func (r *CPUSuppress) getLSLatencyError(podMetas []*statesinformer.PodMeta, cfg *slov1alpha1.CPUSuppressLatencyConfig) (float64, error) {
+	var lsPodMetas []*statesinformer.PodMeta
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil || podMeta.Pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		qosClass := apiext.GetPodQoSClassWithDefault(podMeta.Pod)
+		if qosClass == apiext.QoSLSE || qosClass == apiext.QoSLSR || qosClass == apiext.QoSLS {
+			lsPodMetas = append(lsPodMetas, podMeta)
+		}
+	}
+	if len(lsPodMetas) <= 0 {
+		klog.V(5).Infof("suppressBECPU: no running ls pod, no need to suppress by ls latency")
+		return -1, nil
+	}
+
+	controlErr, hasSignal := -1.0, false
+	if target := *cfg.LSCPUPressureTargetPercent; target > 0 {
+		if pressure, err := r.getLSCPUPressure(lsPodMetas); err != nil {
+			klog.V(5).Infof("suppressBECPU: failed to get ls cpu pressure, err: %v", err)
+		} else {
+			controlErr, hasSignal = math.Max(controlErr, relativeError(pressure, float64(target))), true
+			klog.V(6).Infof("suppressBECPU: ls cpu pressure %v%%, target %v%%", pressure, target)
+		}
+	}
+	if target := *cfg.LSRunQueueLatencyTargetMicroSeconds; target > 0 {
+		if latency, err := r.getLSRunQueueLatency(lsPodMetas); err != nil {
+			klog.V(5).Infof("suppressBECPU: failed to get ls run queue latency, err: %v", err)
+		} else {
+			controlErr, hasSignal = math.Max(controlErr, relativeError(latency, float64(target))), true
+			klog.V(6).Infof("suppressBECPU: ls run queue latency %vus, target %vus", latency, target)
+		}
+	} else {
+		r.latencyState.lastTaskSchedStats = nil
+	}
+	if !hasSignal {
+		return 0, fmt.Errorf("no ls signal available")
+	}
+	return controlErr, nil
+}
+
+// getLSCPUPressure returns the max cpu pressure (some avg10) of the LS pods
+func (r *CPUSuppress) getLSCPUPressure(lsPodMetas []*statesinformer.PodMeta) (float64, error) {
+	pressure, found := 0.0, false
+	for _, podMeta := range lsPodMetas {
+		queryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodPSI(string(podMeta.Pod.UID),
+			string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)))
+		if err != nil {
+			return 0, err
+		}
+		value, err := helpers.CollectPodMetricLast(r.metricCache, queryMeta, r.metricCollectInterval)
+		if err != nil {
+			klog.V(6).Infof("suppressBECPU: failed to get cpu pressure of pod %s, err: %v", util.GetPodKey(podMeta.Pod), err)
+			continue
+		}
+		pressure, found = math.Max(pressure, value), true
+	}
+	if !found {
+		return 0, fmt.Errorf("cpu pressure of ls pods not found")
+	}
+	return pressure, nil
+}
+
+// getLSRunQueueLatency returns the average run queue latency per timeslice of the sampled LS tasks since the last
+// round, unit: microseconds
+func (r *CPUSuppress) getLSRunQueueLatency(lsPodMetas []*statesinformer.PodMeta) (float64, error) {
+	lastStats := r.latencyState.lastTaskSchedStats
+	curStats := map[int32]*system.TaskSchedStat{}
+	var waitTime, timeslices uint64
+	for _, taskID := range r.sampleLSTasks(lsPodMetas) {
+		stat, err := system.ReadTaskSchedStat(taskID)
+		if err != nil {
+			continue
+		}
+		curStats[taskID] = stat
+		lastStat, ok := lastStats[taskID]
+		if !ok || stat.WaitTime < lastStat.WaitTime || stat.Timeslices < lastStat.Timeslices {
+			continue
+		}
+		waitTime += stat.WaitTime - lastStat.WaitTime
+		timeslices += stat.Timeslices - lastStat.Timeslices
+	}
+	r.latencyState.lastTaskSchedStats = curStats
+	if timeslices <= 0 {
+		return 0, fmt.Errorf("no timeslice of ls tasks since the last round")
+	}
+	return float64(waitTime) / float64(timeslices) / 1000, nil
+}
+
+// sampleLSTasks returns at most lsLatencyMaxSampledTasks tasks of the LS containers. The tasks sampled in the last
+// round are kept first so their schedstat deltas can be calculated, and the rest are picked from the containers in
+// turn, so one container with many threads cannot take up the whole sample.
+func (r *CPUSuppress) sampleLSTasks(lsPodMetas []*statesinformer.PodMeta) []int32 {
+	lastStats := r.latencyState.lastTaskSchedStats
+	var sampled []int32
+	var containerTasks [][]int32
+	for _, podMeta := range lsPodMetas {
+		for i := range podMeta.Pod.Status.ContainerStatuses {
+			containerStat := &podMeta.Pod.Status.ContainerStatuses[i]
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+			if err != nil {
+				continue
+			}
+			taskIDs, err := r.cgroupReader.ReadCPUTasks(containerDir)
+			if err != nil {
+				klog.V(6).Infof("suppressBECPU: failed to get tasks of container %s/%s, err: %v",
+					util.GetPodKey(podMeta.Pod), containerStat.Name, err)
+				continue
+			}
+			var newTasks []int32
+			for _, taskID := range taskIDs {
+				if _, ok := lastStats[taskID]; ok && len(sampled) < lsLatencyMaxSampledTasks {
+					sampled = append(sampled, taskID)
+				} else {
+					newTasks = append(newTasks, taskID)
+				}
+			}
+			containerTasks = append(containerTasks, newTasks)
+		}
+	}
+	for i := 0; len(sampled) < lsLatencyMaxSampledTasks; i++ {
+		picked := false
+		for _, taskIDs := range containerTasks {
+			if i >= len(taskIDs) {
+				continue
+			}
+			sampled = append(sampled, taskIDs[i])
+			picked = true
+			if len(sampled) >= lsLatencyMaxSampledTasks {
+				break
+			}
+		}
+		if !picked {
+			break
+		}
+	}
+	return sampled
+}
+
+// relativeError returns (value - target) / target in range [-1, 1]
+func relativeError(value, target float64) float64 {
+	return math.Max(-1, math.Min(1, (value-target)/target))
+}
+
+func mergeCPUSuppressLatencyConfig(cfg *slov1alpha1.CPUSuppressLatencyConfig) *slov1alpha1.CPUSuppressLatencyConfig {
+	defaultCfg := sloconfig.DefaultCPUSuppressLatencyConfig()
+	if cfg == nil {
+		return defaultCfg
+	}
+	merged, err := util.MergeCfg(defaultCfg, cfg.DeepCopy())
+	if err != nil {
+		klog.V(4).Infof("suppressBECPU: failed to merge ls latency config, use the default, err: %v", err)
+		return defaultCfg
+	}
+	return merged.(*slov1alpha1.CPUSuppressLatencyConfig)
+}
@@ -0,0 +1,335 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_cpuSuppress_calculateBESuppressCPUByPID(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	beQosDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node0",
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("10"),
+			},
+		},
+	}
+	cfg := mergeCPUSuppressLatencyConfig(nil)
+	upperQuantity := resource.NewMilliQuantity(8*1000, resource.DecimalSI)
+	tests := []struct {
+		name          string
+		preBECfsQuota int64
+		lastErrors    []float64
+		controlErr    float64
+		want          int64
+		wantErrors    []float64
+	}{
+		{
+			name:          "contended at the first round, suppress by integral",
+			preBECfsQuota: -1,
+			controlErr:    0.5,
+			want:          7500,
+			wantErrors:    []float64{0.5, 0.5},
+		},
+		{
+			name:          "contended more, suppress by proportional and integral",
+			preBECfsQuota: 7 * cfsPeriod,
+			lastErrors:    []float64{0.5, 0.5},
+			controlErr:    1,
+			want:          5000,
+			wantErrors:    []float64{1, 0.5},
+		},
+		{
+			name:          "not contended, relax no more than the upper",
+			preBECfsQuota: 5 * cfsPeriod,
+			lastErrors:    []float64{1, 0.5},
+			controlErr:    -1,
+			want:          8000,
+			wantErrors:    []float64{-1, 1},
+		},
+		{
+			name:          "heavily contended, suppress no less than the min",
+			preBECfsQuota: cfsPeriod / 10,
+			lastErrors:    []float64{0, 0},
+			controlErr:    1,
+			want:          beMinQuota * 1000 / cfsPeriod,
+			wantErrors:    []float64{1, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper.WriteCgroupFileContents(beQosDir, system.CPUCFSQuota, strconv.FormatInt(tt.preBECfsQuota, 10))
+			r := &CPUSuppress{
+				cgroupReader: resourceexecutor.NewCgroupReader(),
+				latencyState: &lsLatencyState{
+					lastErrors: tt.lastErrors,
+				},
+			}
+			got := r.calculateBESuppressCPUByPID(tt.controlErr, upperQuantity, node, cfg)
+			assert.Equal(t, tt.want, got.MilliValue())
+			assert.Equal(t, tt.wantErrors, r.latencyState.lastErrors)
+		})
+	}
+}
+
+func Test_cpuSuppress_getLSLatencyError(t *testing.T) {
+	lsPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ls-pod",
+			Namespace: "default",
+			UID:       "ls-pod-uid",
+			Labels: map[string]string{
+				apiext.LabelPodQoS: string(apiext.QoSLS),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: "containerd://ls-pod-container",
+				},
+			},
+		},
+	}
+	lsPodMeta := &statesinformer.PodMeta{
+		Pod:       lsPod,
+		CgroupDir: koordletutil.GetPodCgroupParentDir(lsPod),
+	}
+	bePod := lsPod.DeepCopy()
+	bePod.UID = "be-pod-uid"
+	bePod.Labels[apiext.LabelPodQoS] = string(apiext.QoSBE)
+	bePodMeta := &statesinformer.PodMeta{
+		Pod:       bePod,
+		CgroupDir: koordletutil.GetPodCgroupParentDir(bePod),
+	}
+	type fields struct {
+		cpuPressure        float64
+		lastTaskSchedStats map[int32]*system.TaskSchedStat
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		podMetas []*statesinformer.PodMeta
+		cfg      *slov1alpha1.CPUSuppressLatencyConfig
+		want     float64
+		wantErr  bool
+	}{
+		{
+			name:     "no ls pod",
+			podMetas: []*statesinformer.PodMeta{bePodMeta},
+			want:     -1,
+		},
+		{
+			name:     "no signal available",
+			podMetas: []*statesinformer.PodMeta{lsPodMeta, bePodMeta},
+			wantErr:  true,
+		},
+		{
+			name: "cpu pressure exceeds the target",
+			fields: fields{
+				cpuPressure: 10,
+			},
+			podMetas: []*statesinformer.PodMeta{lsPodMeta, bePodMeta},
+			want:     1,
+		},
+		{
+			name: "run queue latency exceeds the target",
+			fields: fields{
+				lastTaskSchedStats: map[int32]*system.TaskSchedStat{
+					101: {RunTime: 1000000, WaitTime: 1000000, Timeslices: 100},
+					102: {RunTime: 1000000, WaitTime: 1000000, Timeslices: 100},
+				},
+			},
+			podMetas: []*statesinformer.PodMeta{lsPodMeta, bePodMeta},
+			cfg: &slov1alpha1.CPUSuppressLatencyConfig{
+				LSCPUPressureTargetPercent: pointer.Int64(0),
+			},
+			want: 0.5,
+		},
+		{
+			name: "use the max error of the signals",
+			fields: fields{
+				cpuPressure: 2.5,
+				lastTaskSchedStats: map[int32]*system.TaskSchedStat{
+					101: {RunTime: 1000000, WaitTime: 1000000, Timeslices: 100},
+					102: {RunTime: 1000000, WaitTime: 1000000, Timeslices: 100},
+				},
+			},
+			podMetas: []*statesinformer.PodMeta{lsPodMeta, bePodMeta},
+			want:     0.5,
+		},
+		{
+			name: "run queue latency is disabled",
+			fields: fields{
+				cpuPressure: 2.5,
+				lastTaskSchedStats: map[int32]*system.TaskSchedStat{
+					101: {RunTime: 1000000, WaitTime: 1000000, Timeslices: 100},
+					102: {RunTime: 1000000, WaitTime: 1000000, Timeslices: 100},
+				},
+			},
+			podMetas: []*statesinformer.PodMeta{lsPodMeta, bePodMeta},
+			cfg: &slov1alpha1.CPUSuppressLatencyConfig{
+				LSRunQueueLatencyTargetMicroSeconds: pointer.Int64(0),
+			},
+			want: -0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(lsPodMeta.CgroupDir, &lsPod.Status.ContainerStatuses[0])
+			assert.NoError(t, err)
+			helper.WriteCgroupFileContents(containerDir, system.CPUTasks, "101\n102\n")
+			// wait 3000us per timeslice in average
+			helper.WriteProcSubFileContents("101/schedstat", "2000000 301000000 200")
+			helper.WriteProcSubFileContents("102/schedstat", "2000000 301000000 200")
+
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              t.TempDir(),
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer func() {
+				metricCache.Close()
+			}()
+			if tt.fields.cpuPressure > 0 {
+				sample, err := metriccache.PodPSIMetric.GenerateSample(metriccache.MetricPropertiesFunc.PodPSI(string(lsPod.UID),
+					string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)),
+					time.Now(), tt.fields.cpuPressure)
+				assert.NoError(t, err)
+				appender := metricCache.Appender()
+				assert.NoError(t, appender.Append([]metriccache.MetricSample{sample}))
+				assert.NoError(t, appender.Commit())
+			}
+
+			r := &CPUSuppress{
+				metricCollectInterval: time.Minute,
+				metricCache:           metricCache,
+				cgroupReader:          resourceexecutor.NewCgroupReader(),
+				latencyState: &lsLatencyState{
+					lastTaskSchedStats: tt.fields.lastTaskSchedStats,
+				},
+			}
+			got, gotErr := r.getLSLatencyError(tt.podMetas, mergeCPUSuppressLatencyConfig(tt.cfg))
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.InDelta(t, tt.want, got, 1e-6)
+		})
+	}
+}
+
+func Test_cpuSuppress_sampleLSTasks(t *testing.T) {
+	lsPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ls-pod",
+			Namespace: "default",
+			UID:       "ls-pod-uid",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: "containerd://ls-pod-container",
+				},
+				{
+					Name:        "test-container-1",
+					ContainerID: "containerd://ls-pod-container-1",
+				},
+			},
+		},
+	}
+	lsPodMeta := &statesinformer.PodMeta{
+		Pod:       lsPod,
+		CgroupDir: koordletutil.GetPodCgroupParentDir(lsPod),
+	}
+	tests := []struct {
+		name               string
+		maxSampledTasks    int
+		lastTaskSchedStats map[int32]*system.TaskSchedStat
+		want               []int32
+	}{
+		{
+			name:            "sample all tasks under the limit",
+			maxSampledTasks: 10,
+			want:            []int32{101, 201, 102, 202, 103, 104, 105},
+		},
+		{
+			name:            "pick the containers in turn",
+			maxSampledTasks: 4,
+			want:            []int32{101, 201, 102, 202},
+		},
+		{
+			name:            "keep the tasks sampled in the last round",
+			maxSampledTasks: 3,
+			lastTaskSchedStats: map[int32]*system.TaskSchedStat{
+				104: {},
+				105: {},
+			},
+			want: []int32{104, 105, 101},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(lsPodMeta.CgroupDir, &lsPod.Status.ContainerStatuses[0])
+			assert.NoError(t, err)
+			helper.WriteCgroupFileContents(containerDir, system.CPUTasks, "101\n102\n103\n104\n105\n")
+			containerDir1, err := koordletutil.GetContainerCgroupParentDir(lsPodMeta.CgroupDir, &lsPod.Status.ContainerStatuses[1])
+			assert.NoError(t, err)
+			helper.WriteCgroupFileContents(containerDir1, system.CPUTasks, "201\n202\n")
+
+			oldMaxSampledTasks := lsLatencyMaxSampledTasks
+			lsLatencyMaxSampledTasks = tt.maxSampledTasks
+			defer func() {
+				lsLatencyMaxSampledTasks = oldMaxSampledTasks
+			}()
+			r := &CPUSuppress{
+				cgroupReader: resourceexecutor.NewCgroupReader(),
+				latencyState: &lsLatencyState{
+					lastTaskSchedStats: tt.lastTaskSchedStats,
+				},
+			}
+			got := r.sampleLSTasks([]*statesinformer.PodMeta{lsPodMeta})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
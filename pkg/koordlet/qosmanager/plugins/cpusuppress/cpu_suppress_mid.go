@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"math"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// calculateMidSuppressCPU calculates the quantity of cpu for the koord-mid pods of the Mid tier.
+// suppress(Mid) := node.Capacity * SLOPercent - pod(non-BE, non-Mid).Used - hostApp(non-BE).Used - max(system.Used, node.reserved)
+func (r *CPUSuppress) calculateMidSuppressCPU(node *corev1.Node, nodeMetric float64, podMetrics map[string]float64,
+	podMetas []*statesinformer.PodMeta, hostApps []slov1alpha1.HostApplicationSpec,
+	hostAppMetrics map[string]float64, cpuUsedThreshold int64) *resource.Quantity {
+	nodeReserved := helpers.GetNodeResourceReserved(node)
+	nodeReservedCPU := float64(nodeReserved.Cpu().MilliValue()) / 1000
+
+	podProdUsedCPU, hostAppNonBEUsedCPU, systemUsedCPU := helpers.CalculateFilterPodsUsed(nodeMetric, nodeReservedCPU,
+		podMetas, podMetrics, hostApps, hostAppMetrics, helpers.NonBENonMidPodFilter, helpers.NonBEHostAppFilter)
+	nodeMidSuppress := resource.NewMilliQuantity(node.Status.Capacity.Cpu().MilliValue()*cpuUsedThreshold/100, resource.DecimalSI)
+	nodeMidSuppress.Sub(*resource.NewMilliQuantity(int64(podProdUsedCPU*1000), resource.DecimalSI))
+	nodeMidSuppress.Sub(*resource.NewMilliQuantity(int64(hostAppNonBEUsedCPU*1000), resource.DecimalSI))
+	nodeMidSuppress.Sub(*resource.NewMilliQuantity(int64(systemUsedCPU*1000), resource.DecimalSI))
+	klog.V(6).Infof("nodeSuppressMid[CPU(Core)]:%v = node.Total:%v * SLOPercent:%v%% - systemUsage:%v - podProdUsed:%v - hostAppLSUsed:%v",
+		nodeMidSuppress.AsApproximateFloat64(), node.Status.Capacity.Cpu().Value(), cpuUsedThreshold, systemUsedCPU,
+		podProdUsedCPU, hostAppNonBEUsedCPU)
+	return nodeMidSuppress
+}
+
+// suppressMidCPU suppresses the koord-mid pods of the Mid tier only after the BE pods have been suppressed to the
+// minimum, so that the prod pods reclaim the cpu from the BE pods first and then from the koord-mid pods.
+// The koord-mid pods are under the burstable dir along with the prod pods, so the suppressed cpu is split into
+// the pod-level cfs quota according to their mid-cpu limits.
+// The pod-level cfs quota of the koord-mid pods is owned by the batchresource runtime hook unless they are suppressed,
+// so the suppression is recorded before updating the cgroups to make the runtime hook skip them.
+func (r *CPUSuppress) suppressMidCPU(beSuppressCPU, midSuppressCPU *resource.Quantity, podMetas []*statesinformer.PodMeta,
+	node *corev1.Node, suppressPolicy slov1alpha1.CPUSuppressPolicy) {
+	midPods := getMidTierPods(podMetas)
+	if beSuppressCPU.MilliValue() > 0 || len(midPods) <= 0 {
+		r.recoverMidCFSQuotaIfNeed(midPods, node, suppressPolicy)
+		return
+	}
+	statesinformer.SetMidCPUSuppressed(true)
+
+	totalLimit := int64(0)
+	podLimits := make([]int64, len(midPods))
+	for i, podMeta := range midPods {
+		podLimits[i] = getPodMidCFSQuota(podMeta.Pod)
+		if podLimits[i] <= 0 { // consider the unlimited pod as it is limited by the whole Mid suppression
+			podLimits[i] = int64(math.Max(float64(midSuppressCPU.MilliValue()*cfsPeriod/1000), float64(beMinQuota)))
+		}
+		totalLimit += podLimits[i]
+	}
+	midQuota := int64(math.Max(float64(midSuppressCPU.MilliValue()*cfsPeriod/1000), float64(beMinQuota)))
+	var updaters []resourceexecutor.ResourceUpdater
+	for i, podMeta := range midPods {
+		podQuota := podLimits[i]
+		if midQuota < totalLimit {
+			podQuota = int64(math.Max(float64(midQuota*podLimits[i]/totalLimit), float64(beMinQuota)))
+		}
+		eventHelper := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason(resourceexecutor.AdjustMidByNodeCPUUsage).
+			Message("update Mid pod to cfs_quota: %v", podQuota)
+		u, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, podMeta.CgroupDir, strconv.FormatInt(podQuota, 10), eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to get cfs quota updater for Mid pod %s, err: %v", podMeta.Key(), err)
+			continue
+		}
+		updaters = append(updaters, u)
+	}
+	r.executor.UpdateBatch(true, updaters...)
+	klog.V(4).Infof("suppressMidCPU: suppress %d Mid pods to cfs quota %d in total", len(updaters), midQuota)
+}
+
+// recoverMidCFSQuotaIfNeed restores the pod-level cfs quota of the koord-mid pods to the value reconciled by the
+// batchresource runtime hook, and then hands the cfs quota back to the runtime hook.
+func (r *CPUSuppress) recoverMidCFSQuotaIfNeed(midPods []*statesinformer.PodMeta, node *corev1.Node,
+	suppressPolicy slov1alpha1.CPUSuppressPolicy) {
+	if !statesinformer.IsMidCPUSuppressed() {
+		return
+	}
+	cpuNormalizationRatio := -1.0
+	if node != nil {
+		ratio, err := apiext.GetCPUNormalizationRatio(node)
+		if err != nil {
+			klog.V(4).Infof("failed to get cpu normalization ratio for recovering Mid pods, err: %v", err)
+		} else {
+			cpuNormalizationRatio = ratio
+		}
+	}
+	var updaters []resourceexecutor.ResourceUpdater
+	for _, podMeta := range midPods {
+		podQuota := getPodMidRecoverCFSQuota(podMeta.Pod, suppressPolicy, cpuNormalizationRatio)
+		eventHelper := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason(resourceexecutor.AdjustMidByNodeCPUUsage).
+			Message("recover Mid pod cfs_quota: %v", podQuota)
+		u, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, podMeta.CgroupDir, strconv.FormatInt(podQuota, 10), eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to get cfs quota updater for Mid pod %s, err: %v", podMeta.Key(), err)
+			continue
+		}
+		updaters = append(updaters, u)
+	}
+	r.executor.UpdateBatch(true, updaters...)
+	statesinformer.SetMidCPUSuppressed(false)
+	klog.V(5).Infof("successfully recover the cfs quota of %d Mid pods", len(updaters))
+}
+
+func getMidTierPods(podMetas []*statesinformer.PodMeta) []*statesinformer.PodMeta {
+	var midPods []*statesinformer.PodMeta
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil || !podMeta.IsRunningOrPending() {
+			continue
+		}
+		if helpers.MidTierPodFilter(podMeta.Pod) {
+			midPods = append(midPods, podMeta)
+		}
+	}
+	return midPods
+}
+
+// getPodMidCFSQuota returns the pod-level cfs quota according to the mid-cpu limits, or -1 if any container is unlimited.
+func getPodMidCFSQuota(pod *corev1.Pod) int64 {
+	milliCPULimit := int64(0)
+	for _, c := range pod.Spec.Containers {
+		containerLimit := util.GetMidMilliCPUFromResourceList(c.Resources.Limits)
+		if containerLimit <= 0 {
+			return -1
+		}
+		milliCPULimit += containerLimit
+	}
+	if milliCPULimit <= 0 {
+		return -1
+	}
+	return milliCPULimit * cfsPeriod / 1000
+}
+
+// getPodMidRecoverCFSQuota returns the pod-level cfs quota which the batchresource runtime hook reconciles for the
+// koord-mid pod, i.e. unlimited for the cfsQuota and lsLatency policies, otherwise the mid-cpu limits scaled down by
+// the cpu normalization ratio of the node.
+func getPodMidRecoverCFSQuota(pod *corev1.Pod, suppressPolicy slov1alpha1.CPUSuppressPolicy, cpuNormalizationRatio float64) int64 {
+	if suppressPolicy == slov1alpha1.CPUCfsQuotaPolicy || suppressPolicy == slov1alpha1.CPULSLatencyPolicy {
+		return -1
+	}
+	podQuota := getPodMidCFSQuota(pod)
+	if podQuota > 0 && cpuNormalizationRatio > 1.0 {
+		podQuota = int64(math.Ceil(float64(podQuota) / cpuNormalizationRatio))
+	}
+	return podQuota
+}
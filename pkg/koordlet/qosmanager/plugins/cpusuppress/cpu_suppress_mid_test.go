@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/batchresource"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func newTestMidPodMeta(name string, qos apiext.QoSClass, priority apiext.PriorityClass, midMilliCPU int64) *statesinformer.PodMeta {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID("uid-" + name),
+			Labels: map[string]string{
+				apiext.LabelPodQoS:           string(qos),
+				apiext.LabelPodPriorityClass: string(priority),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							apiext.MidCPU: *resource.NewQuantity(midMilliCPU, resource.DecimalSI),
+						},
+						Limits: corev1.ResourceList{
+							apiext.MidCPU: *resource.NewQuantity(midMilliCPU, resource.DecimalSI),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSBurstable,
+		},
+	}
+	return &statesinformer.PodMeta{
+		Pod:       pod,
+		CgroupDir: koordletutil.GetPodCgroupParentDir(pod),
+	}
+}
+
+func Test_cpuSuppress_suppressMidCPU(t *testing.T) {
+	tests := []struct {
+		name          string
+		beSuppressCPU *resource.Quantity
+		midSuppress   *resource.Quantity
+		midSuppressed bool
+		wantQuotas    []int64
+		wantSuppress  bool
+	}{
+		{
+			name:          "not suppress Mid pods while BE pods can be suppressed",
+			beSuppressCPU: resource.NewMilliQuantity(1000, resource.DecimalSI),
+			midSuppress:   resource.NewMilliQuantity(1000, resource.DecimalSI),
+			wantQuotas:    []int64{400000, 200000},
+			wantSuppress:  false,
+		},
+		{
+			name:          "split the Mid suppression by the mid-cpu limits",
+			beSuppressCPU: resource.NewMilliQuantity(-1000, resource.DecimalSI),
+			midSuppress:   resource.NewMilliQuantity(3000, resource.DecimalSI),
+			wantQuotas:    []int64{200000, 100000},
+			wantSuppress:  true,
+		},
+		{
+			name:          "keep the mid-cpu limits if the Mid suppression is larger",
+			beSuppressCPU: resource.NewMilliQuantity(0, resource.DecimalSI),
+			midSuppress:   resource.NewMilliQuantity(8000, resource.DecimalSI),
+			wantQuotas:    []int64{400000, 200000},
+			wantSuppress:  true,
+		},
+		{
+			name:          "recover the suppressed Mid pods",
+			beSuppressCPU: resource.NewMilliQuantity(1000, resource.DecimalSI),
+			midSuppress:   resource.NewMilliQuantity(1000, resource.DecimalSI),
+			midSuppressed: true,
+			wantQuotas:    []int64{400000, 200000},
+			wantSuppress:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			podMetas := []*statesinformer.PodMeta{
+				newTestMidPodMeta("mid-ls", apiext.QoSLS, apiext.PriorityMid, 4000),
+				newTestMidPodMeta("mid-none", apiext.QoSNone, apiext.PriorityMid, 2000),
+				newTestMidPodMeta("prod-ls", apiext.QoSLS, apiext.PriorityProd, 2000),
+			}
+			for _, podMeta := range podMetas {
+				helper.WriteCgroupFileContents(podMeta.CgroupDir, system.CPUCFSQuota, strconv.FormatInt(getPodMidCFSQuota(podMeta.Pod), 10))
+			}
+
+			r := newTestCPUSuppress(&framework.Options{
+				Config:              framework.NewDefaultConfig(),
+				MetricAdvisorConfig: maframework.NewDefaultConfig(),
+			})
+			stop := make(chan struct{})
+			defer close(stop)
+			r.init(stop)
+			statesinformer.SetMidCPUSuppressed(tt.midSuppressed)
+			defer statesinformer.SetMidCPUSuppressed(false)
+
+			r.suppressMidCPU(tt.beSuppressCPU, tt.midSuppress, podMetas, &corev1.Node{}, slov1alpha1.CPUSetPolicy)
+			assert.Equal(t, tt.wantSuppress, statesinformer.IsMidCPUSuppressed())
+			for i, want := range tt.wantQuotas {
+				got := helper.ReadCgroupFileContents(podMetas[i].CgroupDir, system.CPUCFSQuota)
+				assert.Equal(t, strconv.FormatInt(want, 10), got, podMetas[i].Pod.Name)
+			}
+			// the prod pods are never suppressed
+			got := helper.ReadCgroupFileContents(podMetas[2].CgroupDir, system.CPUCFSQuota)
+			assert.Equal(t, "200000", got)
+		})
+	}
+}
+
+func Test_cpuSuppress_suppressMidCPU_withBatchResourceReconciler(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	podMeta := newTestMidPodMeta("mid-ls", apiext.QoSLS, apiext.PriorityMid, 4000)
+	helper.WriteCgroupFileContents(podMeta.CgroupDir, system.CPUCFSQuota, "400000")
+	node := &corev1.Node{}
+
+	r := newTestCPUSuppress(&framework.Options{
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	})
+	stop := make(chan struct{})
+	defer close(stop)
+	r.init(stop)
+	defer statesinformer.SetMidCPUSuppressed(false)
+	hook := batchresource.Object()
+	reconcileMidPod := func() {
+		podCtx := &protocol.PodContext{}
+		podCtx.FromReconciler(podMeta)
+		assert.NoError(t, hook.SetPodCFSQuota(podCtx))
+		podCtx.ReconcilerDone(r.executor)
+	}
+
+	// the reconciler keeps the suppressed cfs quota
+	r.suppressMidCPU(resource.NewMilliQuantity(0, resource.DecimalSI), resource.NewMilliQuantity(1000, resource.DecimalSI),
+		[]*statesinformer.PodMeta{podMeta}, node, slov1alpha1.CPUSetPolicy)
+	assert.Equal(t, "100000", helper.ReadCgroupFileContents(podMeta.CgroupDir, system.CPUCFSQuota))
+	reconcileMidPod()
+	assert.Equal(t, "100000", helper.ReadCgroupFileContents(podMeta.CgroupDir, system.CPUCFSQuota))
+
+	// the recovered cfs quota is the same as the reconciled one
+	r.suppressMidCPU(resource.NewMilliQuantity(1000, resource.DecimalSI), resource.NewMilliQuantity(1000, resource.DecimalSI),
+		[]*statesinformer.PodMeta{podMeta}, node, slov1alpha1.CPUSetPolicy)
+	assert.Equal(t, "400000", helper.ReadCgroupFileContents(podMeta.CgroupDir, system.CPUCFSQuota))
+	reconcileMidPod()
+	assert.Equal(t, "400000", helper.ReadCgroupFileContents(podMeta.CgroupDir, system.CPUCFSQuota))
+}
+
+func Test_getPodMidRecoverCFSQuota(t *testing.T) {
+	pod := newTestMidPodMeta("mid-ls", apiext.QoSLS, apiext.PriorityMid, 4000).Pod
+	assert.Equal(t, int64(400000), getPodMidRecoverCFSQuota(pod, slov1alpha1.CPUSetPolicy, -1))
+	assert.Equal(t, int64(200000), getPodMidRecoverCFSQuota(pod, slov1alpha1.CPUSetPolicy, 2.0))
+	assert.Equal(t, int64(-1), getPodMidRecoverCFSQuota(pod, slov1alpha1.CPUCfsQuotaPolicy, -1))
+	assert.Equal(t, int64(-1), getPodMidRecoverCFSQuota(pod, slov1alpha1.CPULSLatencyPolicy, 2.0))
+}
+
+func Test_getMidTierPods(t *testing.T) {
+	podMetas := []*statesinformer.PodMeta{
+		newTestMidPodMeta("mid-ls", apiext.QoSLS, apiext.PriorityMid, 4000),
+		newTestMidPodMeta("mid-be", apiext.QoSBE, apiext.PriorityMid, 2000),
+		newTestMidPodMeta("mid-lsr", apiext.QoSLSR, apiext.PriorityMid, 2000),
+		newTestMidPodMeta("batch-be", apiext.QoSBE, apiext.PriorityBatch, 2000),
+	}
+	got := getMidTierPods(podMetas)
+	assert.Len(t, got, 1)
+	assert.Equal(t, "mid-ls", got[0].Pod.Name)
+}
@@ -1345,12 +1345,19 @@ func Test_cpuSuppress_recoverCFSQuotaIfNeed(t *testing.T) {
 			helper.CreateCgroupFile(beQosDir, system.CPUCFSQuota)
 
 			helper.WriteCgroupFileContents(beQosDir, system.CPUCFSQuota, strconv.FormatInt(tt.preBECfsQuota, 10))
+			hostApp := slov1alpha1.HostApplicationSpec{
+				Name: "test-be-host-app",
+				QoS:  apiext.QoSBE,
+			}
+			hostAppDir := koordletutil.GetHostAppCgroupRelativePath(&hostApp)
+			helper.WriteCgroupFileContents(hostAppDir, system.CPUCFSQuota, strconv.FormatInt(tt.preBECfsQuota, 10))
 
 			r := framework.Options{
 				Config:              framework.NewDefaultConfig(),
 				MetricAdvisorConfig: maframework.NewDefaultConfig(),
 			}
 			cpuSuppress := newTestCPUSuppress(&r)
+			cpuSuppress.hostApps = []slov1alpha1.HostApplicationSpec{hostApp}
 			stop := make(chan struct{})
 			assert.NotPanics(t, func() {
 				cpuSuppress.init(stop)
@@ -1365,6 +1372,8 @@ func Test_cpuSuppress_recoverCFSQuotaIfNeed(t *testing.T) {
 			assert.Equal(t, *tt.wantPolicyStatus, gotPolicyStatus, "checkStatus")
 			gotBECfsQuota := helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota)
 			assert.Equal(t, strconv.FormatInt(tt.wantBECfsQuota, 10), gotBECfsQuota)
+			gotHostAppCfsQuota := helper.ReadCgroupFileContents(hostAppDir, system.CPUCFSQuota)
+			assert.Equal(t, strconv.FormatInt(tt.wantBECfsQuota, 10), gotHostAppCfsQuota)
 		})
 	}
 }
@@ -1522,6 +1531,12 @@ func Test_cpuSuppress_applyCPUSetWithNonePolicy(t *testing.T) {
 	helper := system.NewFileTestUtil(t)
 	podDirs := []string{"pod1", "pod2", "pod3"}
 	testingPrepareBECgroupData(helper, podDirs, "1,2")
+	hostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-be-host-app",
+		QoS:  apiext.QoSBE,
+	}
+	hostAppDir := koordletutil.GetHostAppCgroupRelativePath(&hostApp)
+	helper.WriteCgroupFileContents(hostAppDir, system.CPUSet, "1,2")
 
 	cpuset := []int32{3, 2, 1}
 	wantCPUSetStr := "1-3"
@@ -1534,6 +1549,7 @@ func Test_cpuSuppress_applyCPUSetWithNonePolicy(t *testing.T) {
 		MetricAdvisorConfig: maframework.NewDefaultConfig(),
 	}
 	r := newTestCPUSuppress(opt)
+	r.hostApps = []slov1alpha1.HostApplicationSpec{hostApp}
 	stop := make(chan struct{})
 	assert.NotPanics(t, func() {
 		r.init(stop)
@@ -1547,6 +1563,8 @@ func Test_cpuSuppress_applyCPUSetWithNonePolicy(t *testing.T) {
 		gotPodCPUSet := helper.ReadCgroupFileContents(filepath.Join(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), podDir), system.CPUSet)
 		assert.Equal(t, wantCPUSetStr, gotPodCPUSet, "checkPodCPUSet")
 	}
+	gotHostAppCPUSet := helper.ReadCgroupFileContents(hostAppDir, system.CPUSet)
+	assert.Equal(t, wantCPUSetStr, gotHostAppCPUSet, "checkHostAppCPUSet")
 }
 
 func Test_getBECgroupCPUSetPathsRecursive(t *testing.T) {
@@ -1922,6 +1940,12 @@ func Test_cpuSuppress_adjustByCfsQuota(t *testing.T) {
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			helper.WriteCgroupFileContents(beQosDir, system.CPUCFSQuota, strconv.FormatInt(tt.preBECfsQuota, 10))
+			hostApp := slov1alpha1.HostApplicationSpec{
+				Name: "test-be-host-app",
+				QoS:  apiext.QoSBE,
+			}
+			hostAppDir := koordletutil.GetHostAppCgroupRelativePath(&hostApp)
+			helper.WriteCgroupFileContents(hostAppDir, system.CPUCFSQuota, strconv.FormatInt(tt.preBECfsQuota, 10))
 			opt := &framework.Options{
 				Config:              framework.NewDefaultConfig(),
 				MetricAdvisorConfig: maframework.NewDefaultConfig(),
@@ -1942,6 +1966,147 @@ func Test_cpuSuppress_adjustByCfsQuota(t *testing.T) {
 	}
 }
 
+func Test_cpuSuppress_adjustByCfsQuotaWithHostApps(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node0",
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("80"),
+			},
+		},
+	}
+	hostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-be-host-app",
+		QoS:  apiext.QoSBE,
+	}
+	removedHostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-removed-be-host-app",
+		QoS:  apiext.QoSBE,
+	}
+	beQosDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	hostAppDir := koordletutil.GetHostAppCgroupRelativePath(&hostApp)
+	removedHostAppDir := koordletutil.GetHostAppCgroupRelativePath(&removedHostApp)
+	tests := []struct {
+		name                string
+		hostApps            []slov1alpha1.HostApplicationSpec
+		beHostAppDirs       map[string]bool
+		beCPUUsages         map[string]float64
+		cpuQuantity         *resource.Quantity
+		preCfsQuota         int64
+		wantBECfsQuota      int64
+		wantHostAppCfsQuota int64
+		wantRemovedAppQuota int64
+		wantBEHostAppDirs   map[string]bool
+	}{
+		{
+			name:     "split quota by usages for a new host app",
+			hostApps: []slov1alpha1.HostApplicationSpec{hostApp},
+			beCPUUsages: map[string]float64{
+				beQosDir:   3,
+				hostAppDir: 1,
+			},
+			cpuQuantity:         resource.NewMilliQuantity(20*1000, resource.BinarySI),
+			preCfsQuota:         10 * cfsPeriod,
+			wantBECfsQuota:      int64(12.5 * float64(cfsPeriod)),
+			wantHostAppCfsQuota: int64(7.5 * float64(cfsPeriod)),
+			wantRemovedAppQuota: 10 * cfsPeriod,
+			wantBEHostAppDirs:   map[string]bool{hostAppDir: true},
+		},
+		{
+			name:                "bypass small delta for unchanged host apps",
+			hostApps:            []slov1alpha1.HostApplicationSpec{hostApp},
+			beHostAppDirs:       map[string]bool{hostAppDir: true},
+			cpuQuantity:         resource.NewMilliQuantity(20*1000, resource.BinarySI),
+			preCfsQuota:         10 * cfsPeriod,
+			wantBECfsQuota:      10 * cfsPeriod,
+			wantHostAppCfsQuota: 10 * cfsPeriod,
+			wantRemovedAppQuota: 10 * cfsPeriod,
+			wantBEHostAppDirs:   map[string]bool{hostAppDir: true},
+		},
+		{
+			name:                "reset quota for the host app removed from NodeSLO",
+			hostApps:            []slov1alpha1.HostApplicationSpec{hostApp},
+			beHostAppDirs:       map[string]bool{hostAppDir: true, removedHostAppDir: true},
+			cpuQuantity:         resource.NewMilliQuantity(20*1000, resource.BinarySI),
+			preCfsQuota:         10 * cfsPeriod,
+			wantBECfsQuota:      10 * cfsPeriod,
+			wantHostAppCfsQuota: 10 * cfsPeriod,
+			wantRemovedAppQuota: -1,
+			wantBEHostAppDirs:   map[string]bool{hostAppDir: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			helper.WriteCgroupFileContents(beQosDir, system.CPUCFSQuota, strconv.FormatInt(tt.preCfsQuota, 10))
+			helper.WriteCgroupFileContents(hostAppDir, system.CPUCFSQuota, strconv.FormatInt(tt.preCfsQuota, 10))
+			helper.WriteCgroupFileContents(removedHostAppDir, system.CPUCFSQuota, strconv.FormatInt(tt.preCfsQuota, 10))
+			opt := &framework.Options{
+				Config:              framework.NewDefaultConfig(),
+				MetricAdvisorConfig: maframework.NewDefaultConfig(),
+			}
+			r := newTestCPUSuppress(opt)
+			r.hostApps = tt.hostApps
+			r.beHostAppDirs = tt.beHostAppDirs
+			r.beCPUUsages = tt.beCPUUsages
+			stop := make(chan struct{})
+			assert.NotPanics(t, func() {
+				r.init(stop)
+			})
+
+			r.adjustByCfsQuota(tt.cpuQuantity, node)
+			assert.Equal(t, strconv.FormatInt(tt.wantBECfsQuota, 10), helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota))
+			assert.Equal(t, strconv.FormatInt(tt.wantHostAppCfsQuota, 10), helper.ReadCgroupFileContents(hostAppDir, system.CPUCFSQuota))
+			assert.Equal(t, strconv.FormatInt(tt.wantRemovedAppQuota, 10), helper.ReadCgroupFileContents(removedHostAppDir, system.CPUCFSQuota))
+			assert.Equal(t, tt.wantBEHostAppDirs, r.beHostAppDirs)
+		})
+	}
+}
+
+func Test_splitBECFSQuota(t *testing.T) {
+	tests := []struct {
+		name   string
+		quota  int64
+		dirs   []string
+		usages map[string]float64
+		want   map[string]int64
+	}{
+		{
+			name:  "single dir gets all the quota",
+			quota: 10 * cfsPeriod,
+			dirs:  []string{"besteffort"},
+			want:  map[string]int64{"besteffort": 10 * cfsPeriod},
+		},
+		{
+			name:  "split evenly without usages",
+			quota: 10 * cfsPeriod,
+			dirs:  []string{"besteffort", "host-app"},
+			want:  map[string]int64{"besteffort": 5 * cfsPeriod, "host-app": 5 * cfsPeriod},
+		},
+		{
+			name:   "split by usages",
+			quota:  10 * cfsPeriod,
+			dirs:   []string{"besteffort", "host-app"},
+			usages: map[string]float64{"besteffort": 4},
+			want:   map[string]int64{"besteffort": int64(7.5 * float64(cfsPeriod)), "host-app": int64(2.5 * float64(cfsPeriod))},
+		},
+		{
+			name:  "no less than the min quota",
+			quota: beMinQuota,
+			dirs:  []string{"besteffort", "host-app"},
+			want:  map[string]int64{"besteffort": beMinQuota, "host-app": beMinQuota},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitBECFSQuota(tt.quota, tt.dirs, tt.usages)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_cpuSuppress_writeBECgroupsCPUSet(t *testing.T) {
 	// prepare testing files
 	helper := system.NewFileTestUtil(t)
@@ -32,6 +32,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
 )
 
 const (
@@ -199,20 +200,26 @@ func (m *memoryEvictor) getSortedBEPodInfos(podMetricMap map[string]float64) []*
 		}
 	}
 
-	sort.Slice(bePodInfos, func(i, j int) bool {
-		// TODO: https://github.com/koordinator-sh/koordinator/pull/65#discussion_r849048467
-		// compare priority > podMetric > name
-		if bePodInfos[i].pod.Spec.Priority != nil && bePodInfos[j].pod.Spec.Priority != nil && *bePodInfos[i].pod.Spec.Priority != *bePodInfos[j].pod.Spec.Priority {
-			return *bePodInfos[i].pod.Spec.Priority < *bePodInfos[j].pod.Spec.Priority
+	memUsages := make(map[*corev1.Pod]float64, len(bePodInfos))
+	for _, info := range bePodInfos {
+		memUsages[info.pod] = info.memUsed
+	}
+	// the pods with higher memory usage are preferred under the same priority, and the pods without metric are the last
+	ranker := victim.NewRanker(victim.WithUsage(func(p1, p2 *corev1.Pod) int {
+		used1, used2 := memUsages[p1], memUsages[p2]
+		if used1 == used2 {
+			return 0
 		}
-		if bePodInfos[i].memUsed != 0 && bePodInfos[j].memUsed != 0 {
-			//return bePodInfos[i].podMetric.MemoryUsed.MemoryWithoutCache.Value() > bePodInfos[j].podMetric.MemoryUsed.MemoryWithoutCache.Value()
-			return bePodInfos[i].memUsed > bePodInfos[j].memUsed
-		} else if bePodInfos[i].memUsed == 0 && bePodInfos[j].memUsed == 0 {
-
-			return bePodInfos[i].pod.Name > bePodInfos[j].pod.Name
+		if used1 != 0 && (used2 == 0 || used1 > used2) {
+			return -1
+		}
+		return 1
+	}))
+	sort.SliceStable(bePodInfos, func(i, j int) bool {
+		if result := ranker.Compare(bePodInfos[i].pod, bePodInfos[j].pod); result != 0 {
+			return result < 0
 		}
-		return bePodInfos[j].memUsed == 0
+		return bePodInfos[i].pod.Name > bePodInfos[j].pod.Name
 	})
 
 	return bePodInfos
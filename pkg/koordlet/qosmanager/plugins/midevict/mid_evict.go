@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package midevict
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
+)
+
+const (
+	MidEvictName = "midEvict"
+)
+
+var _ framework.QOSStrategy = &midEvictor{}
+
+// midEvictor evicts the koord-mid pods when the usage of the non-BE pods exceeds the mid evict threshold.
+// The usage of the BE pods is excluded since the BE pods are suppressed and evicted ahead of the koord-mid pods.
+type midEvictor struct {
+	evictInterval         time.Duration
+	evictCoolingInterval  time.Duration
+	metricCollectInterval time.Duration
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	evictor               *framework.Evictor
+	lastEvictTime         time.Time
+}
+
+type podInfo struct {
+	pod  *corev1.Pod
+	used float64
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &midEvictor{
+		evictInterval:         time.Duration(opt.Config.MemoryEvictIntervalSeconds) * time.Second,
+		evictCoolingInterval:  time.Duration(opt.Config.MemoryEvictCoolTimeSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+	}
+}
+
+func (m *midEvictor) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.MidEvict) && m.evictInterval > 0
+}
+
+func (m *midEvictor) Setup(ctx *framework.Context) {
+	m.evictor = ctx.Evictor
+}
+
+func (m *midEvictor) Run(stopCh <-chan struct{}) {
+	go wait.Until(m.midEvict, m.evictInterval, stopCh)
+}
+
+func (m *midEvictor) midEvict() {
+	klog.V(5).Infof("starting mid evict process")
+	defer klog.V(5).Infof("mid evict process completed")
+
+	if time.Now().Before(m.lastEvictTime.Add(m.evictCoolingInterval)) {
+		klog.V(5).Infof("skip mid evict process, still in evict cooling time")
+		return
+	}
+
+	nodeSLO := m.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.MidEvict); err != nil {
+		klog.Errorf("failed to acquire mid eviction feature-gate, error: %v", err)
+		return
+	} else if disabled {
+		klog.V(4).Infof("skip mid evict, disabled in NodeSLO")
+		return
+	}
+
+	node := m.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip mid evict, Node is nil")
+		return
+	}
+
+	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	if m.evictByResource(node, corev1.ResourceCPU, thresholdConfig.MidCPUEvictThresholdPercent) {
+		return
+	}
+	m.evictByResource(node, corev1.ResourceMemory, thresholdConfig.MidMemoryEvictThresholdPercent)
+}
+
+// evictByResource evicts the koord-mid pods if the non-BE usage of the resource exceeds the threshold.
+// It returns true if any pod is evicted.
+func (m *midEvictor) evictByResource(node *corev1.Node, resourceName corev1.ResourceName, thresholdPercent *int64) bool {
+	if thresholdPercent == nil {
+		klog.V(5).Infof("skip mid %s evict, threshold percent is nil", resourceName)
+		return false
+	} else if *thresholdPercent < 0 {
+		klog.Warningf("skip mid %s evict, threshold percent(%v) should greater than 0", resourceName, *thresholdPercent)
+		return false
+	}
+
+	var capacity float64
+	var nodeMetric, podMetric metriccache.MetricResource
+	var reason string
+	switch resourceName {
+	case corev1.ResourceCPU:
+		capacity = float64(node.Status.Capacity.Cpu().MilliValue()) / 1000
+		nodeMetric, podMetric = metriccache.NodeCPUUsageMetric, metriccache.PodCPUUsageMetric
+		reason = resourceexecutor.EvictPodByMidCPUUsage
+	case corev1.ResourceMemory:
+		capacity = float64(node.Status.Capacity.Memory().Value())
+		nodeMetric, podMetric = metriccache.NodeMemoryUsageMetric, metriccache.PodMemUsageMetric
+		reason = resourceexecutor.EvictPodByMidMemoryUsage
+	default:
+		return false
+	}
+	if capacity <= 0 {
+		klog.Warningf("skip mid %s evict, capacity(%v) should greater than 0", resourceName, capacity)
+		return false
+	}
+
+	queryMeta, err := nodeMetric.BuildQueryMeta(nil)
+	if err != nil {
+		klog.Warningf("skip mid %s evict, get node query failed, error: %v", resourceName, err)
+		return false
+	}
+	nodeUsed, err := helpers.CollectorNodeMetricLast(m.metricCache, queryMeta, m.metricCollectInterval)
+	if err != nil {
+		klog.Warningf("skip mid %s evict, get node metrics error: %v", resourceName, err)
+		return false
+	}
+	podMetrics := helpers.CollectAllPodMetricsLast(m.statesInformer, m.metricCache, podMetric, m.metricCollectInterval)
+	podMetas := m.statesInformer.GetAllPods()
+
+	needRelease := calculateNeedRelease(nodeUsed, capacity, *thresholdPercent, podMetas, podMetrics)
+	if needRelease <= 0 {
+		klog.V(5).Infof("skip mid %s evict, non-BE usage is below threshold(%v)", resourceName, *thresholdPercent)
+		return false
+	}
+
+	midPodInfos := getSortedMidPodInfos(podMetas, podMetrics)
+	killedPods, released := selectVictims(midPodInfos, needRelease)
+	if len(killedPods) <= 0 {
+		klog.V(4).Infof("skip mid %s evict, no koord-mid pod to evict, need to release %v", resourceName, needRelease)
+		return false
+	}
+
+	message := fmt.Sprintf("killAndEvictMidPods for node, need to release %s: %v", resourceName, needRelease)
+	for _, pod := range killedPods {
+		helpers.KillContainers(pod, fmt.Sprintf("%v, kill pod: %v", message, pod.Name))
+	}
+	m.evictor.EvictPodsIfNotEvicted(killedPods, node, reason, message)
+
+	m.lastEvictTime = time.Now()
+	klog.Infof("killAndEvictMidPods completed, %s needRelease(%v) released(%v)", resourceName, needRelease, released)
+	return true
+}
+
+// calculateNeedRelease returns the amount of resource to release by evicting the koord-mid pods,
+// i.e. nodeUsed - beUsed - capacity * thresholdPercent / 100.
+func calculateNeedRelease(nodeUsed, capacity float64, thresholdPercent int64,
+	podMetas []*statesinformer.PodMeta, podMetrics map[string]float64) float64 {
+	var beUsed float64
+	for _, podMeta := range podMetas {
+		if !helpers.NonBEPodFilter(podMeta.Pod) {
+			beUsed += podMetrics[string(podMeta.Pod.UID)]
+		}
+	}
+	return nodeUsed - beUsed - capacity*float64(thresholdPercent)/100
+}
+
+func getSortedMidPodInfos(podMetas []*statesinformer.PodMeta, podMetrics map[string]float64) []*podInfo {
+	var midPodInfos []*podInfo
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		if extension.GetPodPriorityClassWithDefault(pod) != extension.PriorityMid || !helpers.NonBEPodFilter(pod) {
+			continue
+		}
+		midPodInfos = append(midPodInfos, &podInfo{
+			pod:  pod,
+			used: podMetrics[string(pod.UID)],
+		})
+	}
+
+	usages := make(map[*corev1.Pod]float64, len(midPodInfos))
+	for _, info := range midPodInfos {
+		usages[info.pod] = info.used
+	}
+	// the pods with higher usage are preferred under the same priority, and the pods without metric are the last
+	ranker := victim.NewRanker(victim.WithUsage(func(p1, p2 *corev1.Pod) int {
+		used1, used2 := usages[p1], usages[p2]
+		if used1 == used2 {
+			return 0
+		}
+		if used1 != 0 && (used2 == 0 || used1 > used2) {
+			return -1
+		}
+		return 1
+	}))
+	sort.SliceStable(midPodInfos, func(i, j int) bool {
+		if result := ranker.Compare(midPodInfos[i].pod, midPodInfos[j].pod); result != 0 {
+			return result < 0
+		}
+		return midPodInfos[i].pod.Name > midPodInfos[j].pod.Name
+	})
+	return midPodInfos
+}
+
+func selectVictims(podInfos []*podInfo, needRelease float64) ([]*corev1.Pod, float64) {
+	var victims []*corev1.Pod
+	var released float64
+	for _, info := range podInfos {
+		if released >= needRelease {
+			break
+		}
+		victims = append(victims, info.pod)
+		released += info.used
+	}
+	return victims, released
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package midevict
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+func Test_midEvictVictims(t *testing.T) {
+	podMetas := []*statesinformer.PodMeta{
+		{Pod: createMidEvictTestPod("test_ls_prod_pod", apiext.QoSLS, 9000)},
+		{Pod: createMidEvictTestPod("test_ls_mid_pod_1", apiext.QoSLS, 7000)},
+		{Pod: createMidEvictTestPod("test_ls_mid_pod_2", apiext.QoSLS, 7000)},
+		{Pod: createMidEvictTestPod("test_ls_mid_pod_high", apiext.QoSLS, 7100)},
+		{Pod: createMidEvictTestPod("test_be_mid_pod", apiext.QoSBE, 7000)},
+		{Pod: createMidEvictTestPod("test_be_batch_pod", apiext.QoSBE, 5000)},
+	}
+	podMetrics := map[string]float64{
+		"test_ls_prod_pod":     30,
+		"test_ls_mid_pod_1":    5,
+		"test_ls_mid_pod_2":    10,
+		"test_ls_mid_pod_high": 20,
+		"test_be_mid_pod":      4,
+		"test_be_batch_pod":    6,
+	}
+
+	tests := []struct {
+		name             string
+		nodeUsed         float64
+		thresholdPercent int64
+		wantNeedRelease  float64
+		wantVictims      []string
+	}{
+		{
+			name:             "non-BE usage under threshold",
+			nodeUsed:         80,
+			thresholdPercent: 80,
+			wantNeedRelease:  -10,
+		},
+		{
+			name:             "evict the mid pod with higher usage first",
+			nodeUsed:         100,
+			thresholdPercent: 80,
+			wantNeedRelease:  10,
+			wantVictims:      []string{"test_ls_mid_pod_2"},
+		},
+		{
+			name:             "evict the mid pods with lower priority first",
+			nodeUsed:         100,
+			thresholdPercent: 70,
+			wantNeedRelease:  20,
+			wantVictims:      []string{"test_ls_mid_pod_2", "test_ls_mid_pod_1", "test_ls_mid_pod_high"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			needRelease := calculateNeedRelease(tt.nodeUsed, 100, tt.thresholdPercent, podMetas, podMetrics)
+			assert.Equal(t, tt.wantNeedRelease, needRelease)
+			if needRelease <= 0 {
+				return
+			}
+			victims, released := selectVictims(getSortedMidPodInfos(podMetas, podMetrics), needRelease)
+			var gotVictims []string
+			for _, pod := range victims {
+				gotVictims = append(gotVictims, pod.Name)
+			}
+			assert.Equal(t, tt.wantVictims, gotVictims)
+			assert.GreaterOrEqual(t, released, needRelease)
+		})
+	}
+}
+
+func createMidEvictTestPod(name string, qosClass apiext.QoSClass, priority int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  types.UID(name),
+			Labels: map[string]string{
+				apiext.LabelPodQoS: string(qosClass),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+			Priority: &priority,
+		},
+	}
+}
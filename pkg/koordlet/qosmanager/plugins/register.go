@@ -24,6 +24,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuevict"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpusuppress"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memoryevict"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/midevict"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/sysreconcile"
 )
@@ -36,6 +37,7 @@ var (
 		cpuevict.CPUEvictName:                  cpuevict.New,
 		cpusuppress.CPUSuppressName:            cpusuppress.New,
 		memoryevict.MemoryEvictName:            memoryevict.New,
+		midevict.MidEvictName:                  midevict.New,
 		resctrl.ResctrlReconcileName:           resctrl.New,
 		sysreconcile.SystemConfigReconcileName: sysreconcile.New,
 	}
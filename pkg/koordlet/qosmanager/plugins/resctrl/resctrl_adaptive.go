@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+const (
+	ReasonResctrlAdaptive = "ResctrlAdaptive"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// resctrlAdaptiveState is the current CAT range end and MBA percent of the BE group adjusted by the adaptive policy.
+type resctrlAdaptiveState struct {
+	catRangeEndPercent int64
+	mbaPercent         int64
+	lastAdjustTime     time.Time
+}
+
+// getAdaptiveResourceQOS returns the resource qos of the BE group whose CAT range end and MBA percent are adjusted
+// by the adaptive policy. The NodeSLO config is returned as is if the adaptive policy is disabled.
+func (r *resctrlReconcile) getAdaptiveResourceQOS(resourceQoS *slov1alpha1.ResourceQOS) *slov1alpha1.ResourceQOS {
+	if resourceQoS == nil || resourceQoS.ResctrlQOS == nil || resourceQoS.ResctrlQOS.Adaptive == nil ||
+		resourceQoS.ResctrlQOS.CATRangeStartPercent == nil || resourceQoS.ResctrlQOS.CATRangeEndPercent == nil ||
+		resourceQoS.ResctrlQOS.MBAPercent == nil {
+		r.adaptiveState = nil
+		return resourceQoS
+	}
+	mergedCfgIf, err := util.MergeCfg(sloconfig.DefaultResctrlAdaptiveCfg(), resourceQoS.ResctrlQOS.Adaptive.DeepCopy())
+	if err != nil {
+		klog.Warningf("failed to merge resctrl adaptive config, err: %v", err)
+		r.adaptiveState = nil
+		return resourceQoS
+	}
+	adaptiveCfg := mergedCfgIf.(*slov1alpha1.ResctrlAdaptiveCfg)
+	if adaptiveCfg.Enable == nil || !*adaptiveCfg.Enable {
+		r.adaptiveState = nil
+		return resourceQoS
+	}
+
+	// the CAT range end must be larger than the range start, and the MBA percent must be positive
+	catMax, mbaMax := *resourceQoS.ResctrlQOS.CATRangeEndPercent, *resourceQoS.ResctrlQOS.MBAPercent
+	catMin := util.MinInt64(util.MaxInt64(*adaptiveCfg.CATRangeEndMinPercent, *resourceQoS.ResctrlQOS.CATRangeStartPercent+1), catMax)
+	mbaMin := util.MinInt64(util.MaxInt64(*adaptiveCfg.MBAMinPercent, 1), mbaMax)
+
+	now := timeNow()
+	if r.adaptiveState == nil {
+		// start from the upper bounds and wait for a full window to check the interference
+		r.adaptiveState = &resctrlAdaptiveState{
+			catRangeEndPercent: catMax,
+			mbaPercent:         mbaMax,
+			lastAdjustTime:     now,
+		}
+	}
+	state := r.adaptiveState
+	// the bounds can change with the NodeSLO
+	state.catRangeEndPercent = util.MinInt64(util.MaxInt64(state.catRangeEndPercent, catMin), catMax)
+	state.mbaPercent = util.MinInt64(util.MaxInt64(state.mbaPercent, mbaMin), mbaMax)
+
+	window := time.Duration(*adaptiveCfg.WindowSeconds) * time.Second
+	if now.Sub(state.lastAdjustTime) >= window {
+		state.lastAdjustTime = now
+		interfered, msg := r.checkLSInterference(adaptiveCfg, now)
+		step := *adaptiveCfg.StepPercent
+		oldCAT, oldMBA := state.catRangeEndPercent, state.mbaPercent
+		if interfered {
+			state.catRangeEndPercent = util.MaxInt64(state.catRangeEndPercent-step, catMin)
+			state.mbaPercent = util.MaxInt64(state.mbaPercent-step, mbaMin)
+		} else {
+			state.catRangeEndPercent = util.MinInt64(state.catRangeEndPercent+step, catMax)
+			state.mbaPercent = util.MinInt64(state.mbaPercent+step, mbaMax)
+		}
+		if oldCAT != state.catRangeEndPercent || oldMBA != state.mbaPercent {
+			klog.V(4).Infof("adjust resctrl qos of group %s, catRangeEndPercent %d -> %d, mbaPercent %d -> %d, reason: %s",
+				BEResctrlGroup, oldCAT, state.catRangeEndPercent, oldMBA, state.mbaPercent, msg)
+			_ = audit.V(3).Group(BEResctrlGroup).Reason(ReasonResctrlAdaptive).Message(
+				"adjust catRangeEndPercent %d -> %d, mbaPercent %d -> %d, reason: %s",
+				oldCAT, state.catRangeEndPercent, oldMBA, state.mbaPercent, msg).Do()
+		} else {
+			klog.V(6).Infof("resctrl qos of group %s unchanged, catRangeEndPercent %d, mbaPercent %d, reason: %s",
+				BEResctrlGroup, state.catRangeEndPercent, state.mbaPercent, msg)
+		}
+	}
+
+	adaptiveQoS := resourceQoS.DeepCopy()
+	adaptiveQoS.ResctrlQOS.CATRangeEndPercent = &state.catRangeEndPercent
+	adaptiveQoS.ResctrlQOS.MBAPercent = &state.mbaPercent
+	return adaptiveQoS
+}
+
+// checkLSInterference checks if the LS pods are interfered by comparing the metrics in the recent window with the
+// baseline window. The LS pods are considered interfered if the CPI of the LS pods increases or the memory bandwidth
+// of the LS groups drops while the BE group does not.
+func (r *resctrlReconcile) checkLSInterference(adaptiveCfg *slov1alpha1.ResctrlAdaptiveCfg, now time.Time) (bool, string) {
+	recentStart := now.Add(-time.Duration(*adaptiveCfg.WindowSeconds) * time.Second)
+	baselineStart := recentStart.Add(-time.Duration(*adaptiveCfg.BaselineWindowSeconds) * time.Second)
+
+	recentCPI, err := r.getLSCPI(recentStart, now)
+	if err == nil {
+		baselineCPI, err1 := r.getLSCPI(baselineStart, recentStart)
+		err = err1
+		threshold := float64(100+*adaptiveCfg.CPIDegradationThresholdPercent) / 100
+		if err == nil && recentCPI > baselineCPI*threshold {
+			return true, fmt.Sprintf("LS CPI degraded, recent %.3f, baseline %.3f", recentCPI, baselineCPI)
+		}
+	}
+	if err != nil {
+		klog.V(5).Infof("skip checking LS CPI for resctrl adaptive policy, err: %v", err)
+	}
+
+	recentLSMB, recentBEMB, err := r.getGroupsMemoryBandwidth(recentStart, now)
+	if err != nil {
+		klog.V(5).Infof("skip checking LS memory bandwidth for resctrl adaptive policy, err: %v", err)
+		return false, "no interference"
+	}
+	baselineLSMB, baselineBEMB, err := r.getGroupsMemoryBandwidth(baselineStart, recentStart)
+	if err != nil {
+		klog.V(5).Infof("skip checking LS memory bandwidth for resctrl adaptive policy, err: %v", err)
+		return false, "no interference"
+	}
+	threshold := float64(100-*adaptiveCfg.MemoryBandwidthDegradationThresholdPercent) / 100
+	if recentLSMB < baselineLSMB*threshold && recentBEMB >= baselineBEMB {
+		return true, fmt.Sprintf("LS memory bandwidth degraded, recent %.0f, baseline %.0f, BE recent %.0f, baseline %.0f",
+			recentLSMB, baselineLSMB, recentBEMB, baselineBEMB)
+	}
+	return false, "no interference"
+}
+
+// getLSCPI returns the CPI of all the LSR and LS containers in the time window.
+func (r *resctrlReconcile) getLSCPI(start, end time.Time) (float64, error) {
+	querier, err := r.metricCache.Querier(start, end)
+	if err != nil {
+		return 0, err
+	}
+	var cycles, instructions float64
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if group := getPodResctrlGroup(pod); group != LSRResctrlGroup && group != LSResctrlGroup {
+			continue
+		}
+		for _, containerStat := range pod.Status.ContainerStatuses {
+			cycle, err := queryAvg(querier, metriccache.ContainerCPI, metriccache.MetricPropertiesFunc.ContainerCPI(
+				string(pod.UID), containerStat.ContainerID, string(metriccache.CPIResourceCycle)))
+			if err != nil {
+				continue
+			}
+			instruction, err := queryAvg(querier, metriccache.ContainerCPI, metriccache.MetricPropertiesFunc.ContainerCPI(
+				string(pod.UID), containerStat.ContainerID, string(metriccache.CPIResourceInstruction)))
+			if err != nil {
+				continue
+			}
+			cycles += cycle
+			instructions += instruction
+		}
+	}
+	if instructions <= 0 {
+		return 0, fmt.Errorf("no valid CPI metric of LS pods")
+	}
+	return cycles / instructions, nil
+}
+
+// getGroupsMemoryBandwidth returns the memory bandwidth of the LS groups (LSR and LS) and the BE group in the window.
+func (r *resctrlReconcile) getGroupsMemoryBandwidth(start, end time.Time) (float64, float64, error) {
+	querier, err := r.metricCache.Querier(start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+	var lsBandwidth float64
+	lsValid := false
+	for _, group := range []string{LSRResctrlGroup, LSResctrlGroup} {
+		bandwidth, err := queryAvg(querier, metriccache.ResctrlGroupMBMTotalMetric, metriccache.MetricPropertiesFunc.ResctrlGroup(group))
+		if err != nil {
+			continue
+		}
+		lsBandwidth += bandwidth
+		lsValid = true
+	}
+	if !lsValid {
+		return 0, 0, fmt.Errorf("no valid memory bandwidth metric of LS groups")
+	}
+	beBandwidth, err := queryAvg(querier, metriccache.ResctrlGroupMBMTotalMetric, metriccache.MetricPropertiesFunc.ResctrlGroup(BEResctrlGroup))
+	if err != nil {
+		return 0, 0, fmt.Errorf("no valid memory bandwidth metric of BE group, err: %w", err)
+	}
+	return lsBandwidth, beBandwidth, nil
+}
+
+func queryAvg(querier metriccache.Querier, resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string) (float64, error) {
+	result, err := helpers.Query(querier, resource, properties)
+	if err != nil {
+		return 0, err
+	}
+	if result.Count() <= 0 {
+		return 0, fmt.Errorf("metric is empty")
+	}
+	return result.Value(metriccache.AggregationTypeAVG)
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+)
+
+func Test_getAdaptiveResourceQOS(t *testing.T) {
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+	testContainerID := "containerd://test-ls-container"
+	testLSPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ls-pod",
+			Namespace: "default",
+			UID:       "test-ls-pod-uid",
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSLS),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: testContainerID,
+				},
+			},
+		},
+	}
+	testBEQOS := func(adaptive *slov1alpha1.ResctrlAdaptiveCfg) *slov1alpha1.ResourceQOS {
+		return &slov1alpha1.ResourceQOS{
+			ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+				Enable: pointer.Bool(true),
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					CATRangeStartPercent: pointer.Int64(0),
+					CATRangeEndPercent:   pointer.Int64(30),
+					MBAPercent:           pointer.Int64(100),
+				},
+				Adaptive: adaptive,
+			},
+		}
+	}
+	testAdaptiveCfg := &slov1alpha1.ResctrlAdaptiveCfg{
+		Enable:                pointer.Bool(true),
+		CATRangeEndMinPercent: pointer.Int64(10),
+		MBAMinPercent:         pointer.Int64(30),
+		StepPercent:           pointer.Int64(10),
+	}
+	// the recent window is [now-60s, now], and the baseline window is [now-660s, now-60s]
+	recentTime := testNow.Add(-30 * time.Second)
+	baselineTime := testNow.Add(-300 * time.Second)
+	genCPISamples := func(t *testing.T, recentCPI, baselineCPI float64) []metriccache.MetricSample {
+		var samples []metriccache.MetricSample
+		// the samples of a series must be appended in time order
+		for i, ts := range []time.Time{baselineTime, recentTime} {
+			cpi := []float64{baselineCPI, recentCPI}[i]
+			cycle, err := metriccache.ContainerCPI.GenerateSample(metriccache.MetricPropertiesFunc.ContainerCPI(
+				string(testLSPod.UID), testContainerID, string(metriccache.CPIResourceCycle)), ts, cpi*1000)
+			assert.NoError(t, err)
+			instruction, err := metriccache.ContainerCPI.GenerateSample(metriccache.MetricPropertiesFunc.ContainerCPI(
+				string(testLSPod.UID), testContainerID, string(metriccache.CPIResourceInstruction)), ts, 1000)
+			assert.NoError(t, err)
+			samples = append(samples, cycle, instruction)
+		}
+		return samples
+	}
+	genMBSamples := func(t *testing.T, group string, recentMB, baselineMB float64) []metriccache.MetricSample {
+		var samples []metriccache.MetricSample
+		for i, ts := range []time.Time{baselineTime, recentTime} {
+			mb := []float64{baselineMB, recentMB}[i]
+			sample, err := metriccache.ResctrlGroupMBMTotalMetric.GenerateSample(metriccache.MetricPropertiesFunc.ResctrlGroup(group), ts, mb)
+			assert.NoError(t, err)
+			samples = append(samples, sample)
+		}
+		return samples
+	}
+	type fields struct {
+		adaptiveState *resctrlAdaptiveState
+		genSamples    func(t *testing.T) []metriccache.MetricSample
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		arg       *slov1alpha1.ResourceQOS
+		want      *slov1alpha1.ResourceQOS
+		wantState *resctrlAdaptiveState
+	}{
+		{
+			name: "adaptive not configured",
+			fields: fields{
+				adaptiveState: &resctrlAdaptiveState{catRangeEndPercent: 20, mbaPercent: 50},
+			},
+			arg:       testBEQOS(nil),
+			want:      testBEQOS(nil),
+			wantState: nil,
+		},
+		{
+			name: "adaptive disabled",
+			arg: testBEQOS(&slov1alpha1.ResctrlAdaptiveCfg{
+				Enable: pointer.Bool(false),
+			}),
+			want: testBEQOS(&slov1alpha1.ResctrlAdaptiveCfg{
+				Enable: pointer.Bool(false),
+			}),
+			wantState: nil,
+		},
+		{
+			name: "start from the upper bounds",
+			arg:  testBEQOS(testAdaptiveCfg),
+			want: testBEQOS(testAdaptiveCfg),
+			wantState: &resctrlAdaptiveState{
+				catRangeEndPercent: 30,
+				mbaPercent:         100,
+				lastAdjustTime:     testNow,
+			},
+		},
+		{
+			name: "keep the state within the window",
+			fields: fields{
+				adaptiveState: &resctrlAdaptiveState{
+					catRangeEndPercent: 20,
+					mbaPercent:         90,
+					lastAdjustTime:     testNow.Add(-10 * time.Second),
+				},
+				genSamples: func(t *testing.T) []metriccache.MetricSample {
+					return genCPISamples(t, 2.0, 1.0)
+				},
+			},
+			arg: testBEQOS(testAdaptiveCfg),
+			want: func() *slov1alpha1.ResourceQOS {
+				q := testBEQOS(testAdaptiveCfg)
+				q.ResctrlQOS.CATRangeEndPercent = pointer.Int64(20)
+				q.ResctrlQOS.MBAPercent = pointer.Int64(90)
+				return q
+			}(),
+			wantState: &resctrlAdaptiveState{
+				catRangeEndPercent: 20,
+				mbaPercent:         90,
+				lastAdjustTime:     testNow.Add(-10 * time.Second),
+			},
+		},
+		{
+			name: "shrink when LS CPI degrades",
+			fields: fields{
+				adaptiveState: &resctrlAdaptiveState{
+					catRangeEndPercent: 30,
+					mbaPercent:         100,
+					lastAdjustTime:     testNow.Add(-2 * time.Minute),
+				},
+				genSamples: func(t *testing.T) []metriccache.MetricSample {
+					return genCPISamples(t, 1.5, 1.0)
+				},
+			},
+			arg: testBEQOS(testAdaptiveCfg),
+			want: func() *slov1alpha1.ResourceQOS {
+				q := testBEQOS(testAdaptiveCfg)
+				q.ResctrlQOS.CATRangeEndPercent = pointer.Int64(20)
+				q.ResctrlQOS.MBAPercent = pointer.Int64(90)
+				return q
+			}(),
+			wantState: &resctrlAdaptiveState{
+				catRangeEndPercent: 20,
+				mbaPercent:         90,
+				lastAdjustTime:     testNow,
+			},
+		},
+		{
+			name: "shrink to the lower bounds when LS memory bandwidth degrades",
+			fields: fields{
+				adaptiveState: &resctrlAdaptiveState{
+					catRangeEndPercent: 15,
+					mbaPercent:         35,
+					lastAdjustTime:     testNow.Add(-2 * time.Minute),
+				},
+				genSamples: func(t *testing.T) []metriccache.MetricSample {
+					samples := genCPISamples(t, 1.0, 1.0)
+					samples = append(samples, genMBSamples(t, LSResctrlGroup, 500, 1000)...)
+					return append(samples, genMBSamples(t, BEResctrlGroup, 2000, 1000)...)
+				},
+			},
+			arg: testBEQOS(testAdaptiveCfg),
+			want: func() *slov1alpha1.ResourceQOS {
+				q := testBEQOS(testAdaptiveCfg)
+				q.ResctrlQOS.CATRangeEndPercent = pointer.Int64(10)
+				q.ResctrlQOS.MBAPercent = pointer.Int64(30)
+				return q
+			}(),
+			wantState: &resctrlAdaptiveState{
+				catRangeEndPercent: 10,
+				mbaPercent:         30,
+				lastAdjustTime:     testNow,
+			},
+		},
+		{
+			name: "relax when LS memory bandwidth drops with BE",
+			fields: fields{
+				adaptiveState: &resctrlAdaptiveState{
+					catRangeEndPercent: 10,
+					mbaPercent:         30,
+					lastAdjustTime:     testNow.Add(-2 * time.Minute),
+				},
+				genSamples: func(t *testing.T) []metriccache.MetricSample {
+					samples := genMBSamples(t, LSResctrlGroup, 500, 1000)
+					return append(samples, genMBSamples(t, BEResctrlGroup, 500, 1000)...)
+				},
+			},
+			arg: testBEQOS(testAdaptiveCfg),
+			want: func() *slov1alpha1.ResourceQOS {
+				q := testBEQOS(testAdaptiveCfg)
+				q.ResctrlQOS.CATRangeEndPercent = pointer.Int64(20)
+				q.ResctrlQOS.MBAPercent = pointer.Int64(40)
+				return q
+			}(),
+			wantState: &resctrlAdaptiveState{
+				catRangeEndPercent: 20,
+				mbaPercent:         40,
+				lastAdjustTime:     testNow,
+			},
+		},
+		{
+			name: "relax to the upper bounds when the node is quiet",
+			fields: fields{
+				adaptiveState: &resctrlAdaptiveState{
+					catRangeEndPercent: 25,
+					mbaPercent:         95,
+					lastAdjustTime:     testNow.Add(-2 * time.Minute),
+				},
+			},
+			arg:  testBEQOS(testAdaptiveCfg),
+			want: testBEQOS(testAdaptiveCfg),
+			wantState: &resctrlAdaptiveState{
+				catRangeEndPercent: 30,
+				mbaPercent:         100,
+				lastAdjustTime:     testNow,
+			},
+		},
+		{
+			name: "clamp the state when the upper bounds change",
+			fields: fields{
+				adaptiveState: &resctrlAdaptiveState{
+					catRangeEndPercent: 60,
+					mbaPercent:         100,
+					lastAdjustTime:     testNow.Add(-10 * time.Second),
+				},
+			},
+			arg:  testBEQOS(testAdaptiveCfg),
+			want: testBEQOS(testAdaptiveCfg),
+			wantState: &resctrlAdaptiveState{
+				catRangeEndPercent: 30,
+				mbaPercent:         100,
+				lastAdjustTime:     testNow.Add(-10 * time.Second),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              t.TempDir(),
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer func() {
+				metricCache.Close()
+			}()
+			if tt.fields.genSamples != nil {
+				appender := metricCache.Appender()
+				assert.NoError(t, appender.Append(tt.fields.genSamples(t)))
+				assert.NoError(t, appender.Commit())
+			}
+			statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+			statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+				{
+					Pod: testLSPod,
+				},
+			}).AnyTimes()
+
+			r := &resctrlReconcile{
+				statesInformer: statesInformer,
+				metricCache:    metricCache,
+				adaptiveState:  tt.fields.adaptiveState,
+			}
+			got := r.getAdaptiveResourceQOS(tt.arg)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantState, r.adaptiveState)
+		})
+	}
+}
@@ -77,6 +77,7 @@ type resctrlReconcile struct {
 	metricCache       metriccache.MetricCache
 	cgroupReader      resourceexecutor.CgroupReader
 	eventRecorder     record.EventRecorder
+	adaptiveState     *resctrlAdaptiveState
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -107,8 +108,11 @@ func (r *resctrlReconcile) init(stopCh <-chan struct{}) {
 }
 
 func getPodResctrlGroup(pod *corev1.Pod) string {
-	podQoS := extension.GetPodQoSClassWithDefault(pod)
-	switch podQoS {
+	return getResctrlGroupByQoS(extension.GetPodQoSClassWithDefault(pod))
+}
+
+func getResctrlGroupByQoS(qos extension.QoSClass) string {
+	switch qos {
 	case extension.QoSLSE:
 		return LSRResctrlGroup
 	case extension.QoSLSR:
@@ -427,6 +431,9 @@ func (r *resctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.Re
 	// calculate and apply l3 cat policy for each group
 	for _, group := range resctrlGroupList {
 		resQoSStrategy := getResourceQOSForResctrlGroup(qosStrategy, group)
+		if group == BEResctrlGroup {
+			resQoSStrategy = r.getAdaptiveResourceQOS(resQoSStrategy)
+		}
 		err = r.calculateAndApplyCatL3PolicyForGroup(group, cbm, l3Num, resQoSStrategy)
 		if err != nil {
 			klog.Warningf("failed to apply l3 cat policy for group %v, err: %v", group, err)
@@ -438,8 +445,9 @@ func (r *resctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.Re
 	}
 }
 
-func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
-	// 1. retrieve task ids for each slo by reading cgroup task file of every pod container
+func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.ResourceQOSStrategy,
+	hostApps []slov1alpha1.HostApplicationSpec) {
+	// 1. retrieve task ids for each slo by reading cgroup task file of every pod container and host application
 	// 2. add the related task ids in resctrl groups
 
 	// NOTE: pid_max can be found in `/proc/sys/kernel/pid_max` on linux.
@@ -479,6 +487,26 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 		}
 	}
 
+	for i := range hostApps {
+		hostApp := &hostApps[i]
+		group := getResctrlGroupByQoS(hostApp.QoS)
+		if group == UnknownResctrlGroup {
+			continue
+		}
+		hostAppCfg := helpers.GetHostAppResourceQoS(hostApp, qosStrategy)
+		if hostAppCfg == nil || hostAppCfg.ResctrlQOS == nil || hostAppCfg.ResctrlQOS.Enable == nil || !(*hostAppCfg.ResctrlQOS.Enable) {
+			klog.V(5).Infof("host app %v with qos %v disabled resctrl", hostApp.Name, hostApp.QoS)
+			continue
+		}
+		ids, err := r.getContainerCgroupNewTaskIds(koordletutil.GetHostAppCgroupRelativePath(hostApp), curTaskMaps[group])
+		if err != nil {
+			klog.Warningf("failed to get cgroup task ids for host app %s, err: %s", hostApp.Name, err)
+			continue
+		}
+		taskIds[group] = append(taskIds[group], ids...)
+		klog.V(6).Infof("host app %v apply to group %s with %v tasks", hostApp.Name, group, len(ids))
+	}
+
 	// write Cat L3 tasks for each resctrl group
 	for _, group := range resctrlGroupList {
 		err = r.calculateAndApplyCatL3GroupTasks(group, taskIds[group])
@@ -508,16 +536,23 @@ func (r *resctrlReconcile) reconcile() {
 	// skip if host not support resctrl
 	if support, err := system.IsSupportResctrl(); err != nil {
 		klog.Warningf("check support resctrl failed, err: %s", err)
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(ResctrlReconcileName, slov1alpha1.StrategyConditionError,
+			fmt.Sprintf("check support resctrl failed, err: %s", err))
 		return
 	} else if !support {
 		klog.V(5).Infof("resctrlReconcile skipped, cpu not support CAT/MBA")
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(ResctrlReconcileName, slov1alpha1.StrategyConditionUnsupported,
+			"cpu not support CAT/MBA")
 		return
 	}
 
 	if err := initCatResctrl(); err != nil {
 		klog.V(4).Infof("resctrlReconcile failed, cannot initialize cat resctrl group, err: %s", err)
+		statesinformer.DefaultStrategyStatusRecorder.SetCondition(ResctrlReconcileName, slov1alpha1.StrategyConditionError,
+			fmt.Sprintf("cannot initialize cat resctrl group, err: %s", err))
 		return
 	}
 	r.reconcileCatResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
-	r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy)
+	r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy, nodeSLO.Spec.HostApplications)
+	statesinformer.DefaultStrategyStatusRecorder.SetCondition(ResctrlReconcileName, slov1alpha1.StrategyConditionApplied, "")
 }
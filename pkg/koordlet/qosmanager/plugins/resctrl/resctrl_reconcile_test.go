@@ -1263,6 +1263,26 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 		},
 		CgroupDir: "kubepods.slice/p1",
 	}
+	wantResctrlLSTaskStr := "5001"
+	testingHostApps := []slov1alpha1.HostApplicationSpec{
+		{
+			Name: "test-ls-host-app",
+			QoS:  extension.QoSLS,
+			Strategy: &slov1alpha1.HostApplicationStrategy{
+				ResourceQOS: &slov1alpha1.ResourceQOS{
+					ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+						Enable: pointer.Bool(true),
+					},
+				},
+			},
+		},
+		{
+			// resctrl of the qos class is disabled
+			Name: "test-system-host-app",
+			QoS:  extension.QoSSystem,
+		},
+	}
+	testingHostAppTasksStr := "5001"
 	testQOSStrategy := sloconfig.DefaultResourceQOSStrategy()
 	testQOSStrategy.BEClass.ResctrlQOS.Enable = pointer.Bool(true)
 	testQOSStrategy.LSRClass.ResctrlQOS.Enable = pointer.Bool(true)
@@ -1292,9 +1312,12 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 		testingPrepareResctrlL3CatGroups(t, "", "")
 		testingPrepareContainerCgroupCPUTasks(t, helper, testingContainerParentDir, testingContainerTasksStr)
 		testingPrepareContainerCgroupCPUTasks(t, helper, testingContainer1ParentDir, testingContainer1TasksStr)
+		for i := range testingHostApps {
+			testingPrepareContainerCgroupCPUTasks(t, helper, koordletutil.GetHostAppCgroupRelativePath(&testingHostApps[i]), testingHostAppTasksStr)
+		}
 
 		// run reconcileResctrlGroups for BE & LSE tasks not exist
-		r.reconcileResctrlGroups(testQOSStrategy)
+		r.reconcileResctrlGroups(testQOSStrategy, testingHostApps)
 
 		// check if the reconciliation is a success
 		out, err := os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
@@ -1305,12 +1328,16 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, wantResctrlLSRTaskStr, string(out))
 
+		out, err = os.ReadFile(system.ResctrlTasks.Path(LSResctrlGroup))
+		assert.NoError(t, err)
+		assert.Equal(t, wantResctrlLSTaskStr, string(out))
+
 		beTasksPath := filepath.Join(system.ResctrlTasks.Path(BEResctrlGroup))
 		err = os.WriteFile(beTasksPath, []byte(testingBEResctrlTasksStr), 0666)
 		assert.NoError(t, err)
 
 		// run reconcileResctrlGroups
-		r.reconcileResctrlGroups(testQOSStrategy)
+		r.reconcileResctrlGroups(testQOSStrategy, testingHostApps)
 
 		// check if the reconciliation is a success
 		out, err = os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
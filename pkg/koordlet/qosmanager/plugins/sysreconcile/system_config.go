@@ -34,6 +34,8 @@ import (
 
 const (
 	SystemConfigReconcileName = "SystemConfigReconcile"
+
+	reasonSystemConfigReconcile = "systemConfig reconcile"
 )
 
 type systemConfig struct {
@@ -56,6 +58,10 @@ func (r *systemConfig) Enabled() bool {
 	return features.DefaultKoordletFeatureGate.Enabled(features.SystemConfig) && r.reconcileInterval > 0
 }
 
+func (r *systemConfig) RollbackReasons() []string {
+	return []string{reasonSystemConfigReconcile}
+}
+
 func (r *systemConfig) Setup(context *framework.Context) {
 }
 
@@ -106,7 +112,7 @@ func caculateMemoryConfig(strategy *slov1alpha1.SystemStrategy, nodeMemory int64
 		if sysutil.ValidateResourceValue(&minFreeKbytes, "", sysutil.MinFreeKbytes) {
 			valueStr := strconv.FormatInt(minFreeKbytes, 10)
 			file := sysutil.MinFreeKbytes.Path("")
-			eventHelper := audit.V(3).Node().Reason("systemConfig reconcile").Message("update calculated mem config min_free_kbytes to : %v", valueStr)
+			eventHelper := audit.V(3).Node().Reason(reasonSystemConfigReconcile).Message("update calculated mem config min_free_kbytes to : %v", valueStr)
 			resource, err := resourceexecutor.NewCommonDefaultUpdater(file, file, valueStr, eventHelper)
 			if err != nil {
 				return resources
@@ -118,7 +124,7 @@ func caculateMemoryConfig(strategy *slov1alpha1.SystemStrategy, nodeMemory int64
 	if sysutil.ValidateResourceValue(strategy.WatermarkScaleFactor, "", sysutil.WatermarkScaleFactor) {
 		valueStr := strconv.FormatInt(*strategy.WatermarkScaleFactor, 10)
 		file := sysutil.WatermarkScaleFactor.Path("")
-		eventHelper := audit.V(3).Node().Reason("systemConfig reconcile").Message("update calculated mem config watermark_scale_factor to : %v", valueStr)
+		eventHelper := audit.V(3).Node().Reason(reasonSystemConfigReconcile).Message("update calculated mem config watermark_scale_factor to : %v", valueStr)
 		resource, err := resourceexecutor.NewCommonDefaultUpdater(file, file, valueStr, eventHelper)
 		if err != nil {
 			return resources
@@ -129,7 +135,7 @@ func caculateMemoryConfig(strategy *slov1alpha1.SystemStrategy, nodeMemory int64
 	if sysutil.ValidateResourceValue(strategy.MemcgReapBackGround, "", sysutil.MemcgReapBackGround) {
 		valueStr := strconv.FormatInt(*strategy.MemcgReapBackGround, 10)
 		file := sysutil.MemcgReapBackGround.Path("")
-		eventHelper := audit.V(3).Node().Reason("systemConfig reconcile").Message("update calculated mem config reap_background to : %v", valueStr)
+		eventHelper := audit.V(3).Node().Reason(reasonSystemConfigReconcile).Message("update calculated mem config reap_background to : %v", valueStr)
 		resource, err := resourceexecutor.NewCommonDefaultUpdater(file, file, valueStr, eventHelper)
 		if err != nil {
 			return resources
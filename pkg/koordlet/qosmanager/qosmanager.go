@@ -112,10 +112,13 @@ func (r *qosManager) Run(stopCh <-chan struct{}) error {
 		return fmt.Errorf("time out waiting for states informer caches to sync")
 	}
 
+	// start the executor before the strategies to load the journal for rolling back the disabled ones
+	resourceexecutor.NewResourceUpdateExecutor().Run(stopCh)
 	for name, strategy := range r.context.Strategies {
 		klog.V(4).Infof("ready to start qos strategy %v", name)
 		if !strategy.Enabled() {
 			klog.V(4).Infof("qos strategy %v is not enabled, skip running", name)
+			framework.RollbackStrategyResources(name, strategy)
 			continue
 		}
 		go strategy.Run(stopCh)
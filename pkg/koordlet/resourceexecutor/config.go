@@ -25,22 +25,36 @@ const (
 
 	EvictPodByNodeMemoryUsage   = "EvictPodByNodeMemoryUsage"
 	EvictPodByBECPUSatisfaction = "EvictPodByBECPUSatisfaction"
+	EvictPodByMidCPUUsage       = "EvictPodByMidCPUUsage"
+	EvictPodByMidMemoryUsage    = "EvictPodByMidMemoryUsage"
 
-	AdjustBEByNodeCPUUsage = "AdjustBEByNodeCPUUsage"
+	AdjustBEByNodeCPUUsage  = "AdjustBEByNodeCPUUsage"
+	AdjustMidByNodeCPUUsage = "AdjustMidByNodeCPUUsage"
 )
 
 var Conf = NewDefaultConfig()
 
 type Config struct {
 	ResourceForceUpdateSeconds int
+	// JournalDir is the directory of the on-disk journal of the resource updates.
+	JournalDir string
+	// JournalMaxEntries is the max number of entries kept in a journal file.
+	JournalMaxEntries int
+	// JournalRollbackOnExit restores the resources recorded in the journal when koordlet exits.
+	JournalRollbackOnExit bool
 }
 
 func NewDefaultConfig() *Config {
 	return &Config{
 		ResourceForceUpdateSeconds: 60,
+		JournalDir:                 "/host-var-run-koordlet/resource-journal/",
+		JournalMaxEntries:          10000,
 	}
 }
 
 func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.ResourceForceUpdateSeconds, "resource-force-update-seconds", c.ResourceForceUpdateSeconds, "executor force update resources interval by seconds")
+	fs.StringVar(&c.JournalDir, "resource-journal-dir", c.JournalDir, "the directory of the journal which records the resource updates, it takes effect when the feature-gate ResourceJournal is enabled")
+	fs.IntVar(&c.JournalMaxEntries, "resource-journal-max-entries", c.JournalMaxEntries, "the max number of entries in a journal file, the older file is rotated when exceeded")
+	fs.BoolVar(&c.JournalRollbackOnExit, "resource-journal-rollback-on-exit", c.JournalRollbackOnExit, "restore the resources recorded in the journal to their origin values when koordlet exits, e.g. koordlet is uninstalled from the node")
 }
@@ -26,6 +26,8 @@ import (
 func Test_NewDefaultConfig(t *testing.T) {
 	expectConfig := &Config{
 		ResourceForceUpdateSeconds: 60,
+		JournalDir:                 "/host-var-run-koordlet/resource-journal/",
+		JournalMaxEntries:          10000,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -34,6 +36,8 @@ func Test_NewDefaultConfig(t *testing.T) {
 func Test_InitFlags(t *testing.T) {
 	type fields struct {
 		ResourceForceUpdateSeconds int
+		JournalDir                 string
+		JournalMaxEntries          int
 	}
 	type args struct {
 		fs      *flag.FlagSet
@@ -48,6 +52,8 @@ func Test_InitFlags(t *testing.T) {
 			name: "not default",
 			fields: fields{
 				ResourceForceUpdateSeconds: 120,
+				JournalDir:                 "/host-var-run-koordlet/resource-journal/",
+				JournalMaxEntries:          10000,
 			},
 			args: args{
 				fs: flag.NewFlagSet("", flag.ExitOnError),
@@ -61,12 +67,16 @@ func Test_InitFlags(t *testing.T) {
 			name: "not default 1",
 			fields: fields{
 				ResourceForceUpdateSeconds: 90,
+				JournalDir:                 "/tmp/resource-journal/",
+				JournalMaxEntries:          100,
 			},
 			args: args{
 				fs: flag.NewFlagSet("", flag.ExitOnError),
 				cmdArgs: []string{
 					"",
 					"--resource-force-update-seconds=90",
+					"--resource-journal-dir=/tmp/resource-journal/",
+					"--resource-journal-max-entries=100",
 				},
 			},
 		},
@@ -75,6 +85,8 @@ func Test_InitFlags(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			want := &Config{
 				ResourceForceUpdateSeconds: tt.fields.ResourceForceUpdateSeconds,
+				JournalDir:                 tt.fields.JournalDir,
+				JournalMaxEntries:          tt.fields.JournalMaxEntries,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"container/list"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+)
+
+const (
+	ReasonDryRunUpdateResources = "DryRunUpdateResources"
+
+	// DefaultDryRunMaxRecords is the max number of the resources recorded in the dry-run mode.
+	DefaultDryRunMaxRecords = 4096
+)
+
+// DefaultDryRunRecorder records the resource updates when the executor runs in the dry-run mode.
+var DefaultDryRunRecorder = NewDryRunRecorder(DefaultDryRunMaxRecords)
+
+// DryRunRecord is a resource update which is recorded instead of applied in the dry-run mode.
+type DryRunRecord struct {
+	Key          string    `json:"key"`
+	Path         string    `json:"path"`
+	ResourceType string    `json:"resourceType"`
+	OldValue     string    `json:"oldValue"`
+	NewValue     string    `json:"newValue"`
+	ReadError    string    `json:"readError,omitempty"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Pod          string    `json:"pod,omitempty"`
+	Container    string    `json:"container,omitempty"`
+	Strategy     string    `json:"strategy,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	Count        int64     `json:"count"`
+	RecordedAt   time.Time `json:"recordedAt"`
+}
+
+// Changed returns whether the recorded update would change the current value.
+func (r DryRunRecord) Changed() bool {
+	return r.ReadError != "" || r.OldValue != r.NewValue
+}
+
+type DryRunRecorder struct {
+	lock       sync.RWMutex
+	maxRecords int
+	records    map[string]*list.Element
+	// recordList orders the records from the least recently recorded, which is evicted first when the records
+	// exceed the max number
+	recordList *list.List
+}
+
+func NewDryRunRecorder(maxRecords int) *DryRunRecorder {
+	return &DryRunRecorder{
+		maxRecords: maxRecords,
+		records:    map[string]*list.Element{},
+		recordList: list.New(),
+	}
+}
+
+// Record records the updates with the current values and the owners, and returns the number of updates which would
+// change the current values. The latest update of a resource overrides the previous one, and the least recently
+// recorded resources are evicted when the records exceed the max number.
+func (r *DryRunRecorder) Record(updaters ...ResourceUpdater) int {
+	changed := 0
+	now := time.Now()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, updater := range updaters {
+		record := newDryRunRecord(updater, now)
+		if record.Changed() {
+			changed++
+		}
+		if e, ok := r.records[record.Key]; ok {
+			record.Count = e.Value.(*DryRunRecord).Count + 1
+			e.Value = record
+			r.recordList.MoveToBack(e)
+			continue
+		}
+		r.records[record.Key] = r.recordList.PushBack(record)
+		for r.maxRecords > 0 && r.recordList.Len() > r.maxRecords {
+			oldest := r.recordList.Front()
+			r.recordList.Remove(oldest)
+			delete(r.records, oldest.Value.(*DryRunRecord).Key)
+		}
+	}
+	return changed
+}
+
+// List returns the records sorted by the key.
+func (r *DryRunRecorder) List() []DryRunRecord {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	records := make([]DryRunRecord, 0, len(r.records))
+	for e := r.recordList.Front(); e != nil; e = e.Next() {
+		records = append(records, *e.Value.(*DryRunRecord))
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+	return records
+}
+
+// Reset clears the records.
+func (r *DryRunRecorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.records = map[string]*list.Element{}
+	r.recordList = list.New()
+}
+
+func newDryRunRecord(updater ResourceUpdater, now time.Time) *DryRunRecord {
+	record := &DryRunRecord{
+		Key:          updater.Key(),
+		Path:         updater.Path(),
+		ResourceType: string(updater.ResourceType()),
+		NewValue:     updater.Value(),
+		Count:        1,
+		RecordedAt:   now,
+	}
+	oldValue, err := readResourceValue(updater.Path())
+	if err != nil {
+		record.ReadError = err.Error()
+	} else {
+		record.OldValue = oldValue
+	}
+	if e := getEventHelper(updater); e != nil {
+		if e.Event.Type == "pod" {
+			record.Namespace = e.Event.Namespace
+			record.Pod = e.Event.Name
+		}
+		record.Container = e.Event.Container
+		record.Strategy = e.Event.Reason
+		record.Message = e.Event.Message
+	}
+	return record
+}
+
+func getEventHelper(updater ResourceUpdater) *audit.EventHelper {
+	u, ok := updater.(interface{ GetEventHelper() *audit.EventHelper })
+	if !ok {
+		return nil
+	}
+	return u.GetEventHelper()
+}
+
+// DryRunResponse is the response of the dry-run http endpoint.
+type DryRunResponse struct {
+	DryRun  bool           `json:"dryRun"`
+	Changed int            `json:"changed"`
+	Records []DryRunRecord `json:"records"`
+}
+
+// DryRunHttpHandler returns the http handler to preview the resource updates recorded in the dry-run mode.
+// Use the query `changed=true` to list only the updates which would change the current values.
+func DryRunHttpHandler() func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		onlyChanged := r.URL.Query().Get("changed") == "true"
+		response := &DryRunResponse{
+			DryRun:  singleton.IsDryRun(),
+			Records: []DryRunRecord{},
+		}
+		for _, record := range DefaultDryRunRecorder.List() {
+			if !record.Changed() {
+				if onlyChanged {
+					continue
+				}
+			} else {
+				response.Changed++
+			}
+			response.Records = append(response.Records, record)
+		}
+		writeJSON(rw, response)
+	}
+}
+
+// IsDryRunByNodeSLO returns whether the NodeSLO of the node enables the dry-run mode of the executor.
+func IsDryRunByNodeSLO(nodeSLOSpec *slov1alpha1.NodeSLOSpec) bool {
+	if nodeSLOSpec == nil || nodeSLOSpec.SystemStrategy == nil || nodeSLOSpec.SystemStrategy.ResourceExecutorDryRun == nil {
+		return false
+	}
+	return *nodeSLOSpec.SystemStrategy.ResourceExecutorDryRun
+}
+
+// dryRun records the updates instead of applying them, and summarises the updates as an audit event.
+func (e *ResourceUpdateExecutorImpl) dryRun(updaters ...ResourceUpdater) {
+	if len(updaters) <= 0 {
+		return
+	}
+	changed := DefaultDryRunRecorder.Record(updaters...)
+	klog.V(5).Infof("dry-run update resources, total %v, changed %v", len(updaters), changed)
+	if changed > 0 {
+		_ = audit.V(3).Node().Reason(ReasonDryRunUpdateResources).Message("dry-run update %v resources, %v of them would be changed, e.g. %s to %v",
+			len(updaters), changed, updaters[0].Key(), updaters[0].Value()).Do()
+	}
+}
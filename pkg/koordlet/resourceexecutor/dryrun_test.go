@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func TestResourceUpdateExecutor_DryRun(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	DefaultDryRunRecorder.Reset()
+	defer DefaultDryRunRecorder.Reset()
+	singleton.SetDryRun(true)
+	defer singleton.SetDryRun(false)
+
+	sharesUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSharesName, "kubepods/pod-test", "1024",
+		audit.V(3).Pod("default", "test-pod").Reason(ReasonUpdateCgroups).Message("update cpu shares"))
+	assert.NoError(t, err)
+	quotaUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, "kubepods/pod-test", "-1", nil)
+	assert.NoError(t, err)
+	cpusetUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSetCPUSName, "kubepods", "0-7", nil)
+	assert.NoError(t, err)
+	helper.WriteFileContents(sharesUpdater.Path(), "2")
+	helper.WriteFileContents(quotaUpdater.Path(), "-1")
+
+	e := &ResourceUpdateExecutorImpl{
+		ResourceCache: cache.NewCacheDefault(),
+		Config:        Conf,
+	}
+	e.SetDryRun(true)
+	stop := make(chan struct{})
+	defer close(stop)
+	e.Run(stop)
+
+	updated, err := e.Update(true, sharesUpdater)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	e.UpdateBatch(false, quotaUpdater)
+	e.LeveledUpdateBatch([][]ResourceUpdater{{cpusetUpdater}})
+
+	// nothing is applied
+	assert.Equal(t, "2", helper.ReadFileContents(sharesUpdater.Path()))
+	_, err = os.Stat(cpusetUpdater.Path())
+	assert.True(t, os.IsNotExist(err))
+
+	records := DefaultDryRunRecorder.List()
+	assert.Equal(t, 3, len(records))
+	recordMap := map[string]DryRunRecord{}
+	for _, record := range records {
+		recordMap[record.Key] = record
+	}
+	sharesRecord := recordMap[sharesUpdater.Key()]
+	assert.Equal(t, "2", sharesRecord.OldValue)
+	assert.Equal(t, "1024", sharesRecord.NewValue)
+	assert.Equal(t, "default", sharesRecord.Namespace)
+	assert.Equal(t, "test-pod", sharesRecord.Pod)
+	assert.Equal(t, ReasonUpdateCgroups, sharesRecord.Strategy)
+	assert.True(t, sharesRecord.Changed())
+	assert.False(t, recordMap[quotaUpdater.Key()].Changed())
+	assert.NotEmpty(t, recordMap[cpusetUpdater.Key()].ReadError)
+
+	// the latest update overrides the previous one
+	e.UpdateBatch(true, sharesUpdater)
+	for _, record := range DefaultDryRunRecorder.List() {
+		if record.Key == sharesUpdater.Key() {
+			assert.Equal(t, int64(2), record.Count)
+		}
+	}
+
+	rw := httptest.NewRecorder()
+	DryRunHttpHandler()(rw, httptest.NewRequest(http.MethodGet, "/resource-updates/dry-run?changed=true", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+	response := &DryRunResponse{}
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), response))
+	assert.True(t, response.DryRun)
+	assert.Equal(t, 2, response.Changed)
+	assert.Equal(t, 2, len(response.Records))
+}
+
+func TestDryRunRecorder_MaxRecords(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	r := NewDryRunRecorder(2)
+
+	updaterA, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSharesName, "kubepods/pod-a", "1024", nil)
+	assert.NoError(t, err)
+	updaterB, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSharesName, "kubepods/pod-b", "1024", nil)
+	assert.NoError(t, err)
+	updaterC, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSharesName, "kubepods/pod-c", "1024", nil)
+	assert.NoError(t, err)
+
+	r.Record(updaterA, updaterB)
+	// the recently recorded resource is kept
+	r.Record(updaterA)
+	r.Record(updaterC)
+	records := r.List()
+	assert.Equal(t, 2, len(records))
+	keys := map[string]int64{}
+	for _, record := range records {
+		keys[record.Key] = record.Count
+	}
+	assert.Equal(t, map[string]int64{updaterA.Key(): 2, updaterC.Key(): 1}, keys)
+
+	r.Reset()
+	assert.Equal(t, 0, len(r.List()))
+}
+
+func TestIsDryRunByNodeSLO(t *testing.T) {
+	assert.False(t, IsDryRunByNodeSLO(nil))
+	assert.False(t, IsDryRunByNodeSLO(&slov1alpha1.NodeSLOSpec{}))
+	assert.False(t, IsDryRunByNodeSLO(&slov1alpha1.NodeSLOSpec{
+		SystemStrategy: &slov1alpha1.SystemStrategy{ResourceExecutorDryRun: pointer.Bool(false)},
+	}))
+	assert.True(t, IsDryRunByNodeSLO(&slov1alpha1.NodeSLOSpec{
+		SystemStrategy: &slov1alpha1.SystemStrategy{ResourceExecutorDryRun: pointer.Bool(true)},
+	}))
+}
@@ -18,11 +18,15 @@ package resourceexecutor
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"go.uber.org/atomic"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util/cache"
 )
@@ -38,7 +42,11 @@ type ResourceUpdateExecutor interface {
 	// 1. update batch of cgroup resources group by cgroup interface, i.e. cgroup filename.
 	// 2. update each cgroup resource by the order of layers: firstly update resources from upper to lower by merging
 	//    the new value with old value; then update resources from lower to upper with the new value.
-	LeveledUpdateBatch(updaters [][]ResourceUpdater)
+	// It returns the aggregated error of the resources failed to update.
+	LeveledUpdateBatch(updaters [][]ResourceUpdater) error
+	// SetDryRun sets whether the executor records the resource updates instead of applying them.
+	SetDryRun(dryRun bool)
+	IsDryRun() bool
 	Run(stopCh <-chan struct{})
 }
 
@@ -49,6 +57,11 @@ type ResourceUpdateExecutorImpl struct {
 
 	onceRun   sync.Once
 	gcStarted bool
+	// dryRun is set by the NodeSLO of the node
+	dryRunEnabled atomic.Bool
+	// journal records the resource updates on disk if the ResourceJournal is enabled
+	journalLock sync.RWMutex
+	journal     *Journal
 }
 
 var singleton = &ResourceUpdateExecutorImpl{
@@ -62,6 +75,10 @@ func NewResourceUpdateExecutor() ResourceUpdateExecutor {
 
 // Update updates the resources with the given cacheable attribute with the cacheable attribute directly.
 func (e *ResourceUpdateExecutorImpl) Update(cacheable bool, resource ResourceUpdater) (bool, error) {
+	if e.IsDryRun() {
+		e.dryRun(resource)
+		return false, nil
+	}
 	if cacheable {
 		if !e.gcStarted {
 			klog.V(5).Info("failed to cacheable update resources, err: cache GC is not started")
@@ -75,6 +92,10 @@ func (e *ResourceUpdateExecutorImpl) Update(cacheable bool, resource ResourceUpd
 // UpdateBatch updates a batch of resources with the given cacheable attribute.
 // TODO: merge and resolve conflicts of batch updates from multiple callers.
 func (e *ResourceUpdateExecutorImpl) UpdateBatch(cacheable bool, updaters ...ResourceUpdater) {
+	if e.IsDryRun() {
+		e.dryRun(updaters...)
+		return
+	}
 	failures := 0
 	if cacheable {
 		if !e.gcStarted {
@@ -110,23 +131,38 @@ func (e *ResourceUpdateExecutorImpl) UpdateBatch(cacheable bool, updaters ...Res
 		cacheable, len(updaters), failures)
 }
 
-func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpdater) {
+func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpdater) error {
 	e.LeveledUpdateLock.Lock()
 	defer e.LeveledUpdateLock.Unlock()
+	if e.IsDryRun() {
+		var flattened []ResourceUpdater
+		for i := range updaters {
+			flattened = append(flattened, updaters[i]...)
+		}
+		e.dryRun(flattened...)
+		return nil
+	}
 	if !e.gcStarted {
 		klog.Error("failed to cacheable level update resources, err: cache GC is not started")
-		return
+		return fmt.Errorf("cache GC is not started")
 	}
 
 	var err error
 	skipMerge := map[string]bool{}
+	// failures records the last error of each resource, which is cleared if the resource is updated at last
+	failures := map[string]error{}
 	for i := 0; i < len(updaters); i++ {
 		for _, updater := range updaters[i] {
 			if !e.needUpdate(updater) {
 				continue
 			}
 
-			mergedUpdater, err := updater.MergeUpdate()
+			var mergedUpdater ResourceUpdater
+			err := e.journaledUpdate(updater, func() error {
+				var mergeErr error
+				mergedUpdater, mergeErr = updater.MergeUpdate()
+				return mergeErr
+			})
 			if err != nil && e.isUpdateErrIgnored(err) {
 				klog.V(5).Infof("failed to merge update resource %s to %v, ignored err: %v",
 					updater.Key(), updater.Value(), err)
@@ -135,6 +171,7 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 			if err != nil {
 				klog.V(4).Infof("failed to merge update resource %s to %v, err: %v",
 					updater.Key(), updater.Value(), err)
+				failures[updater.Key()] = err
 				continue
 			}
 			klog.V(5).Infof("successfully merge update resource %s to %v", updater.Key(), updater.Value())
@@ -165,16 +202,19 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 				klog.V(6).Infof("skip update resource %s since it should skip the merge", updater.Key())
 				continue
 			}
-			err = updater.update()
+			err = e.journaledUpdate(updater, updater.update)
 			if err != nil && e.isUpdateErrIgnored(err) {
 				klog.V(5).Infof("failed to update resource %s to %v, ignored err: %v", updater.Key(), updater.Value(), err)
+				delete(failures, updater.Key())
 				continue
 			}
 			if err != nil {
 				klog.V(4).Infof("failed update resource %s, err: %v", updater.Key(), err)
+				failures[updater.Key()] = err
 				continue
 			}
 			klog.V(6).Infof("successfully update resource %s to %v", updater.Key(), updater.Value())
+			delete(failures, updater.Key())
 
 			updater.UpdateLastUpdateTimestamp(time.Now())
 			err = e.ResourceCache.SetDefault(updater.Key(), updater)
@@ -184,6 +224,17 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 			}
 		}
 	}
+
+	keys := make([]string, 0, len(failures))
+	for key := range failures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	errs := make([]error, 0, len(keys))
+	for _, key := range keys {
+		errs = append(errs, fmt.Errorf("%s: %w", key, failures[key]))
+	}
+	return utilerrors.NewAggregate(errs)
 }
 
 // Run runs the ResourceUpdateExecutor.
@@ -196,10 +247,62 @@ func (e *ResourceUpdateExecutorImpl) Run(stopCh <-chan struct{}) {
 
 func (e *ResourceUpdateExecutorImpl) run(stopCh <-chan struct{}) {
 	_ = e.ResourceCache.Run(stopCh)
+	if features.DefaultKoordletFeatureGate.Enabled(features.ResourceJournal) && e.Config != nil {
+		journal, err := NewJournal(e.Config.JournalDir, e.Config.JournalMaxEntries)
+		if err != nil {
+			klog.Errorf("failed to create resource journal, err: %v", err)
+		} else {
+			journal.onRestored = e.ResourceCache.Delete
+			e.setJournal(journal)
+			go journal.Run(stopCh)
+		}
+	}
 	klog.V(4).Info("starting ResourceUpdateExecutor successfully")
 	e.gcStarted = true
 }
 
+// journaledUpdate applies the update and records it in the journal if the journal is enabled.
+func (e *ResourceUpdateExecutorImpl) journaledUpdate(updater ResourceUpdater, updateFn func() error) error {
+	journal := e.getJournal()
+	if journal == nil {
+		return updateFn()
+	}
+	oldValue, readErr := readResourceValue(updater.Path())
+	err := updateFn()
+	if err != nil {
+		return err
+	}
+	// the written value can differ from the updater's, e.g. the merged cpuset
+	newValue, newReadErr := readResourceValue(updater.Path())
+	if newReadErr != nil {
+		newValue = updater.Value()
+	}
+	journal.Record(updater, oldValue, newValue, readErr)
+	return nil
+}
+
+func (e *ResourceUpdateExecutorImpl) getJournal() *Journal {
+	e.journalLock.RLock()
+	defer e.journalLock.RUnlock()
+	return e.journal
+}
+
+func (e *ResourceUpdateExecutorImpl) setJournal(journal *Journal) {
+	e.journalLock.Lock()
+	defer e.journalLock.Unlock()
+	e.journal = journal
+}
+
+func (e *ResourceUpdateExecutorImpl) SetDryRun(dryRun bool) {
+	if e.dryRunEnabled.Swap(dryRun) != dryRun {
+		klog.V(4).Infof("resource executor dry-run mode changed to %v", dryRun)
+	}
+}
+
+func (e *ResourceUpdateExecutorImpl) IsDryRun() bool {
+	return e.dryRunEnabled.Load()
+}
+
 func (e *ResourceUpdateExecutorImpl) needUpdate(updater ResourceUpdater) bool {
 	preResource, _ := e.ResourceCache.Get(updater.Key())
 	if preResource == nil {
@@ -221,7 +324,7 @@ func (e *ResourceUpdateExecutorImpl) needUpdate(updater ResourceUpdater) bool {
 }
 
 func (e *ResourceUpdateExecutorImpl) update(updater ResourceUpdater) error {
-	err := updater.update()
+	err := e.journaledUpdate(updater, updater.update)
 	if err != nil && e.isUpdateErrIgnored(err) {
 		klog.V(5).Infof("failed to update resource %s to %v, ignored err: %v", updater.Key(), updater.Value(), err)
 		return nil
@@ -236,7 +339,7 @@ func (e *ResourceUpdateExecutorImpl) update(updater ResourceUpdater) error {
 
 func (e *ResourceUpdateExecutorImpl) updateByCache(updater ResourceUpdater) (bool, error) {
 	if e.needUpdate(updater) {
-		err := updater.update()
+		err := e.journaledUpdate(updater, updater.update)
 		if err != nil && e.isUpdateErrIgnored(err) {
 			klog.V(5).Infof("failed to cacheable update resource %s to %v, ignored err: %v", updater.Key(), updater.Value(), err)
 			return false, nil
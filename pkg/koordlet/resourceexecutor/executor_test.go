@@ -246,3 +246,34 @@ func TestResourceUpdateExecutor_UpdateBatch(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceUpdateExecutor_LeveledUpdateBatch(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	e := &ResourceUpdateExecutorImpl{
+		ResourceCache: cache.NewCacheDefault(),
+		Config:        NewDefaultConfig(),
+	}
+	assert.Error(t, e.LeveledUpdateBatch(nil))
+	stop := make(chan struct{})
+	defer close(stop)
+	e.Run(stop)
+
+	qosUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, "kubepods/besteffort", "-1", nil)
+	assert.NoError(t, err)
+	podUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, "kubepods/besteffort/pod-test", "100000", nil)
+	assert.NoError(t, err)
+	helper.WriteFileContents(qosUpdater.Path(), "100000")
+	helper.WriteFileContents(podUpdater.Path(), "-1")
+	assert.NoError(t, e.LeveledUpdateBatch([][]ResourceUpdater{{qosUpdater}, {podUpdater}}))
+	assert.Equal(t, "-1", helper.ReadFileContents(qosUpdater.Path()))
+	assert.Equal(t, "100000", helper.ReadFileContents(podUpdater.Path()))
+
+	// the failed update is returned
+	failedUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, "kubepods/besteffort/pod-failed", "100000", nil)
+	assert.NoError(t, err)
+	helper.MkDirAll(failedUpdater.Path())
+	err = e.LeveledUpdateBatch([][]ResourceUpdater{{qosUpdater}, {failedUpdater}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), failedUpdater.Key())
+}
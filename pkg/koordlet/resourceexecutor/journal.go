@@ -0,0 +1,503 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	ReasonRollbackResources = "RollbackResources"
+
+	journalFileName        = "journal.log"
+	journalRotatedFileName = "journal.log.1"
+	journalOriginsFileName = "origins.json"
+
+	journalGCInterval = 10 * time.Minute
+)
+
+// JournalScope is the scope of the resource recorded in the journal.
+type JournalScope string
+
+const (
+	// JournalScopeRoot is the root cgroup of the kubernetes pods, e.g. `kubepods.slice`.
+	JournalScopeRoot JournalScope = "root"
+	// JournalScopeQoS is the cgroup of a kubernetes QoS class, e.g. `kubepods.slice/kubepods-besteffort.slice`.
+	JournalScopeQoS JournalScope = "qos"
+	// JournalScopePod is the cgroup of a pod or a container.
+	JournalScopePod JournalScope = "pod"
+	// JournalScopeOther is the other resource, e.g. the resctrl and the system config.
+	JournalScopeOther JournalScope = "other"
+)
+
+// JournalEntry is a resource update recorded in the journal.
+type JournalEntry struct {
+	Timestamp    time.Time    `json:"timestamp"`
+	Key          string       `json:"key"`
+	Path         string       `json:"path"`
+	ResourceType string       `json:"resourceType"`
+	Scope        JournalScope `json:"scope"`
+	OldValue     string       `json:"oldValue"`
+	NewValue     string       `json:"newValue"`
+	Strategy     string       `json:"strategy,omitempty"`
+}
+
+// JournalOrigin is the value of a resource before koordlet updates it, and the strategies updating it.
+type JournalOrigin struct {
+	// Key is the key of the resource in the executor cache.
+	Key        string       `json:"key,omitempty"`
+	Path       string       `json:"path"`
+	Scope      JournalScope `json:"scope"`
+	Value      string       `json:"value"`
+	Strategies []string     `json:"strategies,omitempty"`
+}
+
+// Journal records the resource updates on disk. The journal file is rotated when the entries exceed the limit,
+// so at most two files are kept. The origin values of the resources are kept separately for the rollback.
+type Journal struct {
+	lock       sync.Mutex
+	dir        string
+	maxEntries int
+	entries    int
+	origins    map[string]*JournalOrigin
+	// onRestored is called with the cache key of each resource restored by the rollback, since the restored value
+	// is written out of the executor and the cached value becomes stale.
+	onRestored func(key string)
+}
+
+func NewJournal(dir string, maxEntries int) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal dir %s, err: %w", dir, err)
+	}
+	j := &Journal{
+		dir:        dir,
+		maxEntries: maxEntries,
+		origins:    map[string]*JournalOrigin{},
+	}
+	if err := j.loadOrigins(); err != nil {
+		return nil, err
+	}
+	entries, err := j.readEntries(journalFileName)
+	if err != nil {
+		return nil, err
+	}
+	j.entries = len(entries)
+	return j, nil
+}
+
+// Record records a successful update of the resource from oldValue to newValue.
+// The oldValue is taken as the origin when the resource is recorded for the first time.
+func (j *Journal) Record(updater ResourceUpdater, oldValue, newValue string, readErr error) {
+	entry := &JournalEntry{
+		Timestamp:    time.Now(),
+		Key:          updater.Key(),
+		Path:         updater.Path(),
+		ResourceType: string(updater.ResourceType()),
+		Scope:        getJournalScope(updater),
+		OldValue:     oldValue,
+		NewValue:     newValue,
+	}
+	if e := getEventHelper(updater); e != nil {
+		entry.Strategy = e.Event.Reason
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if err := j.appendEntry(entry); err != nil {
+		klog.V(4).Infof("failed to append journal entry for resource %s, err: %v", entry.Key, err)
+	}
+	if readErr != nil {
+		return
+	}
+	origin, ok := j.origins[entry.Path]
+	if !ok {
+		j.origins[entry.Path] = &JournalOrigin{
+			Key:        entry.Key,
+			Path:       entry.Path,
+			Scope:      entry.Scope,
+			Value:      oldValue,
+			Strategies: []string{entry.Strategy},
+		}
+		if err := j.saveOrigins(); err != nil {
+			klog.V(4).Infof("failed to save journal origins, err: %v", err)
+		}
+		return
+	}
+	for _, s := range origin.Strategies {
+		if s == entry.Strategy {
+			return
+		}
+	}
+	origin.Strategies = append(origin.Strategies, entry.Strategy)
+	if err := j.saveOrigins(); err != nil {
+		klog.V(4).Infof("failed to save journal origins, err: %v", err)
+	}
+}
+
+// Entries returns the latest entries in the journal, at most the given size if size is positive.
+func (j *Journal) Entries(size int) ([]JournalEntry, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	rotated, err := j.readEntries(journalRotatedFileName)
+	if err != nil {
+		return nil, err
+	}
+	current, err := j.readEntries(journalFileName)
+	if err != nil {
+		return nil, err
+	}
+	entries := append(rotated, current...)
+	if size > 0 && len(entries) > size {
+		entries = entries[len(entries)-size:]
+	}
+	return entries, nil
+}
+
+// Rollback restores the resources of the given scopes and updated by the given strategies to their origin values.
+// All scopes or strategies are matched if none is specified. It returns the number of restored resources.
+func (j *Journal) Rollback(scopes []JournalScope, strategies []string) (int, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	var origins []*JournalOrigin
+	for _, origin := range j.origins {
+		if isJournalOriginMatched(origin, scopes, strategies) {
+			origins = append(origins, origin)
+		}
+	}
+	// restore the upper cgroups before the lower ones, e.g. the parent cpuset should be broader than the children;
+	// then retry the failed ones reversely in case the values are narrowed
+	sort.Slice(origins, func(i, k int) bool {
+		di, dk := strings.Count(origins[i].Path, "/"), strings.Count(origins[k].Path, "/")
+		if di != dk {
+			return di < dk
+		}
+		return origins[i].Path < origins[k].Path
+	})
+	var failed []*JournalOrigin
+	restored := 0
+	for _, origin := range origins {
+		if err := j.restore(origin); err != nil {
+			failed = append(failed, origin)
+			continue
+		}
+		restored++
+	}
+	var errs []string
+	for i := len(failed) - 1; i >= 0; i-- {
+		if err := j.restore(failed[i]); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		restored++
+	}
+	if err := j.saveOrigins(); err != nil {
+		klog.V(4).Infof("failed to save journal origins, err: %v", err)
+	}
+	if restored > 0 {
+		_ = audit.V(3).Node().Reason(ReasonRollbackResources).Message("rollback %v resources, scopes %v, strategies %v",
+			restored, scopes, strategies).Do()
+	}
+	if len(errs) > 0 {
+		return restored, fmt.Errorf("failed to rollback %v resources, err: %s", len(errs), strings.Join(errs, "; "))
+	}
+	return restored, nil
+}
+
+// restore writes the origin value back, and forgets the origin once it is restored or the resource is gone.
+func (j *Journal) restore(origin *JournalOrigin) error {
+	if _, err := os.Stat(origin.Path); os.IsNotExist(err) {
+		delete(j.origins, origin.Path)
+		return nil
+	}
+	oldValue, _ := readResourceValue(origin.Path)
+	if origin.Value == "" {
+		// e.g. an empty cpuset cannot be written back
+		delete(j.origins, origin.Path)
+		return nil
+	}
+	if err := os.WriteFile(origin.Path, []byte(origin.Value), 0644); err != nil {
+		return fmt.Errorf("restore %s to %s failed, %v", origin.Path, origin.Value, err)
+	}
+	if j.onRestored != nil {
+		key := origin.Key
+		if key == "" { // the origins recorded before the key is introduced, whose keys are the paths of the cgroups
+			key = origin.Path
+		}
+		j.onRestored(key)
+	}
+	if err := j.appendEntry(&JournalEntry{
+		Timestamp:    time.Now(),
+		Key:          origin.Path,
+		Path:         origin.Path,
+		ResourceType: filepath.Base(origin.Path),
+		Scope:        origin.Scope,
+		OldValue:     oldValue,
+		NewValue:     origin.Value,
+		Strategy:     ReasonRollbackResources,
+	}); err != nil {
+		klog.V(4).Infof("failed to append journal entry for resource %s, err: %v", origin.Path, err)
+	}
+	delete(j.origins, origin.Path)
+	return nil
+}
+
+// Run collects the origins of the resources which no longer exist, e.g. the cgroups of the deleted pods.
+func (j *Journal) Run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(journalGCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			j.gc()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (j *Journal) gc() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	removed := 0
+	for path := range j.origins {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(j.origins, path)
+			removed++
+		}
+	}
+	if removed <= 0 {
+		return
+	}
+	if err := j.saveOrigins(); err != nil {
+		klog.V(4).Infof("failed to save journal origins, err: %v", err)
+	}
+	klog.V(5).Infof("journal gc removed %v origins", removed)
+}
+
+func (j *Journal) appendEntry(entry *JournalEntry) error {
+	if j.maxEntries > 0 && j.entries >= j.maxEntries {
+		if err := os.Rename(filepath.Join(j.dir, journalFileName), filepath.Join(j.dir, journalRotatedFileName)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		j.entries = 0
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(j.dir, journalFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	j.entries++
+	return nil
+}
+
+func (j *Journal) readEntries(fileName string) ([]JournalEntry, error) {
+	f, err := os.Open(filepath.Join(j.dir, fileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entry := JournalEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			klog.V(5).Infof("skip invalid journal entry %s, err: %v", scanner.Text(), err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func (j *Journal) loadOrigins() error {
+	data, err := os.ReadFile(filepath.Join(j.dir, journalOriginsFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var origins []*JournalOrigin
+	if err = json.Unmarshal(data, &origins); err != nil {
+		return fmt.Errorf("failed to parse journal origins, err: %w", err)
+	}
+	for _, origin := range origins {
+		j.origins[origin.Path] = origin
+	}
+	return nil
+}
+
+func (j *Journal) saveOrigins() error {
+	origins := make([]*JournalOrigin, 0, len(j.origins))
+	for _, origin := range j.origins {
+		origins = append(origins, origin)
+	}
+	sort.Slice(origins, func(i, k int) bool {
+		return origins[i].Path < origins[k].Path
+	})
+	data, err := json.Marshal(origins)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file and rename to keep the origins complete
+	tmpPath := filepath.Join(j.dir, journalOriginsFileName+".tmp")
+	if err = os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(j.dir, journalOriginsFileName))
+}
+
+func isJournalOriginMatched(origin *JournalOrigin, scopes []JournalScope, strategies []string) bool {
+	if len(scopes) > 0 {
+		matched := false
+		for _, scope := range scopes {
+			if origin.Scope == scope {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(strategies) <= 0 {
+		return true
+	}
+	for _, strategy := range strategies {
+		for _, s := range origin.Strategies {
+			if s == strategy {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getJournalScope(updater ResourceUpdater) JournalScope {
+	var parentDir string
+	switch u := updater.(type) {
+	case *CgroupResourceUpdater:
+		parentDir = u.parentDir
+	default:
+		return JournalScopeOther
+	}
+	parentDir = filepath.Clean(parentDir)
+	rootDir := filepath.Clean(sysutil.CgroupPathFormatter.ParentDir)
+	if parentDir == rootDir {
+		return JournalScopeRoot
+	}
+	for _, qosClass := range []corev1.PodQOSClass{corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
+		if parentDir == filepath.Join(rootDir, sysutil.CgroupPathFormatter.QOSDirFn(qosClass)) {
+			return JournalScopeQoS
+		}
+	}
+	if strings.HasPrefix(parentDir, rootDir+"/") {
+		return JournalScopePod
+	}
+	return JournalScopeOther
+}
+
+func readResourceValue(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// RollbackByStrategies restores the resources updated by the given strategies to their origin values, e.g. when the
+// strategies are disabled. It returns the number of restored resources, and does nothing if the journal is disabled.
+func RollbackByStrategies(strategies []string) (int, error) {
+	j := singleton.getJournal()
+	if j == nil || len(strategies) <= 0 {
+		return 0, nil
+	}
+	return j.Rollback(nil, strategies)
+}
+
+// RollbackOnExit restores all the resources recorded in the journal to their origin values if the rollback on exit
+// is configured, e.g. when koordlet is uninstalled from the node.
+func RollbackOnExit() {
+	j := singleton.getJournal()
+	if j == nil || singleton.Config == nil || !singleton.Config.JournalRollbackOnExit {
+		return
+	}
+	restored, err := j.Rollback(nil, nil)
+	if err != nil {
+		klog.Errorf("failed to rollback resources on exit, restored %v, err: %v", restored, err)
+		return
+	}
+	klog.Infof("rollback %v resources on exit", restored)
+}
+
+// JournalHttpHandler returns the http handler to list the latest journal entries, e.g. `?size=100`.
+func JournalHttpHandler() func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		j := singleton.getJournal()
+		if j == nil {
+			http.Error(rw, "resource journal is not enabled", http.StatusNotFound)
+			return
+		}
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		entries, err := j.Entries(size)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("read journal failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if entries == nil {
+			entries = []JournalEntry{}
+		}
+		writeJSON(rw, entries)
+	}
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("marshal response failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.Write(data)
+}
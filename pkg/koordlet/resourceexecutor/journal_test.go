@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func TestJournal(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	journalDir := t.TempDir()
+
+	rootDir := sysutil.CgroupPathFormatter.ParentDir
+	qosDir := rootDir + "/" + sysutil.CgroupPathFormatter.QOSDirFn("BestEffort")
+	podDir := qosDir + "/" + sysutil.CgroupPathFormatter.PodDirFn("BestEffort", "xxx")
+	rootUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSharesName, rootDir, "1024",
+		audit.V(3).Reason("strategyA"))
+	assert.NoError(t, err)
+	qosUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSharesName, qosDir, "2",
+		audit.V(3).Reason("strategyA"))
+	assert.NoError(t, err)
+	podUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, podDir, "100000",
+		audit.V(3).Reason("strategyB"))
+	assert.NoError(t, err)
+	helper.WriteFileContents(rootUpdater.Path(), "2048")
+	helper.WriteFileContents(qosUpdater.Path(), "1024")
+	helper.WriteFileContents(podUpdater.Path(), "-1")
+	assert.Equal(t, JournalScopeRoot, getJournalScope(rootUpdater))
+	assert.Equal(t, JournalScopeQoS, getJournalScope(qosUpdater))
+	assert.Equal(t, JournalScopePod, getJournalScope(podUpdater))
+
+	e := &ResourceUpdateExecutorImpl{
+		ResourceCache: cache.NewCacheDefault(),
+		Config:        NewDefaultConfig(),
+	}
+	journal, err := NewJournal(journalDir, 3)
+	assert.NoError(t, err)
+	e.setJournal(journal)
+	for _, updater := range []ResourceUpdater{rootUpdater, qosUpdater, podUpdater} {
+		assert.NoError(t, e.update(updater))
+	}
+	// the second update keeps the origin
+	podUpdater1, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, podDir, "200000",
+		audit.V(3).Reason("strategyA"))
+	assert.NoError(t, err)
+	assert.NoError(t, e.update(podUpdater1))
+	assert.Equal(t, "200000", helper.ReadFileContents(podUpdater.Path()))
+
+	// the journal is rotated and bounded
+	entries, err := e.getJournal().Entries(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(entries))
+	assert.Equal(t, "100000", entries[3].OldValue)
+	assert.Equal(t, "200000", entries[3].NewValue)
+	assert.Equal(t, "strategyA", entries[3].Strategy)
+	entries, err = e.getJournal().Entries(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(entries))
+
+	// the origins are reloaded from the disk
+	journal, err = NewJournal(journalDir, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(journal.origins))
+	assert.Equal(t, "-1", journal.origins[podUpdater.Path()].Value)
+	assert.Equal(t, []string{"strategyB", "strategyA"}, journal.origins[podUpdater.Path()].Strategies)
+
+	// rollback by the strategy and the scope, and the cached values of the restored resources are invalidated
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	assert.NoError(t, e.ResourceCache.Run(stopCh))
+	for _, updater := range []ResourceUpdater{rootUpdater, qosUpdater, podUpdater1} {
+		assert.NoError(t, e.ResourceCache.SetDefault(updater.Key(), updater))
+	}
+	journal.onRestored = e.ResourceCache.Delete
+	restored, err := journal.Rollback([]JournalScope{JournalScopeQoS, JournalScopePod}, []string{"strategyA"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restored)
+	assert.Equal(t, "1024", helper.ReadFileContents(rootUpdater.Path()))
+	assert.Equal(t, "1024", helper.ReadFileContents(qosUpdater.Path()))
+	assert.Equal(t, "-1", helper.ReadFileContents(podUpdater.Path()))
+	_, ok := e.ResourceCache.Get(rootUpdater.Key())
+	assert.True(t, ok)
+	_, ok = e.ResourceCache.Get(qosUpdater.Key())
+	assert.False(t, ok)
+	_, ok = e.ResourceCache.Get(podUpdater1.Key())
+	assert.False(t, ok)
+	restored, err = journal.Rollback(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, restored)
+	assert.Equal(t, "2048", helper.ReadFileContents(rootUpdater.Path()))
+	assert.Equal(t, 0, len(journal.origins))
+}
+
+func TestRollbackByStrategiesAndOnExit(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	// nothing to do if the journal is disabled
+	restored, err := RollbackByStrategies([]string{"strategyA"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, restored)
+
+	journal, err := NewJournal(t.TempDir(), 10)
+	assert.NoError(t, err)
+	singleton.setJournal(journal)
+	defer singleton.setJournal(nil)
+	oldConfig := singleton.Config
+	singleton.Config = NewDefaultConfig()
+	defer func() {
+		singleton.Config = oldConfig
+	}()
+
+	updaterA, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSharesName, "kubepods/pod-a", "1024",
+		audit.V(3).Reason("strategyA"))
+	assert.NoError(t, err)
+	updaterB, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSharesName, "kubepods/pod-b", "1024",
+		audit.V(3).Reason("strategyB"))
+	assert.NoError(t, err)
+	helper.WriteFileContents(updaterA.Path(), "2")
+	helper.WriteFileContents(updaterB.Path(), "2")
+	assert.NoError(t, singleton.update(updaterA))
+	assert.NoError(t, singleton.update(updaterB))
+
+	restored, err = RollbackByStrategies([]string{"strategyA"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, restored)
+	assert.Equal(t, "2", helper.ReadFileContents(updaterA.Path()))
+	assert.Equal(t, "1024", helper.ReadFileContents(updaterB.Path()))
+
+	// not rollback on exit by default
+	RollbackOnExit()
+	assert.Equal(t, "1024", helper.ReadFileContents(updaterB.Path()))
+	singleton.Config.JournalRollbackOnExit = true
+	RollbackOnExit()
+	assert.Equal(t, "2", helper.ReadFileContents(updaterB.Path()))
+}
@@ -23,6 +23,7 @@ import (
 	"math"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -46,6 +47,7 @@ func init() {
 	DefaultCgroupUpdaterFactory.Register(NewCommonCgroupUpdater,
 		sysutil.CPUBurstName,
 		sysutil.CPUBVTWarpNsName,
+		sysutil.CPUIdleName,
 		sysutil.CPUTasksName,
 		sysutil.CPUProcsName,
 		sysutil.MemoryWmarkRatioName,
@@ -67,6 +69,7 @@ func init() {
 	)
 	DefaultCgroupUpdaterFactory.Register(NewMergeableCgroupUpdaterWithConditionFunc(CommonCgroupUpdateFunc, MergeConditionIfCPUSetIsLooser),
 		sysutil.CPUSetCPUSName,
+		sysutil.CPUSetMemsName,
 	)
 	DefaultCgroupUpdaterFactory.Register(NewBlkIOResourceUpdater,
 		sysutil.BlkioTRIopsName,
@@ -75,6 +78,7 @@ func init() {
 		sysutil.BlkioTWBpsName,
 		sysutil.BlkioIOQoSName,
 		sysutil.BlkioIOWeightName,
+		sysutil.BlkioIOMaxName,
 	)
 }
 
@@ -205,6 +209,10 @@ func (u *DefaultResourceUpdater) update() error {
 	return u.updateFunc(u)
 }
 
+func (u *DefaultResourceUpdater) GetEventHelper() *audit.EventHelper {
+	return u.eventHelper
+}
+
 func (u *DefaultResourceUpdater) MergeUpdate() (ResourceUpdater, error) {
 	return nil, u.updateFunc(u)
 }
@@ -465,6 +473,7 @@ func MergeConditionIfValueIsLarger(oldValue, newValue string) (string, bool, err
 }
 
 // MergeConditionIfCPUSetIsLooser returns a merge condition where only do update when the new cpuset value is looser.
+// It also applies to the cpuset.mems since the memory nodes are formatted as a cpuset list.
 func MergeConditionIfCPUSetIsLooser(oldValue, newValue string) (string, bool, error) {
 	v, err := cpuset.Parse(newValue)
 	if err != nil {
@@ -536,6 +545,8 @@ func cgroupBlkIOFileWriteIfDifferent(cgroupTaskDir string, file sysutil.Resource
 		needUpdate = CheckIfBlkRootConfigNeedUpdate(currentValue, value)
 	case sysutil.BlkioTRIopsName, sysutil.BlkioTRBpsName, sysutil.BlkioTWIopsName, sysutil.BlkioTWBpsName, sysutil.BlkioIOWeightName:
 		needUpdate = CheckIfBlkQOSNeedUpdate(currentValue, value)
+	case sysutil.BlkioIOMaxName:
+		needUpdate = CheckIfBlkIOMaxNeedUpdate(currentValue, value)
 	default:
 		return fmt.Errorf("unknown blkio resource file %s", file.ResourceType())
 	}
@@ -602,3 +613,43 @@ func CheckIfBlkQOSNeedUpdate(oldValue string, newValue string) bool {
 
 	return needUpdate
 }
+
+// CheckIfBlkIOMaxNeedUpdate checks if the cgroups-v2 io.max of a device needs to update.
+// The device is not listed in io.max if all of its limits are unlimited, and the unspecified keys keep the old values.
+// oldValue: "253:16 rbps=2097152 wbps=max riops=max wiops=max"
+// newValue: "253:16 rbps=1048576 wbps=max riops=max wiops=max"
+func CheckIfBlkIOMaxNeedUpdate(oldValue string, newValue string) bool {
+	newFields := strings.Fields(newValue)
+	if len(newFields) < 2 {
+		return true
+	}
+	device := newFields[0]
+	oldLimits := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader([]byte(oldValue)))
+	for scanner.Scan() {
+		oldFields := strings.Fields(scanner.Text())
+		if len(oldFields) < 2 || oldFields[0] != device {
+			continue
+		}
+		for _, kv := range oldFields[1:] {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				oldLimits[k] = v
+			}
+		}
+		break
+	}
+	for _, kv := range newFields[1:] {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return true
+		}
+		oldV, exist := oldLimits[k]
+		if !exist {
+			oldV = sysutil.CgroupMaxSymbolStr
+		}
+		if oldV != v {
+			return true
+		}
+	}
+	return false
+}
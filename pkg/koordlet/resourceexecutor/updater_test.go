@@ -254,3 +254,48 @@ func TestDefaultResourceUpdater_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckIfBlkIOMaxNeedUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldValue string
+		newValue string
+		want     bool
+	}{
+		{
+			name:     "no limit on the device and set unlimited",
+			oldValue: "253:0 rbps=1024 wbps=max riops=max wiops=max\n",
+			newValue: "253:16 rbps=max wbps=max riops=max wiops=max",
+			want:     false,
+		},
+		{
+			name:     "no limit on the device and set limited",
+			oldValue: "",
+			newValue: "253:16 rbps=1024 wbps=max riops=max wiops=max",
+			want:     true,
+		},
+		{
+			name:     "limits unchanged",
+			oldValue: "253:0 rbps=1024 wbps=max riops=max wiops=max\n253:16 rbps=2048 wbps=4096 riops=max wiops=max\n",
+			newValue: "253:16 rbps=2048 wbps=4096 riops=max wiops=max",
+			want:     false,
+		},
+		{
+			name:     "limits changed",
+			oldValue: "253:16 rbps=2048 wbps=4096 riops=max wiops=max\n",
+			newValue: "253:16 rbps=1024 wbps=4096 riops=max wiops=max",
+			want:     true,
+		},
+		{
+			name:     "invalid new value",
+			oldValue: "253:16 rbps=2048 wbps=4096 riops=max wiops=max\n",
+			newValue: "253:16",
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckIfBlkIOMaxNeedUpdate(tt.oldValue, tt.newValue))
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 
+	corev1 "k8s.io/api/core/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
@@ -38,7 +39,7 @@ import (
 
 const (
 	name        = "BatchResource"
-	description = "set fundamental cgroups value for batch pod"
+	description = "set fundamental cgroups value for batch and mid pod"
 
 	ruleNameForNodeSLO  = name + " (nodeSLO)"
 	ruleNameForNodeMeta = name + " (nodeMeta)"
@@ -49,7 +50,7 @@ type plugin struct {
 	executor resourceexecutor.ResourceUpdateExecutor
 }
 
-var podQOSConditions = []string{string(apiext.QoSBE)}
+var podQOSConditions = []string{string(apiext.QoSBE), reconciler.PodQOSFilterConditionMid}
 
 func (p *plugin) Register(op hooks.Options) {
 	klog.V(5).Infof("register hook %v", name)
@@ -125,15 +126,15 @@ func (p *plugin) SetPodCPUShares(proto protocol.HooksProtocol) error {
 		return fmt.Errorf("pod protocol is nil for plugin %v", name)
 	}
 
-	if !isPodQoSBEByAttr(podCtx.Request.Labels, podCtx.Request.Annotations) {
-		return nil
-	}
-
 	extendedResourceSpec := podCtx.Request.ExtendedResources
 	// if the extendedResourceSpec is empty, do nothing and keep the original cgroup configs
 	if extendedResourceSpec == nil {
 		return nil
 	}
+	getters, ok := getPodResourceGetters(podCtx.Request.Labels, podCtx.Request.Annotations, extendedResourceSpec)
+	if !ok {
+		return nil
+	}
 
 	milliCPURequest := int64(0)
 	// TODO: count init container and pod overhead
@@ -141,7 +142,7 @@ func (p *plugin) SetPodCPUShares(proto protocol.HooksProtocol) error {
 		if c.Requests == nil {
 			continue
 		}
-		containerRequest := util.GetBatchMilliCPUFromResourceList(c.Requests)
+		containerRequest := getters.milliCPU(c.Requests)
 		if containerRequest <= 0 {
 			continue
 		}
@@ -159,15 +160,21 @@ func (p *plugin) SetPodCFSQuota(proto protocol.HooksProtocol) error {
 		return fmt.Errorf("pod protocol is nil for plugin %v", name)
 	}
 
-	if !isPodQoSBEByAttr(podCtx.Request.Labels, podCtx.Request.Annotations) {
-		return nil
-	}
-
 	extendedResourceSpec := podCtx.Request.ExtendedResources
 	// if the extendedResourceSpec is empty, do nothing and keep the original cgroup configs
 	if extendedResourceSpec == nil {
 		return nil
 	}
+	getters, ok := getPodResourceGetters(podCtx.Request.Labels, podCtx.Request.Annotations, extendedResourceSpec)
+	if !ok {
+		return nil
+	}
+	// the pod-level cfs quota of the koord-mid pods is owned by the CPUSuppress strategy while they are suppressed
+	if getters == midResourceGetters && statesinformer.IsMidCPUSuppressed() {
+		klog.V(5).Infof("skip pod-level cfs quota since the Mid pods are suppressed, plugin %v, pod %s/%s",
+			name, podCtx.Request.PodMeta.Namespace, podCtx.Request.PodMeta.Name)
+		return nil
+	}
 
 	isCFSQuotaEnabled, scaleRatio := p.rule.GetCFSQuotaScaleRatio()
 
@@ -186,7 +193,7 @@ func (p *plugin) SetPodCFSQuota(proto protocol.HooksProtocol) error {
 			milliCPULimit = -1
 			break
 		}
-		containerLimit := util.GetBatchMilliCPUFromResourceList(c.Limits)
+		containerLimit := getters.milliCPU(c.Limits)
 		if containerLimit <= 0 { // pod unlimited once a container is unlimited
 			milliCPULimit = -1
 			break
@@ -198,8 +205,8 @@ func (p *plugin) SetPodCFSQuota(proto protocol.HooksProtocol) error {
 	if cfsQuota > 0 && scaleRatio > 1.0 { // no support ratio in (0, 1) yet
 		originalCFSQuota := cfsQuota
 		cfsQuota = int64(math.Ceil(float64(originalCFSQuota) / scaleRatio))
-		klog.V(6).Infof("plugin %s adjusts BE pod %s/%s cfs quota from %d to %d",
-			name, podCtx.Request.PodMeta.Namespace, podCtx.Request.PodMeta.Name, originalCFSQuota, cfsQuota)
+		klog.V(6).Infof("plugin %s adjusts %s pod %s/%s cfs quota from %d to %d",
+			name, getters.tier, podCtx.Request.PodMeta.Namespace, podCtx.Request.PodMeta.Name, originalCFSQuota, cfsQuota)
 	}
 
 	podCtx.Response.Resources.CFSQuota = pointer.Int64(cfsQuota)
@@ -212,15 +219,15 @@ func (p *plugin) SetPodMemoryLimit(proto protocol.HooksProtocol) error {
 		return fmt.Errorf("pod protocol is nil for plugin %v", name)
 	}
 
-	if !isPodQoSBEByAttr(podCtx.Request.Labels, podCtx.Request.Annotations) {
-		return nil
-	}
-
 	extendedResourceSpec := podCtx.Request.ExtendedResources
 	// if the extendedResourceSpec is empty, do nothing and keep the original cgroup configs
 	if extendedResourceSpec == nil {
 		return nil
 	}
+	getters, ok := getPodResourceGetters(podCtx.Request.Labels, podCtx.Request.Annotations, extendedResourceSpec)
+	if !ok {
+		return nil
+	}
 
 	memoryLimit := int64(0)
 	// TODO: count init container and pod overhead
@@ -229,7 +236,7 @@ func (p *plugin) SetPodMemoryLimit(proto protocol.HooksProtocol) error {
 			memoryLimit = -1
 			break
 		}
-		containerLimit := util.GetBatchMemoryFromResourceList(c.Limits)
+		containerLimit := getters.memory(c.Limits)
 		if containerLimit <= 0 { // pod unlimited once a container is unlimited
 			memoryLimit = -1
 			break
@@ -277,19 +284,19 @@ func (p *plugin) SetContainerCPUShares(proto protocol.HooksProtocol) error {
 		return fmt.Errorf("container protocol is nil for plugin %v", name)
 	}
 
-	if !isPodQoSBEByAttr(containerCtx.Request.PodLabels, containerCtx.Request.PodAnnotations) {
-		return nil
-	}
-
 	containerSpec := containerCtx.Request.ExtendedResources
 	// if the extendedResourceSpec is empty, do nothing and keep the original cgroup configs
 	if containerSpec == nil {
 		return nil
 	}
+	getters, ok := getResourceGetters(containerCtx.Request.PodLabels, containerCtx.Request.PodAnnotations, *containerSpec)
+	if !ok {
+		return nil
+	}
 
 	milliCPURequest := int64(0)
 	if containerSpec.Requests != nil {
-		containerRequest := util.GetBatchMilliCPUFromResourceList(containerSpec.Requests)
+		containerRequest := getters.milliCPU(containerSpec.Requests)
 		if containerRequest > 0 {
 			milliCPURequest = containerRequest
 		}
@@ -306,15 +313,15 @@ func (p *plugin) SetContainerCFSQuota(proto protocol.HooksProtocol) error {
 		return fmt.Errorf("container protocol is nil for plugin %v", name)
 	}
 
-	if !isPodQoSBEByAttr(containerCtx.Request.PodLabels, containerCtx.Request.PodAnnotations) {
-		return nil
-	}
-
 	containerSpec := containerCtx.Request.ExtendedResources
 	// if the extendedResourceSpec is empty, do nothing and keep the original cgroup configs
 	if containerSpec == nil {
 		return nil
 	}
+	getters, ok := getResourceGetters(containerCtx.Request.PodLabels, containerCtx.Request.PodAnnotations, *containerSpec)
+	if !ok {
+		return nil
+	}
 
 	isCFSQuotaEnabled, scaleRatio := p.rule.GetCFSQuotaScaleRatio()
 
@@ -329,7 +336,7 @@ func (p *plugin) SetContainerCFSQuota(proto protocol.HooksProtocol) error {
 
 	milliCPULimit := int64(0)
 	if containerSpec.Limits != nil {
-		containerLimit := util.GetBatchMilliCPUFromResourceList(containerSpec.Limits)
+		containerLimit := getters.milliCPU(containerSpec.Limits)
 		if containerLimit > 0 {
 			milliCPULimit = containerLimit
 		}
@@ -339,8 +346,8 @@ func (p *plugin) SetContainerCFSQuota(proto protocol.HooksProtocol) error {
 	if cfsQuota > 0 && scaleRatio > 1.0 { // no support ratio in (0, 1) yet
 		originalCFSQuota := cfsQuota
 		cfsQuota = int64(math.Ceil(float64(originalCFSQuota) / scaleRatio))
-		klog.V(6).Infof("plugin %s adjusts BE container %s/%s/%s cfs quota from %d to %d",
-			name, containerCtx.Request.PodMeta.Namespace, containerCtx.Request.PodMeta.Name,
+		klog.V(6).Infof("plugin %s adjusts %s container %s/%s/%s cfs quota from %d to %d",
+			name, getters.tier, containerCtx.Request.PodMeta.Namespace, containerCtx.Request.PodMeta.Name,
 			containerCtx.Request.ContainerMeta.Name, originalCFSQuota, cfsQuota)
 	}
 
@@ -354,19 +361,19 @@ func (p *plugin) SetContainerMemoryLimit(proto protocol.HooksProtocol) error {
 		return fmt.Errorf("container protocol is nil for plugin %v", name)
 	}
 
-	if !isPodQoSBEByAttr(containerCtx.Request.PodLabels, containerCtx.Request.PodAnnotations) {
-		return nil
-	}
-
 	containerSpec := containerCtx.Request.ExtendedResources
 	// if the extendedResourceSpec is empty, do nothing and keep the original cgroup configs
 	if containerSpec == nil {
 		return nil
 	}
+	getters, ok := getResourceGetters(containerCtx.Request.PodLabels, containerCtx.Request.PodAnnotations, *containerSpec)
+	if !ok {
+		return nil
+	}
 
 	memoryLimit := int64(0)
 	if containerSpec.Limits != nil {
-		containerLimit := util.GetBatchMemoryFromResourceList(containerSpec.Limits)
+		containerLimit := getters.memory(containerSpec.Limits)
 		if containerLimit > 0 {
 			memoryLimit = containerLimit
 		}
@@ -382,3 +389,51 @@ func (p *plugin) SetContainerMemoryLimit(proto protocol.HooksProtocol) error {
 func isPodQoSBEByAttr(labels map[string]string, annotations map[string]string) bool {
 	return apiext.GetQoSClassByAttrs(labels, annotations) == apiext.QoSBE
 }
+
+// resourceGetters gets the extended resources of a colocation tier from the resource list.
+type resourceGetters struct {
+	tier     string
+	milliCPU func(r corev1.ResourceList) int64
+	memory   func(r corev1.ResourceList) int64
+}
+
+var (
+	batchResourceGetters = &resourceGetters{
+		tier:     string(apiext.QoSBE),
+		milliCPU: util.GetBatchMilliCPUFromResourceList,
+		memory:   util.GetBatchMemoryFromResourceList,
+	}
+	midResourceGetters = &resourceGetters{
+		tier:     string(apiext.PriorityMid),
+		milliCPU: util.GetMidMilliCPUFromResourceList,
+		memory:   util.GetMidMemoryFromResourceList,
+	}
+)
+
+func getPodResourceGetters(labels map[string]string, annotations map[string]string, spec *apiext.ExtendedResourceSpec) (*resourceGetters, bool) {
+	containers := make([]apiext.ExtendedResourceContainerSpec, 0, len(spec.Containers))
+	for _, c := range spec.Containers {
+		containers = append(containers, c)
+	}
+	return getResourceGetters(labels, annotations, containers...)
+}
+
+// getResourceGetters returns the resource getters of the colocation tier which the pod belongs to.
+// The pods requesting the mid-tier resources are considered as the koord-mid pods no matter what the QoS is,
+// and the other BE pods use the batch resources.
+func getResourceGetters(labels map[string]string, annotations map[string]string, containers ...apiext.ExtendedResourceContainerSpec) (*resourceGetters, bool) {
+	for _, c := range containers {
+		for _, r := range []corev1.ResourceList{c.Requests, c.Limits} {
+			if _, ok := r[apiext.MidCPU]; ok {
+				return midResourceGetters, true
+			}
+			if _, ok := r[apiext.MidMemory]; ok {
+				return midResourceGetters, true
+			}
+		}
+	}
+	if isPodQoSBEByAttr(labels, annotations) {
+		return batchResourceGetters, true
+	}
+	return nil, false
+}
@@ -122,6 +122,20 @@ func Test_plugin_SetPodResources(t *testing.T) {
 	}
 	testSpecBytes3, err := json.Marshal(testSpec3)
 	assert.NoError(t, err)
+	testMidSpec := &apiext.ExtendedResourceSpec{
+		Containers: map[string]apiext.ExtendedResourceContainerSpec{
+			"container-0": {
+				Requests: corev1.ResourceList{
+					apiext.MidCPU:    resource.MustParse("1000"),
+					apiext.MidMemory: resource.MustParse("2Gi"),
+				},
+				Limits: corev1.ResourceList{
+					apiext.MidCPU:    resource.MustParse("2000"),
+					apiext.MidMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
+	}
 	type fields struct {
 		rule *Rule
 	}
@@ -425,6 +439,40 @@ func Test_plugin_SetPodResources(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "a Mid pod with mid cpu memory requests",
+			fields: fields{
+				rule: &Rule{
+					enableCFSQuota:        pointer.Bool(true),
+					cpuNormalizationRatio: pointer.Float64(-1),
+				},
+			},
+			args: args{
+				proto: &protocol.PodContext{
+					Request: protocol.PodRequest{
+						Labels: map[string]string{
+							apiext.LabelPodQoS: string(apiext.QoSLS),
+						},
+						ExtendedResources: testMidSpec,
+					},
+				},
+			},
+			want: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						apiext.LabelPodQoS: string(apiext.QoSLS),
+					},
+					ExtendedResources: testMidSpec,
+				},
+				Response: protocol.PodResponse{
+					Resources: protocol.Resources{
+						CPUShares:   pointer.Int64(1024 * 1000 / 1000),
+						CFSQuota:    pointer.Int64(100000 * 2000 / 1000),
+						MemoryLimit: pointer.Int64(4 * 1024 * 1024 * 1024),
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -460,6 +508,16 @@ func Test_plugin_SetContainerResources(t *testing.T) {
 	}
 	testSpecBytes, err := json.Marshal(testSpec)
 	assert.NoError(t, err)
+	testMidContainerSpec := &apiext.ExtendedResourceContainerSpec{
+		Requests: corev1.ResourceList{
+			apiext.MidCPU:    resource.MustParse("1000"),
+			apiext.MidMemory: resource.MustParse("2Gi"),
+		},
+		Limits: corev1.ResourceList{
+			apiext.MidCPU:    resource.MustParse("2000"),
+			apiext.MidMemory: resource.MustParse("4Gi"),
+		},
+	}
 	testContainerSpec1 := &apiext.ExtendedResourceContainerSpec{
 		Limits: corev1.ResourceList{
 			apiext.BatchCPU:    resource.MustParse("1000"),
@@ -795,6 +853,40 @@ func Test_plugin_SetContainerResources(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "a Mid container with mid cpu memory requests",
+			fields: fields{
+				rule: &Rule{
+					enableCFSQuota:        pointer.Bool(true),
+					cpuNormalizationRatio: pointer.Float64(-1),
+				},
+			},
+			args: args{
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						ContainerMeta: protocol.ContainerMeta{
+							Name: "container-0",
+						},
+						ExtendedResources: testMidContainerSpec,
+					},
+				},
+			},
+			want: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					ContainerMeta: protocol.ContainerMeta{
+						Name: "container-0",
+					},
+					ExtendedResources: testMidContainerSpec,
+				},
+				Response: protocol.ContainerResponse{
+					Resources: protocol.Resources{
+						CPUShares:   pointer.Int64(1024 * 1000 / 1000),
+						CFSQuota:    pointer.Int64(100000 * 2000 / 1000),
+						MemoryLimit: pointer.Int64(4 * 1024 * 1024 * 1024),
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -101,10 +101,11 @@ func (p *plugin) parseRuleForNodeSLO(mergedNodeSLOIf interface{}) (bool, error)
 	mergedNodeSLO := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
 
 	enableCFSQuota := true
-	// NOTE: If CPU Suppress Policy `CPUCfsQuotaPolicy` is enabled for batch pods, batch pods' cfs_quota should be unset
-	// since the cfs quota of `kubepods-besteffort` is required to be no less than the children's. Then the cpu usage
-	// of Batch is limited by pod-level cpu.shares and qos-level cfs_quota.
-	if enable, policy := getCPUSuppressPolicy(mergedNodeSLO); enable && policy == slov1alpha1.CPUCfsQuotaPolicy {
+	// NOTE: If CPU Suppress Policy `CPUCfsQuotaPolicy` or `CPULSLatencyPolicy` is enabled for batch pods, batch pods'
+	// cfs_quota should be unset since the cfs quota of `kubepods-besteffort` is required to be no less than the
+	// children's. Then the cpu usage of Batch is limited by pod-level cpu.shares and qos-level cfs_quota.
+	if enable, policy := getCPUSuppressPolicy(mergedNodeSLO); enable &&
+		(policy == slov1alpha1.CPUCfsQuotaPolicy || policy == slov1alpha1.CPULSLatencyPolicy) {
 		enableCFSQuota = false
 	}
 
@@ -192,7 +193,7 @@ func (p *plugin) ruleUpdateCbForNodeMeta(target *statesinformer.CallbackTarget)
 	filter := reconciler.PodQOSFilter()
 	var podUpdaters, containerUpdaters []resourceexecutor.ResourceUpdater
 	for _, podMeta := range target.Pods {
-		if qos := apiext.QoSClass(filter.Filter(podMeta)); qos != apiext.QoSBE {
+		if qos := apiext.QoSClass(filter.Filter(podMeta)); qos != apiext.QoSBE && !reconciler.IsPodMidTier(podMeta) {
 			continue
 		}
 
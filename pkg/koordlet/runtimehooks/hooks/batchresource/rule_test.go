@@ -142,6 +142,29 @@ func Test_plugin_parseRuleForNodeSLO(t *testing.T) {
 				cpuNormalizationRatio: pointer.Float64(-1),
 			},
 		},
+		{
+			name: "rule change to false for lsLatency policy",
+			fields: fields{
+				rule: &Rule{
+					enableCFSQuota:        pointer.Bool(true),
+					cpuNormalizationRatio: pointer.Float64(-1),
+				},
+			},
+			args: args{
+				mergedNodeSLO: &slov1alpha1.NodeSLOSpec{
+					ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+						Enable:            pointer.Bool(true),
+						CPUSuppressPolicy: slov1alpha1.CPULSLatencyPolicy,
+					},
+				},
+			},
+			want:    true,
+			wantErr: false,
+			wantRule: &Rule{
+				enableCFSQuota:        pointer.Bool(false),
+				cpuNormalizationRatio: pointer.Float64(-1),
+			},
+		},
 		{
 			name: "rule change to true",
 			fields: fields{
@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coresched
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+)
+
+const (
+	name        = "CoreSched"
+	description = "set core sched cookie to container tasks by qos class"
+)
+
+type Plugin struct {
+	rule         *Rule
+	sysSupported *bool
+	supportedMsg string
+
+	cookieLock sync.Mutex
+	// group id -> the cookie of the group and the reference pid which holds it
+	cookieCache map[string]groupCookie
+
+	reader   resourceexecutor.CgroupReader
+	cse      sysutil.CoreSchedInterface
+	executor resourceexecutor.ResourceUpdateExecutor
+}
+
+// groupCookie is the cookie of a core sched group. The cookie is kept to validate the reference pid, since the pid
+// can be reused by another task after the reference task exits.
+type groupCookie struct {
+	refPID uint32
+	cookie uint64
+}
+
+var singleton *Plugin
+
+func Object() *Plugin {
+	if singleton == nil {
+		singleton = newPlugin()
+	}
+	return singleton
+}
+
+func newPlugin() *Plugin {
+	return &Plugin{
+		rule:        newRule(),
+		cookieCache: map[string]groupCookie{},
+		reader:      resourceexecutor.NewCgroupReader(),
+		cse:         sysutil.NewCoreSched(),
+	}
+}
+
+func (p *Plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	// container tasks exist only after the container starts, the reconciler tags the tasks for the nri mode
+	hooks.Register(rmconfig.PostStartContainer, name, description+" (container)", p.SetContainerCookie)
+	rule.Register(name, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeSLOSpec, p.parseRule),
+		rule.WithUpdateCallback(p.ruleUpdateCb),
+		rule.WithSystemSupported(p.SystemSupported))
+	reconciler.RegisterCgroupReconciler(reconciler.ContainerLevel, sysutil.CPUTasks, description+" (container)",
+		p.SetContainerCookie, reconciler.NoneFilter())
+	p.executor = op.Executor
+}
+
+func (p *Plugin) SystemSupported() bool {
+	if p.sysSupported == nil {
+		isSupported, msg := sysutil.IsCoreSchedSupported()
+		p.sysSupported = pointer.Bool(isSupported)
+		p.supportedMsg = msg
+		klog.Infof("update system supported info to %v for plugin %v, supported msg %s",
+			*p.sysSupported, name, msg)
+	}
+	return *p.sysSupported
+}
+
+// SetContainerCookie tags the tasks of the container with the cookie of its core sched group, or clears the cookies
+// if the container does not belong to any group.
+// Tasks with different cookies never run on the SMT siblings of a physical core simultaneously, so the containers of
+// the expeller group (e.g. LSR, LS) are isolated from the untagged tasks (e.g. BE).
+func (p *Plugin) SetContainerCookie(proto protocol.HooksProtocol) error {
+	if !p.SystemSupported() {
+		klog.V(6).Infof("plugin %s is not supported by system, msg: %s", name, p.supportedMsg)
+		return nil
+	}
+	if !p.rule.IsInited() {
+		klog.V(5).Infof("plugin %s rule is not inited, skip set container cookie", name)
+		return nil
+	}
+	containerCtx, ok := proto.(*protocol.ContainerContext)
+	if !ok || containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin %s", name)
+	}
+	req := containerCtx.Request
+	containerKey := getContainerKey(&req)
+	if req.CgroupParent == "" {
+		klog.V(5).Infof("container %s has no cgroup parent, skip set container cookie", containerKey)
+		return nil
+	}
+
+	pids, err := p.reader.ReadCPUTasks(req.CgroupParent)
+	if err != nil && resourceexecutor.IsCgroupDirErr(err) {
+		klog.V(5).Infof("cgroup dir of container %s not found, skip set container cookie, err: %s", containerKey, err)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read tasks of container %s, err: %w", containerKey, err)
+	}
+	if len(pids) <= 0 {
+		return nil
+	}
+
+	podQOS := ext.GetQoSClassByAttrs(req.PodLabels, req.PodAnnotations)
+	kubeQOS := koordletutil.GetKubeQoSByCgroupParent(req.CgroupParent)
+	groupID := p.rule.GetGroupID(podQOS, kubeQOS)
+	if groupID == "" {
+		return p.clearCookie(&req, toUint32s(pids))
+	}
+	return p.setCookie(&req, groupID, toUint32s(pids))
+}
+
+func (p *Plugin) setCookie(req *protocol.ContainerRequest, groupID string, pids []uint32) error {
+	containerKey := getContainerKey(req)
+	p.cookieLock.Lock()
+	defer p.cookieLock.Unlock()
+
+	refPID, refCookie := p.getGroupCookie(groupID, pids)
+	if refCookie == 0 { // no available cookie for the group, create a new one by the first alive task
+		for _, pid := range pids {
+			if err := p.cse.Create(sysutil.CoreSchedScopeThread, pid); err != nil {
+				klog.V(5).Infof("failed to create cookie for group %s by pid %d, err: %s", groupID, pid, err)
+				continue
+			}
+			cookie, err := p.cse.Get(sysutil.CoreSchedScopeThread, pid)
+			if err != nil || cookie == 0 {
+				continue
+			}
+			refPID, refCookie = pid, cookie
+			break
+		}
+		if refCookie == 0 {
+			return fmt.Errorf("failed to create cookie for group %s by container %s", groupID, containerKey)
+		}
+		p.cookieCache[groupID] = groupCookie{refPID: refPID, cookie: refCookie}
+		klog.V(4).Infof("plugin %s created cookie %v for group %s by pid %d of container %s",
+			name, refCookie, groupID, refPID, containerKey)
+	}
+
+	var toAssign []uint32
+	for _, pid := range pids {
+		cookie, err := p.cse.Get(sysutil.CoreSchedScopeThread, pid)
+		if err != nil { // the task may have exited
+			continue
+		}
+		if cookie != refCookie {
+			toAssign = append(toAssign, pid)
+		}
+	}
+	if len(toAssign) <= 0 {
+		return nil
+	}
+	failedPIDs, err := p.cse.Assign(refPID, sysutil.CoreSchedScopeThread, toAssign...)
+	if err != nil {
+		klog.V(4).Infof("failed to assign cookie of group %s to pids %v of container %s, err: %s",
+			groupID, failedPIDs, containerKey, err)
+	}
+	_ = audit.V(3).Pod(req.PodMeta.Namespace, req.PodMeta.Name).Container(req.ContainerMeta.Name).Reason(name).
+		Message("set cookie of group %s to %d tasks", groupID, len(toAssign)-len(failedPIDs)).Do()
+	return nil
+}
+
+func (p *Plugin) clearCookie(req *protocol.ContainerRequest, pids []uint32) error {
+	containerKey := getContainerKey(req)
+	var toClear []uint32
+	for _, pid := range pids {
+		cookie, err := p.cse.Get(sysutil.CoreSchedScopeThread, pid)
+		if err != nil { // the task may have exited
+			continue
+		}
+		if cookie != 0 {
+			toClear = append(toClear, pid)
+		}
+	}
+	if len(toClear) <= 0 {
+		return nil
+	}
+	failedPIDs, err := p.cse.Clear(sysutil.CoreSchedScopeThread, toClear...)
+	if err != nil {
+		klog.V(4).Infof("failed to clear cookie of pids %v of container %s, err: %s", failedPIDs, containerKey, err)
+	}
+	_ = audit.V(3).Pod(req.PodMeta.Namespace, req.PodMeta.Name).Container(req.ContainerMeta.Name).Reason(name).
+		Message("clear cookie of %d tasks", len(toClear)-len(failedPIDs)).Do()
+	return nil
+}
+
+// getGroupCookie returns the reference pid and the cookie of the group. The reference pid is valid only if it still
+// holds the cookie of the group, otherwise a live task of the given pids which holds the cookie is picked instead.
+// It returns a zero cookie if no task is found holding the cookie.
+func (p *Plugin) getGroupCookie(groupID string, pids []uint32) (uint32, uint64) {
+	cached, ok := p.cookieCache[groupID]
+	if !ok {
+		return 0, 0
+	}
+	cookie, err := p.cse.Get(sysutil.CoreSchedScopeThread, cached.refPID)
+	if err == nil && cookie == cached.cookie {
+		return cached.refPID, cached.cookie
+	}
+	klog.V(5).Infof("reference pid %d of group %s is invalid, cookie %v, expected %v, err: %v",
+		cached.refPID, groupID, cookie, cached.cookie, err)
+	for _, pid := range pids {
+		cookie, err = p.cse.Get(sysutil.CoreSchedScopeThread, pid)
+		if err == nil && cookie == cached.cookie {
+			p.cookieCache[groupID] = groupCookie{refPID: pid, cookie: cookie}
+			klog.V(5).Infof("reference pid of group %s is updated to %d", groupID, pid)
+			return pid, cookie
+		}
+	}
+	delete(p.cookieCache, groupID)
+	return 0, 0
+}
+
+func getContainerKey(req *protocol.ContainerRequest) string {
+	return fmt.Sprintf("%s/%s/%s", req.PodMeta.Namespace, req.PodMeta.Name, req.ContainerMeta.Name)
+}
+
+func toUint32s(pids []int32) []uint32 {
+	result := make([]uint32, 0, len(pids))
+	for _, pid := range pids {
+		result = append(result, uint32(pid))
+	}
+	return result
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coresched
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		p := Object()
+		assert.NotNil(t, p)
+	})
+}
+
+func TestPlugin_Register(t *testing.T) {
+	t.Run("test not panic", func(t *testing.T) {
+		p := newPlugin()
+		p.Register(hooks.Options{})
+	})
+}
+
+func TestPluginSetContainerCookie(t *testing.T) {
+	type fields struct {
+		sysSupported *bool
+		podQOSParams map[ext.QoSClass]string
+		cookieCache  map[string]groupCookie
+		pidCookies   map[uint32]uint64
+		cgroupTasks  string
+	}
+	tests := []struct {
+		name            string
+		fields          fields
+		arg             protocol.HooksProtocol
+		wantErr         bool
+		wantPIDCookies  map[uint32]uint64
+		wantCookieCache map[string]groupCookie
+	}{
+		{
+			name: "system not supported",
+			fields: fields{
+				sysSupported: pointer.Bool(false),
+				podQOSParams: map[ext.QoSClass]string{
+					ext.QoSLS: ExpellerGroupID,
+				},
+				cookieCache: map[string]groupCookie{},
+				pidCookies: map[uint32]uint64{
+					1000: 0,
+				},
+				cgroupTasks: "1000\n",
+			},
+			arg:     newContainerCtx(ext.QoSLS),
+			wantErr: false,
+			wantPIDCookies: map[uint32]uint64{
+				1000: 0,
+			},
+			wantCookieCache: map[string]groupCookie{},
+		},
+		{
+			name: "invalid protocol",
+			fields: fields{
+				sysSupported: pointer.Bool(true),
+				podQOSParams: map[ext.QoSClass]string{},
+				cookieCache:  map[string]groupCookie{},
+			},
+			arg:             (*protocol.ContainerContext)(nil),
+			wantErr:         true,
+			wantCookieCache: map[string]groupCookie{},
+		},
+		{
+			name: "create cookie for the first container of the group",
+			fields: fields{
+				sysSupported: pointer.Bool(true),
+				podQOSParams: map[ext.QoSClass]string{
+					ext.QoSLS: ExpellerGroupID,
+				},
+				cookieCache: map[string]groupCookie{},
+				pidCookies: map[uint32]uint64{
+					1000: 0,
+					1001: 0,
+					1002: 0,
+				},
+				cgroupTasks: "1000\n1001\n1002\n",
+			},
+			arg:     newContainerCtx(ext.QoSLS),
+			wantErr: false,
+			wantPIDCookies: map[uint32]uint64{
+				1000: 1,
+				1001: 1,
+				1002: 1,
+			},
+			wantCookieCache: map[string]groupCookie{
+				ExpellerGroupID: {refPID: 1000, cookie: 1},
+			},
+		},
+		{
+			name: "assign the cookie of the group to new tasks",
+			fields: fields{
+				sysSupported: pointer.Bool(true),
+				podQOSParams: map[ext.QoSClass]string{
+					ext.QoSLSR: ExpellerGroupID,
+					ext.QoSLS:  ExpellerGroupID,
+				},
+				cookieCache: map[string]groupCookie{
+					ExpellerGroupID: {refPID: 100, cookie: 5},
+				},
+				pidCookies: map[uint32]uint64{
+					100:  5,
+					1000: 5,
+					1001: 0,
+				},
+				cgroupTasks: "1000\n1001\n",
+			},
+			arg:     newContainerCtx(ext.QoSLSR),
+			wantErr: false,
+			wantPIDCookies: map[uint32]uint64{
+				100:  5,
+				1000: 5,
+				1001: 5,
+			},
+			wantCookieCache: map[string]groupCookie{
+				ExpellerGroupID: {refPID: 100, cookie: 5},
+			},
+		},
+		{
+			name: "recreate cookie when the reference task exited",
+			fields: fields{
+				sysSupported: pointer.Bool(true),
+				podQOSParams: map[ext.QoSClass]string{
+					ext.QoSLS: ExpellerGroupID,
+				},
+				cookieCache: map[string]groupCookie{
+					ExpellerGroupID: {refPID: 100, cookie: 5},
+				},
+				pidCookies: map[uint32]uint64{
+					1000: 0,
+					1001: 0,
+				},
+				cgroupTasks: "1000\n1001\n",
+			},
+			arg:     newContainerCtx(ext.QoSLS),
+			wantErr: false,
+			wantPIDCookies: map[uint32]uint64{
+				1000: 1,
+				1001: 1,
+			},
+			wantCookieCache: map[string]groupCookie{
+				ExpellerGroupID: {refPID: 1000, cookie: 1},
+			},
+		},
+		{
+			name: "recreate cookie when the reference pid is reused by another task",
+			fields: fields{
+				sysSupported: pointer.Bool(true),
+				podQOSParams: map[ext.QoSClass]string{
+					ext.QoSLS: ExpellerGroupID,
+				},
+				cookieCache: map[string]groupCookie{
+					ExpellerGroupID: {refPID: 100, cookie: 5},
+				},
+				pidCookies: map[uint32]uint64{
+					100:  7,
+					1000: 0,
+					1001: 0,
+				},
+				cgroupTasks: "1000\n1001\n",
+			},
+			arg:     newContainerCtx(ext.QoSLS),
+			wantErr: false,
+			wantPIDCookies: map[uint32]uint64{
+				100:  7,
+				1000: 8,
+				1001: 8,
+			},
+			wantCookieCache: map[string]groupCookie{
+				ExpellerGroupID: {refPID: 1000, cookie: 8},
+			},
+		},
+		{
+			name: "re-pick the reference pid from the live tasks of the group",
+			fields: fields{
+				sysSupported: pointer.Bool(true),
+				podQOSParams: map[ext.QoSClass]string{
+					ext.QoSLS: ExpellerGroupID,
+				},
+				cookieCache: map[string]groupCookie{
+					ExpellerGroupID: {refPID: 100, cookie: 5},
+				},
+				pidCookies: map[uint32]uint64{
+					1000: 5,
+					1001: 0,
+				},
+				cgroupTasks: "1000\n1001\n",
+			},
+			arg:     newContainerCtx(ext.QoSLS),
+			wantErr: false,
+			wantPIDCookies: map[uint32]uint64{
+				1000: 5,
+				1001: 5,
+			},
+			wantCookieCache: map[string]groupCookie{
+				ExpellerGroupID: {refPID: 1000, cookie: 5},
+			},
+		},
+		{
+			name: "clear cookie for the container not in any group",
+			fields: fields{
+				sysSupported: pointer.Bool(true),
+				podQOSParams: map[ext.QoSClass]string{
+					ext.QoSLS: ExpellerGroupID,
+					ext.QoSBE: "",
+				},
+				cookieCache: map[string]groupCookie{},
+				pidCookies: map[uint32]uint64{
+					1000: 3,
+					1001: 0,
+				},
+				cgroupTasks: "1000\n1001\n",
+			},
+			arg:     newContainerCtx(ext.QoSBE),
+			wantErr: false,
+			wantPIDCookies: map[uint32]uint64{
+				1000: 0,
+				1001: 0,
+			},
+			wantCookieCache: map[string]groupCookie{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.fields.cgroupTasks != "" {
+				helper.WriteCgroupFileContents(testContainerCgroupParent, sysutil.CPUTasks, tt.fields.cgroupTasks)
+			}
+
+			p := newPlugin()
+			p.sysSupported = tt.fields.sysSupported
+			p.rule.Update(&Rule{podQOSParams: tt.fields.podQOSParams})
+			p.cookieCache = tt.fields.cookieCache
+			cse := sysutil.NewFakeCoreSched(tt.fields.pidCookies)
+			p.cse = cse
+
+			gotErr := p.SetContainerCookie(tt.arg)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			for pid, wantCookie := range tt.wantPIDCookies {
+				gotCookie, err := cse.Get(sysutil.CoreSchedScopeThread, pid)
+				assert.NoError(t, err)
+				assert.Equal(t, wantCookie, gotCookie, pid)
+			}
+			assert.Equal(t, tt.wantCookieCache, p.cookieCache)
+		})
+	}
+}
+
+const testContainerCgroupParent = "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod-xxx.slice/cri-containerd-yyy.scope"
+
+func newContainerCtx(qos ext.QoSClass) *protocol.ContainerContext {
+	return &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodMeta: protocol.PodMeta{
+				Namespace: "default",
+				Name:      "test-pod",
+			},
+			ContainerMeta: protocol.ContainerMeta{
+				Name: "test-container",
+				ID:   "containerd://yyy",
+			},
+			PodLabels: map[string]string{
+				ext.LabelPodQoS: string(qos),
+			},
+			CgroupParent: testContainerCgroupParent,
+		},
+	}
+}
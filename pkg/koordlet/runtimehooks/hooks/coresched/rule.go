@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coresched
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+const (
+	// ExpellerGroupID is the core sched group of the QoS classes which enable the CoreExpeller.
+	// Tasks in the group share the same cookie, so they never run with the untagged tasks (e.g. BE) on the SMT siblings.
+	ExpellerGroupID = "expeller"
+)
+
+type Rule struct {
+	lock   sync.RWMutex
+	inited bool
+	// qos class -> core sched group id, empty group id means the cookie should be cleared
+	podQOSParams map[ext.QoSClass]string
+}
+
+func newRule() *Rule {
+	return &Rule{
+		podQOSParams: map[ext.QoSClass]string{},
+	}
+}
+
+func (r *Rule) IsInited() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.inited
+}
+
+// GetGroupID returns the core sched group id of the pod. The pod without a koordinator QoS is considered as BE if it
+// is a kubernetes BestEffort pod, otherwise it is considered as LS.
+func (r *Rule) GetGroupID(podQOS ext.QoSClass, kubeQOS corev1.PodQOSClass) string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if podQOS == ext.QoSNone {
+		podQOS = ext.QoSLS
+		if kubeQOS == corev1.PodQOSBestEffort {
+			podQOS = ext.QoSBE
+		}
+	}
+	return r.podQOSParams[podQOS]
+}
+
+func (r *Rule) Update(newRule *Rule) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.inited && reflect.DeepEqual(r.podQOSParams, newRule.podQOSParams) {
+		return false
+	}
+	r.inited = true
+	r.podQOSParams = newRule.podQOSParams
+	return true
+}
+
+func (p *Plugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
+	mergedNodeSLO, ok := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
+	if !ok {
+		return false, fmt.Errorf("type input %T is not *NodeSLOSpec", mergedNodeSLOIf)
+	}
+	qosStrategy := mergedNodeSLO.ResourceQOSStrategy
+	if qosStrategy == nil {
+		return false, fmt.Errorf("resource qos strategy is nil")
+	}
+
+	isPolicyCoreSched := qosStrategy.Policies != nil && qosStrategy.Policies.CPUPolicy != nil &&
+		*qosStrategy.Policies.CPUPolicy == slov1alpha1.CPUQOSPolicyCoreSched
+	lsrGroupID := getGroupID(isPolicyCoreSched, qosStrategy.LSRClass)
+	lsGroupID := getGroupID(isPolicyCoreSched, qosStrategy.LSClass)
+	beGroupID := getGroupID(isPolicyCoreSched, qosStrategy.BEClass)
+
+	newRule := &Rule{
+		podQOSParams: map[ext.QoSClass]string{
+			ext.QoSLSE: lsrGroupID,
+			ext.QoSLSR: lsrGroupID,
+			ext.QoSLS:  lsGroupID,
+			ext.QoSBE:  beGroupID,
+		},
+	}
+	updated := p.rule.Update(newRule)
+	klog.Infof("runtime hook plugin %s update rule %v, new rule %v", name, updated, newRule.podQOSParams)
+	return updated, nil
+}
+
+// getGroupID returns the core sched group id of the QoS class. Only the classes which enable the CPU QoS and the
+// CoreExpeller under the "coreSched" policy are tagged.
+func getGroupID(isPolicyCoreSched bool, resourceQOS *slov1alpha1.ResourceQOS) string {
+	if !isPolicyCoreSched || resourceQOS == nil || resourceQOS.CPUQOS == nil {
+		return ""
+	}
+	cpuQOS := resourceQOS.CPUQOS
+	if cpuQOS.Enable == nil || !*cpuQOS.Enable || cpuQOS.CoreExpeller == nil || !*cpuQOS.CoreExpeller {
+		return ""
+	}
+	return ExpellerGroupID
+}
+
+func (p *Plugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
+	if target == nil {
+		klog.Warningf("callback target is nil")
+		return nil
+	}
+	for _, podMeta := range target.Pods {
+		if !podMeta.IsRunningOrPending() {
+			continue
+		}
+		for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
+			containerCtx := &protocol.ContainerContext{}
+			containerCtx.FromReconciler(podMeta, containerStat.Name, false)
+			if err := p.SetContainerCookie(containerCtx); err != nil {
+				klog.V(4).Infof("failed to set container cookie during callback %s, container %s/%s, err: %s",
+					name, podMeta.Key(), containerStat.Name, err)
+			}
+		}
+		// ignore sandbox containers
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coresched
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func TestPluginParseRule(t *testing.T) {
+	coreSchedPolicy := slov1alpha1.CPUQOSPolicyCoreSched
+	groupIdentityPolicy := slov1alpha1.CPUQOSPolicyGroupIdentity
+	tests := []struct {
+		name        string
+		arg         interface{}
+		wantUpdated bool
+		wantErr     bool
+		wantParams  map[ext.QoSClass]string
+	}{
+		{
+			name:       "invalid input",
+			arg:        &slov1alpha1.NodeSLO{},
+			wantErr:    true,
+			wantParams: map[ext.QoSClass]string{},
+		},
+		{
+			name: "policy is not core sched",
+			arg: &slov1alpha1.NodeSLOSpec{
+				ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+					Policies: &slov1alpha1.ResourceQOSPolicies{
+						CPUPolicy: &groupIdentityPolicy,
+					},
+					LSRClass: sloconfig.DefaultResourceQOSStrategy().LSRClass,
+					LSClass:  sloconfig.DefaultResourceQOSStrategy().LSClass,
+					BEClass:  sloconfig.DefaultResourceQOSStrategy().BEClass,
+				},
+			},
+			wantUpdated: true,
+			wantParams: map[ext.QoSClass]string{
+				ext.QoSLSE: "",
+				ext.QoSLSR: "",
+				ext.QoSLS:  "",
+				ext.QoSBE:  "",
+			},
+		},
+		{
+			name: "policy is core sched",
+			arg: &slov1alpha1.NodeSLOSpec{
+				ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+					Policies: &slov1alpha1.ResourceQOSPolicies{
+						CPUPolicy: &coreSchedPolicy,
+					},
+					LSRClass: &slov1alpha1.ResourceQOS{
+						CPUQOS: &slov1alpha1.CPUQOSCfg{
+							Enable: pointer.Bool(true),
+							CPUQOS: slov1alpha1.CPUQOS{
+								CoreExpeller: pointer.Bool(true),
+							},
+						},
+					},
+					LSClass: &slov1alpha1.ResourceQOS{
+						CPUQOS: &slov1alpha1.CPUQOSCfg{
+							Enable: pointer.Bool(false),
+							CPUQOS: slov1alpha1.CPUQOS{
+								CoreExpeller: pointer.Bool(true),
+							},
+						},
+					},
+					BEClass: &slov1alpha1.ResourceQOS{
+						CPUQOS: &slov1alpha1.CPUQOSCfg{
+							Enable: pointer.Bool(true),
+							CPUQOS: slov1alpha1.CPUQOS{
+								CoreExpeller: pointer.Bool(false),
+							},
+						},
+					},
+				},
+			},
+			wantUpdated: true,
+			wantParams: map[ext.QoSClass]string{
+				ext.QoSLSE: ExpellerGroupID,
+				ext.QoSLSR: ExpellerGroupID,
+				ext.QoSLS:  "",
+				ext.QoSBE:  "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPlugin()
+			gotUpdated, gotErr := p.parseRule(tt.arg)
+			assert.Equal(t, tt.wantUpdated, gotUpdated)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.wantParams, p.rule.podQOSParams)
+		})
+	}
+}
+
+func TestRuleGetGroupID(t *testing.T) {
+	r := newRule()
+	updated := r.Update(&Rule{
+		podQOSParams: map[ext.QoSClass]string{
+			ext.QoSLSR: ExpellerGroupID,
+			ext.QoSLS:  ExpellerGroupID,
+			ext.QoSBE:  "",
+		},
+	})
+	assert.True(t, updated)
+	assert.True(t, r.IsInited())
+	assert.Equal(t, ExpellerGroupID, r.GetGroupID(ext.QoSLSR, corev1.PodQOSGuaranteed))
+	assert.Equal(t, "", r.GetGroupID(ext.QoSBE, corev1.PodQOSBestEffort))
+	assert.Equal(t, ExpellerGroupID, r.GetGroupID(ext.QoSNone, corev1.PodQOSBurstable))
+	assert.Equal(t, "", r.GetGroupID(ext.QoSNone, corev1.PodQOSBestEffort))
+	assert.Equal(t, "", r.GetGroupID(ext.QoSLSE, corev1.PodQOSGuaranteed))
+}
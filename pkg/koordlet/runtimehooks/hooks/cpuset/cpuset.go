@@ -38,12 +38,17 @@ import (
 const (
 	name        = "CPUSetAllocator"
 	description = "set cpuset value by pod allocation"
+
+	ruleNameForNodeMeta = name + " (nodeMeta)"
 )
 
 type cpusetPlugin struct {
 	rule        *cpusetRule
 	ruleRWMutex sync.RWMutex
-	executor    resourceexecutor.ResourceUpdateExecutor
+	// nodeNUMATopologyPolicy is the NUMA topology policy specified in the node labels,
+	// which takes precedence over the kubelet topology manager policy in the rule.
+	nodeNUMATopologyPolicy apiext.NUMATopologyPolicy
+	executor               resourceexecutor.ResourceUpdateExecutor
 }
 
 var (
@@ -59,6 +64,9 @@ func (p *cpusetPlugin) Register(op hooks.Options) {
 	rule.Register(name, description,
 		rule.WithParseFunc(statesinformer.RegisterTypeNodeTopology, p.parseRule),
 		rule.WithUpdateCallback(p.ruleUpdateCb))
+	rule.Register(ruleNameForNodeMeta, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeMetadata, p.parseRuleForNodeMeta),
+		rule.WithUpdateCallback(p.ruleUpdateCb))
 
 	reconciler.RegisterCgroupReconciler(reconciler.ContainerLevel, sysutil.CPUSet,
 		"set container cpuset and unset container cpu quota if needed for cpuset pod",
@@ -117,7 +125,8 @@ func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
 		containerCtx.Response.Resources.CPUSet = pointer.String(cpusetVal)
 		klog.V(5).Infof("get cpuset %v for container %v/%v from pod annotation", cpusetVal,
 			containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
-		return nil
+		// bind the memory of the cpuset pod to the NUMA nodes allocated by the scheduler
+		return p.setContainerCPUSetMems(containerCtx)
 	}
 
 	r := p.getRule()
@@ -139,6 +148,27 @@ func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
 			containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
 	}
 	containerCtx.Response.Resources.CPUSet = cpusetValue
+	// bind the memory of the cpushare pod (e.g. LS pod in the share pools of the NUMA nodes) to the NUMA nodes
+	// allocated by the scheduler
+	return p.setContainerCPUSetMems(containerCtx)
+}
+
+func (p *cpusetPlugin) setContainerCPUSetMems(containerCtx *protocol.ContainerContext) error {
+	containerReq := containerCtx.Request
+	podAlloc, err := apiext.GetResourceStatus(containerReq.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	numaTopologyPolicy := p.getNUMATopologyPolicy()
+	memsValue := getCPUSetMemsByAllocation(podAlloc, numaTopologyPolicy)
+	if memsValue == nil {
+		klog.V(6).Infof("skip cpuset mems for container %v/%v, NUMA topology policy %q, allocated NUMA nodes %v",
+			containerReq.PodMeta.String(), containerReq.ContainerMeta.Name, numaTopologyPolicy, len(podAlloc.NUMANodeResources))
+		return nil
+	}
+	containerCtx.Response.Resources.CPUSetMems = memsValue
+	klog.V(5).Infof("get cpuset mems %v for container %v/%v from pod annotation", *memsValue,
+		containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
 	return nil
 }
 
@@ -39,6 +39,14 @@ func getCPUSet(dirWithKube string, helper *system.FileTestUtil) string {
 	return helper.ReadCgroupFileContents(dirWithKube, system.CPUSet)
 }
 
+func initCPUSetMems(dirWithKube string, value string, helper *system.FileTestUtil) {
+	helper.WriteCgroupFileContents(dirWithKube, system.CPUSetMems, value)
+}
+
+func getCPUSetMems(dirWithKube string, helper *system.FileTestUtil) string {
+	return helper.ReadCgroupFileContents(dirWithKube, system.CPUSetMems)
+}
+
 func initCPUQuota(dirWithKube string, value string, helper *system.FileTestUtil) {
 	helper.WriteCgroupFileContents(dirWithKube, system.CPUCFSQuota, value)
 }
@@ -118,18 +126,20 @@ func Test_cpusetPlugin_SetContainerCPUSetAndUnsetCFS(t *testing.T) {
 
 func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 	type fields struct {
-		rule *cpusetRule
+		rule                   *cpusetRule
+		nodeNUMATopologyPolicy ext.NUMATopologyPolicy
 	}
 	type args struct {
 		podAlloc *ext.ResourceStatus
 		proto    protocol.HooksProtocol
 	}
 	tests := []struct {
-		name       string
-		fields     fields
-		args       args
-		wantErr    bool
-		wantCPUSet *string
+		name           string
+		fields         fields
+		args           args
+		wantErr        bool
+		wantCPUSet     *string
+		wantCPUSetMems *string
 	}{
 		{
 			name: "set cpu with nil protocol",
@@ -178,6 +188,87 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 			wantErr:    false,
 			wantCPUSet: pointer.StringPtr("2-4"),
 		},
+		{
+			name: "set cpu and mems by pod allocated with restricted policy from kubelet",
+			fields: fields{
+				rule: &cpusetRule{
+					numaTopologyPolicy: ext.NUMATopologyPolicyRestricted,
+				},
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					CPUSet: "2-4,34-36",
+					NUMANodeResources: []ext.NUMANodeResource{
+						{
+							Node: 1,
+						},
+						{
+							Node: 0,
+						},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						CgroupParent: "kubepods/test-pod/test-container/",
+					},
+				},
+			},
+			wantErr:        false,
+			wantCPUSet:     pointer.String("2-4,34-36"),
+			wantCPUSetMems: pointer.String("0-1"),
+		},
+		{
+			name: "set cpu and mems by pod allocated with single numa node policy from node label",
+			fields: fields{
+				rule:                   nil,
+				nodeNUMATopologyPolicy: ext.NUMATopologyPolicySingleNUMANode,
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					CPUSet: "2-4",
+					NUMANodeResources: []ext.NUMANodeResource{
+						{
+							Node: 1,
+						},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						CgroupParent: "kubepods/test-pod/test-container/",
+					},
+				},
+			},
+			wantErr:        false,
+			wantCPUSet:     pointer.String("2-4"),
+			wantCPUSetMems: pointer.String("1"),
+		},
+		{
+			name: "set cpu but not mems by pod allocated with best-effort policy",
+			fields: fields{
+				rule: &cpusetRule{
+					numaTopologyPolicy: ext.NUMATopologyPolicyRestricted,
+				},
+				nodeNUMATopologyPolicy: ext.NUMATopologyPolicyBestEffort,
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					CPUSet: "2-4",
+					NUMANodeResources: []ext.NUMANodeResource{
+						{
+							Node: 0,
+						},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						CgroupParent: "kubepods/test-pod/test-container/",
+					},
+				},
+			},
+			wantErr:        false,
+			wantCPUSet:     pointer.String("2-4"),
+			wantCPUSetMems: nil,
+		},
 		{
 			name: "set cpu by pod allocated share pool with nil rule",
 			fields: fields{
@@ -299,6 +390,46 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 			wantErr:    false,
 			wantCPUSet: pointer.String("0-7"),
 		},
+		{
+			name: "set cpu and mems by pod allocated share pool with single numa node policy",
+			fields: fields{
+				rule: &cpusetRule{
+					sharePools: []ext.CPUSharedPool{
+						{
+							Socket: 0,
+							Node:   0,
+							CPUSet: "0-7",
+						},
+						{
+							Socket: 1,
+							Node:   1,
+							CPUSet: "8-15",
+						},
+					},
+					numaTopologyPolicy: ext.NUMATopologyPolicySingleNUMANode,
+				},
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					NUMANodeResources: []ext.NUMANodeResource{
+						{
+							Node: 1,
+						},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						CgroupParent: "kubepods/test-pod/test-container/",
+						PodLabels: map[string]string{
+							ext.LabelPodQoS: string(ext.QoSLS),
+						},
+					},
+				},
+			},
+			wantErr:        false,
+			wantCPUSet:     pointer.String("8-15"),
+			wantCPUSetMems: pointer.String("1"),
+		},
 		{
 			name: "set cpu for origin besteffort pod",
 			fields: fields{
@@ -334,12 +465,14 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 			var containerCtx *protocol.ContainerContext
 
 			p := &cpusetPlugin{
-				rule:     tt.fields.rule,
-				executor: resourceexecutor.NewResourceUpdateExecutor(),
+				rule:                   tt.fields.rule,
+				nodeNUMATopologyPolicy: tt.fields.nodeNUMATopologyPolicy,
+				executor:               resourceexecutor.NewResourceUpdateExecutor(),
 			}
 			if tt.args.proto != nil {
 				containerCtx = tt.args.proto.(*protocol.ContainerContext)
 				initCPUSet(containerCtx.Request.CgroupParent, "", testHelper)
+				initCPUSetMems(containerCtx.Request.CgroupParent, "", testHelper)
 				if tt.args.podAlloc != nil {
 					podAllocJson := util.DumpJSON(tt.args.podAlloc)
 					containerCtx.Request.PodAnnotations = map[string]string{
@@ -369,6 +502,13 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 				gotCPUSet := getCPUSet(containerCtx.Request.CgroupParent, testHelper)
 				assert.Equal(t, *tt.wantCPUSet, gotCPUSet, "container cpuset should be equal")
 			}
+			if tt.wantCPUSetMems == nil {
+				assert.Nil(t, containerCtx.Response.Resources.CPUSetMems, "cpuset mems value should be nil")
+			} else {
+				assert.Equal(t, *tt.wantCPUSetMems, *containerCtx.Response.Resources.CPUSetMems, "container cpuset mems should be equal")
+				gotCPUSetMems := getCPUSetMems(containerCtx.Request.CgroupParent, testHelper)
+				assert.Equal(t, *tt.wantCPUSetMems, gotCPUSetMems, "container cpuset mems should be equal")
+			}
 		})
 	}
 }
@@ -39,6 +39,8 @@ type cpusetRule struct {
 	sharePools      []ext.CPUSharedPool
 	beSharePools    []ext.CPUSharedPool
 	systemQOSCPUSet string
+	// numaTopologyPolicy is the kubelet topology manager policy reported in the NodeResourceTopology
+	numaTopologyPolicy ext.NUMATopologyPolicy
 }
 
 func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest) (*string, error) {
@@ -125,6 +127,23 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 	}
 }
 
+// getCPUSetMemsByAllocation returns the cpuset.mems of the allocated NUMA nodes.
+// The memory is only bound when the NUMA topology policy is Restricted or SingleNUMANode, since the memory binding
+// is strict and the memory of the BestEffort policy is not guaranteed to be aligned with the allocated NUMA nodes.
+func getCPUSetMemsByAllocation(podAlloc *ext.ResourceStatus, numaTopologyPolicy ext.NUMATopologyPolicy) *string {
+	if podAlloc == nil || len(podAlloc.NUMANodeResources) == 0 {
+		return nil
+	}
+	if numaTopologyPolicy != ext.NUMATopologyPolicyRestricted && numaTopologyPolicy != ext.NUMATopologyPolicySingleNUMANode {
+		return nil
+	}
+	numaNodes := make([]int, 0, len(podAlloc.NUMANodeResources))
+	for _, numaNode := range podAlloc.NUMANodeResources {
+		numaNodes = append(numaNodes, int(numaNode.Node))
+	}
+	return pointer.String(cpuset.NewCPUSet(numaNodes...).String())
+}
+
 func (r *cpusetRule) getHostAppCpuset(hostAppReq *protocol.HostAppRequest) (*string, error) {
 	if hostAppReq == nil {
 		return nil, nil
@@ -173,15 +192,38 @@ func (p *cpusetPlugin) parseRule(nodeTopoIf interface{}) (bool, error) {
 	}
 
 	newRule := &cpusetRule{
-		kubeletPolicy:   *cpuManagerPolicy,
-		sharePools:      cpuSharePools,
-		beSharePools:    beCPUSharePools,
-		systemQOSCPUSet: systemQOSCPUSet,
+		kubeletPolicy:      *cpuManagerPolicy,
+		sharePools:         cpuSharePools,
+		beSharePools:       beCPUSharePools,
+		systemQOSCPUSet:    systemQOSCPUSet,
+		numaTopologyPolicy: ext.GetNUMATopologyPolicyFromNRT(nodeTopo),
 	}
 	updated := p.updateRule(newRule)
 	return updated, nil
 }
 
+func (p *cpusetPlugin) parseRuleForNodeMeta(nodeIf interface{}) (bool, error) {
+	node, ok := nodeIf.(*corev1.Node)
+	if !ok {
+		return false, fmt.Errorf("parse format for hook plugin %v failed, expect: %v, got: %T",
+			ruleNameForNodeMeta, "*corev1.Node", nodeIf)
+	}
+	if node == nil {
+		return false, fmt.Errorf("got nil node")
+	}
+
+	numaTopologyPolicy := ext.GetNodeNUMATopologyPolicy(node.Labels)
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	if p.nodeNUMATopologyPolicy == numaTopologyPolicy {
+		return false, nil
+	}
+	klog.V(4).Infof("runtime hook plugin %s update rule, node NUMA topology policy %q -> %q",
+		ruleNameForNodeMeta, p.nodeNUMATopologyPolicy, numaTopologyPolicy)
+	p.nodeNUMATopologyPolicy = numaTopologyPolicy
+	return true, nil
+}
+
 func (p *cpusetPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 	if target == nil {
 		klog.Warningf("callback target is nil")
@@ -231,6 +273,20 @@ func (p *cpusetPlugin) getRule() *cpusetRule {
 	return &rule
 }
 
+// getNUMATopologyPolicy returns the NUMA topology policy of the node, where the node label takes precedence
+// over the kubelet topology manager policy.
+func (p *cpusetPlugin) getNUMATopologyPolicy() ext.NUMATopologyPolicy {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	if p.nodeNUMATopologyPolicy != ext.NUMATopologyPolicyNone {
+		return p.nodeNUMATopologyPolicy
+	}
+	if p.rule == nil {
+		return ext.NUMATopologyPolicyNone
+	}
+	return p.rule.numaTopologyPolicy
+}
+
 func (p *cpusetPlugin) updateRule(newRule *cpusetRule) bool {
 	p.ruleRWMutex.RLock()
 	defer p.ruleRWMutex.RUnlock()
@@ -584,6 +584,161 @@ func Test_cpusetPlugin_parseRule(t *testing.T) {
 	}
 }
 
+func Test_cpusetPlugin_parseRuleForNodeMeta(t *testing.T) {
+	tests := []struct {
+		name        string
+		field       ext.NUMATopologyPolicy
+		arg         interface{}
+		wantUpdated bool
+		wantPolicy  ext.NUMATopologyPolicy
+		wantErr     bool
+	}{
+		{
+			name:        "parse with bad type",
+			arg:         &topov1alpha1.NodeResourceTopology{},
+			wantUpdated: false,
+			wantPolicy:  ext.NUMATopologyPolicyNone,
+			wantErr:     true,
+		},
+		{
+			name: "update node numa topology policy",
+			arg: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Labels: map[string]string{
+						ext.LabelNUMATopologyPolicy: string(ext.NUMATopologyPolicySingleNUMANode),
+					},
+				},
+			},
+			wantUpdated: true,
+			wantPolicy:  ext.NUMATopologyPolicySingleNUMANode,
+			wantErr:     false,
+		},
+		{
+			name:  "node numa topology policy not changed",
+			field: ext.NUMATopologyPolicyRestricted,
+			arg: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Labels: map[string]string{
+						ext.LabelNUMATopologyPolicy: string(ext.NUMATopologyPolicyRestricted),
+					},
+				},
+			},
+			wantUpdated: false,
+			wantPolicy:  ext.NUMATopologyPolicyRestricted,
+			wantErr:     false,
+		},
+		{
+			name:  "node numa topology policy removed",
+			field: ext.NUMATopologyPolicyRestricted,
+			arg: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+				},
+			},
+			wantUpdated: true,
+			wantPolicy:  ext.NUMATopologyPolicyNone,
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &cpusetPlugin{
+				nodeNUMATopologyPolicy: tt.field,
+			}
+			got, err := p.parseRuleForNodeMeta(tt.arg)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantUpdated, got)
+			assert.Equal(t, tt.wantPolicy, p.getNUMATopologyPolicy())
+		})
+	}
+}
+
+func Test_getCPUSetMemsByAllocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		podAlloc *ext.ResourceStatus
+		policy   ext.NUMATopologyPolicy
+		want     *string
+	}{
+		{
+			name:   "no allocation",
+			policy: ext.NUMATopologyPolicyRestricted,
+			want:   nil,
+		},
+		{
+			name: "no numa node allocated",
+			podAlloc: &ext.ResourceStatus{
+				CPUSet: "0-3",
+			},
+			policy: ext.NUMATopologyPolicyRestricted,
+			want:   nil,
+		},
+		{
+			name: "none policy",
+			podAlloc: &ext.ResourceStatus{
+				CPUSet: "0-3",
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 0,
+					},
+				},
+			},
+			policy: ext.NUMATopologyPolicyNone,
+			want:   nil,
+		},
+		{
+			name: "best-effort policy",
+			podAlloc: &ext.ResourceStatus{
+				CPUSet: "0-3",
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 0,
+					},
+				},
+			},
+			policy: ext.NUMATopologyPolicyBestEffort,
+			want:   nil,
+		},
+		{
+			name: "restricted policy with multiple numa nodes",
+			podAlloc: &ext.ResourceStatus{
+				CPUSet: "0-3,32-35",
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 2,
+					},
+					{
+						Node: 0,
+					},
+				},
+			},
+			policy: ext.NUMATopologyPolicyRestricted,
+			want:   pointer.String("0,2"),
+		},
+		{
+			name: "single numa node policy",
+			podAlloc: &ext.ResourceStatus{
+				CPUSet: "0-3",
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 1,
+					},
+				},
+			},
+			policy: ext.NUMATopologyPolicySingleNUMANode,
+			want:   pointer.String("1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getCPUSetMemsByAllocation(tt.podAlloc, tt.policy)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_cpusetPlugin_ruleUpdateCbForPods(t *testing.T) {
 	type testPod struct {
 		pod       *corev1.Pod
@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"sync"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 
@@ -29,7 +28,6 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
-	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
 )
@@ -45,6 +43,8 @@ type bvtPlugin struct {
 	sysSupported     *bool
 	hasKernelEnabled *bool // whether kernel is configurable for enabling bvt (via `kernel.sched_group_identity_enabled`)
 	kernelEnabled    *bool // if not nil, indicates whether bvt feature is enabled via `kernel.sched_group_identity_enabled`
+	priorityMode     cpuPriorityMode
+	idleRule         *cpuIdleRule // the cgroups-v2 fallback rule when the bvt is not supported
 	executor         resourceexecutor.ResourceUpdateExecutor
 }
 
@@ -66,15 +66,12 @@ func (b *bvtPlugin) Register(op hooks.Options) {
 
 func (b *bvtPlugin) SystemSupported() bool {
 	if b.sysSupported == nil {
-		isBVTSupported, msg := false, "resource not found"
-		bvtResource, err := sysutil.GetCgroupResource(sysutil.CPUBVTWarpNsName)
-		if err == nil {
-			isBVTSupported, msg = bvtResource.IsSupported(util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed))
-		}
-		bvtConfigPath := sysutil.GetProcSysFilePath(sysutil.KernelSchedGroupIdentityEnable)
-		b.sysSupported = pointer.Bool(isBVTSupported || sysutil.FileExists(bvtConfigPath))
-		klog.Infof("update system supported info to %v for plugin %v, supported msg %s",
-			*b.sysSupported, name, msg)
+		// use the cgroups-v2 fallbacks on the upstream kernels which have no bvt
+		mode, msg := probeCPUPriorityMode()
+		b.priorityMode = mode
+		b.sysSupported = pointer.Bool(mode != cpuPriorityModeNone)
+		klog.Infof("update system supported info to %v for plugin %v, cpu priority mode %q, supported msg %s",
+			*b.sysSupported, name, mode, msg)
 	}
 	return *b.sysSupported
 }
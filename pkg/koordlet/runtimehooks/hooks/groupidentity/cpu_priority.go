@@ -0,0 +1,292 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groupidentity
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+// cpuPriorityMode is the kernel interface used to apply the cpu priorities of the QoS classes.
+type cpuPriorityMode string
+
+const (
+	cpuPriorityModeNone cpuPriorityMode = ""
+	// cpuPriorityModeBVT uses the group identity (cpu.bvt_warp_ns) of the Anolis kernels.
+	cpuPriorityModeBVT cpuPriorityMode = "bvt"
+	// cpuPriorityModeIdle uses the cgroups-v2 cpu.idle (SCHED_IDLE, since mainline Linux 5.15) of the upstream kernels.
+	cpuPriorityModeIdle cpuPriorityMode = "idle"
+	// cpuPriorityModeWeight uses the cgroups-v2 cpu.weight of the upstream kernels which have no cpu.idle.
+	cpuPriorityModeWeight cpuPriorityMode = "weight"
+)
+
+// probeCPUPriorityMode probes the kernel features in the order of bvt, cpu.idle and cpu.weight.
+func probeCPUPriorityMode() (cpuPriorityMode, string) {
+	isBVTSupported, msg := false, "resource not found"
+	bvtResource, err := sysutil.GetCgroupResource(sysutil.CPUBVTWarpNsName)
+	if err == nil {
+		isBVTSupported, msg = bvtResource.IsSupported(util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed))
+	}
+	bvtConfigPath := sysutil.GetProcSysFilePath(sysutil.KernelSchedGroupIdentityEnable)
+	if isBVTSupported || sysutil.FileExists(bvtConfigPath) {
+		return cpuPriorityModeBVT, ""
+	}
+	if sysutil.GetCurrentCgroupVersion() != sysutil.CgroupVersionV2 {
+		return cpuPriorityModeNone, "bvt " + msg
+	}
+
+	idleResource, err := sysutil.GetCgroupResource(sysutil.CPUIdleName)
+	if err == nil {
+		isIdleSupported, idleMsg := idleResource.IsSupported(util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed))
+		if isIdleSupported {
+			return cpuPriorityModeIdle, ""
+		}
+		msg += ", " + sysutil.CPUIdleName + " " + idleMsg
+	}
+	// the cpu.weight exists when the cpu controller is enabled in the kubepods
+	weightResource, err := sysutil.GetCgroupResource(sysutil.CPUSharesName)
+	if err == nil {
+		isWeightSupported, weightMsg := sysutil.SupportedIfFileExistsInKubepods(weightResource, "")
+		if isWeightSupported {
+			return cpuPriorityModeWeight, ""
+		}
+		msg += ", " + sysutil.CPUWeightName + " " + weightMsg
+	}
+	return cpuPriorityModeNone, "bvt " + msg
+}
+
+// getCPUPriorityMode returns the probed cpu priority mode.
+func (b *bvtPlugin) getCPUPriorityMode() cpuPriorityMode {
+	if !b.SystemSupported() {
+		return cpuPriorityModeNone
+	}
+	return b.priorityMode
+}
+
+// scaleCPUSharesByBvt scales the cpu shares requested by the kubelet with the group identity, so the tiers keep
+// their relative priorities on the cgroups-v2 cpu.weight. Each level above the none identity doubles the shares and
+// each level below halves them. The none identity keeps the kubelet value, which resets the shares when the CPU QoS
+// is disabled.
+func scaleCPUSharesByBvt(kubeletShares int64, bvtValue int64) int64 {
+	noneValue := *sloconfig.NoneCPUQOS().GroupIdentity
+	shares := kubeletShares
+	if bvtValue > noneValue {
+		shares = shares << uint(bvtValue-noneValue)
+	} else if bvtValue < noneValue {
+		shares = shares >> uint(noneValue-bvtValue)
+	}
+	if shares < sysutil.CPUSharesMinValue {
+		return sysutil.CPUSharesMinValue
+	}
+	if shares > sysutil.CPUSharesMaxValue {
+		return sysutil.CPUSharesMaxValue
+	}
+	return shares
+}
+
+// cpuIdleRule is the cgroups-v2 fallback of the bvtRule for the upstream kernels with the cpu.idle.
+// The BE pods are set as SCHED_IDLE according to the CPUQOS.SchedIdle.
+type cpuIdleRule struct {
+	enable           bool
+	podQOSParams     map[ext.QoSClass]int64
+	kubeQOSDirParams map[corev1.PodQOSClass]int64
+	kubeQOSPodParams map[corev1.PodQOSClass]int64
+}
+
+func (r *cpuIdleRule) getPodIdleValue(podQoSClass ext.QoSClass, podKubeQOS corev1.PodQOSClass) int64 {
+	if val, exist := r.podQOSParams[podQoSClass]; exist {
+		return val
+	}
+	if val, exist := r.kubeQOSPodParams[podKubeQOS]; exist {
+		return val
+	}
+	return 0
+}
+
+func (r *cpuIdleRule) getKubeQOSDirIdleValue(kubeQOS corev1.PodQOSClass) int64 {
+	if val, exist := r.kubeQOSDirParams[kubeQOS]; exist {
+		return val
+	}
+	return 0
+}
+
+func (r *cpuIdleRule) getHostQOSIdleValue(qosClass ext.QoSClass) int64 {
+	if val, exist := r.podQOSParams[qosClass]; exist {
+		return val
+	}
+	return 0
+}
+
+func newCPUIdleRule(qosStrategy *slov1alpha1.ResourceQOSStrategy, isPolicyGroupIdentity bool) *cpuIdleRule {
+	lsrEnabled := isPolicyGroupIdentity && *qosStrategy.LSRClass.CPUQOS.Enable
+	lsEnabled := isPolicyGroupIdentity && *qosStrategy.LSClass.CPUQOS.Enable
+	beEnabled := isPolicyGroupIdentity && *qosStrategy.BEClass.CPUQOS.Enable
+
+	// cpu.idle should be reset if the CPU QOS disables or the CPU QoS policy is not "groupIdentity"
+	var lsrValue, lsValue, beValue int64
+	if lsrEnabled {
+		lsrValue = getSchedIdleValue(&qosStrategy.LSRClass.CPUQOS.CPUQOS)
+	}
+	if lsEnabled {
+		lsValue = getSchedIdleValue(&qosStrategy.LSClass.CPUQOS.CPUQOS)
+	}
+	if beEnabled {
+		beValue = getSchedIdleValue(&qosStrategy.BEClass.CPUQOS.CPUQOS)
+	}
+
+	guaranteedPodVal := int64(0)
+	if lsrEnabled {
+		guaranteedPodVal = lsrValue
+	} else if lsEnabled {
+		guaranteedPodVal = lsValue
+	}
+
+	return &cpuIdleRule{
+		enable: lsrEnabled || lsEnabled || beEnabled,
+		podQOSParams: map[ext.QoSClass]int64{
+			ext.QoSLSE: lsrValue,
+			ext.QoSLSR: lsrValue,
+			ext.QoSLS:  lsValue,
+			ext.QoSBE:  beValue,
+		},
+		kubeQOSDirParams: map[corev1.PodQOSClass]int64{
+			// NOTE: guaranteed root dir is the kubepods, keep it as non-idle
+			corev1.PodQOSGuaranteed: 0,
+			corev1.PodQOSBurstable:  lsValue,
+			corev1.PodQOSBestEffort: beValue,
+		},
+		kubeQOSPodParams: map[corev1.PodQOSClass]int64{
+			corev1.PodQOSGuaranteed: guaranteedPodVal,
+			corev1.PodQOSBurstable:  lsValue,
+			corev1.PodQOSBestEffort: beValue,
+		},
+	}
+}
+
+func getSchedIdleValue(cpuQOS *slov1alpha1.CPUQOS) int64 {
+	if cpuQOS.SchedIdle == nil {
+		return 0
+	}
+	return *cpuQOS.SchedIdle
+}
+
+func (b *bvtPlugin) getIdleRule() *cpuIdleRule {
+	b.ruleRWMutex.RLock()
+	defer b.ruleRWMutex.RUnlock()
+	if b.idleRule == nil {
+		return nil
+	}
+	rule := *b.idleRule
+	return &rule
+}
+
+func (b *bvtPlugin) updateIdleRule(newRule *cpuIdleRule) bool {
+	b.ruleRWMutex.Lock()
+	defer b.ruleRWMutex.Unlock()
+	if !reflect.DeepEqual(newRule, b.idleRule) {
+		b.idleRule = newRule
+		return true
+	}
+	return false
+}
+
+// setPodCPUPriority sets the cgroups-v2 fallbacks of the pod bvt.
+// In the weight mode, the pod cpu.weight is scaled from the kubelet value by the group identity. The BE pods and the
+// koord-mid pods of the Mid tier are skipped since their cpu weights are managed by the batch resource hook, and the
+// besteffort kubeqos already has the lowest weight.
+func (b *bvtPlugin) setPodCPUPriority(r *bvtRule, mode cpuPriorityMode, podCtx *protocol.PodContext) {
+	req := podCtx.Request
+	podQOS := ext.GetQoSClassByAttrs(req.Labels, req.Annotations)
+	podKubeQOS := util.GetKubeQoSByCgroupParent(req.CgroupParent)
+	switch mode {
+	case cpuPriorityModeIdle:
+		idleRule := b.getIdleRule()
+		if idleRule == nil {
+			return
+		}
+		podCtx.Response.Resources.CPUIdle = pointer.Int64(idleRule.getPodIdleValue(podQOS, podKubeQOS))
+	case cpuPriorityModeWeight:
+		// the kubelet shares are only known in the reconciler
+		if r == nil || podQOS == ext.QoSBE || req.Resources == nil || req.Resources.CPUShares == nil {
+			return
+		}
+		podPriority := ext.GetPodPriorityClassByName(req.Labels[ext.LabelPodPriorityClass])
+		// keep consistent with reconciler.IsPodMidTier
+		if podPriority == ext.PriorityMid && (podQOS == ext.QoSLS || podQOS == ext.QoSNone) {
+			return
+		}
+		podBvt := r.getPodBvtValue(podQOS, podPriority, podKubeQOS)
+		podCtx.Response.Resources.CPUShares = pointer.Int64(scaleCPUSharesByBvt(*req.Resources.CPUShares, podBvt))
+	}
+}
+
+// setKubeQOSCPUPriority sets the cgroups-v2 fallbacks of the kubeqos bvt.
+// The kubeqos cpu weights are kept in the weight mode since they are managed by the kubelet.
+func (b *bvtPlugin) setKubeQOSCPUPriority(mode cpuPriorityMode, kubeQOSCtx *protocol.KubeQOSContext) {
+	r := b.getIdleRule()
+	if r == nil || mode != cpuPriorityModeIdle {
+		return
+	}
+	kubeQOSCtx.Response.Resources.CPUIdle = pointer.Int64(r.getKubeQOSDirIdleValue(kubeQOSCtx.Request.KubeQOSClass))
+}
+
+// setHostAppCPUPriority sets the cgroups-v2 fallbacks of the host application bvt.
+func (b *bvtPlugin) setHostAppCPUPriority(mode cpuPriorityMode, hostQOSCtx *protocol.HostAppContext) {
+	r := b.getIdleRule()
+	if r == nil || mode != cpuPriorityModeIdle {
+		return
+	}
+	hostQOSCtx.Response.Resources.CPUIdle = pointer.Int64(r.getHostQOSIdleValue(hostQOSCtx.Request.QOSClass))
+}
+
+// ruleUpdateCbForCPUPriority updates the cgroups-v2 fallbacks of the bvt for the kubeqos, pods and host applications.
+func (b *bvtPlugin) ruleUpdateCbForCPUPriority(r *bvtRule, mode cpuPriorityMode, target *statesinformer.CallbackTarget) {
+	for _, kubeQOS := range []corev1.PodQOSClass{
+		corev1.PodQOSGuaranteed, corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
+		kubeQOSCtx := &protocol.KubeQOSContext{}
+		kubeQOSCtx.FromReconciler(kubeQOS)
+		b.setKubeQOSCPUPriority(mode, kubeQOSCtx)
+		kubeQOSCtx.ReconcilerDone(b.executor)
+	}
+	if target == nil {
+		return
+	}
+	for _, podMeta := range target.Pods {
+		podCtx := &protocol.PodContext{}
+		podCtx.FromReconciler(podMeta)
+		b.setPodCPUPriority(r, mode, podCtx)
+		podCtx.ReconcilerDone(b.executor)
+	}
+	for _, hostApp := range target.HostApplications {
+		hostCtx := &protocol.HostAppContext{}
+		hostCtx.FromReconciler(&hostApp)
+		b.setHostAppCPUPriority(mode, hostCtx)
+		hostCtx.ReconcilerDone(b.executor)
+	}
+	klog.V(5).Infof("plugin %s updates cpu priority in mode %s finished", name, mode)
+}
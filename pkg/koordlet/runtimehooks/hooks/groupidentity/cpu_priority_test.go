@@ -0,0 +1,432 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groupidentity
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func Test_probeCPUPriorityMode(t *testing.T) {
+	kubeRootDir := util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed)
+	type fields struct {
+		useCgroupsV2     bool
+		initBVT          bool
+		cpuIdleSupported bool
+		cpuWeightExists  bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   cpuPriorityMode
+	}{
+		{
+			name: "bvt supported",
+			fields: fields{
+				initBVT: true,
+			},
+			want: cpuPriorityModeBVT,
+		},
+		{
+			name:   "nothing supported on cgroups-v1",
+			fields: fields{},
+			want:   cpuPriorityModeNone,
+		},
+		{
+			name: "cpu.idle supported on cgroups-v2",
+			fields: fields{
+				useCgroupsV2:     true,
+				cpuIdleSupported: true,
+				cpuWeightExists:  true,
+			},
+			want: cpuPriorityModeIdle,
+		},
+		{
+			name: "cpu.weight supported on cgroups-v2",
+			fields: fields{
+				useCgroupsV2:    true,
+				cpuWeightExists: true,
+			},
+			want: cpuPriorityModeWeight,
+		},
+		{
+			name: "nothing supported on cgroups-v2",
+			fields: fields{
+				useCgroupsV2: true,
+			},
+			want: cpuPriorityModeNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.fields.useCgroupsV2)
+			helper.SetValidateResource(false)
+			if tt.fields.initBVT {
+				initCPUBvt(kubeRootDir, 0, helper)
+			}
+			helper.SetResourcesSupported(tt.fields.cpuIdleSupported, system.CPUIdleV2)
+			if tt.fields.cpuWeightExists {
+				helper.WriteCgroupFileContents(kubeRootDir, system.CPUSharesV2, "100")
+			}
+
+			got, msg := probeCPUPriorityMode()
+			assert.Equal(t, tt.want, got, msg)
+		})
+	}
+}
+
+func Test_newCPUIdleRule(t *testing.T) {
+	policyGroupIdentity := slov1alpha1.CPUQOSPolicyGroupIdentity
+	tests := []struct {
+		name                  string
+		qosStrategy           *slov1alpha1.ResourceQOSStrategy
+		isPolicyGroupIdentity bool
+		want                  *cpuIdleRule
+	}{
+		{
+			name:                  "policy is group identity",
+			qosStrategy:           newCPUQOSEnabledStrategy(&policyGroupIdentity),
+			isPolicyGroupIdentity: true,
+			want: &cpuIdleRule{
+				enable: true,
+				podQOSParams: map[ext.QoSClass]int64{
+					ext.QoSLSE: 0,
+					ext.QoSLSR: 0,
+					ext.QoSLS:  0,
+					ext.QoSBE:  1,
+				},
+				kubeQOSDirParams: map[corev1.PodQOSClass]int64{
+					corev1.PodQOSGuaranteed: 0,
+					corev1.PodQOSBurstable:  0,
+					corev1.PodQOSBestEffort: 1,
+				},
+				kubeQOSPodParams: map[corev1.PodQOSClass]int64{
+					corev1.PodQOSGuaranteed: 0,
+					corev1.PodQOSBurstable:  0,
+					corev1.PodQOSBestEffort: 1,
+				},
+			},
+		},
+		{
+			name:                  "policy is not group identity",
+			qosStrategy:           newCPUQOSEnabledStrategy(nil),
+			isPolicyGroupIdentity: false,
+			want: &cpuIdleRule{
+				enable: false,
+				podQOSParams: map[ext.QoSClass]int64{
+					ext.QoSLSE: 0,
+					ext.QoSLSR: 0,
+					ext.QoSLS:  0,
+					ext.QoSBE:  0,
+				},
+				kubeQOSDirParams: map[corev1.PodQOSClass]int64{
+					corev1.PodQOSGuaranteed: 0,
+					corev1.PodQOSBurstable:  0,
+					corev1.PodQOSBestEffort: 0,
+				},
+				kubeQOSPodParams: map[corev1.PodQOSClass]int64{
+					corev1.PodQOSGuaranteed: 0,
+					corev1.PodQOSBurstable:  0,
+					corev1.PodQOSBestEffort: 0,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newCPUIdleRule(tt.qosStrategy, tt.isPolicyGroupIdentity)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_bvtPlugin_cpuPriorityFallback(t *testing.T) {
+	policyGroupIdentity := slov1alpha1.CPUQOSPolicyGroupIdentity
+	nodeSLO := &slov1alpha1.NodeSLOSpec{
+		ResourceQOSStrategy: newCPUQOSEnabledStrategy(&policyGroupIdentity),
+	}
+	lsPod := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ls-pod",
+				Labels: map[string]string{
+					ext.LabelPodQoS: string(ext.QoSLS),
+				},
+			},
+			Status: corev1.PodStatus{
+				QOSClass: corev1.PodQOSBurstable,
+			},
+		},
+		CgroupDir: "kubepods.slice/kubepods-burstable.slice/kubepods-test-ls-pod.slice",
+	}
+	bePod := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "be-pod",
+				Labels: map[string]string{
+					ext.LabelPodQoS: string(ext.QoSBE),
+				},
+			},
+			Status: corev1.PodStatus{
+				QOSClass: corev1.PodQOSBestEffort,
+			},
+		},
+		CgroupDir: "kubepods.slice/kubepods-besteffort.slice/kubepods-test-be-pod.slice",
+	}
+	besteffortDir := util.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	burstableDir := util.GetPodQoSRelativePath(corev1.PodQOSBurstable)
+
+	t.Run("use cpu.idle", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+		helper.SetCgroupsV2(true)
+		helper.SetResourcesSupported(true, system.CPUIdleV2)
+		for _, dir := range []string{besteffortDir, burstableDir, lsPod.CgroupDir, bePod.CgroupDir} {
+			helper.WriteCgroupFileContents(dir, system.CPUIdleV2, "0")
+		}
+
+		b := &bvtPlugin{
+			sysSupported: pointer.Bool(true),
+			priorityMode: cpuPriorityModeIdle,
+			executor:     resourceexecutor.NewResourceUpdateExecutor(),
+		}
+		stop := make(chan struct{})
+		defer close(stop)
+		b.executor.Run(stop)
+
+		updated, err := b.parseRule(nodeSLO)
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		assert.NotNil(t, b.getIdleRule())
+
+		err = b.ruleUpdateCb(&statesinformer.CallbackTarget{
+			Pods: []*statesinformer.PodMeta{lsPod, bePod},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", helper.ReadCgroupFileContents(besteffortDir, system.CPUIdleV2))
+		assert.Equal(t, "0", helper.ReadCgroupFileContents(burstableDir, system.CPUIdleV2))
+		assert.Equal(t, "1", helper.ReadCgroupFileContents(bePod.CgroupDir, system.CPUIdleV2))
+		assert.Equal(t, "0", helper.ReadCgroupFileContents(lsPod.CgroupDir, system.CPUIdleV2))
+
+		podCtx := &protocol.PodContext{
+			Request: protocol.PodRequest{
+				Labels: map[string]string{
+					ext.LabelPodQoS: string(ext.QoSBE),
+				},
+				CgroupParent: bePod.CgroupDir,
+			},
+		}
+		err = b.SetPodBvtValue(podCtx)
+		assert.NoError(t, err)
+		assert.Nil(t, podCtx.Response.Resources.CPUBvt)
+		assert.Equal(t, pointer.Int64(1), podCtx.Response.Resources.CPUIdle)
+	})
+
+	t.Run("use cpu.weight", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+		helper.SetCgroupsV2(true)
+		helper.SetResourcesSupported(true, system.CPUIdleV2)
+		lsrPod := &statesinformer.PodMeta{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "lsr-pod",
+					Labels: map[string]string{
+						ext.LabelPodQoS: string(ext.QoSLSR),
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						newTestContainer("2", "2"),
+					},
+				},
+				Status: corev1.PodStatus{
+					QOSClass: corev1.PodQOSGuaranteed,
+				},
+			},
+			CgroupDir: "kubepods.slice/kubepods-test-lsr-pod.slice",
+		}
+		lsPodWithRequests := lsPod.DeepCopy()
+		lsPodWithRequests.Pod.Spec.Containers = []corev1.Container{
+			newTestContainer("1", ""),
+		}
+		midPod := &statesinformer.PodMeta{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mid-pod",
+					Labels: map[string]string{
+						ext.LabelPodQoS:           string(ext.QoSLS),
+						ext.LabelPodPriorityClass: string(ext.PriorityMid),
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						newTestContainer("1", ""),
+					},
+				},
+				Status: corev1.PodStatus{
+					QOSClass: corev1.PodQOSBurstable,
+				},
+			},
+			CgroupDir: "kubepods.slice/kubepods-burstable.slice/kubepods-test-mid-pod.slice",
+		}
+		podDirs := []string{lsrPod.CgroupDir, lsPodWithRequests.CgroupDir, midPod.CgroupDir, bePod.CgroupDir}
+		for _, dir := range podDirs {
+			helper.WriteCgroupFileContents(dir, system.CPUSharesV2, "100")
+		}
+		getWeight := func(shares int64) string {
+			weight, err := system.ConvertCPUSharesToWeight(strconv.FormatInt(shares, 10))
+			assert.NoError(t, err)
+			return strconv.FormatInt(weight, 10)
+		}
+
+		b := &bvtPlugin{
+			sysSupported: pointer.Bool(true),
+			priorityMode: cpuPriorityModeWeight,
+			executor:     resourceexecutor.NewResourceUpdateExecutor(),
+		}
+		stop := make(chan struct{})
+		defer close(stop)
+		b.executor.Run(stop)
+
+		updated, err := b.parseRule(nodeSLO)
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		assert.Nil(t, b.getIdleRule())
+
+		target := &statesinformer.CallbackTarget{
+			Pods: []*statesinformer.PodMeta{lsrPod, lsPodWithRequests, midPod, bePod},
+		}
+		err = b.ruleUpdateCb(target)
+		assert.NoError(t, err)
+		// LSR and LS are raised by the group identity 2 from the kubelet shares
+		assert.Equal(t, getWeight(4*2048), helper.ReadCgroupFileContents(lsrPod.CgroupDir, system.CPUSharesV2))
+		assert.Equal(t, getWeight(4*1024), helper.ReadCgroupFileContents(lsPodWithRequests.CgroupDir, system.CPUSharesV2))
+		// Mid and BE pods are managed by the batch resource hook
+		assert.Equal(t, "100", helper.ReadCgroupFileContents(midPod.CgroupDir, system.CPUSharesV2))
+		assert.Equal(t, "100", helper.ReadCgroupFileContents(bePod.CgroupDir, system.CPUSharesV2))
+
+		kubeQOSCtx := &protocol.KubeQOSContext{}
+		kubeQOSCtx.FromReconciler(corev1.PodQOSBurstable)
+		err = b.SetKubeQOSBvtValue(kubeQOSCtx)
+		assert.NoError(t, err)
+		assert.Nil(t, kubeQOSCtx.Response.Resources.CPUBvt)
+		assert.Nil(t, kubeQOSCtx.Response.Resources.CPUIdle)
+
+		// reset to the kubelet shares when the CPU QoS is disabled
+		updated, err = b.parseRule(&slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: sloconfig.DefaultResourceQOSStrategy(),
+		})
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		err = b.ruleUpdateCb(target)
+		assert.NoError(t, err)
+		assert.Equal(t, getWeight(2048), helper.ReadCgroupFileContents(lsrPod.CgroupDir, system.CPUSharesV2))
+		assert.Equal(t, getWeight(1024), helper.ReadCgroupFileContents(lsPodWithRequests.CgroupDir, system.CPUSharesV2))
+		assert.Equal(t, "100", helper.ReadCgroupFileContents(midPod.CgroupDir, system.CPUSharesV2))
+	})
+}
+
+func Test_scaleCPUSharesByBvt(t *testing.T) {
+	tests := []struct {
+		name          string
+		kubeletShares int64
+		bvtValue      int64
+		want          int64
+	}{
+		{
+			name:          "none identity keeps the kubelet shares",
+			kubeletShares: 1024,
+			bvtValue:      0,
+			want:          1024,
+		},
+		{
+			name:          "higher identity raises the shares",
+			kubeletShares: 1024,
+			bvtValue:      2,
+			want:          4096,
+		},
+		{
+			name:          "lower identity lowers the shares",
+			kubeletShares: 1024,
+			bvtValue:      -1,
+			want:          512,
+		},
+		{
+			name:          "keep the minimum shares",
+			kubeletShares: system.CPUSharesMinValue,
+			bvtValue:      -1,
+			want:          system.CPUSharesMinValue,
+		},
+		{
+			name:          "keep the maximum shares",
+			kubeletShares: system.CPUSharesMaxValue,
+			bvtValue:      2,
+			want:          system.CPUSharesMaxValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scaleCPUSharesByBvt(tt.kubeletShares, tt.bvtValue))
+		})
+	}
+}
+
+func newTestContainer(cpuRequest, cpuLimit string) corev1.Container {
+	container := corev1.Container{
+		Name: "test-container",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse(cpuRequest),
+			},
+		},
+	}
+	if len(cpuLimit) > 0 {
+		container.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse(cpuLimit),
+		}
+	}
+	return container
+}
+
+func newCPUQOSEnabledStrategy(cpuPolicy *slov1alpha1.CPUQOSPolicy) *slov1alpha1.ResourceQOSStrategy {
+	strategy := sloconfig.DefaultResourceQOSStrategy()
+	strategy.Policies = &slov1alpha1.ResourceQOSPolicies{
+		CPUPolicy: cpuPolicy,
+	}
+	for _, resourceQOS := range []*slov1alpha1.ResourceQOS{strategy.LSRClass, strategy.LSClass, strategy.BEClass} {
+		resourceQOS.CPUQOS.Enable = pointer.Bool(true)
+	}
+	return strategy
+}
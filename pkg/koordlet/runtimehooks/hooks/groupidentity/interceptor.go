@@ -31,10 +31,15 @@ func (b *bvtPlugin) SetPodBvtValue(p protocol.HooksProtocol) error {
 		return nil
 	}
 	podCtx := p.(*protocol.PodContext)
+	if mode := b.getCPUPriorityMode(); mode != cpuPriorityModeBVT {
+		b.setPodCPUPriority(r, mode, podCtx)
+		return nil
+	}
 	req := podCtx.Request
 	podQOS := ext.GetQoSClassByAttrs(req.Labels, req.Annotations)
 	podKubeQOS := util.GetKubeQoSByCgroupParent(req.CgroupParent)
-	podBvt := r.getPodBvtValue(podQOS, podKubeQOS)
+	podPriority := ext.GetPodPriorityClassByName(req.Labels[ext.LabelPodPriorityClass])
+	podBvt := r.getPodBvtValue(podQOS, podPriority, podKubeQOS)
 	podCtx.Response.Resources.CPUBvt = pointer.Int64(podBvt)
 	return nil
 }
@@ -45,6 +50,10 @@ func (b *bvtPlugin) SetKubeQOSBvtValue(p protocol.HooksProtocol) error {
 		return nil
 	}
 	kubeQOSCtx := p.(*protocol.KubeQOSContext)
+	if mode := b.getCPUPriorityMode(); mode != cpuPriorityModeBVT {
+		b.setKubeQOSCPUPriority(mode, kubeQOSCtx)
+		return nil
+	}
 	req := kubeQOSCtx.Request
 	bvtValue := r.getKubeQOSDirBvtValue(req.KubeQOSClass)
 	kubeQOSCtx.Response.Resources.CPUBvt = pointer.Int64(bvtValue)
@@ -57,6 +66,10 @@ func (b *bvtPlugin) SetHostAppBvtValue(p protocol.HooksProtocol) error {
 		return nil
 	}
 	hostQOSCtx := p.(*protocol.HostAppContext)
+	if mode := b.getCPUPriorityMode(); mode != cpuPriorityModeBVT {
+		b.setHostAppCPUPriority(mode, hostQOSCtx)
+		return nil
+	}
 	req := hostQOSCtx.Request
 	bvtValue := r.getHostQOSBvtValue(req.QOSClass)
 	hostQOSCtx.Response.Resources.CPUBvt = pointer.Int64(bvtValue)
@@ -259,6 +259,7 @@ func Test_bvtPlugin_SetPodBvtValue_Proxy(t *testing.T) {
 			b := &bvtPlugin{
 				rule:             tt.fields.rule,
 				sysSupported:     tt.fields.systemSupported,
+				priorityMode:     cpuPriorityModeBVT,
 				hasKernelEnabled: tt.fields.hasKernelEnable,
 				executor:         resourceexecutor.NewResourceUpdateExecutor(),
 			}
@@ -461,6 +462,7 @@ func Test_bvtPlugin_SetKubeQOSBvtValue_Reconciler(t *testing.T) {
 			b := &bvtPlugin{
 				rule:             tt.fields.rule,
 				sysSupported:     tt.fields.sysSupported,
+				priorityMode:     cpuPriorityModeBVT,
 				hasKernelEnabled: tt.fields.hasKernelEnable,
 				executor:         resourceexecutor.NewResourceUpdateExecutor(),
 			}
@@ -552,6 +554,7 @@ func Test_bvtPlugin_SetHostAppBvtValue(t *testing.T) {
 			b := &bvtPlugin{
 				rule:          tt.fields.rule,
 				sysSupported:  tt.fields.sysSupported,
+				priorityMode:  cpuPriorityModeBVT,
 				kernelEnabled: tt.fields.kernelEnabled,
 			}
 
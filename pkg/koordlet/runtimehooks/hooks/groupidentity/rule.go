@@ -50,14 +50,20 @@ func (r *bvtRule) getEnable() bool {
 	return r.enable
 }
 
-func (r *bvtRule) getPodBvtValue(podQoSClass ext.QoSClass, podKubeQOS corev1.PodQOSClass) int64 {
-	if val, exist := r.podQOSParams[podQoSClass]; exist {
-		return val
-	}
-	if val, exist := r.kubeQOSPodParams[podKubeQOS]; exist {
-		return val
-	}
-	return *sloconfig.NoneCPUQOS().GroupIdentity
+func (r *bvtRule) getPodBvtValue(podQoSClass ext.QoSClass, podPriority ext.PriorityClass, podKubeQOS corev1.PodQOSClass) int64 {
+	val := *sloconfig.NoneCPUQOS().GroupIdentity
+	if qosVal, exist := r.podQOSParams[podQoSClass]; exist {
+		val = qosVal
+	} else if kubeQOSVal, exist := r.kubeQOSPodParams[podKubeQOS]; exist {
+		val = kubeQOSVal
+	}
+	// the koord-mid pods of QoS LS or None are in the Mid tier, which is identified no higher than the none identity,
+	// so they are preempted by the LS pods while they still preempt the BE pods
+	if podPriority == ext.PriorityMid && (podQoSClass == ext.QoSLS || podQoSClass == ext.QoSNone) &&
+		val > *sloconfig.NoneCPUQOS().GroupIdentity {
+		return *sloconfig.NoneCPUQOS().GroupIdentity
+	}
+	return val
 }
 
 func (r *bvtRule) getKubeQOSDirBvtValue(kubeQOS corev1.PodQOSClass) int64 {
@@ -142,6 +148,14 @@ func (b *bvtPlugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
 
 	updated := b.updateRule(newRule)
 	klog.Infof("runtime hook plugin %s update rule %v, new rule %v", name, updated, newRule)
+
+	// parse the cgroups-v2 cpu.idle rule only when the kernel has no bvt, the weight mode uses the bvt rule
+	if b.priorityMode == cpuPriorityModeIdle {
+		newIdleRule := newCPUIdleRule(qosStrategy, isPolicyGroupIdentity)
+		idleUpdated := b.updateIdleRule(newIdleRule)
+		klog.Infof("runtime hook plugin %s update cpu idle rule %v, new rule %v", name, idleUpdated, newIdleRule)
+		updated = updated || idleUpdated
+	}
 	return updated, nil
 }
 
@@ -155,6 +169,10 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 		klog.V(5).Infof("hook plugin rule is nil, nothing to do for plugin %v", name)
 		return nil
 	}
+	if mode := b.getCPUPriorityMode(); mode != cpuPriorityModeBVT {
+		b.ruleUpdateCbForCPUPriority(r, mode, target)
+		return nil
+	}
 	for _, kubeQOS := range []corev1.PodQOSClass{
 		corev1.PodQOSGuaranteed, corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
 		bvtValue := r.getKubeQOSDirBvtValue(kubeQOS)
@@ -174,7 +192,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 	for _, podMeta := range target.Pods {
 		podQOS := ext.GetPodQoSClassRaw(podMeta.Pod)
 		podKubeQOS := podMeta.Pod.Status.QOSClass
-		podBvt := r.getPodBvtValue(podQOS, podKubeQOS)
+		podBvt := r.getPodBvtValue(podQOS, ext.GetPodPriorityClassRaw(podMeta.Pod), podKubeQOS)
 		podCgroupPath := podMeta.CgroupDir
 		e := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason(name).Message("set bvt to %v", podBvt)
 		bvtUpdater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUBVTWarpNsName, podCgroupPath, strconv.FormatInt(podBvt, 10), e)
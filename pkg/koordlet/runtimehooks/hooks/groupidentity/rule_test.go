@@ -43,6 +43,7 @@ func Test_bvtRule_getPodBvtValue(t *testing.T) {
 	}
 	type args struct {
 		podQoSClass ext.QoSClass
+		podPriority ext.PriorityClass
 		podKubeQoS  corev1.PodQOSClass
 	}
 	tests := []struct {
@@ -83,6 +84,36 @@ func Test_bvtRule_getPodBvtValue(t *testing.T) {
 			},
 			want: 1,
 		},
+		{
+			name: "koord-mid ls pod in the mid tier",
+			fields: fields{
+				podQOSParams: map[ext.QoSClass]int64{
+					ext.QoSLS: 2,
+					ext.QoSBE: -1,
+				},
+			},
+			args: args{
+				podQoSClass: ext.QoSLS,
+				podPriority: ext.PriorityMid,
+				podKubeQoS:  corev1.PodQOSBurstable,
+			},
+			want: 0,
+		},
+		{
+			name: "koord-mid be pod keeps the be identity",
+			fields: fields{
+				podQOSParams: map[ext.QoSClass]int64{
+					ext.QoSLS: 2,
+					ext.QoSBE: -1,
+				},
+			},
+			args: args{
+				podQoSClass: ext.QoSBE,
+				podPriority: ext.PriorityMid,
+				podKubeQoS:  corev1.PodQOSBestEffort,
+			},
+			want: -1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -91,7 +122,7 @@ func Test_bvtRule_getPodBvtValue(t *testing.T) {
 				kubeQOSDirParams: tt.fields.kubeQOSDirParams,
 				kubeQOSPodParams: tt.fields.kubeQOSPodParams,
 			}
-			if got := r.getPodBvtValue(tt.args.podQoSClass, tt.args.podKubeQoS); got != tt.want {
+			if got := r.getPodBvtValue(tt.args.podQoSClass, tt.args.podPriority, tt.args.podKubeQoS); got != tt.want {
 				t.Errorf("getPodBvtValue() = %v, want %v", got, tt.want)
 			}
 		})
@@ -853,6 +884,7 @@ func Test_bvtPlugin_ruleUpdateCbForHostApp(t *testing.T) {
 			b := &bvtPlugin{
 				rule:          tt.fields.rule,
 				sysSupported:  pointer.Bool(true),
+				priorityMode:  cpuPriorityModeBVT,
 				kernelEnabled: pointer.Bool(true),
 				executor:      resourceexecutor.NewResourceUpdateExecutor(),
 			}
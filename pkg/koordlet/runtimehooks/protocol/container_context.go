@@ -191,6 +191,9 @@ func (c *ContainerResponse) ProxyDone(resp *runtimeapi.ContainerResourceHookResp
 	if c.Resources.CPUSet != nil {
 		resp.ContainerResources.CpusetCpus = *c.Resources.CPUSet
 	}
+	if c.Resources.CPUSetMems != nil {
+		resp.ContainerResources.CpusetMems = *c.Resources.CPUSetMems
+	}
 	if c.Resources.CFSQuota != nil {
 		resp.ContainerResources.CpuQuota = *c.Resources.CFSQuota
 	}
@@ -225,9 +228,16 @@ func (c *ContainerContext) FromProxy(req *runtimeapi.ContainerResourceHookReques
 	c.Request.FromProxy(req)
 }
 
-func (c *ContainerContext) ProxyDone(resp *runtimeapi.ContainerResourceHookResponse) {
+func (c *ContainerContext) ProxyDone(resp *runtimeapi.ContainerResourceHookResponse, executor resourceexecutor.ResourceUpdateExecutor) {
+	if c.executor == nil {
+		c.executor = executor
+	}
 	c.injectForExt()
-	c.Response.ProxyDone(resp)
+	if c.executor.IsDryRun() {
+		c.injectForDryRun()
+	} else {
+		c.Response.ProxyDone(resp)
+	}
 	c.Update()
 }
 
@@ -238,12 +248,22 @@ func (c *ContainerContext) NriDone(executor resourceexecutor.ResourceUpdateExecu
 	c.injectForExt()
 	adjust := &api.ContainerAdjustment{}
 	update := &api.ContainerUpdate{}
+	if c.executor.IsDryRun() {
+		c.injectForDryRun()
+		c.Update()
+		return adjust, update, nil
+	}
 	// todo: add more fields conversions
 	if c.Response.Resources.CPUSet != nil {
 		adjust.SetLinuxCPUSetCPUs(*c.Response.Resources.CPUSet)
 		update.SetLinuxCPUSetCPUs(*c.Response.Resources.CPUSet)
 	}
 
+	if c.Response.Resources.CPUSetMems != nil {
+		adjust.SetLinuxCPUSetMems(*c.Response.Resources.CPUSetMems)
+		update.SetLinuxCPUSetMems(*c.Response.Resources.CPUSetMems)
+	}
+
 	if c.Response.Resources.CFSQuota != nil {
 		adjust.SetLinuxCPUQuota(*c.Response.Resources.CFSQuota)
 		update.SetLinuxCPUQuota(*c.Response.Resources.CFSQuota)
@@ -334,6 +354,20 @@ func (c *ContainerContext) injectForOrigin() {
 				*c.Response.Resources.CPUSet, c.Request.CgroupParent)
 		}
 	}
+	// If CPUSetMems is not nil and is not an empty string, set container cpuset mems
+	if c.Response.Resources.CPUSetMems != nil && *c.Response.Resources.CPUSetMems != "" {
+		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message("set container cpuset mems to %v", *c.Response.Resources.CPUSetMems)
+		updater, err := injectCPUSetMems(c.Request.CgroupParent, *c.Response.Resources.CPUSetMems, eventHelper, c.executor)
+		if err != nil {
+			klog.Infof("set container %v/%v/%v cpuset mems %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
+				c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, *c.Response.Resources.CPUSetMems, c.Request.CgroupParent, err)
+		} else {
+			c.updaters = append(c.updaters, updater)
+			klog.V(5).Infof("set container %v/%v/%v cpuset mems %v on cgroup parent %v",
+				c.Request.PodMeta.Namespace, c.Request.PodMeta.Name, c.Request.ContainerMeta.Name,
+				*c.Response.Resources.CPUSetMems, c.Request.CgroupParent)
+		}
+	}
 	// If CFSQuota is not nil, set container cfs quota
 	if c.Response.Resources.CFSQuota != nil {
 		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message(
@@ -367,6 +401,17 @@ func (c *ContainerContext) injectForOrigin() {
 	// TODO other fields
 }
 
+// injectForDryRun generates the updaters of the response resources, so that they are recorded by the executor instead
+// of being returned to the runtime in the dry-run mode.
+func (c *ContainerContext) injectForDryRun() {
+	if len(c.Request.CgroupParent) == 0 {
+		klog.V(5).Infof("container cgroup parent is empty, skip recording dry-run resources for %v/%v",
+			c.Request.PodMeta.String(), c.Request.ContainerMeta.Name)
+		return
+	}
+	c.injectForOrigin()
+}
+
 func (c *ContainerContext) injectForExt() {
 	// TODO
 }
@@ -22,11 +22,13 @@ import (
 	"testing"
 
 	"github.com/containerd/nri/pkg/api"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	runtimeapi "github.com/koordinator-sh/koordinator/apis/runtime/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
@@ -136,6 +138,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 						CPUShares:   pointer.Int64(1024 * 500 / 1000),
 						CFSQuota:    pointer.Int64(1024 * 500 / 1000),
 						CPUSet:      pointer.String("0,1,2"),
+						CPUSetMems:  pointer.String("0"),
 						MemoryLimit: pointer.Int64(2 * 1024 * 1024 * 1024),
 					},
 					AddContainerEnvs: map[string]string{"test": "test"},
@@ -158,6 +161,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 								Value: 512,
 							},
 							Cpus: "0,1,2",
+							Mems: "0",
 						},
 					},
 				},
@@ -178,6 +182,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 								Value: 512,
 							},
 							Cpus: "0,1,2",
+							Mems: "0",
 						},
 					},
 				},
@@ -211,6 +216,47 @@ func TestContainerContext_NriDone(t *testing.T) {
 	}
 }
 
+func TestContainerContext_DryRun(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	resourceexecutor.DefaultDryRunRecorder.Reset()
+	defer resourceexecutor.DefaultDryRunRecorder.Reset()
+	executor := resourceexecutor.NewTestResourceExecutor()
+	executor.SetDryRun(true)
+	stop := make(chan struct{})
+	defer close(stop)
+	executor.Run(stop)
+
+	newContext := func() *ContainerContext {
+		return &ContainerContext{
+			Request: ContainerRequest{
+				CgroupParent: "kubepods/pod-test/test-container",
+			},
+			Response: ContainerResponse{
+				Resources: Resources{
+					CPUSet:      pointer.String("0,1,2"),
+					MemoryLimit: pointer.Int64(2 * 1024 * 1024 * 1024),
+				},
+				AddContainerEnvs: map[string]string{"test": "test"},
+			},
+		}
+	}
+
+	adjust, update, err := newContext().NriDone(executor)
+	assert.NoError(t, err)
+	assert.Equal(t, &api.ContainerAdjustment{}, adjust)
+	assert.Equal(t, &api.ContainerUpdate{}, update)
+	assert.Equal(t, 2, len(resourceexecutor.DefaultDryRunRecorder.List()))
+
+	resp := &runtimeapi.ContainerResourceHookResponse{}
+	newContext().ProxyDone(resp, executor)
+	assert.Nil(t, resp.ContainerResources)
+	assert.Nil(t, resp.ContainerEnvs)
+	for _, record := range resourceexecutor.DefaultDryRunRecorder.List() {
+		assert.Equal(t, int64(2), record.Count)
+	}
+}
+
 func Test_getContainerID(t *testing.T) {
 	type args struct {
 		podAnnotations           map[string]string
@@ -107,4 +107,17 @@ func (c *HostAppContext) injectForExt() {
 				*c.Response.Resources.CPUBvt, c.Request.CgroupParent)
 		}
 	}
+	if c.Response.Resources.CPUIdle != nil {
+		eventHelper := audit.V(3).Group(c.Request.Name).Reason("runtime-hooks").Message(
+			"set host application cpu idle to %v", *c.Response.Resources.CPUIdle)
+		updater, err := injectCPUIdle(c.Request.CgroupParent, *c.Response.Resources.CPUIdle, eventHelper, c.executor)
+		if err != nil {
+			klog.Infof("set host application %v cpu idle %v on cgroup parent %v failed, error %v", c.Request.Name,
+				*c.Response.Resources.CPUIdle, c.Request.CgroupParent, err)
+		} else {
+			c.updaters = append(c.updaters, updater)
+			klog.V(5).Infof("set host application %v cpu idle %v on cgroup parent %v", c.Request.Name,
+				*c.Response.Resources.CPUIdle, c.Request.CgroupParent)
+		}
+	}
 }
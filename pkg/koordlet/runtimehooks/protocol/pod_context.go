@@ -165,7 +165,11 @@ func (p *PodContext) ProxyDone(resp *runtimeapi.PodSandboxHookResponse, executor
 		p.executor = executor
 	}
 	p.injectForExt()
-	p.Response.ProxyDone(resp)
+	// the pod resources are updated via the executor in injectForExt, and the response is not mutated in the
+	// dry-run mode
+	if !p.executor.IsDryRun() {
+		p.Response.ProxyDone(resp)
+	}
 	p.Update()
 }
 
@@ -222,6 +226,19 @@ func (p *PodContext) injectForExt() {
 				p.Request.PodMeta.Name, *p.Response.Resources.CPUBvt, p.Request.CgroupParent)
 		}
 	}
+	if p.Response.Resources.CPUIdle != nil {
+		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
+			"set pod cpu idle to %v", *p.Response.Resources.CPUIdle)
+		updater, err := injectCPUIdle(p.Request.CgroupParent, *p.Response.Resources.CPUIdle, eventHelper, p.executor)
+		if err != nil {
+			klog.Infof("set pod %v/%v cpu idle %v on cgroup parent %v failed, error %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.CPUIdle, p.Request.CgroupParent, err)
+		} else {
+			p.updaters = append(p.updaters, updater)
+			klog.V(5).Infof("set pod %v/%v cpu idle %v on cgroup parent %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.CPUIdle, p.Request.CgroupParent)
+		}
+	}
 	// some of pod-level cgroups are manually updated since pod-stage hooks do not support it;
 	// kubelet may set the cgroups when pod is created or restarted, so we need to update the cgroups repeatedly
 	if p.Response.Resources.CPUShares != nil {
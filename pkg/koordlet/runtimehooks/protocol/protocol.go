@@ -76,14 +76,17 @@ type Resources struct {
 	CPUShares   *int64
 	CFSQuota    *int64
 	CPUSet      *string
+	CPUSetMems  *string
 	MemoryLimit *int64
 
 	// extended resources
 	CPUBvt *int64
+	// cgroups-v2 fallbacks of the CPUBvt for the upstream kernels
+	CPUIdle *int64
 }
 
 func (r *Resources) IsOriginResSet() bool {
-	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.MemoryLimit != nil
+	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.CPUSetMems != nil || r.MemoryLimit != nil
 }
 
 func (r *Resources) FromPod(pod *corev1.Pod) {
@@ -140,6 +143,14 @@ func injectCPUSet(cgroupParent string, cpuset string, a *audit.EventHelper, e re
 	return updater, nil
 }
 
+func injectCPUSetMems(cgroupParent string, mems string, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUSetMemsName, cgroupParent, mems, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
 func injectCPUQuota(cgroupParent string, cpuQuota int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
 	cpuQuotaStr := strconv.FormatInt(cpuQuota, 10)
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, cgroupParent, cpuQuotaStr, a)
@@ -166,3 +177,12 @@ func injectCPUBvt(cgroupParent string, bvtValue int64, a *audit.EventHelper, e r
 	}
 	return updater, nil
 }
+
+func injectCPUIdle(cgroupParent string, idleValue int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	idleValueStr := strconv.FormatInt(idleValue, 10)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUIdleName, cgroupParent, idleValueStr, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
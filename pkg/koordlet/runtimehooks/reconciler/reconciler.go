@@ -73,6 +73,32 @@ type Filter interface {
 	Filter(podMeta *statesinformer.PodMeta) string
 }
 
+// MultiConditionFilter is a Filter which can filter a pod into more than one condition, e.g. the koord-mid pods
+// match both the condition of their QoS class and the Mid condition. The reconcile functions of all the matched
+// conditions are called in order.
+type MultiConditionFilter interface {
+	Filter
+	FilterConditions(podMeta *statesinformer.PodMeta) []string
+}
+
+func getFilterConditions(filter Filter, podMeta *statesinformer.PodMeta) []string {
+	if f, ok := filter.(MultiConditionFilter); ok {
+		return f.FilterConditions(podMeta)
+	}
+	return []string{filter.Filter(podMeta)}
+}
+
+// getReconcileFns returns the reconcile functions of the conditions which the pod matches.
+func (r *cgroupReconciler) getReconcileFns(podMeta *statesinformer.PodMeta) []reconcileFunc {
+	var fns []reconcileFunc
+	for _, condition := range getFilterConditions(r.filter, podMeta) {
+		if fn, ok := r.fn[condition]; ok {
+			fns = append(fns, fn)
+		}
+	}
+	return fns
+}
+
 type noneFilter struct{}
 
 const (
@@ -102,6 +128,9 @@ type podQOSFilter struct{}
 
 const (
 	PodQOSFilterName = "podQOS"
+
+	// PodQOSFilterConditionMid is the condition of the koord-mid pods.
+	PodQOSFilterConditionMid = string(apiext.PriorityMid)
 )
 
 func (p *podQOSFilter) Name() string {
@@ -122,6 +151,27 @@ func (p *podQOSFilter) Filter(podMeta *statesinformer.PodMeta) string {
 	return string(qosClass)
 }
 
+// FilterConditions returns the QoS condition of the pod, and the Mid condition if the pod is koord-mid and is not
+// bound to exclusive cpus, so the mid-tier resources are reconciled along with the QoS-level cgroups.
+// The koord-mid BE pods are reconciled by the BE condition since they are under the besteffort dir.
+func (p *podQOSFilter) FilterConditions(podMeta *statesinformer.PodMeta) []string {
+	condition := p.Filter(podMeta)
+	if IsPodMidTier(podMeta) {
+		return []string{condition, PodQOSFilterConditionMid}
+	}
+	return []string{condition}
+}
+
+// IsPodMidTier returns whether the pod is a koord-mid pod of the Mid tier, i.e. a koord-mid pod of QoS LS or None
+// which is not bound to exclusive cpus.
+func IsPodMidTier(podMeta *statesinformer.PodMeta) bool {
+	if podMeta == nil || podMeta.Pod == nil || apiext.GetPodPriorityClassRaw(podMeta.Pod) != apiext.PriorityMid {
+		return false
+	}
+	condition := PodQOSFilter().Filter(podMeta)
+	return condition == string(apiext.QoSLS) || condition == string(apiext.QoSNone)
+}
+
 var singletonPodQOSFilter *podQOSFilter
 
 // PodQOSFilter returns a Filter which filters pod qos class
@@ -138,9 +188,7 @@ type reconcileFunc func(protocol.HooksProtocol) error
 // conditions. A cgroup file of one level can have multiple reconcile functions with different filtered conditions.
 //
 //	e.g. pod-level cfs_quota can be registered both by cpuset hook and batchresource hook. While cpuset hook reconciles
-//	cfs_quota for LSE and LSR pods, batchresource reconciles pods of BE QoS.
-//
-// TODO: support priority+qos filter.
+//	cfs_quota for LSE and LSR pods, batchresource reconciles pods of BE QoS and koord-mid pods.
 func RegisterCgroupReconciler(level ReconcilerLevel, cgroupFile system.Resource, description string,
 	fn reconcileFunc, filter Filter, conditions ...string) {
 	if len(conditions) <= 0 { // default condition
@@ -314,56 +362,62 @@ func (c *reconciler) reconcilePodCgroup(stopCh <-chan struct{}) {
 			podsMeta := c.getPodsMeta()
 			for _, podMeta := range podsMeta {
 				for _, r := range globalCgroupReconcilers.podLevel {
-					reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
-					if !ok {
-						klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
-							r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
+					reconcileFns := r.getReconcileFns(podMeta)
+					if len(reconcileFns) <= 0 {
+						klog.V(5).Infof("calling reconcile function %v aborted for pod %v, conditions %v not registered",
+							r.description, util.GetPodKey(podMeta.Pod), getFilterConditions(r.filter, podMeta))
 						continue
 					}
 
-					podCtx := protocol.HooksProtocolBuilder.Pod(podMeta)
-					if err := reconcileFn(podCtx); err != nil {
-						klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
-					} else {
-						podCtx.ReconcilerDone(c.executor)
-						klog.V(5).Infof("calling reconcile function %v for pod %v finished",
-							r.description, util.GetPodKey(podMeta.Pod))
+					for _, reconcileFn := range reconcileFns {
+						podCtx := protocol.HooksProtocolBuilder.Pod(podMeta)
+						if err := reconcileFn(podCtx); err != nil {
+							klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
+						} else {
+							podCtx.ReconcilerDone(c.executor)
+							klog.V(5).Infof("calling reconcile function %v for pod %v finished",
+								r.description, util.GetPodKey(podMeta.Pod))
+						}
 					}
 				}
 
 				for _, r := range globalCgroupReconcilers.sandboxContainerLevel {
-					reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
-					if !ok {
-						klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
-							r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
+					reconcileFns := r.getReconcileFns(podMeta)
+					if len(reconcileFns) <= 0 {
+						klog.V(5).Infof("calling reconcile function %v aborted for pod %v, conditions %v not registered",
+							r.description, util.GetPodKey(podMeta.Pod), getFilterConditions(r.filter, podMeta))
 						continue
 					}
-					sandboxContainerCtx := protocol.HooksProtocolBuilder.Sandbox(podMeta)
-					if err := reconcileFn(sandboxContainerCtx); err != nil {
-						klog.Warningf("calling reconcile function %v failed for sandbox, error %v", r.description, err)
-					} else {
-						sandboxContainerCtx.ReconcilerDone(c.executor)
-						klog.V(5).Infof("calling reconcile function %v for pod sandbox %v finished",
-							r.description, util.GetPodKey(podMeta.Pod))
+					for _, reconcileFn := range reconcileFns {
+						sandboxContainerCtx := protocol.HooksProtocolBuilder.Sandbox(podMeta)
+						if err := reconcileFn(sandboxContainerCtx); err != nil {
+							klog.Warningf("calling reconcile function %v failed for sandbox, error %v", r.description, err)
+						} else {
+							sandboxContainerCtx.ReconcilerDone(c.executor)
+							klog.V(5).Infof("calling reconcile function %v for pod sandbox %v finished",
+								r.description, util.GetPodKey(podMeta.Pod))
+						}
 					}
 				}
 
 				for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
 					for _, r := range globalCgroupReconcilers.containerLevel {
-						reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
-						if !ok {
-							klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
-								r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
+						reconcileFns := r.getReconcileFns(podMeta)
+						if len(reconcileFns) <= 0 {
+							klog.V(5).Infof("calling reconcile function %v aborted for pod %v, conditions %v not registered",
+								r.description, util.GetPodKey(podMeta.Pod), getFilterConditions(r.filter, podMeta))
 							continue
 						}
 
-						containerCtx := protocol.HooksProtocolBuilder.Container(podMeta, containerStat.Name)
-						if err := reconcileFn(containerCtx); err != nil {
-							klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
-						} else {
-							containerCtx.ReconcilerDone(c.executor)
-							klog.V(5).Infof("calling reconcile function %v for container %v/%v finish",
-								r.description, util.GetPodKey(podMeta.Pod), containerStat.Name)
+						for _, reconcileFn := range reconcileFns {
+							containerCtx := protocol.HooksProtocolBuilder.Container(podMeta, containerStat.Name)
+							if err := reconcileFn(containerCtx); err != nil {
+								klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
+							} else {
+								containerCtx.ReconcilerDone(c.executor)
+								klog.V(5).Infof("calling reconcile function %v for container %v/%v finish",
+									r.description, util.GetPodKey(podMeta.Pod), containerStat.Name)
+							}
 						}
 					}
 				}
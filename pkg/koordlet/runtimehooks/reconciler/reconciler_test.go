@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
@@ -248,3 +249,92 @@ func TestNewReconciler(t *testing.T) {
 	err := r.Run(stopCh)
 	assert.NoError(t, err, "run reconciler without error")
 }
+
+func TestPodQOSFilter(t *testing.T) {
+	tests := []struct {
+		name           string
+		labels         map[string]string
+		want           string
+		wantConditions []string
+	}{
+		{
+			name:           "ls pod",
+			labels:         map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+			want:           string(apiext.QoSLS),
+			wantConditions: []string{string(apiext.QoSLS)},
+		},
+		{
+			name: "ls mid pod",
+			labels: map[string]string{
+				apiext.LabelPodQoS:           string(apiext.QoSLS),
+				apiext.LabelPodPriorityClass: string(apiext.PriorityMid),
+			},
+			want:           string(apiext.QoSLS),
+			wantConditions: []string{string(apiext.QoSLS), PodQOSFilterConditionMid},
+		},
+		{
+			name:           "mid pod without qos",
+			labels:         map[string]string{apiext.LabelPodPriorityClass: string(apiext.PriorityMid)},
+			want:           string(apiext.QoSNone),
+			wantConditions: []string{string(apiext.QoSNone), PodQOSFilterConditionMid},
+		},
+		{
+			name: "be mid pod",
+			labels: map[string]string{
+				apiext.LabelPodQoS:           string(apiext.QoSBE),
+				apiext.LabelPodPriorityClass: string(apiext.PriorityMid),
+			},
+			want:           string(apiext.QoSBE),
+			wantConditions: []string{string(apiext.QoSBE)},
+		},
+		{
+			name: "lse mid pod",
+			labels: map[string]string{
+				apiext.LabelPodQoS:           string(apiext.QoSLSE),
+				apiext.LabelPodPriorityClass: string(apiext.PriorityMid),
+			},
+			want:           string(apiext.QoSLSE),
+			wantConditions: []string{string(apiext.QoSLSE)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podMeta := &statesinformer.PodMeta{
+				Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}},
+			}
+			assert.Equal(t, tt.want, PodQOSFilter().Filter(podMeta))
+			assert.Equal(t, tt.wantConditions, getFilterConditions(PodQOSFilter(), podMeta))
+		})
+	}
+}
+
+func Test_cgroupReconciler_getReconcileFns(t *testing.T) {
+	var called []string
+	r := &cgroupReconciler{
+		filter: PodQOSFilter(),
+		fn: map[string]reconcileFunc{
+			string(apiext.QoSLS): func(protocol.HooksProtocol) error {
+				called = append(called, string(apiext.QoSLS))
+				return nil
+			},
+			PodQOSFilterConditionMid: func(protocol.HooksProtocol) error {
+				called = append(called, PodQOSFilterConditionMid)
+				return nil
+			},
+		},
+	}
+	podMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			apiext.LabelPodQoS:           string(apiext.QoSLS),
+			apiext.LabelPodPriorityClass: string(apiext.PriorityMid),
+		}}},
+	}
+	for _, fn := range r.getReconcileFns(podMeta) {
+		assert.NoError(t, fn(nil))
+	}
+	assert.Equal(t, []string{string(apiext.QoSLS), PodQOSFilterConditionMid}, called)
+
+	called = nil
+	podMeta.Pod.Labels = map[string]string{apiext.LabelPodQoS: string(apiext.QoSBE)}
+	assert.Len(t, r.getReconcileFns(podMeta), 0)
+}
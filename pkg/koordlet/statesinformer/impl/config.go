@@ -27,11 +27,15 @@ type Config struct {
 	KubeletPreferredAddressType string
 	KubeletSyncInterval         time.Duration
 	KubeletSyncTimeout          time.Duration
+	PodsSyncCoalesceInterval    time.Duration
+	PodsSyncMinInterval         time.Duration
+	EnablePodsAPIServerFallback bool
 	InsecureKubeletTLS          bool
 	KubeletReadOnlyPort         uint
 	NodeTopologySyncInterval    time.Duration
 	DisableQueryKubeletConfig   bool
 	EnableNodeMetricReport      bool
+	NodeSLOStatusReportInterval time.Duration
 	MetricReportInterval        time.Duration // Deprecated
 }
 
@@ -40,11 +44,15 @@ func NewDefaultConfig() *Config {
 		KubeletPreferredAddressType: string(corev1.NodeInternalIP),
 		KubeletSyncInterval:         10 * time.Second,
 		KubeletSyncTimeout:          3 * time.Second,
+		PodsSyncCoalesceInterval:    100 * time.Millisecond,
+		PodsSyncMinInterval:         time.Second,
+		EnablePodsAPIServerFallback: false,
 		InsecureKubeletTLS:          false,
 		KubeletReadOnlyPort:         10255,
 		NodeTopologySyncInterval:    3 * time.Second,
 		DisableQueryKubeletConfig:   false,
 		EnableNodeMetricReport:      true,
+		NodeSLOStatusReportInterval: 60 * time.Second,
 	}
 }
 
@@ -52,10 +60,14 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.KubeletPreferredAddressType, "kubelet-preferred-address-type", c.KubeletPreferredAddressType, "The node address types to use when determining which address to use to connect to a particular node.")
 	fs.DurationVar(&c.KubeletSyncInterval, "kubelet-sync-interval", c.KubeletSyncInterval, "The interval at which Koordlet will retain data from Kubelet. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 	fs.DurationVar(&c.KubeletSyncTimeout, "kubelet-sync-timeout", c.KubeletSyncTimeout, "The length of time to wait before giving up on a single request to Kubelet. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.DurationVar(&c.PodsSyncCoalesceInterval, "pods-sync-coalesce-interval", c.PodsSyncCoalesceInterval, "The interval to coalesce the PLEG pod and container events before syncing pods from Kubelet. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.DurationVar(&c.PodsSyncMinInterval, "pods-sync-min-interval", c.PodsSyncMinInterval, "The minimum interval between two syncs of pods from Kubelet triggered by the PLEG events, which also bounds the coalescing of a burst of events. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.BoolVar(&c.EnablePodsAPIServerFallback, "enable-pods-apiserver-fallback", c.EnablePodsAPIServerFallback, "Watch the pods of the node from kube-apiserver and use them when Kubelet is unavailable.")
 	fs.BoolVar(&c.InsecureKubeletTLS, "kubelet-insecure-tls", c.InsecureKubeletTLS, "Using read-only port to communicate with Kubelet. For testing purposes only, not recommended for production use.")
 	fs.UintVar(&c.KubeletReadOnlyPort, "kubelet-read-only-port", c.KubeletReadOnlyPort, "The read-only port for the kubelet to serve on with no authentication/authorization. Default: 10255.")
 	fs.DurationVar(&c.NodeTopologySyncInterval, "node-topology-sync-interval", c.NodeTopologySyncInterval, "The interval which Koordlet will report the node topology info, include cpu and gpu")
 	fs.BoolVar(&c.DisableQueryKubeletConfig, "disable-query-kubelet-config", c.DisableQueryKubeletConfig, "Disables querying the kubelet configuration from kubelet. Flag must be set to true if kubelet-insecure-tls=true is configured")
 	fs.DurationVar(&c.MetricReportInterval, "report-interval", c.MetricReportInterval, "Deprecated since v1.1, use ColocationStrategy.MetricReportIntervalSeconds in config map of slo-controller")
 	fs.BoolVar(&c.EnableNodeMetricReport, "enable-node-metric-report", c.EnableNodeMetricReport, "Enable status update of node metric crd.")
+	fs.DurationVar(&c.NodeSLOStatusReportInterval, "nodeslo-status-report-interval", c.NodeSLOStatusReportInterval, "The interval at which Koordlet reports the effective strategy and enforcement health to the NodeSLO status. Zero value disables the report. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 }
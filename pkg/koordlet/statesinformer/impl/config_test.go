@@ -36,11 +36,15 @@ func TestNewDefaultConfig(t *testing.T) {
 				KubeletPreferredAddressType: string(corev1.NodeInternalIP),
 				KubeletSyncInterval:         10 * time.Second,
 				KubeletSyncTimeout:          3 * time.Second,
+				PodsSyncCoalesceInterval:    100 * time.Millisecond,
+				PodsSyncMinInterval:         time.Second,
+				EnablePodsAPIServerFallback: false,
 				InsecureKubeletTLS:          false,
 				KubeletReadOnlyPort:         10255,
 				NodeTopologySyncInterval:    3 * time.Second,
 				DisableQueryKubeletConfig:   false,
 				EnableNodeMetricReport:      true,
+				NodeSLOStatusReportInterval: 60 * time.Second,
 				MetricReportInterval:        0,
 			},
 		},
@@ -59,11 +63,15 @@ func TestConfig_InitFlags(t *testing.T) {
 		"--kubelet-preferred-address-type=Hostname",
 		"--kubelet-sync-interval=30s",
 		"--kubelet-sync-timeout=10s",
+		"--pods-sync-coalesce-interval=1s",
+		"--pods-sync-min-interval=5s",
+		"--enable-pods-apiserver-fallback=true",
 		"--kubelet-insecure-tls=true",
 		"--kubelet-read-only-port=10258",
 		"--node-topology-sync-interval=10s",
 		"--disable-query-kubelet-config=true",
 		"--enable-node-metric-report=false",
+		"--nodeslo-status-report-interval=30s",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -71,11 +79,15 @@ func TestConfig_InitFlags(t *testing.T) {
 		KubeletPreferredAddressType string
 		KubeletSyncInterval         time.Duration
 		KubeletSyncTimeout          time.Duration
+		PodsSyncCoalesceInterval    time.Duration
+		PodsSyncMinInterval         time.Duration
+		EnablePodsAPIServerFallback bool
 		InsecureKubeletTLS          bool
 		KubeletReadOnlyPort         uint
 		NodeTopologySyncInterval    time.Duration
 		DisableQueryKubeletConfig   bool
 		EnableNodeMetricReport      bool
+		NodeSLOStatusReportInterval time.Duration
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -91,11 +103,15 @@ func TestConfig_InitFlags(t *testing.T) {
 				KubeletPreferredAddressType: "Hostname",
 				KubeletSyncInterval:         30 * time.Second,
 				KubeletSyncTimeout:          10 * time.Second,
+				PodsSyncCoalesceInterval:    time.Second,
+				PodsSyncMinInterval:         5 * time.Second,
+				EnablePodsAPIServerFallback: true,
 				InsecureKubeletTLS:          true,
 				KubeletReadOnlyPort:         10258,
 				NodeTopologySyncInterval:    10 * time.Second,
 				DisableQueryKubeletConfig:   true,
 				EnableNodeMetricReport:      false,
+				NodeSLOStatusReportInterval: 30 * time.Second,
 			},
 			args: args{fs: fs},
 		},
@@ -106,11 +122,15 @@ func TestConfig_InitFlags(t *testing.T) {
 				KubeletPreferredAddressType: tt.fields.KubeletPreferredAddressType,
 				KubeletSyncInterval:         tt.fields.KubeletSyncInterval,
 				KubeletSyncTimeout:          tt.fields.KubeletSyncTimeout,
+				PodsSyncCoalesceInterval:    tt.fields.PodsSyncCoalesceInterval,
+				PodsSyncMinInterval:         tt.fields.PodsSyncMinInterval,
+				EnablePodsAPIServerFallback: tt.fields.EnablePodsAPIServerFallback,
 				InsecureKubeletTLS:          tt.fields.InsecureKubeletTLS,
 				KubeletReadOnlyPort:         tt.fields.KubeletReadOnlyPort,
 				NodeTopologySyncInterval:    tt.fields.NodeTopologySyncInterval,
 				DisableQueryKubeletConfig:   tt.fields.DisableQueryKubeletConfig,
 				EnableNodeMetricReport:      tt.fields.EnableNodeMetricReport,
+				NodeSLOStatusReportInterval: tt.fields.NodeSLOStatusReportInterval,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
@@ -45,6 +45,7 @@ import (
 	clientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	clientsetv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1"
 	listerv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
@@ -380,6 +381,9 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		}
 		podsMetricInfo = append(podsMetricInfo, podMetric)
 	}
+	if features.DefaultKoordletFeatureGate.Enabled(features.PSICollector) {
+		nodeMetricInfo.LSCPUPressurePercent = r.collectLSCPUPressure(podsMeta, queryParam)
+	}
 	for _, hostApp := range nodeSLO.Spec.HostApplications {
 		appMetric, err := r.collectHostAppMetric(&hostApp, queryParam)
 		if err != nil {
@@ -714,6 +718,39 @@ func (r *nodeMetricInformer) collectPodMetric(podMeta *statesinformer.PodMeta, q
 	return podMetric, nil
 }
 
+// collectLSCPUPressure returns the max cpu pressure (some avg10) of the LS pods, or nil if no pressure is collected.
+func (r *nodeMetricInformer) collectLSCPUPressure(podsMeta []*statesinformer.PodMeta, queryParam metriccache.QueryParam) *int64 {
+	querier, err := r.metricCache.Querier(*queryParam.Start, *queryParam.End)
+	if err != nil {
+		klog.V(5).Infof("failed to get querier for ls cpu pressure, error %v", err)
+		return nil
+	}
+	var pressure *int64
+	for _, podMeta := range podsMeta {
+		if podMeta == nil || podMeta.Pod == nil {
+			continue
+		}
+		switch apiext.GetPodQoSClassWithDefault(podMeta.Pod) {
+		case apiext.QoSLSE, apiext.QoSLSR, apiext.QoSLS:
+		default:
+			continue
+		}
+		aggregateResult, err := doQuery(querier, metriccache.PodPSIMetric, metriccache.MetricPropertiesFunc.PodPSI(string(podMeta.Pod.UID),
+			string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)))
+		if err != nil || aggregateResult.Count() <= 0 {
+			continue
+		}
+		value, err := aggregateResult.Value(queryParam.Aggregate)
+		if err != nil {
+			continue
+		}
+		if pressure == nil || int64(value) > *pressure {
+			pressure = pointer.Int64(int64(value))
+		}
+	}
+	return pressure
+}
+
 func (r *nodeMetricInformer) collectHostAppMetric(hostApp *slov1alpha1.HostApplicationSpec, queryParam metriccache.QueryParam) (*slov1alpha1.HostApplicationMetricInfo, error) {
 	if hostApp == nil {
 		return nil, fmt.Errorf("invalid nil host application")
@@ -32,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/pointer"
 
@@ -1467,3 +1468,69 @@ func Test_nodeMetricInformer_collectHostAppMetric(t *testing.T) {
 		})
 	}
 }
+
+func Test_nodeMetricInformer_collectLSCPUPressure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	startTime := now.Add(-time.Second * 120)
+	queryParam := metriccache.QueryParam{Start: &startTime, End: &now, Aggregate: metriccache.AggregationTypeAVG}
+	newPodMeta := func(uid string, qos apiext.QoSClass) *statesinformer.PodMeta {
+		return &statesinformer.PodMeta{
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   uid,
+					UID:    types.UID(uid),
+					Labels: map[string]string{apiext.LabelPodQoS: string(qos)},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		podsMeta  []*statesinformer.PodMeta
+		pressures map[string]float64
+		want      *int64
+	}{
+		{
+			name: "no ls pod",
+			podsMeta: []*statesinformer.PodMeta{
+				newPodMeta("be-pod", apiext.QoSBE),
+			},
+			want: nil,
+		},
+		{
+			name: "max pressure of the ls pods",
+			podsMeta: []*statesinformer.PodMeta{
+				newPodMeta("ls-pod-0", apiext.QoSLS),
+				newPodMeta("ls-pod-1", apiext.QoSLSR),
+				newPodMeta("be-pod", apiext.QoSBE),
+			},
+			pressures: map[string]float64{
+				"ls-pod-0": 5.5,
+				"ls-pod-1": 12.3,
+			},
+			want: pointer.Int64(12),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+			mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+			metriccache.DefaultAggregateResultFactory = mockResultFactory
+			mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+			mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+			for uid, pressure := range tt.pressures {
+				queryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodPSI(uid,
+					string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)))
+				assert.NoError(t, err)
+				buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, queryMeta, pressure, now.Sub(startTime))
+			}
+			r := &nodeMetricInformer{
+				metricCache: mockMetricCache,
+			}
+			got := r.collectLSCPUPressure(tt.podsMeta, queryParam)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
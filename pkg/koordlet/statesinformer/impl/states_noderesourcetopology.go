@@ -748,6 +748,7 @@ func (s *nodeTopoInformer) calCPUTopology() (*metriccache.NodeCPUInfo, *extensio
 			Core:   cpu.CoreID,
 			Socket: cpu.SocketID,
 			Node:   cpu.NodeID,
+			L3:     cpu.L3,
 		}
 		cpuTopology.Detail = append(cpuTopology.Detail, info)
 		cpus[cpu.CPUID] = &info
@@ -19,19 +19,27 @@ package impl
 import (
 	"context"
 	"encoding/json"
+	"hash/fnv"
 	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
@@ -45,6 +53,11 @@ type nodeSLOInformer struct {
 	nodeSLORWMutex  sync.RWMutex
 	nodeSLO         *slov1alpha1.NodeSLO
 
+	nodeName             string
+	koordClient          koordclientset.Interface
+	statusReportInterval time.Duration
+	statusRecorder       *statesinformer.StrategyStatusRecorder
+
 	callbackRunner *callbackRunner
 }
 
@@ -85,12 +98,19 @@ func (s *nodeSLOInformer) Setup(ctx *PluginOption, state *PluginState) {
 			s.updateNodeSLOSpec(newNodeSLO)
 		},
 	})
+	s.nodeName = ctx.NodeName
+	s.koordClient = ctx.KoordClient
+	s.statusReportInterval = ctx.config.NodeSLOStatusReportInterval
+	s.statusRecorder = statesinformer.DefaultStrategyStatusRecorder
 	s.callbackRunner = state.callbackRunner
 }
 
 func (s *nodeSLOInformer) Start(stopCh <-chan struct{}) {
 	klog.V(2).Infof("starting node slo informer")
 	go s.nodeSLOInformer.Run(stopCh)
+	if s.statusReportInterval > 0 {
+		go wait.Until(s.reportNodeSLOStatus, s.statusReportInterval, stopCh)
+	}
 	klog.V(2).Infof("node slo informer started")
 }
 
@@ -171,6 +191,133 @@ func (s *nodeSLOInformer) mergeNodeSLOSpec(nodeSLO *slov1alpha1.NodeSLO) {
 
 }
 
+// reportNodeSLOStatus writes back the effective strategy and the enforcement health of the node to the NodeSLO status.
+func (s *nodeSLOInformer) reportNodeSLOStatus() {
+	nodeSLO := s.GetNodeSLO()
+	if nodeSLO == nil {
+		klog.V(4).Infof("skip reporting NodeSLO status, NodeSLO %s has not been synced", s.nodeName)
+		return
+	}
+	newStatus := s.generateNodeSLOStatus(&nodeSLO.Spec)
+
+	skipped := false
+	retErr := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		oldNodeSLO, err := s.koordClient.SloV1alpha1().NodeSLOs().Get(context.TODO(), s.nodeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !isNodeSLOStatusChanged(&oldNodeSLO.Status, newStatus) {
+			skipped = true
+			return nil
+		}
+		newNodeSLO := oldNodeSLO.DeepCopy()
+		newNodeSLO.Status = *newStatus
+		_, err = s.koordClient.SloV1alpha1().NodeSLOs().UpdateStatus(context.TODO(), newNodeSLO, metav1.UpdateOptions{})
+		return err
+	})
+	if retErr != nil {
+		klog.Warningf("update NodeSLO status failed, status %v, err %v", util.DumpJSON(newStatus), retErr)
+	} else if skipped {
+		klog.V(5).Infof("NodeSLO status is not changed, skip updating")
+	} else {
+		klog.V(4).Infof("update NodeSLO status success, detail: %v", util.DumpJSON(newStatus))
+	}
+}
+
+func (s *nodeSLOInformer) generateNodeSLOStatus(spec *slov1alpha1.NodeSLOSpec) *slov1alpha1.NodeSLOStatus {
+	now := metav1.Now()
+	status := &slov1alpha1.NodeSLOStatus{
+		ObservedSpecHash:  hashNodeSLOSpec(spec),
+		LastReconcileTime: &now,
+		KernelFeatures:    detectKernelFeatures(),
+	}
+	if s.statusRecorder != nil {
+		status.Conditions = s.statusRecorder.Conditions()
+	}
+	return status
+}
+
+// isNodeSLOStatusChanged compares the statuses without the timestamps.
+func isNodeSLOStatusChanged(oldStatus, newStatus *slov1alpha1.NodeSLOStatus) bool {
+	o, n := oldStatus.DeepCopy(), newStatus.DeepCopy()
+	for _, status := range []*slov1alpha1.NodeSLOStatus{o, n} {
+		status.LastReconcileTime = nil
+		for i := range status.Conditions {
+			status.Conditions[i].LastTransitionTime = metav1.Time{}
+		}
+	}
+	return !equality.Semantic.DeepEqual(o, n)
+}
+
+// hashNodeSLOSpec returns the hash of the effective NodeSLO spec merged with the default config.
+func hashNodeSLOSpec(spec *slov1alpha1.NodeSLOSpec) string {
+	h := fnv.New64a()
+	h.Write([]byte(util.DumpJSON(spec)))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+type kernelFeatureChecker struct {
+	name  string
+	check func() (bool, string)
+}
+
+// newCgroupFeatureChecker checks the cgroup resource on the qos-level dir, or the root dir if the qos is empty.
+func newCgroupFeatureChecker(name string, resourceType system.ResourceType, qos corev1.PodQOSClass) kernelFeatureChecker {
+	return kernelFeatureChecker{
+		name: name,
+		check: func() (bool, string) {
+			r, err := system.GetCgroupResource(resourceType)
+			if err != nil {
+				return false, err.Error()
+			}
+			parentDir := ""
+			if qos != "" {
+				parentDir = koordletutil.GetPodQoSRelativePath(qos)
+			}
+			return r.IsSupported(parentDir)
+		},
+	}
+}
+
+var kernelFeatureCheckers = []kernelFeatureChecker{
+	newCgroupFeatureChecker("GroupIdentity", system.CPUBVTWarpNsName, corev1.PodQOSGuaranteed),
+	newCgroupFeatureChecker("CPUBurst", system.CPUBurstName, corev1.PodQOSGuaranteed),
+	newCgroupFeatureChecker("MemoryQOS", system.MemoryMinName, corev1.PodQOSGuaranteed),
+	newCgroupFeatureChecker("MemoryWatermark", system.MemoryWmarkRatioName, corev1.PodQOSGuaranteed),
+	newCgroupFeatureChecker("MemoryPriority", system.MemoryPriorityName, corev1.PodQOSGuaranteed),
+	newCgroupFeatureChecker("BlkIOQOS", system.BlkioIOQoSName, ""),
+	{
+		name: "Resctrl",
+		check: func() (bool, string) {
+			isSupported, err := system.IsSupportResctrl()
+			if err != nil {
+				return false, err.Error()
+			}
+			if !isSupported {
+				return false, "cpu not support CAT/MBA"
+			}
+			return true, ""
+		},
+	},
+}
+
+// detectKernelFeatures checks the kernel features which the qos strategies depend on.
+func detectKernelFeatures() []slov1alpha1.KernelFeature {
+	features := make([]slov1alpha1.KernelFeature, 0, len(kernelFeatureCheckers))
+	for _, checker := range kernelFeatureCheckers {
+		isSupported, msg := checker.check()
+		feature := slov1alpha1.KernelFeature{
+			Name:      checker.name,
+			Supported: isSupported,
+		}
+		if !isSupported {
+			feature.Message = msg
+		}
+		features = append(features, feature)
+	}
+	return features
+}
+
 func newNodeSLOInformer(client koordclientset.Interface, nodeName string) cache.SharedIndexInformer {
 	tweakListOptionFunc := func(opt *metav1.ListOptions) {
 		opt.FieldSelector = "metadata.name=" + nodeName
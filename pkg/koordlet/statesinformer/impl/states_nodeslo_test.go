@@ -17,13 +17,19 @@ limitations under the License.
 package impl
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	fakekoordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
@@ -461,3 +467,75 @@ func Test_mergeNoneResourceQOSIfDisabled(t *testing.T) {
 		})
 	}
 }
+
+func Test_reportNodeSLOStatus(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetResourcesSupported(true, system.CPUBurst)
+	helper.SetResourcesSupported(false, system.CPUBVTWarpNs)
+
+	testingNodeSLO := &slov1alpha1.NodeSLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Spec: sloconfig.DefaultNodeSLOSpecConfig(),
+	}
+	koordClient := fakekoordclientset.NewSimpleClientset(testingNodeSLO)
+	recorder := statesinformer.NewStrategyStatusRecorder()
+	recorder.SetCondition("CPUSuppress", slov1alpha1.StrategyConditionApplied, "")
+	recorder.SetCondition("ResctrlReconcile", slov1alpha1.StrategyConditionUnsupported, "cpu not support CAT/MBA")
+	s := &nodeSLOInformer{
+		nodeName:       "test-node",
+		koordClient:    koordClient,
+		statusRecorder: recorder,
+	}
+
+	// skip if the nodeSLO is not synced
+	s.reportNodeSLOStatus()
+	got, err := koordClient.SloV1alpha1().NodeSLOs().Get(context.TODO(), "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Nil(t, got.Status.LastReconcileTime)
+
+	s.setNodeSLOSpec(testingNodeSLO)
+	s.reportNodeSLOStatus()
+	got, err = koordClient.SloV1alpha1().NodeSLOs().Get(context.TODO(), "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotNil(t, got.Status.LastReconcileTime)
+	assert.Equal(t, hashNodeSLOSpec(&s.GetNodeSLO().Spec), got.Status.ObservedSpecHash)
+	assert.Equal(t, recorder.Conditions(), got.Status.Conditions)
+	assert.Equal(t, len(kernelFeatureCheckers), len(got.Status.KernelFeatures))
+	for _, feature := range got.Status.KernelFeatures {
+		switch feature.Name {
+		case "CPUBurst":
+			assert.True(t, feature.Supported)
+			assert.Equal(t, "", feature.Message)
+		case "GroupIdentity":
+			assert.False(t, feature.Supported)
+			assert.NotEqual(t, "", feature.Message)
+		}
+	}
+
+	// skip updating if the status is not changed
+	lastReconcileTime := got.Status.LastReconcileTime
+	time.Sleep(10 * time.Millisecond)
+	s.reportNodeSLOStatus()
+	got, err = koordClient.SloV1alpha1().NodeSLOs().Get(context.TODO(), "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, lastReconcileTime, got.Status.LastReconcileTime)
+
+	recorder.SetCondition("CPUSuppress", slov1alpha1.StrategyConditionError, "failed")
+	s.reportNodeSLOStatus()
+	got, err = koordClient.SloV1alpha1().NodeSLOs().Get(context.TODO(), "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, lastReconcileTime, got.Status.LastReconcileTime)
+	assert.Equal(t, recorder.Conditions(), got.Status.Conditions)
+}
+
+func Test_hashNodeSLOSpec(t *testing.T) {
+	spec := sloconfig.DefaultNodeSLOSpecConfig()
+	hash := hashNodeSLOSpec(&spec)
+	assert.Equal(t, hash, hashNodeSLOSpec(&spec))
+
+	spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent = pointer.Int64(10)
+	assert.NotEqual(t, hash, hashNodeSLOSpec(&spec))
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package impl
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"go.uber.org/atomic"
-	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -50,11 +54,20 @@ type podsInformer struct {
 	podHasSynced   *atomic.Bool
 
 	// use pleg to accelerate the efficiency of Pod meta update
-	pleg       pleg.Pleg
-	podCreated chan string
+	pleg pleg.Pleg
+	// podSyncTriggered is notified when the pleg events show the pods are changed, and the events are coalesced
+	// since the channel is buffered with only one event
+	podSyncTriggered chan struct{}
+	// dirtyPods records the pods changed by the pleg events, where the value is whether the pod is deleted,
+	// so that the sync triggered by the events only updates these pods
+	dirtyPodsLock sync.Mutex
+	dirtyPods     map[string]bool
 
 	kubelet      KubeletStub
 	nodeInformer *nodeInformer
+	// apiServerPodInformer watches the pods of the node from kube-apiserver, and provides the pods when the kubelet
+	// is unavailable. It is nil if the fallback is disabled.
+	apiServerPodInformer cache.SharedIndexInformer
 
 	callbackRunner *callbackRunner
 }
@@ -66,10 +79,11 @@ func NewPodsInformer() *podsInformer {
 	}
 
 	podsInformer := &podsInformer{
-		podMap:       map[string]*statesinformer.PodMeta{},
-		podHasSynced: atomic.NewBool(false),
-		pleg:         p,
-		podCreated:   make(chan string, 1),
+		podMap:           map[string]*statesinformer.PodMeta{},
+		podHasSynced:     atomic.NewBool(false),
+		pleg:             p,
+		podSyncTriggered: make(chan struct{}, 1),
+		dirtyPods:        map[string]bool{},
 	}
 	return podsInformer
 }
@@ -85,6 +99,10 @@ func (s *podsInformer) Setup(ctx *PluginOption, states *PluginState) {
 	s.nodeInformer = nodeInformer
 
 	s.callbackRunner = states.callbackRunner
+
+	if s.config.EnablePodsAPIServerFallback {
+		s.apiServerPodInformer = newAPIServerPodInformer(ctx.KubeClient, ctx.NodeName)
+	}
 }
 
 func (s *podsInformer) Start(stopCh <-chan struct{}) {
@@ -102,18 +120,40 @@ func (s *podsInformer) Start(stopCh <-chan struct{}) {
 	s.kubelet = stub
 	hdlID := s.pleg.AddHandler(pleg.PodLifeCycleHandlerFuncs{
 		PodAddedFunc: func(podID string) {
-			// There is no need to notify to update the data when the channel is not empty
-			if len(s.podCreated) == 0 {
-				s.podCreated <- podID
-				klog.V(5).Infof("new pod %v created, send event to sync pods", podID)
-			} else {
-				klog.V(5).Infof("new pod %v created, last event has not been consumed, no need to send event",
-					podID)
+			if s.isPodKnown(podID) {
+				klog.V(5).Infof("pod %v created but already synced, no need to sync pods", podID)
+				return
+			}
+			s.triggerSyncPods(podID, false, "pod %v created", podID)
+		},
+		PodDeletedFunc: func(podID string) {
+			if !s.isPodKnown(podID) {
+				klog.V(5).Infof("pod %v deleted but not synced, no need to sync pods", podID)
+				return
+			}
+			s.triggerSyncPods(podID, true, "pod %v deleted", podID)
+		},
+		ContainerAddedFunc: func(podID, containerID string) {
+			if running, known := s.getContainerState(podID, containerID); known && running {
+				klog.V(5).Infof("container %v/%v created but already synced, no need to sync pods", podID, containerID)
+				return
 			}
+			s.triggerSyncPods(podID, false, "container %v/%v created", podID, containerID)
+		},
+		ContainerDeletedFunc: func(podID, containerID string) {
+			if running, _ := s.getContainerState(podID, containerID); !running {
+				klog.V(5).Infof("container %v/%v deleted but not running, no need to sync pods", podID, containerID)
+				return
+			}
+			s.triggerSyncPods(podID, false, "container %v/%v deleted", podID, containerID)
 		},
 	})
 	defer s.pleg.RemoverHandler(hdlID)
 
+	if s.apiServerPodInformer != nil {
+		go s.apiServerPodInformer.Run(stopCh)
+	}
+
 	go s.syncKubeletLoop(s.config.KubeletSyncInterval, stopCh)
 	go func() {
 		if err := s.pleg.Run(stopCh); err != nil {
@@ -141,13 +181,78 @@ func (s *podsInformer) GetAllPods() []*statesinformer.PodMeta {
 	return pods
 }
 
+// isPodKnown returns whether the pod of the pleg event has been synced.
+func (s *podsInformer) isPodKnown(podID string) bool {
+	s.podRWMutex.RLock()
+	defer s.podRWMutex.RUnlock()
+	_, ok := s.podMap[podID]
+	return ok
+}
+
+// getContainerState returns whether the container of the pleg event is running and whether it has been synced.
+func (s *podsInformer) getContainerState(podID, containerID string) (running bool, known bool) {
+	s.podRWMutex.RLock()
+	defer s.podRWMutex.RUnlock()
+	podMeta, ok := s.podMap[podID]
+	if !ok {
+		return false, false
+	}
+	for _, statuses := range [][]corev1.ContainerStatus{podMeta.Pod.Status.InitContainerStatuses, podMeta.Pod.Status.ContainerStatuses} {
+		for i := range statuses {
+			if statuses[i].ContainerID == "" {
+				continue
+			}
+			_, id, err := util.ParseContainerId(statuses[i].ContainerID)
+			if err != nil || id != containerID {
+				continue
+			}
+			return statuses[i].State.Running != nil, true
+		}
+	}
+	return false, false
+}
+
+// triggerSyncPods marks the pod dirty and notifies the sync loop to sync it. The events are coalesced if the last
+// one is not consumed.
+func (s *podsInformer) triggerSyncPods(podID string, deleted bool, format string, args ...interface{}) {
+	s.dirtyPodsLock.Lock()
+	if s.dirtyPods == nil {
+		s.dirtyPods = map[string]bool{}
+	}
+	s.dirtyPods[podID] = deleted
+	s.dirtyPodsLock.Unlock()
+
+	select {
+	case s.podSyncTriggered <- struct{}{}:
+		klog.V(5).Infof(format+", send event to sync pods", args...)
+	default:
+		klog.V(5).Infof(format+", last event has not been consumed, no need to send event", args...)
+	}
+}
+
+// popDirtyPods returns the pods changed since the last sync and clears them.
+func (s *podsInformer) popDirtyPods() map[string]bool {
+	s.dirtyPodsLock.Lock()
+	defer s.dirtyPodsLock.Unlock()
+	dirtyPods := s.dirtyPods
+	s.dirtyPods = map[string]bool{}
+	return dirtyPods
+}
+
 func (s *podsInformer) syncPods() error {
+	// the full sync covers all the dirty pods
+	s.popDirtyPods()
 	podList, err := s.kubelet.GetAllPods()
 
 	// when kubelet recovers from crash, podList may be empty.
 	if err != nil || len(podList.Items) == 0 {
 		klog.Warningf("get pods from kubelet failed, err: %v", err)
-		return err
+		apiServerPodList, ok := s.getPodsFromAPIServer()
+		if !ok {
+			return err
+		}
+		klog.V(4).Infof("kubelet is unavailable, use %d pods from kube-apiserver", len(apiServerPodList.Items))
+		podList, err = apiServerPodList, nil
 	}
 	newPodMap := make(map[string]*statesinformer.PodMeta, len(podList.Items))
 	// reset pod container metrics
@@ -161,10 +266,68 @@ func (s *podsInformer) syncPods() error {
 		// record pod container metrics
 		recordPodResourceMetrics(podMeta)
 	}
+	s.podRWMutex.Lock()
 	s.podMap = newPodMap
+	s.podUpdatedTime = time.Now()
+	s.podRWMutex.Unlock()
 	s.podHasSynced.Store(true)
+	klog.V(4).Infof("get pods success, len %d, time %s", len(newPodMap), s.podUpdatedTime.String())
+	s.callbackRunner.SendCallback(statesinformer.RegisterTypeAllPods)
+	return nil
+}
+
+// syncPodsByIDs updates only the given pods changed by the pleg events. The deleted pods are removed without
+// requesting the kubelet, and the other pods are picked from the kubelet pods. It falls back to the full sync
+// if the pods have not been synced or the kubelet is unavailable.
+func (s *podsInformer) syncPodsByIDs(dirtyPods map[string]bool) error {
+	if len(dirtyPods) == 0 {
+		return nil
+	}
+	if !s.podHasSynced.Load() {
+		return s.syncPods()
+	}
+
+	onlyDeleted := true
+	for _, deleted := range dirtyPods {
+		if !deleted {
+			onlyDeleted = false
+			break
+		}
+	}
+	kubeletPods := map[string]*corev1.Pod{}
+	if !onlyDeleted {
+		podList, err := s.kubelet.GetAllPods()
+		if err != nil || len(podList.Items) == 0 {
+			klog.Warningf("get pods from kubelet failed, fall back to sync all pods, err: %v", err)
+			return s.syncPods()
+		}
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			if _, ok := dirtyPods[string(pod.UID)]; ok {
+				kubeletPods[string(pod.UID)] = pod
+			}
+		}
+	}
+
+	s.podRWMutex.Lock()
+	for podID := range dirtyPods {
+		metrics.DeletePodResourceMetrics(podID)
+		pod, ok := kubeletPods[podID]
+		if !ok {
+			delete(s.podMap, podID)
+			continue
+		}
+		podMeta := &statesinformer.PodMeta{
+			Pod:       pod.DeepCopy(),
+			CgroupDir: genPodCgroupParentDir(pod),
+		}
+		s.podMap[podID] = podMeta
+		recordPodResourceMetrics(podMeta)
+	}
 	s.podUpdatedTime = time.Now()
-	klog.V(4).Infof("get pods success, len %d, time %s", len(s.podMap), s.podUpdatedTime.String())
+	podNum := len(s.podMap)
+	s.podRWMutex.Unlock()
+	klog.V(4).Infof("sync %d changed pods success, len %d, time %s", len(dirtyPods), podNum, s.podUpdatedTime.String())
 	s.callbackRunner.SendCallback(statesinformer.RegisterTypeAllPods)
 	return nil
 }
@@ -173,26 +336,27 @@ func (s *podsInformer) syncKubeletLoop(duration time.Duration, stopCh <-chan str
 	timer := time.NewTimer(duration)
 	defer timer.Stop()
 	s.syncPods()
-	// TODO add a config to setup the values
-	rateLimiter := rate.NewLimiter(5, 10)
+	lastSynced := time.Now()
 	for {
 		select {
-		case <-s.podCreated:
-			if rateLimiter.Allow() {
-				// sync kubelet triggered immediately when the Pod is created
-				klog.V(4).Infof("new pod created, sync from kubelet immediately")
-				s.syncPods()
-				// reset timer to
-				if !timer.Stop() {
-					<-timer.C
-				}
-				timer.Reset(duration)
-			} else {
-				klog.V(4).Infof("new pod created, but sync rate limiter is not allowed")
+		case <-s.podSyncTriggered:
+			if !s.waitSyncCoalesced(stopCh, lastSynced) {
+				klog.Infof("sync kubelet loop is exited")
+				return
+			}
+			// sync kubelet triggered when the pods are changed
+			klog.V(4).Infof("pods changed, sync the changed pods")
+			s.syncPodsByIDs(s.popDirtyPods())
+			lastSynced = time.Now()
+			// reset timer to
+			if !timer.Stop() {
+				<-timer.C
 			}
+			timer.Reset(duration)
 		case <-timer.C:
 			timer.Reset(duration)
 			s.syncPods()
+			lastSynced = time.Now()
 		case <-stopCh:
 			klog.Infof("sync kubelet loop is exited")
 			return
@@ -200,6 +364,94 @@ func (s *podsInformer) syncKubeletLoop(duration time.Duration, stopCh <-chan str
 	}
 }
 
+// waitSyncCoalesced debounces the pleg events so that a burst of them triggers only one sync. It waits until no event
+// is received for the coalesce interval, but no longer than the minimum sync interval since the first event, and
+// no earlier than the minimum sync interval since the last sync. So the pleg events fetch the kubelet pods at most
+// once per minimum sync interval. It returns false if the loop is stopped.
+func (s *podsInformer) waitSyncCoalesced(stopCh <-chan struct{}, lastSynced time.Time) bool {
+	if s.config == nil {
+		return true
+	}
+	coalesceInterval, minInterval := s.config.PodsSyncCoalesceInterval, s.config.PodsSyncMinInterval
+	now := time.Now()
+	quietDeadline := now.Add(coalesceInterval)
+	maxDeadline := now.Add(minInterval)
+	if coalesceInterval > minInterval {
+		maxDeadline = quietDeadline
+	}
+	earliestDeadline := lastSynced.Add(minInterval)
+	for {
+		deadline := quietDeadline
+		if deadline.After(maxDeadline) {
+			deadline = maxDeadline
+		}
+		if deadline.Before(earliestDeadline) {
+			deadline = earliestDeadline
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			break
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.podSyncTriggered: // restart the quiet period
+			timer.Stop()
+			quietDeadline = time.Now().Add(coalesceInterval)
+			continue
+		case <-timer.C:
+		case <-stopCh:
+			timer.Stop()
+			return false
+		}
+		break
+	}
+	// drop the events received during the wait since the next sync covers them
+	select {
+	case <-s.podSyncTriggered:
+	default:
+	}
+	return true
+}
+
+// getPodsFromAPIServer returns the pods of the node watched from kube-apiserver if the fallback is enabled.
+func (s *podsInformer) getPodsFromAPIServer() (corev1.PodList, bool) {
+	if s.apiServerPodInformer == nil || !s.apiServerPodInformer.HasSynced() {
+		return corev1.PodList{}, false
+	}
+	objs := s.apiServerPodInformer.GetStore().List()
+	podList := corev1.PodList{Items: make([]corev1.Pod, 0, len(objs))}
+	for _, obj := range objs {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			continue
+		}
+		podList.Items = append(podList.Items, *pod)
+	}
+	return podList, len(podList.Items) > 0
+}
+
+func newAPIServerPodInformer(client clientset.Interface, nodeName string) cache.SharedIndexInformer {
+	tweakListOptionsFunc := func(opt *metav1.ListOptions) {
+		opt.FieldSelector = "spec.nodeName=" + nodeName
+	}
+
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (apiruntime.Object, error) {
+				tweakListOptionsFunc(&options)
+				return client.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				tweakListOptionsFunc(&options)
+				return client.CoreV1().Pods(metav1.NamespaceAll).Watch(context.TODO(), options)
+			},
+		},
+		&corev1.Pod{},
+		time.Hour*12,
+		cache.Indexers{},
+	)
+}
+
 func newKubeletStubFromConfig(node *corev1.Node, cfg *Config) (KubeletStub, error) {
 	var port int
 	var scheme string
@@ -287,4 +539,18 @@ func recordContainerResourceMetrics(container *corev1.Container, containerStatus
 	if q, ok := container.Resources.Limits[apiext.BatchMemory]; ok {
 		metrics.RecordContainerResourceLimits(string(apiext.BatchMemory), metrics.UnitByte, containerStatus, pod, float64(util.QuantityPtr(q).Value()))
 	}
+
+	// record pod requests/limits of MidCPU & MidMemory
+	if q, ok := container.Resources.Requests[apiext.MidCPU]; ok {
+		metrics.RecordContainerResourceRequests(string(apiext.MidCPU), metrics.UnitInteger, containerStatus, pod, float64(util.QuantityPtr(q).Value()))
+	}
+	if q, ok := container.Resources.Requests[apiext.MidMemory]; ok {
+		metrics.RecordContainerResourceRequests(string(apiext.MidMemory), metrics.UnitByte, containerStatus, pod, float64(util.QuantityPtr(q).Value()))
+	}
+	if q, ok := container.Resources.Limits[apiext.MidCPU]; ok {
+		metrics.RecordContainerResourceLimits(string(apiext.MidCPU), metrics.UnitInteger, containerStatus, pod, float64(util.QuantityPtr(q).Value()))
+	}
+	if q, ok := container.Resources.Limits[apiext.MidMemory]; ok {
+		metrics.RecordContainerResourceLimits(string(apiext.MidMemory), metrics.UnitByte, containerStatus, pod, float64(util.QuantityPtr(q).Value()))
+	}
 }
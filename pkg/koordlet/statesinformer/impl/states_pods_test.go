@@ -30,7 +30,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	kubeletconfiginternal "k8s.io/kubernetes/pkg/kubelet/apis/config"
 
@@ -155,6 +158,169 @@ func Test_statesInformer_syncPods(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_podsInformer_syncPodsFallbackToAPIServer(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			UID:       "test-pod-uid",
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node",
+		},
+	}
+	m := &podsInformer{
+		kubelet:              &testErrorKubeletStub{},
+		podHasSynced:         atomic.NewBool(false),
+		callbackRunner:       NewCallbackRunner(),
+		apiServerPodInformer: newAPIServerPodInformer(fakeclientset.NewSimpleClientset(testPod), "test-node"),
+	}
+	go m.apiServerPodInformer.Run(stopCh)
+	assert.True(t, cache.WaitForCacheSync(stopCh, m.apiServerPodInformer.HasSynced))
+
+	err := m.syncPods()
+	assert.NoError(t, err)
+	pods := m.GetAllPods()
+	assert.Equal(t, 1, len(pods))
+	assert.Equal(t, testPod.UID, pods[0].Pod.UID)
+	assert.True(t, m.HasSynced())
+}
+
+func Test_podsInformer_syncPodsByIDs(t *testing.T) {
+	newPod := func(uid string, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: uid, UID: types.UID(uid)},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	m := &podsInformer{
+		kubelet: &testKubeletStub{pods: corev1.PodList{
+			Items: []corev1.Pod{
+				newPod("pod-a", corev1.PodPending),
+				newPod("pod-b", corev1.PodPending),
+			},
+		}},
+		podHasSynced:   atomic.NewBool(false),
+		callbackRunner: NewCallbackRunner(),
+	}
+
+	// not synced, fall back to sync all pods
+	err := m.syncPodsByIDs(map[string]bool{"pod-a": false})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(m.GetAllPods()))
+	assert.True(t, m.HasSynced())
+
+	// only the changed pods are updated
+	m.kubelet = &testKubeletStub{pods: corev1.PodList{
+		Items: []corev1.Pod{
+			newPod("pod-a", corev1.PodRunning),
+			newPod("pod-b", corev1.PodRunning),
+			newPod("pod-c", corev1.PodRunning),
+		},
+	}}
+	err = m.syncPodsByIDs(map[string]bool{"pod-a": false, "pod-c": false})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(m.podMap))
+	assert.Equal(t, corev1.PodRunning, m.podMap["pod-a"].Pod.Status.Phase)
+	assert.Equal(t, corev1.PodPending, m.podMap["pod-b"].Pod.Status.Phase)
+	assert.Equal(t, corev1.PodRunning, m.podMap["pod-c"].Pod.Status.Phase)
+
+	// the deleted pods are removed without requesting the kubelet
+	m.kubelet = &testErrorKubeletStub{}
+	err = m.syncPodsByIDs(map[string]bool{"pod-c": true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(m.podMap))
+	_, ok := m.podMap["pod-c"]
+	assert.False(t, ok)
+
+	// fall back to sync all pods when the kubelet is unavailable
+	err = m.syncPodsByIDs(map[string]bool{"pod-a": false})
+	assert.Error(t, err)
+	assert.Equal(t, 2, len(m.podMap))
+}
+
+func Test_podsInformer_triggerSyncPodsByPLEG(t *testing.T) {
+	m := &podsInformer{
+		podMap: map[string]*statesinformer.PodMeta{
+			"test-pod-uid": {
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{UID: "test-pod-uid"},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name:        "running",
+								ContainerID: "containerd://running-id",
+								State:       corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+							},
+							{
+								Name:        "terminated",
+								ContainerID: "containerd://terminated-id",
+								State:       corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}},
+							},
+						},
+					},
+				},
+			},
+		},
+		podSyncTriggered: make(chan struct{}, 1),
+	}
+
+	assert.True(t, m.isPodKnown("test-pod-uid"))
+	assert.False(t, m.isPodKnown("unknown-pod-uid"))
+	running, known := m.getContainerState("test-pod-uid", "running-id")
+	assert.True(t, running)
+	assert.True(t, known)
+	running, known = m.getContainerState("test-pod-uid", "terminated-id")
+	assert.False(t, running)
+	assert.True(t, known)
+	running, known = m.getContainerState("test-pod-uid", "unknown-id")
+	assert.False(t, running)
+	assert.False(t, known)
+
+	// the burst of events are coalesced
+	m.triggerSyncPods("test-pod-uid", false, "test event %v", 1)
+	m.triggerSyncPods("deleted-pod-uid", true, "test event %v", 2)
+	assert.Equal(t, 1, len(m.podSyncTriggered))
+	assert.Equal(t, map[string]bool{"test-pod-uid": false, "deleted-pod-uid": true}, m.popDirtyPods())
+	assert.Equal(t, 0, len(m.popDirtyPods()))
+
+	m.config = &Config{PodsSyncCoalesceInterval: 10 * time.Millisecond, PodsSyncMinInterval: 100 * time.Millisecond}
+	<-m.podSyncTriggered
+	m.triggerSyncPods("test-pod-uid", false, "test event %v", 3)
+	stopCh := make(chan struct{})
+	assert.True(t, m.waitSyncCoalesced(stopCh, time.Time{}))
+	assert.Equal(t, 0, len(m.podSyncTriggered))
+
+	// the sync waits for the minimum interval since the last sync
+	lastSynced := time.Now()
+	assert.True(t, m.waitSyncCoalesced(stopCh, lastSynced))
+	assert.GreaterOrEqual(t, time.Since(lastSynced), 100*time.Millisecond)
+
+	// the continuous events are coalesced for no longer than the minimum interval
+	burstStopCh := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.triggerSyncPods("test-pod-uid", false, "test event %v", 4)
+			case <-burstStopCh:
+				return
+			}
+		}
+	}()
+	start := time.Now()
+	assert.True(t, m.waitSyncCoalesced(stopCh, time.Time{}))
+	assert.Less(t, time.Since(start), time.Second)
+	close(burstStopCh)
+
+	close(stopCh)
+	assert.False(t, m.waitSyncCoalesced(stopCh, time.Now()))
+}
+
 func Test_newKubeletStub(t *testing.T) {
 	testingNode := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statesinformer
+
+import (
+	"go.uber.org/atomic"
+)
+
+// midCPUSuppressed indicates whether the koord-mid pods are suppressed by the CPUSuppress strategy of the qosmanager.
+// While they are suppressed, the pod-level cfs quota of the koord-mid pods belongs to the CPUSuppress strategy, and the
+// runtime hooks reconcile it only when the suppression is recovered.
+var midCPUSuppressed = atomic.NewBool(false)
+
+// SetMidCPUSuppressed records whether the koord-mid pods are suppressed.
+func SetMidCPUSuppressed(suppressed bool) {
+	midCPUSuppressed.Store(suppressed)
+}
+
+// IsMidCPUSuppressed returns whether the koord-mid pods are suppressed.
+func IsMidCPUSuppressed() bool {
+	return midCPUSuppressed.Load()
+}
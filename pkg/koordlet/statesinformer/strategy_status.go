@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statesinformer
+
+import (
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+// DefaultStrategyStatusRecorder collects the enforcement states of the qos strategies, which are reported to the
+// NodeSLO status by the states informer.
+var DefaultStrategyStatusRecorder = NewStrategyStatusRecorder()
+
+type StrategyStatusRecorder struct {
+	lock       sync.RWMutex
+	conditions map[string]slov1alpha1.StrategyCondition
+}
+
+func NewStrategyStatusRecorder() *StrategyStatusRecorder {
+	return &StrategyStatusRecorder{
+		conditions: map[string]slov1alpha1.StrategyCondition{},
+	}
+}
+
+// SetCondition records the latest state of the strategy. The transition time is kept when the type is unchanged.
+func (r *StrategyStatusRecorder) SetCondition(strategy string, conditionType slov1alpha1.StrategyConditionType, message string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	condition := slov1alpha1.StrategyCondition{
+		Strategy:           strategy,
+		Type:               conditionType,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	if old, ok := r.conditions[strategy]; ok && old.Type == conditionType {
+		condition.LastTransitionTime = old.LastTransitionTime
+	}
+	r.conditions[strategy] = condition
+}
+
+// RemoveCondition removes the state of the strategy, e.g. when the strategy is disabled.
+func (r *StrategyStatusRecorder) RemoveCondition(strategy string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.conditions, strategy)
+}
+
+// Conditions returns the recorded states sorted by the strategy name.
+func (r *StrategyStatusRecorder) Conditions() []slov1alpha1.StrategyCondition {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if len(r.conditions) <= 0 {
+		return nil
+	}
+	conditions := make([]slov1alpha1.StrategyCondition, 0, len(r.conditions))
+	for _, condition := range r.conditions {
+		conditions = append(conditions, condition)
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		return conditions[i].Strategy < conditions[j].Strategy
+	})
+	return conditions
+}
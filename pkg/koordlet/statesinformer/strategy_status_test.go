@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statesinformer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestStrategyStatusRecorder(t *testing.T) {
+	r := NewStrategyStatusRecorder()
+	assert.Nil(t, r.Conditions())
+
+	r.SetCondition("ResctrlReconcile", slov1alpha1.StrategyConditionUnsupported, "cpu not support CAT/MBA")
+	r.SetCondition("CPUSuppress", slov1alpha1.StrategyConditionError, "node is invalid")
+	conditions := r.Conditions()
+	assert.Equal(t, 2, len(conditions))
+	assert.Equal(t, "CPUSuppress", conditions[0].Strategy)
+	assert.Equal(t, "ResctrlReconcile", conditions[1].Strategy)
+	assert.Equal(t, slov1alpha1.StrategyConditionUnsupported, conditions[1].Type)
+	assert.Equal(t, "cpu not support CAT/MBA", conditions[1].Message)
+
+	// keep the transition time when the type is unchanged
+	transitionTime := conditions[1].LastTransitionTime
+	r.SetCondition("ResctrlReconcile", slov1alpha1.StrategyConditionUnsupported, "cpu not support CAT")
+	conditions = r.Conditions()
+	assert.Equal(t, transitionTime, conditions[1].LastTransitionTime)
+	assert.Equal(t, "cpu not support CAT", conditions[1].Message)
+
+	r.SetCondition("CPUSuppress", slov1alpha1.StrategyConditionApplied, "")
+	conditions = r.Conditions()
+	assert.Equal(t, slov1alpha1.StrategyConditionApplied, conditions[0].Type)
+	assert.Equal(t, "", conditions[0].Message)
+
+	r.RemoveCondition("ResctrlReconcile")
+	conditions = r.Conditions()
+	assert.Equal(t, 1, len(conditions))
+	assert.Equal(t, "CPUSuppress", conditions[0].Strategy)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CoreSchedScopeType is the scope type of the core scheduling prctl operations.
+type CoreSchedScopeType uint
+
+const (
+	// CoreSchedScopeThread means the operation is applied to the thread.
+	CoreSchedScopeThread CoreSchedScopeType = 0
+	// CoreSchedScopeThreadGroup means the operation is applied to all threads of the thread group (process).
+	CoreSchedScopeThreadGroup CoreSchedScopeType = 1
+	// CoreSchedScopeProcessGroup means the operation is applied to all processes of the process group.
+	CoreSchedScopeProcessGroup CoreSchedScopeType = 2
+)
+
+// CoreSchedInterface manages the core scheduling cookies of the tasks. Tasks with different cookies never run on
+// the SMT siblings of a physical core simultaneously.
+// https://docs.kernel.org/admin-guide/hw-vuln/core-scheduling.html
+type CoreSchedInterface interface {
+	// Get gets the cookie of the task. A zero cookie means the task is not tagged.
+	Get(pidType CoreSchedScopeType, pid uint32) (uint64, error)
+	// Create creates a new unique cookie for the task.
+	Create(pidType CoreSchedScopeType, pid uint32) error
+	// Assign assigns the cookie of the task pidFrom to the tasks pidsTo.
+	// It returns the pids which are failed to assign.
+	Assign(pidFrom uint32, pidTypeTo CoreSchedScopeType, pidsTo ...uint32) ([]uint32, error)
+	// Clear clears the cookies of the tasks.
+	// It returns the pids which are failed to clear.
+	Clear(pidType CoreSchedScopeType, pids ...uint32) ([]uint32, error)
+}
+
+var _ CoreSchedInterface = &FakeCoreSched{}
+
+// FakeCoreSched is a fake implementation of the CoreSchedInterface for testing, which only supports the thread scope.
+type FakeCoreSched struct {
+	lock       sync.Mutex
+	nextCookie uint64
+	pidCookies map[uint32]uint64
+}
+
+func NewFakeCoreSched(pidCookies map[uint32]uint64) *FakeCoreSched {
+	f := &FakeCoreSched{
+		nextCookie: 1,
+		pidCookies: map[uint32]uint64{},
+	}
+	for pid, cookie := range pidCookies {
+		f.pidCookies[pid] = cookie
+		if cookie >= f.nextCookie {
+			f.nextCookie = cookie + 1
+		}
+	}
+	return f
+}
+
+func (f *FakeCoreSched) Get(pidType CoreSchedScopeType, pid uint32) (uint64, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	cookie, ok := f.pidCookies[pid]
+	if !ok {
+		return 0, fmt.Errorf("pid %d not found", pid)
+	}
+	return cookie, nil
+}
+
+func (f *FakeCoreSched) Create(pidType CoreSchedScopeType, pid uint32) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, ok := f.pidCookies[pid]; !ok {
+		return fmt.Errorf("pid %d not found", pid)
+	}
+	f.pidCookies[pid] = f.nextCookie
+	f.nextCookie++
+	return nil
+}
+
+func (f *FakeCoreSched) Assign(pidFrom uint32, pidTypeTo CoreSchedScopeType, pidsTo ...uint32) ([]uint32, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	cookie, ok := f.pidCookies[pidFrom]
+	if !ok {
+		return pidsTo, fmt.Errorf("pid %d not found", pidFrom)
+	}
+	return f.setCookie(cookie, pidsTo)
+}
+
+func (f *FakeCoreSched) Clear(pidType CoreSchedScopeType, pids ...uint32) ([]uint32, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.setCookie(0, pids)
+}
+
+func (f *FakeCoreSched) setCookie(cookie uint64, pids []uint32) ([]uint32, error) {
+	var failedPIDs []uint32
+	for _, pid := range pids {
+		if _, ok := f.pidCookies[pid]; !ok {
+			failedPIDs = append(failedPIDs, pid)
+			continue
+		}
+		f.pidCookies[pid] = cookie
+	}
+	if len(failedPIDs) > 0 {
+		return failedPIDs, fmt.Errorf("pids %v not found", failedPIDs)
+	}
+	return nil, nil
+}
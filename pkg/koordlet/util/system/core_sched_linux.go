@@ -0,0 +1,100 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+var _ CoreSchedInterface = &CoreSched{}
+
+type CoreSched struct{}
+
+func NewCoreSched() CoreSchedInterface {
+	return &CoreSched{}
+}
+
+func (s *CoreSched) Get(pidType CoreSchedScopeType, pid uint32) (uint64, error) {
+	var cookie uint64
+	_, _, errno := unix.Syscall6(unix.SYS_PRCTL, unix.PR_SCHED_CORE, unix.PR_SCHED_CORE_GET, uintptr(pid),
+		uintptr(pidType), uintptr(unsafe.Pointer(&cookie)), 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return cookie, nil
+}
+
+func (s *CoreSched) Create(pidType CoreSchedScopeType, pid uint32) error {
+	return unix.Prctl(unix.PR_SCHED_CORE, unix.PR_SCHED_CORE_CREATE, uintptr(pid), uintptr(pidType), 0)
+}
+
+func (s *CoreSched) Assign(pidFrom uint32, pidTypeTo CoreSchedScopeType, pidsTo ...uint32) ([]uint32, error) {
+	var failedPIDs []uint32
+	var err error
+	doInDedicatedThread(func() {
+		// pull the cookie of pidFrom to the current thread, then push it to the targets
+		if err = unix.Prctl(unix.PR_SCHED_CORE, unix.PR_SCHED_CORE_SHARE_FROM, uintptr(pidFrom),
+			uintptr(CoreSchedScopeThread), 0); err != nil {
+			failedPIDs, err = pidsTo, fmt.Errorf("failed to share cookie from pid %d, err: %w", pidFrom, err)
+			return
+		}
+		failedPIDs, err = shareCookieTo(pidTypeTo, pidsTo)
+	})
+	return failedPIDs, err
+}
+
+func (s *CoreSched) Clear(pidType CoreSchedScopeType, pids ...uint32) ([]uint32, error) {
+	var failedPIDs []uint32
+	var err error
+	doInDedicatedThread(func() {
+		// the cookie of the dedicated thread is zero, so pushing it clears the cookies of the targets
+		failedPIDs, err = shareCookieTo(pidType, pids)
+	})
+	return failedPIDs, err
+}
+
+func shareCookieTo(pidType CoreSchedScopeType, pids []uint32) ([]uint32, error) {
+	var failedPIDs []uint32
+	var errs []error
+	for _, pid := range pids {
+		if err := unix.Prctl(unix.PR_SCHED_CORE, unix.PR_SCHED_CORE_SHARE_TO, uintptr(pid), uintptr(pidType), 0); err != nil {
+			failedPIDs = append(failedPIDs, pid)
+			errs = append(errs, fmt.Errorf("pid %d, err: %w", pid, err))
+		}
+	}
+	return failedPIDs, utilerrors.NewAggregate(errs)
+}
+
+// doInDedicatedThread runs fn in a goroutine locked to its OS thread. The thread is never unlocked, so it is
+// terminated when the goroutine exits and the modified cookie of the thread cannot leak to other goroutines.
+func doInDedicatedThread(fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runtime.LockOSThread()
+		fn()
+	}()
+	<-done
+}
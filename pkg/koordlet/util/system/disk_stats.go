@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DiskStat is the IO statistics of a block device in /proc/diskstats.
+// https://www.kernel.org/doc/Documentation/ABI/testing/procfs-diskstats
+type DiskStat struct {
+	ReadsCompleted  uint64
+	SectorsRead     uint64
+	ReadTicks       uint64 // ms
+	WritesCompleted uint64
+	SectorsWritten  uint64
+	WriteTicks      uint64 // ms
+	IOTicks         uint64 // ms
+}
+
+// DiskIOLatencyAndUtil returns the average IO latency in microseconds and the utilization percentage of the device
+// in the duration between the two stats. The latency is zero if no IO is completed.
+func DiskIOLatencyAndUtil(last, cur *DiskStat, durationMilliSeconds float64) (float64, float64, error) {
+	if last == nil || cur == nil || durationMilliSeconds <= 0 {
+		return 0, 0, fmt.Errorf("invalid disk stats or duration")
+	}
+	if cur.ReadsCompleted < last.ReadsCompleted || cur.WritesCompleted < last.WritesCompleted ||
+		cur.ReadTicks < last.ReadTicks || cur.WriteTicks < last.WriteTicks || cur.IOTicks < last.IOTicks {
+		return 0, 0, fmt.Errorf("disk stats counters decreased")
+	}
+	ios := float64(cur.ReadsCompleted - last.ReadsCompleted + cur.WritesCompleted - last.WritesCompleted)
+	ticks := float64(cur.ReadTicks - last.ReadTicks + cur.WriteTicks - last.WriteTicks)
+	var latency float64
+	if ios > 0 {
+		latency = ticks * 1000 / ios
+	}
+	util := float64(cur.IOTicks-last.IOTicks) * 100 / durationMilliSeconds
+	if util > 100 {
+		util = 100
+	}
+	return latency, util, nil
+}
+
+func GetProcDiskStatsPath() string {
+	return GetProcFilePath(ProcDiskStatsName)
+}
+
+// ReadDiskStats reads /proc/diskstats and returns the stats of the block devices keyed by the device number.
+// eg.
+// $ cat /proc/diskstats
+// 253       0 vda 2326 0 136426 1015 1542 1093 43376 2158 0 2316 3174 0 0 0 0
+// 253      16 vdb 301 0 13512 121 24 9 264 27 0 145 149 0 0 0 0
+func ReadDiskStats() (map[string]*DiskStat, error) {
+	content, err := os.ReadFile(GetProcDiskStatsPath())
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiskStats(content)
+}
+
+func ParseDiskStats(content []byte) (map[string]*DiskStat, error) {
+	stats := map[string]*DiskStat{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		// major, minor, device name and at least 11 stats fields, skip the malformed line of a device rather than
+		// dropping the stats of all devices
+		if len(fields) < 14 {
+			continue
+		}
+		values := make([]uint64, 11)
+		for i := range values {
+			v, err := strconv.ParseUint(fields[i+3], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid diskstats line %q, err: %w", scanner.Text(), err)
+			}
+			values[i] = v
+		}
+		stats[fields[0]+":"+fields[1]] = &DiskStat{
+			ReadsCompleted:  values[0],
+			SectorsRead:     values[2],
+			ReadTicks:       values[3],
+			WritesCompleted: values[4],
+			SectorsWritten:  values[6],
+			WriteTicks:      values[7],
+			IOTicks:         values[9],
+		}
+	}
+	return stats, nil
+}
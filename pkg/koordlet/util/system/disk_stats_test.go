@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDiskStats(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := ReadDiskStats()
+	assert.Error(t, err)
+
+	helper.WriteProcSubFileContents(ProcDiskStatsName, `253       0 vda 2326 0 136426 1015 1542 1093 43376 2158 0 2316 3174 0 0 0 0
+ 253      16 vdb 301 0 13512 121 24 9 264 27 0 145 149
+`)
+	got, err := ReadDiskStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*DiskStat{
+		"253:0": {
+			ReadsCompleted:  2326,
+			SectorsRead:     136426,
+			ReadTicks:       1015,
+			WritesCompleted: 1542,
+			SectorsWritten:  43376,
+			WriteTicks:      2158,
+			IOTicks:         2316,
+		},
+		"253:16": {
+			ReadsCompleted:  301,
+			SectorsRead:     13512,
+			ReadTicks:       121,
+			WritesCompleted: 24,
+			SectorsWritten:  264,
+			WriteTicks:      27,
+			IOTicks:         145,
+		},
+	}, got)
+
+	helper.WriteProcSubFileContents(ProcDiskStatsName, `253 0 vda 1 2 3
+ 253      16 vdb 301 0 13512 121 24 9 264 27 0 145 149
+`)
+	got, err = ReadDiskStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*DiskStat{
+		"253:16": {
+			ReadsCompleted:  301,
+			SectorsRead:     13512,
+			ReadTicks:       121,
+			WritesCompleted: 24,
+			SectorsWritten:  264,
+			WriteTicks:      27,
+			IOTicks:         145,
+		},
+	}, got)
+
+	helper.WriteProcSubFileContents(ProcDiskStatsName, "253 0 vda 1 2 3 4 5 6 7 8 9 10 x\n")
+	_, err = ReadDiskStats()
+	assert.Error(t, err)
+}
+
+func TestDiskIOLatencyAndUtil(t *testing.T) {
+	last := &DiskStat{ReadsCompleted: 100, ReadTicks: 200, WritesCompleted: 100, WriteTicks: 300, IOTicks: 1000}
+	cur := &DiskStat{ReadsCompleted: 150, ReadTicks: 400, WritesCompleted: 150, WriteTicks: 600, IOTicks: 1600}
+	latency, util, err := DiskIOLatencyAndUtil(last, cur, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5000), latency)
+	assert.Equal(t, float64(60), util)
+
+	latency, util, err = DiskIOLatencyAndUtil(last, last, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), latency)
+	assert.Equal(t, float64(0), util)
+
+	_, _, err = DiskIOLatencyAndUtil(cur, last, 1000)
+	assert.Error(t, err)
+	_, _, err = DiskIOLatencyAndUtil(last, cur, 0)
+	assert.Error(t, err)
+}
@@ -35,11 +35,21 @@ const (
 	ResctrlDir string = "resctrl/"
 	RdtInfoDir string = "info"
 	L3CatDir   string = "L3"
+	L3MonDir   string = "L3_MON"
+
+	ResctrlMonDataDir   string = "mon_data"
+	ResctrlMonGroupsDir string = "mon_groups"
+	// ResctrlL3MonDataDirPrefix is the prefix of mon_data dirs for each l3 cache domain, e.g. mon_L3_00
+	ResctrlL3MonDataDirPrefix string = "mon_L3_"
 
 	ResctrlSchemataName string = "schemata"
 	ResctrlCbmMaskName  string = "cbm_mask"
 	ResctrlTasksName    string = "tasks"
 
+	ResctrlLLCOccupancyName  string = "llc_occupancy"
+	ResctrlMBMTotalBytesName string = "mbm_total_bytes"
+	ResctrlMBMLocalBytesName string = "mbm_local_bytes"
+
 	// L3SchemataPrefix is the prefix of l3 cat schemata
 	L3SchemataPrefix = "L3"
 	// MbSchemataPrefix is the prefix of mba schemata
@@ -409,6 +419,18 @@ func GetResctrlTasksFilePath(groupPath string) string {
 	return ResctrlTasks.Path(groupPath)
 }
 
+// @groupPath BE, BE/mon_groups/test-pod
+// @return /sys/fs/resctrl/BE/mon_data
+func GetResctrlMonDataDirPath(groupPath string) string {
+	return filepath.Join(Conf.SysFSRootDir, ResctrlDir, groupPath, ResctrlMonDataDir)
+}
+
+// @groupPath BE
+// @return /sys/fs/resctrl/BE/mon_groups
+func GetResctrlMonGroupsDirPath(groupPath string) string {
+	return filepath.Join(Conf.SysFSRootDir, ResctrlDir, groupPath, ResctrlMonGroupsDir)
+}
+
 func ReadResctrlSchemataRaw(schemataFile string, l3Num int) (*ResctrlSchemataRaw, error) {
 	content, err := os.ReadFile(schemataFile)
 	if err != nil {
@@ -500,6 +522,108 @@ func ReadResctrlTasksMap(groupPath string) (map[int32]struct{}, error) {
 	return tasksMap, nil
 }
 
+// ResctrlMonData is the monitoring data of a resctrl group, which sums up all the l3 cache domains.
+type ResctrlMonData struct {
+	// LLCOccupancy is the current l3 cache occupancy in bytes
+	LLCOccupancy uint64
+	// MBMTotalBytes is the accumulated total memory bandwidth in bytes
+	MBMTotalBytes uint64
+	// MBMLocalBytes is the accumulated local memory bandwidth in bytes
+	MBMLocalBytes uint64
+}
+
+// IsResctrlMonitorSupported checks if the resctrl l3 monitoring (CMT/MBM) is enabled.
+func IsResctrlMonitorSupported() bool {
+	_, err := os.Stat(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, L3MonDir))
+	return err == nil
+}
+
+// ReadResctrlMonData reads the monitoring data of the given resctrl ctrl group or mon group.
+// The event which is missing or unavailable on the domain (e.g. MBM is not supported) is counted as zero.
+// eg.
+// $ ls /sys/fs/resctrl/BE/mon_data/
+// mon_L3_00  mon_L3_01
+// $ cat /sys/fs/resctrl/BE/mon_data/mon_L3_00/llc_occupancy
+// 1015808
+func ReadResctrlMonData(groupPath string) (*ResctrlMonData, error) {
+	monDataDir := GetResctrlMonDataDirPath(groupPath)
+	entries, err := os.ReadDir(monDataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	monData := &ResctrlMonData{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), ResctrlL3MonDataDirPrefix) {
+			continue
+		}
+		domainDir := filepath.Join(monDataDir, entry.Name())
+		monData.LLCOccupancy += readResctrlMonEvent(filepath.Join(domainDir, ResctrlLLCOccupancyName))
+		monData.MBMTotalBytes += readResctrlMonEvent(filepath.Join(domainDir, ResctrlMBMTotalBytesName))
+		monData.MBMLocalBytes += readResctrlMonEvent(filepath.Join(domainDir, ResctrlMBMLocalBytesName))
+	}
+	return monData, nil
+}
+
+func readResctrlMonEvent(eventPath string) uint64 {
+	content, err := os.ReadFile(eventPath)
+	if err != nil {
+		klog.V(6).Infof("failed to read resctrl mon event %s, err: %s", eventPath, err)
+		return 0
+	}
+	// the content is "Unavailable" if the event is not ready
+	value, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		klog.V(6).Infof("failed to parse resctrl mon event %s, content %s, err: %s", eventPath, string(content), err)
+		return 0
+	}
+	return value
+}
+
+// ListResctrlMonGroups returns the names of the mon groups under the given ctrl group.
+func ListResctrlMonGroups(group string) ([]string, error) {
+	entries, err := os.ReadDir(GetResctrlMonGroupsDirPath(group))
+	if err != nil {
+		return nil, err
+	}
+	var monGroups []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			monGroups = append(monGroups, entry.Name())
+		}
+	}
+	return monGroups, nil
+}
+
+// InitResctrlMonGroupIfNotExist creates the mon group under the given ctrl group if it does not exist.
+// It returns whether the mon group is created.
+func InitResctrlMonGroupIfNotExist(group, monGroup string) (bool, error) {
+	path := filepath.Join(GetResctrlMonGroupsDirPath(group), monGroup)
+	_, err := os.Stat(path)
+	if err == nil {
+		return false, nil
+	} else if !os.IsNotExist(err) {
+		return false, fmt.Errorf("check dir %v for mon group %s but got unexpected err: %v", path, monGroup, err)
+	}
+	// NOTE: the mkdir fails with ENOSPC when the RMIDs are exhausted
+	err = os.Mkdir(path, 0755)
+	if err != nil {
+		return false, fmt.Errorf("create dir %v failed for mon group %s, err: %v", path, monGroup, err)
+	}
+	return true, nil
+}
+
+// RemoveResctrlMonGroup removes the mon group under the given ctrl group, and the RMID would be released.
+func RemoveResctrlMonGroup(group, monGroup string) error {
+	path := filepath.Join(GetResctrlMonGroupsDirPath(group), monGroup)
+	// the rmdir of resctrl fs is done by os.RemoveAll without removing the interface files
+	err := os.RemoveAll(path)
+	if err != nil {
+		return fmt.Errorf("remove dir %v failed for mon group %s, err: %v", path, monGroup, err)
+	}
+	return nil
+}
+
 // CheckAndTryEnableResctrlCat checks if resctrl and l3_cat are enabled; if not, try to enable the features by mount
 // resctrl subsystem; See MountResctrlSubsystem() for the detail.
 // It returns whether the resctrl cat is enabled, and the error if failed to enable or to check resctrl interfaces
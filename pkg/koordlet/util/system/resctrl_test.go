@@ -283,3 +283,58 @@ func Test_MountResctrlSubsystem(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestReadResctrlMonData(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := ReadResctrlMonData("BE")
+	assert.Error(t, err)
+	assert.False(t, IsResctrlMonitorSupported())
+
+	helper.MkDirAll(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, L3MonDir))
+	assert.True(t, IsResctrlMonitorSupported())
+
+	monDataDir := GetResctrlMonDataDirPath("BE")
+	helper.WriteFileContents(filepath.Join(monDataDir, "mon_L3_00", ResctrlLLCOccupancyName), "1048576\n")
+	helper.WriteFileContents(filepath.Join(monDataDir, "mon_L3_00", ResctrlMBMTotalBytesName), "4000\n")
+	helper.WriteFileContents(filepath.Join(monDataDir, "mon_L3_00", ResctrlMBMLocalBytesName), "3000\n")
+	helper.WriteFileContents(filepath.Join(monDataDir, "mon_L3_01", ResctrlLLCOccupancyName), "2097152\n")
+	helper.WriteFileContents(filepath.Join(monDataDir, "mon_L3_01", ResctrlMBMTotalBytesName), "Unavailable\n")
+	got, err := ReadResctrlMonData("BE")
+	assert.NoError(t, err)
+	assert.Equal(t, &ResctrlMonData{
+		LLCOccupancy:  3145728,
+		MBMTotalBytes: 4000,
+		MBMLocalBytes: 3000,
+	}, got)
+}
+
+func TestResctrlMonGroup(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := ListResctrlMonGroups("LS")
+	assert.Error(t, err)
+
+	helper.MkDirAll(GetResctrlMonGroupsDirPath("LS"))
+	got, err := ListResctrlMonGroups("LS")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	created, err := InitResctrlMonGroupIfNotExist("LS", "test-pod")
+	assert.NoError(t, err)
+	assert.True(t, created)
+	created, err = InitResctrlMonGroupIfNotExist("LS", "test-pod")
+	assert.NoError(t, err)
+	assert.False(t, created)
+	got, err = ListResctrlMonGroups("LS")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-pod"}, got)
+
+	assert.NoError(t, RemoveResctrlMonGroup("LS", "test-pod"))
+	assert.NoError(t, RemoveResctrlMonGroup("LS", "test-pod"))
+	got, err = ListResctrlMonGroups("LS")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
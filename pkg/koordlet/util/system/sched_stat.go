@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TaskSchedStat is the scheduler statistics of a task in /proc/<pid>/schedstat.
+// https://docs.kernel.org/scheduler/sched-stats.html
+type TaskSchedStat struct {
+	// time spent on the cpu, unit: nanoseconds
+	RunTime uint64
+	// time spent waiting on a runqueue, unit: nanoseconds
+	WaitTime uint64
+	// number of timeslices run on this cpu
+	Timeslices uint64
+}
+
+func GetProcPIDSchedStatPath(pid int32) string {
+	return GetProcFilePath(filepath.Join(strconv.FormatInt(int64(pid), 10), ProcSchedStatName))
+}
+
+// ReadTaskSchedStat reads /proc/<pid>/schedstat of the task.
+// eg.
+// $ cat /proc/1/schedstat
+// 2237386929 130545394 9470
+func ReadTaskSchedStat(pid int32) (*TaskSchedStat, error) {
+	content, err := os.ReadFile(GetProcPIDSchedStatPath(pid))
+	if err != nil {
+		return nil, err
+	}
+	return ParseTaskSchedStat(string(content))
+}
+
+func ParseTaskSchedStat(content string) (*TaskSchedStat, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid schedstat content %q", content)
+	}
+	var values [3]uint64
+	for i := range values {
+		v, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schedstat field %q, err: %w", fields[i], err)
+		}
+		values[i] = v
+	}
+	return &TaskSchedStat{
+		RunTime:    values[0],
+		WaitTime:   values[1],
+		Timeslices: values[2],
+	}, nil
+}
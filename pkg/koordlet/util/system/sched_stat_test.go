@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTaskSchedStat(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := ReadTaskSchedStat(1)
+	assert.Error(t, err)
+
+	helper.WriteProcSubFileContents("1/schedstat", "2237386929 130545394 9470\n")
+	got, err := ReadTaskSchedStat(1)
+	assert.NoError(t, err)
+	assert.Equal(t, &TaskSchedStat{
+		RunTime:    2237386929,
+		WaitTime:   130545394,
+		Timeslices: 9470,
+	}, got)
+
+	helper.WriteProcSubFileContents("2/schedstat", "2237386929 130545394\n")
+	_, err = ReadTaskSchedStat(2)
+	assert.Error(t, err)
+
+	helper.WriteProcSubFileContents("3/schedstat", "2237386929 abc 9470\n")
+	_, err = ReadTaskSchedStat(3)
+	assert.Error(t, err)
+}
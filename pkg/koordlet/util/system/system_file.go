@@ -36,6 +36,8 @@ const (
 	SysctlSubDir          = "sys"
 	ProcCPUInfoName       = "cpuinfo"
 	KernelCmdlineFileName = "cmdline"
+	ProcDiskStatsName     = "diskstats"
+	ProcSchedStatName     = "schedstat"
 
 	KernelSchedGroupIdentityEnable = "kernel/sched_group_identity_enabled"
 
@@ -92,6 +92,14 @@ func (r *BlkIORangeValidator) Validate(value string) (bool, string) {
 		if len(rst) == 5 {
 			newValues = append(newValues, []string{rst[3][5:], rst[4][5:]}...)
 		}
+	case BlkioIOMaxName:
+		// 253:16 rbps=2097152 wbps=max riops=max wiops=max
+		rst := strings.Split(value, " ")
+		for _, kv := range rst[1:] {
+			if i := strings.Index(kv, "="); i > 0 {
+				newValues = append(newValues, kv[i+1:])
+			}
+		}
 	default:
 		return false, "unknown blkio resource name"
 	}
@@ -114,3 +122,18 @@ func (r *BlkIORangeValidator) Validate(value string) (bool, string) {
 
 	return true, ""
 }
+
+// IsCoreSchedSupported checks if the kernel supports the core scheduling (CONFIG_SCHED_CORE) and the SMT is active.
+// The prctl PR_SCHED_CORE returns EINVAL if the kernel is not built with the core scheduling, and returns ENODEV if
+// the SMT is not present or disabled.
+func IsCoreSchedSupported() (bool, string) {
+	return isCoreSchedSupported(NewCoreSched())
+}
+
+func isCoreSchedSupported(cse CoreSchedInterface) (bool, string) {
+	// get the cookie of the current thread
+	if _, err := cse.Get(CoreSchedScopeThread, 0); err != nil {
+		return false, fmt.Sprintf("core sched is unsupported, err: %v", err)
+	}
+	return true, ""
+}
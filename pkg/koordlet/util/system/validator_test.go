@@ -61,6 +61,18 @@ func Test_RangeValidate(t *testing.T) {
 			value:     "20",
 			expect:    true,
 		},
+		{
+			name:      "test_validate_valid_io_max",
+			validator: BlkioIOMaxValidator,
+			value:     "253:16 rbps=2097152 wbps=max riops=max wiops=max",
+			expect:    true,
+		},
+		{
+			name:      "test_validate_invalid_io_max",
+			validator: BlkioIOMaxValidator,
+			value:     "253:16 rbps=-1 wbps=max",
+			expect:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -70,3 +82,30 @@ func Test_RangeValidate(t *testing.T) {
 		})
 	}
 }
+
+func Test_isCoreSchedSupported(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  CoreSchedInterface
+		want bool
+	}{
+		{
+			name: "core sched unsupported",
+			arg:  NewFakeCoreSched(nil),
+			want: false,
+		},
+		{
+			name: "core sched supported",
+			arg: NewFakeCoreSched(map[uint32]uint64{
+				0: 0,
+			}),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, msg := isCoreSchedSupported(tt.arg)
+			assert.Equal(t, tt.want, got, msg)
+		})
+	}
+}
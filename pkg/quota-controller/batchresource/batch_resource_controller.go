@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchresource
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	schedv1alpha1 "sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	utilquota "github.com/koordinator-sh/koordinator/pkg/util/quota"
+)
+
+const Name = "batchresourcequota"
+
+const (
+	// elasticQuotaSyncInterval is the interval to report the batch resources used of the ElasticQuotas,
+	// since the changes of the Pods are not watched for ElasticQuotas.
+	elasticQuotaSyncInterval = 30 * time.Second
+)
+
+// NamespaceReconciler reports the batch resources used of the Namespaces with batch resource quota.
+type NamespaceReconciler struct {
+	client.Client
+}
+
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.sigs.k8s.io,resources=elasticquotas,verbs=get;list;watch;update;patch
+
+func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	quota, err := extension.GetBatchResourceQuota(namespace.Annotations)
+	if err != nil {
+		klog.V(4).Infof("failed to parse batch resource quota of namespace %s, err: %v", namespace.Name, err)
+		return ctrl.Result{}, nil
+	}
+	var used corev1.ResourceList
+	if quota != nil {
+		used, err = utilquota.GetNamespaceBatchUsed(ctx, r.Client, namespace.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, updateBatchResourceUsed(ctx, r.Client, namespace, quota, used)
+}
+
+// ElasticQuotaReconciler reports the batch resources used of the ElasticQuotas with batch resource quota.
+type ElasticQuotaReconciler struct {
+	client.Client
+}
+
+func (r *ElasticQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	quota := &schedv1alpha1.ElasticQuota{}
+	if err := r.Client.Get(ctx, req.NamespacedName, quota); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	batchQuota, err := extension.GetBatchResourceQuota(quota.Annotations)
+	if err != nil {
+		klog.V(4).Infof("failed to parse batch resource quota of elasticQuota %s, err: %v", req.NamespacedName, err)
+		return ctrl.Result{}, nil
+	}
+	if batchQuota == nil {
+		return ctrl.Result{}, updateBatchResourceUsed(ctx, r.Client, quota, nil, nil)
+	}
+	used, err := utilquota.GetElasticQuotaBatchUsed(ctx, r.Client, quota)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err = updateBatchResourceUsed(ctx, r.Client, quota, batchQuota, used); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: elasticQuotaSyncInterval}, nil
+}
+
+// updateBatchResourceUsed patches the batch resources used annotation of the object,
+// and removes the annotation if the object has no batch resource quota.
+func updateBatchResourceUsed(ctx context.Context, c client.Client, obj client.Object, quota, used corev1.ResourceList) error {
+	annotations := obj.GetAnnotations()
+	oldData, exist := annotations[extension.AnnotationBatchResourceUsed]
+	var newData string
+	if quota != nil {
+		reported := corev1.ResourceList{}
+		for resourceName := range quota {
+			if quantity, ok := used[resourceName]; ok {
+				reported[resourceName] = quantity
+			} else {
+				reported[resourceName] = *resource.NewQuantity(0, resource.DecimalSI)
+			}
+		}
+		data, err := json.Marshal(reported)
+		if err != nil {
+			return err
+		}
+		newData = string(data)
+	}
+	if (quota == nil && !exist) || (quota != nil && oldData == newData) {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	newAnnotations := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		newAnnotations[k] = v
+	}
+	if quota == nil {
+		delete(newAnnotations, extension.AnnotationBatchResourceUsed)
+	} else {
+		newAnnotations[extension.AnnotationBatchResourceUsed] = newData
+	}
+	obj.SetAnnotations(newAnnotations)
+	if err := c.Patch(ctx, obj, patch); err != nil {
+		klog.Warningf("failed to update batch resource used of %s, err: %v", obj.GetName(), err)
+		return err
+	}
+	klog.V(5).Infof("update batch resource used of %s to %s", obj.GetName(), newData)
+	return nil
+}
+
+func hasBatchResourceAnnotations(obj client.Object) bool {
+	annotations := obj.GetAnnotations()
+	_, hasQuota := annotations[extension.AnnotationBatchResourceQuota]
+	_, hasUsed := annotations[extension.AnnotationBatchResourceUsed]
+	return hasQuota || hasUsed
+}
+
+func Add(mgr ctrl.Manager) error {
+	namespaceReconciler := &NamespaceReconciler{
+		Client: mgr.GetClient(),
+	}
+	if err := namespaceReconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+	quotaReconciler := &ElasticQuotaReconciler{
+		Client: mgr.GetClient(),
+	}
+	return quotaReconciler.SetupWithManager(mgr)
+}
+
+func (r *NamespaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Namespace{}, builder.WithPredicates(predicate.NewPredicateFuncs(hasBatchResourceAnnotations))).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: obj.GetNamespace()}}}
+		})).
+		Named(Name + "-namespace").
+		Complete(r)
+}
+
+func (r *ElasticQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&schedv1alpha1.ElasticQuota{}, builder.WithPredicates(predicate.NewPredicateFuncs(hasBatchResourceAnnotations))).
+		Named(Name + "-elasticquota").
+		Complete(r)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	schedv1alpha1 "sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func newBatchPod(namespace, name, quotaName string, batchCPU int64) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							extension.BatchCPU: *resource.NewQuantity(batchCPU, resource.DecimalSI),
+						},
+					},
+				},
+			},
+		},
+	}
+	if quotaName != "" {
+		pod.Labels[extension.LabelQuotaName] = quotaName
+	}
+	return pod
+}
+
+func newTestClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = schedv1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestNamespaceReconciler(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+			Annotations: map[string]string{
+				extension.AnnotationBatchResourceQuota: `{"kubernetes.io/batch-cpu":"10k","kubernetes.io/batch-memory":"10Gi"}`,
+			},
+		},
+	}
+	c := newTestClient(namespace, newBatchPod("default", "pod-1", "", 2000), newBatchPod("default", "pod-2", "", 3000),
+		newBatchPod("other", "pod-3", "", 3000))
+	r := &NamespaceReconciler{Client: c}
+
+	_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "default"}})
+	assert.NoError(t, err)
+	got := &corev1.Namespace{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "default"}, got))
+	assert.Equal(t, `{"kubernetes.io/batch-cpu":"5k","kubernetes.io/batch-memory":"0"}`, got.Annotations[extension.AnnotationBatchResourceUsed])
+
+	// remove the used annotation when the quota is removed
+	delete(got.Annotations, extension.AnnotationBatchResourceQuota)
+	assert.NoError(t, c.Update(context.TODO(), got))
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "default"}})
+	assert.NoError(t, err)
+	got = &corev1.Namespace{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "default"}, got))
+	_, exist := got.Annotations[extension.AnnotationBatchResourceUsed]
+	assert.False(t, exist)
+
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "not-exist"}})
+	assert.NoError(t, err)
+}
+
+func TestElasticQuotaReconciler(t *testing.T) {
+	quota := &schedv1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "team-a",
+			Name:      "team-a",
+			Annotations: map[string]string{
+				extension.AnnotationBatchResourceQuota: `{"kubernetes.io/batch-cpu":"10k"}`,
+				extension.AnnotationQuotaNamespaces:    `["team-a-dev"]`,
+			},
+		},
+	}
+	c := newTestClient(quota,
+		newBatchPod("other", "labeled-pod", "team-a", 1000),
+		newBatchPod("team-a", "unlabeled-pod", "", 2000),
+		newBatchPod("team-a-dev", "unlabeled-dev-pod", "", 3000),
+		newBatchPod("team-a-dev", "other-quota-pod", "team-b", 4000))
+	r := &ElasticQuotaReconciler{Client: c}
+
+	result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "team-a"}})
+	assert.NoError(t, err)
+	assert.Equal(t, elasticQuotaSyncInterval, result.RequeueAfter)
+	got := &schedv1alpha1.ElasticQuota{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "team-a"}, got))
+	assert.Equal(t, `{"kubernetes.io/batch-cpu":"6k"}`, got.Annotations[extension.AnnotationBatchResourceUsed])
+}
@@ -68,6 +68,8 @@ var (
 	defaultMonitorAllQuotas       = pointer.Bool(false)
 	defaultEnableCheckParentQuota = pointer.Bool(false)
 
+	defaultEnableL3CacheAwareCPUAllocation = pointer.Bool(false)
+
 	defaultTimeout           = 600 * time.Second
 	defaultControllerWorkers = 1
 )
@@ -133,6 +135,9 @@ func SetDefaults_NodeNUMAResourceArgs(obj *NodeNUMAResourceArgs) {
 			},
 		}
 	}
+	if obj.EnableL3CacheAwareCPUAllocation == nil {
+		obj.EnableL3CacheAwareCPUAllocation = defaultEnableL3CacheAwareCPUAllocation
+	}
 }
 
 func SetDefaults_ReservationArgs(obj *ReservationArgs) {
@@ -106,6 +106,9 @@ type NodeNUMAResourceArgs struct {
 	ScoringStrategy *ScoringStrategy `json:"scoringStrategy,omitempty"`
 	// NUMAScoringStrategy is used to configure the scoring strategy of the NUMANode-level
 	NUMAScoringStrategy *ScoringStrategy `json:"numaScoringStrategy,omitempty"`
+	// EnableL3CacheAwareCPUAllocation indicates whether to pack the FullPCPUs within one L3 Cache (e.g. the CCD of AMD EPYC)
+	// before spilling across the L3 Caches of the NUMA Node.
+	EnableL3CacheAwareCPUAllocation *bool `json:"enableL3CacheAwareCPUAllocation,omitempty"`
 }
 
 // CPUBindPolicy defines the CPU binding policy
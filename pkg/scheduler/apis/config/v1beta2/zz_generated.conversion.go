@@ -299,6 +299,7 @@ func autoConvert_v1beta2_NodeNUMAResourceArgs_To_config_NodeNUMAResourceArgs(in
 	}
 	out.ScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
 	out.NUMAScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.NUMAScoringStrategy))
+	out.EnableL3CacheAwareCPUAllocation = (*bool)(unsafe.Pointer(in.EnableL3CacheAwareCPUAllocation))
 	return nil
 }
 
@@ -313,6 +314,7 @@ func autoConvert_config_NodeNUMAResourceArgs_To_v1beta2_NodeNUMAResourceArgs(in
 	}
 	out.ScoringStrategy = (*ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
 	out.NUMAScoringStrategy = (*ScoringStrategy)(unsafe.Pointer(in.NUMAScoringStrategy))
+	out.EnableL3CacheAwareCPUAllocation = (*bool)(unsafe.Pointer(in.EnableL3CacheAwareCPUAllocation))
 	return nil
 }
 
@@ -259,6 +259,11 @@ func (in *NodeNUMAResourceArgs) DeepCopyInto(out *NodeNUMAResourceArgs) {
 		*out = new(ScoringStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EnableL3CacheAwareCPUAllocation != nil {
+		in, out := &in.EnableL3CacheAwareCPUAllocation, &out.EnableL3CacheAwareCPUAllocation
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -28,6 +28,7 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	koordinatorclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
 	"github.com/koordinator-sh/koordinator/pkg/features"
@@ -57,6 +58,7 @@ type frameworkExtenderImpl struct {
 	reservationScorePlugins   []ReservationScorePlugin
 	reservationPreBindPlugins []ReservationPreBindPlugin
 	reservationRestorePlugins []ReservationRestorePlugin
+	reservationResizePlugins  []ReservationResizePlugin
 
 	resizePodPlugins         []ResizePodPlugin
 	preBindExtensionsPlugins map[string]PreBindExtensions
@@ -121,6 +123,9 @@ func (ext *frameworkExtenderImpl) updatePlugins(pl framework.Plugin) {
 	if r, ok := pl.(ReservationRestorePlugin); ok {
 		ext.reservationRestorePlugins = append(ext.reservationRestorePlugins, r)
 	}
+	if r, ok := pl.(ReservationResizePlugin); ok {
+		ext.reservationResizePlugins = append(ext.reservationResizePlugins, r)
+	}
 	if r, ok := pl.(ResizePodPlugin); ok {
 		ext.resizePodPlugins = append(ext.resizePodPlugins, r)
 	}
@@ -334,6 +339,18 @@ func (ext *frameworkExtenderImpl) RunReservationExtensionFinalRestoreReservation
 	return nil
 }
 
+// RunReservationResizePlugins resizes the resources held by the Available Reservation in place.
+func (ext *frameworkExtenderImpl) RunReservationResizePlugins(ctx context.Context, cycleState *framework.CycleState, reservation *schedulingv1alpha1.Reservation, reservationInfo *ReservationInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	for _, pl := range ext.reservationResizePlugins {
+		status := pl.ResizeReservation(ctx, cycleState, reservation, reservationInfo, nodeInfo)
+		if !status.IsSuccess() {
+			klog.ErrorS(status.AsError(), "Failed running ResizeReservation on plugin", "plugin", pl.Name(), "reservation", klog.KObj(reservation))
+			return status
+		}
+	}
+	return nil
+}
+
 // RunReservationFilterPlugins determines whether the Reservation can participate in the Reserve
 func (ext *frameworkExtenderImpl) RunReservationFilterPlugins(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, reservationInfo *ReservationInfo, nodeName string) *framework.Status {
 	for _, pl := range ext.reservationFilterPlugins {
@@ -59,6 +59,7 @@ type FrameworkExtender interface {
 	RunReservationExtensionPreRestoreReservation(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) *framework.Status
 	RunReservationExtensionRestoreReservation(ctx context.Context, cycleState *framework.CycleState, podToSchedule *corev1.Pod, matched []*ReservationInfo, unmatched []*ReservationInfo, nodeInfo *framework.NodeInfo) (PluginToReservationRestoreStates, *framework.Status)
 	RunReservationExtensionFinalRestoreReservation(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, states PluginToNodeReservationRestoreStates) *framework.Status
+	RunReservationResizePlugins(ctx context.Context, cycleState *framework.CycleState, reservation *schedulingv1alpha1.Reservation, reservationInfo *ReservationInfo, nodeInfo *framework.NodeInfo) *framework.Status
 
 	RunReservationFilterPlugins(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, reservationInfo *ReservationInfo, nodeName string) *framework.Status
 	RunReservationScorePlugins(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, reservationInfos []*ReservationInfo, nodeName string) (PluginToReservationScores, *framework.Status)
@@ -114,6 +115,15 @@ type ReservationRestorePlugin interface {
 	FinalRestoreReservation(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, states NodeReservationRestoreStates) *framework.Status
 }
 
+// ReservationResizePlugin is used to resize the fine-grained resources held by an Available Reservation
+// in place, such as CPU Cores, GPU Devices, etc. The reservation passed in has the desired allocatable set,
+// and the plugin records the adjusted allocation in the reservation object, e.g. the annotations.
+// After the reservation is updated, the ReservationRestorePlugin restores the resized resources.
+type ReservationResizePlugin interface {
+	framework.Plugin
+	ResizeReservation(ctx context.Context, cycleState *framework.CycleState, reservation *schedulingv1alpha1.Reservation, reservationInfo *ReservationInfo, nodeInfo *framework.NodeInfo) *framework.Status
+}
+
 // ReservationFilterPlugin is an interface for Filter Reservation plugins.
 // These plugins will be called during the Reserve phase to determine whether the Reservation can participate in the Reserve
 type ReservationFilterPlugin interface {
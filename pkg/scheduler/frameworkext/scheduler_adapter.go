@@ -17,11 +17,13 @@ limitations under the License.
 package frameworkext
 
 import (
+	"fmt"
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/kubernetes/pkg/scheduler"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 )
@@ -57,6 +59,9 @@ type SchedulerCache interface {
 	GetPod(pod *corev1.Pod) (*corev1.Pod, error)
 	ForgetPod(pod *corev1.Pod) error
 	InvalidNodeInfo(nodeName string) error
+	// GetNodeInfo returns a copy of the NodeInfo in the cache, which includes the assumed pods
+	// that may not be in the snapshot of the current scheduling cycle yet.
+	GetNodeInfo(nodeName string) (*framework.NodeInfo, error)
 }
 
 type PreEnqueueCheck func(pod *corev1.Pod) bool
@@ -75,11 +80,12 @@ type SchedulingQueue interface {
 var _ Scheduler = &SchedulerAdapter{}
 
 type SchedulerAdapter struct {
-	Scheduler *scheduler.Scheduler
+	Scheduler  *scheduler.Scheduler
+	NodeLister corelisters.NodeLister
 }
 
 func (s *SchedulerAdapter) GetCache() SchedulerCache {
-	return &cacheAdapter{scheduler: s.Scheduler}
+	return &cacheAdapter{scheduler: s.Scheduler, nodeLister: s.NodeLister}
 }
 
 func (s *SchedulerAdapter) GetSchedulingQueue() SchedulingQueue {
@@ -98,7 +104,8 @@ func (s *SchedulerAdapter) MoveAllToActiveOrBackoffQueue(event framework.Cluster
 var _ SchedulerCache = &cacheAdapter{}
 
 type cacheAdapter struct {
-	scheduler *scheduler.Scheduler
+	scheduler  *scheduler.Scheduler
+	nodeLister corelisters.NodeLister
 }
 
 func (c *cacheAdapter) AddPod(pod *corev1.Pod) error {
@@ -140,6 +147,18 @@ func (c *cacheAdapter) InvalidNodeInfo(nodeName string) error {
 	return c.scheduler.Cache.RemovePod(pod)
 }
 
+// GetNodeInfo returns a copy of the NodeInfo of the node in the cache.
+// The scheduler cache has no accessor of a single node and the Dump clones all the nodes, so the node from the lister
+// is set again into the cache to get the copy of only this node, which is what the node update event of the informer
+// does.
+func (c *cacheAdapter) GetNodeInfo(nodeName string) (*framework.NodeInfo, error) {
+	node, err := c.nodeLister.Get(nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("node %s not found, err: %w", nodeName, err)
+	}
+	return c.scheduler.Cache.UpdateNode(node, node), nil
+}
+
 var _ SchedulingQueue = &queueAdapter{}
 
 type queueAdapter struct {
@@ -283,6 +302,16 @@ func (f *FakeScheduler) InvalidNodeInfo(nodeName string) error {
 	return nodeInfo.RemovePod(pod)
 }
 
+func (f *FakeScheduler) GetNodeInfo(nodeName string) (*framework.NodeInfo, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	nodeInfo := f.NodeInfos[nodeName]
+	if nodeInfo == nil {
+		return nil, fmt.Errorf("node %s not found in cache", nodeName)
+	}
+	return nodeInfo.Clone(), nil
+}
+
 func (f *FakeQueue) Add(pod *corev1.Pod) error {
 	key, _ := framework.GetPodKey(pod)
 	f.Pods[key] = pod
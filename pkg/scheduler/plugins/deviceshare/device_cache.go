@@ -35,6 +35,7 @@ import (
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
 )
 
 const (
@@ -47,6 +48,8 @@ type nodeDevice struct {
 	deviceFree  map[schedulingv1alpha1.DeviceType]deviceResources
 	deviceUsed  map[schedulingv1alpha1.DeviceType]deviceResources
 	allocateSet map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources
+	// numaNodes records the NUMA node of each device minor, devices without topology are absent
+	numaNodes map[schedulingv1alpha1.DeviceType]map[int]int
 }
 
 func newNodeDevice() *nodeDevice {
@@ -55,6 +58,7 @@ func newNodeDevice() *nodeDevice {
 		deviceFree:  make(map[schedulingv1alpha1.DeviceType]deviceResources),
 		deviceUsed:  make(map[schedulingv1alpha1.DeviceType]deviceResources),
 		allocateSet: make(map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources),
+		numaNodes:   make(map[schedulingv1alpha1.DeviceType]map[int]int),
 	}
 }
 
@@ -148,6 +152,8 @@ func (n *nodeDevice) getUsed(namespace, name string) map[schedulingv1alpha1.Devi
 
 func (n *nodeDevice) replaceWith(freeDevices map[schedulingv1alpha1.DeviceType]deviceResources) *nodeDevice {
 	nn := newNodeDevice()
+	// numaNodes is immutable once built, so it can be shared
+	nn.numaNodes = n.numaNodes
 	usedDevices := map[schedulingv1alpha1.DeviceType]deviceResources{}
 	for deviceType, total := range n.deviceTotal {
 		resources, ok := freeDevices[deviceType]
@@ -182,6 +188,47 @@ func (n *nodeDevice) replaceWith(freeDevices map[schedulingv1alpha1.DeviceType]d
 	return nn
 }
 
+// getNUMANodes returns the sorted NUMA nodes which the devices of the type belong to.
+func (n *nodeDevice) getNUMANodes(deviceType schedulingv1alpha1.DeviceType) []int {
+	numaNodes := sets.NewInt()
+	for _, numaNode := range n.numaNodes[deviceType] {
+		numaNodes.Insert(numaNode)
+	}
+	return numaNodes.List()
+}
+
+// getDeviceMinorsByNUMANodes returns the minors of devices of the type which belong to the given NUMA nodes.
+func (n *nodeDevice) getDeviceMinorsByNUMANodes(deviceType schedulingv1alpha1.DeviceType, numaNodes []int) sets.Int {
+	nodes := sets.NewInt(numaNodes...)
+	minors := sets.NewInt()
+	for minor, numaNode := range n.numaNodes[deviceType] {
+		if nodes.Has(numaNode) {
+			minors.Insert(minor)
+		}
+	}
+	return minors
+}
+
+// getRequiredDevicesByNUMAAffinity returns the required device minors of each requested device type to align with the
+// NUMA affinity. It returns nil if the affinity is not specified.
+func (n *nodeDevice) getRequiredDevicesByNUMAAffinity(podRequest corev1.ResourceList, affinity topologymanager.NUMATopologyHint) (map[schedulingv1alpha1.DeviceType]sets.Int, error) {
+	if affinity.NUMANodeAffinity == nil || affinity.NUMANodeAffinity.IsEmpty() {
+		return nil, nil
+	}
+	required := map[schedulingv1alpha1.DeviceType]sets.Int{}
+	for deviceType, supportedResourceNames := range DeviceResourceNames {
+		if quotav1.IsZero(quotav1.Mask(podRequest, supportedResourceNames)) || len(n.numaNodes[deviceType]) == 0 {
+			continue
+		}
+		minors := n.getDeviceMinorsByNUMANodes(deviceType, affinity.NUMANodeAffinity.GetBits())
+		if minors.Len() == 0 {
+			return nil, fmt.Errorf("node does not have %v in NUMA nodes %v", deviceType, affinity.NUMANodeAffinity.GetBits())
+		}
+		required[deviceType] = minors
+	}
+	return required, nil
+}
+
 func (n *nodeDevice) resetDeviceFree(deviceType schedulingv1alpha1.DeviceType) {
 	if n.deviceFree[deviceType] == nil {
 		n.deviceFree[deviceType] = make(deviceResources)
@@ -541,10 +588,12 @@ func (n *nodeDeviceCache) updateNodeDevice(nodeName string, device *schedulingv1
 	}
 
 	nodeDeviceResource := buildDeviceResources(device)
+	numaNodes := buildDeviceNUMANodes(device)
 	info := n.getNodeDevice(nodeName, true)
 	info.lock.Lock()
 	defer info.lock.Unlock()
 	info.resetDeviceTotal(nodeDeviceResource)
+	info.numaNodes = numaNodes
 }
 
 func buildDeviceResources(device *schedulingv1alpha1.Device) map[schedulingv1alpha1.DeviceType]deviceResources {
@@ -567,6 +616,20 @@ func buildDeviceResources(device *schedulingv1alpha1.Device) map[schedulingv1alp
 	return nodeDeviceResource
 }
 
+func buildDeviceNUMANodes(device *schedulingv1alpha1.Device) map[schedulingv1alpha1.DeviceType]map[int]int {
+	numaNodes := map[schedulingv1alpha1.DeviceType]map[int]int{}
+	for _, deviceInfo := range device.Spec.Devices {
+		if deviceInfo.Minor == nil || deviceInfo.Topology == nil || deviceInfo.Topology.NodeID < 0 {
+			continue
+		}
+		if numaNodes[deviceInfo.Type] == nil {
+			numaNodes[deviceInfo.Type] = map[int]int{}
+		}
+		numaNodes[deviceInfo.Type][int(*deviceInfo.Minor)] = int(deviceInfo.Topology.NodeID)
+	}
+	return numaNodes
+}
+
 func (n *nodeDeviceCache) getNodeDeviceSummary(nodeName string) (*NodeDeviceSummary, bool) {
 	n.lock.Lock()
 	defer n.lock.Unlock()
@@ -51,6 +51,7 @@ func Test_newNodeDevice(t *testing.T) {
 		deviceFree:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 		deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 		allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+		numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 	}
 	assert.Equal(t, expectNodeDevice, newNodeDevice())
 }
@@ -70,6 +70,7 @@ func Test_nodeDeviceCache_onDeviceAdd(t *testing.T) {
 						},
 						deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 						allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+						numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 					},
 				},
 			},
@@ -101,6 +102,7 @@ func Test_nodeDeviceCache_onDeviceAdd(t *testing.T) {
 						},
 						deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 						allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+						numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 					},
 				},
 			},
@@ -136,6 +138,7 @@ func Test_nodeDeviceCache_onDeviceAdd(t *testing.T) {
 					},
 					deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 					allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+					numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 				},
 			},
 		},
@@ -208,6 +211,7 @@ func Test_nodeDeviceCache_onDeviceUpdate(t *testing.T) {
 					},
 					deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 					allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+					numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 				},
 			},
 		},
@@ -235,6 +239,7 @@ func Test_nodeDeviceCache_onDeviceUpdate(t *testing.T) {
 					},
 					deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 					allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+					numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 				},
 			},
 		},
@@ -301,6 +306,7 @@ func Test_nodeDeviceCache_onDeviceUpdate(t *testing.T) {
 						},
 						deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 						allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+						numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 					},
 				},
 			},
@@ -328,6 +334,7 @@ func Test_nodeDeviceCache_onDeviceUpdate(t *testing.T) {
 					},
 					deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 					allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+					numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 				},
 			},
 		},
@@ -391,6 +398,7 @@ func Test_nodeDeviceCache_onDeviceDelete(t *testing.T) {
 					},
 					deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 					allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+					numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 				},
 			},
 		},
@@ -487,6 +495,7 @@ func generateFakeNodeDeviceInfos() map[string]*nodeDevice {
 			},
 			deviceUsed:  map[schedulingv1alpha1.DeviceType]deviceResources{},
 			allocateSet: map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources{},
+			numaNodes:   map[schedulingv1alpha1.DeviceType]map[int]int{},
 		},
 	}
 }
@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	k8sfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/kubernetes/pkg/api/v1/resource"
@@ -35,6 +36,7 @@ import (
 	schedulerconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
 )
 
@@ -68,6 +70,7 @@ var (
 	_ frameworkext.ReservationScorePlugin     = &Plugin{}
 	_ frameworkext.ReservationScoreExtensions = &Plugin{}
 	_ frameworkext.ReservationPreBindPlugin   = &Plugin{}
+	_ frameworkext.ReservationResizePlugin    = &Plugin{}
 )
 
 type Plugin struct {
@@ -394,7 +397,12 @@ func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 	var err error
 	if len(result) == 0 {
 		preemptible = appendAllocated(preemptible, restoreState.mergedMatchedAllocatable)
-		result, err = p.allocator.Allocate(nodeName, pod, state.podRequests, nodeDeviceInfo, nil, nil, nil, preemptible, p.scorer)
+		affinity := topologymanager.GetStore(cycleState).GetAffinity(nodeName)
+		var required map[schedulingv1alpha1.DeviceType]sets.Int
+		required, err = nodeDeviceInfo.getRequiredDevicesByNUMAAffinity(state.podRequests, affinity)
+		if err == nil {
+			result, err = p.allocator.Allocate(nodeName, pod, state.podRequests, nodeDeviceInfo, required, nil, nil, preemptible, p.scorer)
+		}
 	}
 	if err != nil || len(result) == 0 {
 		return framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices)
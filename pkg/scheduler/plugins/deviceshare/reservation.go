@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
@@ -441,3 +442,156 @@ func (p *Plugin) scoreWithNominatedReservation(
 	)
 	return score, status
 }
+
+// ResizeReservation shrinks or grows the devices held by the reservation according to the desired allocatable.
+// The devices are shrunk at the granularity of device instances, and the instances allocated by the owner Pods are kept.
+// When growing, the increased resources are allocated from the free devices of the node.
+func (p *Plugin) ResizeReservation(ctx context.Context, cycleState *framework.CycleState, reservation *schedulingv1alpha1.Reservation, reservationInfo *frameworkext.ReservationInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	allocations, err := apiext.GetDeviceAllocations(reservation.Annotations)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+
+	nodeName := nodeInfo.Node().Name
+	nd := p.nodeDeviceCache.getNodeDevice(nodeName, false)
+	if nd == nil {
+		if len(allocations) == 0 {
+			return nil
+		}
+		return framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices)
+	}
+
+	nd.lock.RLock()
+	defer nd.lock.RUnlock()
+
+	changed := false
+	for deviceType := range DeviceResourceNames {
+		desired, err := getDesiredDeviceResources(reservation.Status.Allocatable, deviceType)
+		if err != nil {
+			return framework.NewStatus(framework.Unschedulable, err.Error())
+		}
+		var current corev1.ResourceList
+		for _, alloc := range allocations[deviceType] {
+			current = quotav1.Add(current, alloc.Resources)
+		}
+		current = quotav1.Mask(current, quotav1.ResourceNames(desired))
+		if quotav1.Equals(current, desired) {
+			continue
+		}
+
+		if lessThanOrEqual, _ := quotav1.LessThanOrEqual(desired, current); lessThanOrEqual {
+			toRelease := quotav1.Subtract(current, desired)
+			deviceAllocations, status := shrinkDeviceAllocations(nd, deviceType, allocations[deviceType], toRelease, reservationInfo)
+			if !status.IsSuccess() {
+				return status
+			}
+			allocations[deviceType] = deviceAllocations
+		} else if greaterThanOrEqual, _ := quotav1.LessThanOrEqual(current, desired); greaterThanOrEqual {
+			toAllocate := quotav1.Subtract(desired, current)
+			result, err := nd.tryAllocateDevice(toAllocate, nil, nil, nil, nil, p.scorer)
+			if err != nil {
+				return framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices)
+			}
+			allocations[deviceType] = mergeDeviceAllocations(allocations[deviceType], result[deviceType])
+		} else {
+			return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("can not shrink and grow the %v resources at the same time", deviceType))
+		}
+		if len(allocations[deviceType]) == 0 {
+			delete(allocations, deviceType)
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := apiext.SetDeviceAllocations(reservation, allocations); err != nil {
+		return framework.AsStatus(err)
+	}
+	// keep the allocated device resources recorded in the allocatable consistent with the allocations
+	var allocated corev1.ResourceList
+	for _, deviceAllocations := range allocations {
+		for _, alloc := range deviceAllocations {
+			allocated = quotav1.Add(allocated, alloc.Resources)
+		}
+	}
+	for _, resourceName := range []corev1.ResourceName{apiext.ResourceGPUCore, apiext.ResourceGPUMemory, apiext.ResourceGPUMemoryRatio} {
+		if _, ok := reservation.Status.Allocatable[resourceName]; ok {
+			reservation.Status.Allocatable[resourceName] = allocated[resourceName]
+		}
+	}
+	return nil
+}
+
+// getDesiredDeviceResources returns the desired resources of the device type in the form of device allocations.
+func getDesiredDeviceResources(allocatable corev1.ResourceList, deviceType schedulingv1alpha1.DeviceType) (corev1.ResourceList, error) {
+	requests := quotav1.Mask(allocatable, DeviceResourceNames[deviceType])
+	if quotav1.IsZero(requests) {
+		return nil, nil
+	}
+	if deviceType == schedulingv1alpha1.GPU {
+		// The allocatable of the reservation scheduled with ResizePod contains the allocated GPU resources.
+		allocated := quotav1.Mask(requests, []corev1.ResourceName{apiext.ResourceGPUCore, apiext.ResourceGPUMemory, apiext.ResourceGPUMemoryRatio})
+		if !quotav1.IsZero(allocated) {
+			requests = allocated
+			if _, ok := requests[apiext.ResourceGPUMemoryRatio]; ok {
+				delete(requests, apiext.ResourceGPUMemory)
+			}
+		}
+	}
+	combination, err := ValidateDeviceRequest(requests)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertDeviceRequest(requests, combination), nil
+}
+
+func shrinkDeviceAllocations(nd *nodeDevice, deviceType schedulingv1alpha1.DeviceType, allocations []*apiext.DeviceAllocation, toRelease corev1.ResourceList, reservationInfo *frameworkext.ReservationInfo) ([]*apiext.DeviceAllocation, *framework.Status) {
+	usedMinors := sets.NewInt()
+	for _, pod := range reservationInfo.AssignedPods {
+		for minor := range nd.getUsed(pod.Namespace, pod.Name)[deviceType] {
+			usedMinors.Insert(minor)
+		}
+	}
+
+	released := sets.NewInt()
+	for i := len(allocations) - 1; i >= 0 && !quotav1.IsZero(toRelease); i-- {
+		alloc := allocations[i]
+		if usedMinors.Has(int(alloc.Minor)) {
+			continue
+		}
+		resources := quotav1.Mask(alloc.Resources, quotav1.ResourceNames(toRelease))
+		if lessThanOrEqual, _ := quotav1.LessThanOrEqual(resources, toRelease); lessThanOrEqual {
+			toRelease = quotav1.Subtract(toRelease, resources)
+			released.Insert(i)
+		}
+	}
+	if !quotav1.IsZero(toRelease) {
+		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("can not shrink the %v resources by whole device instances", deviceType))
+	}
+
+	result := make([]*apiext.DeviceAllocation, 0, len(allocations)-released.Len())
+	for i, alloc := range allocations {
+		if !released.Has(i) {
+			result = append(result, alloc)
+		}
+	}
+	return result, nil
+}
+
+func mergeDeviceAllocations(allocations []*apiext.DeviceAllocation, increased []*apiext.DeviceAllocation) []*apiext.DeviceAllocation {
+	for _, alloc := range increased {
+		merged := false
+		for _, existing := range allocations {
+			if existing.Minor == alloc.Minor {
+				existing.Resources = quotav1.Add(existing.Resources, alloc.Resources)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			allocations = append(allocations, alloc)
+		}
+	}
+	return allocations
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/utils/pointer"
 
@@ -661,3 +662,139 @@ func Test_tryAllocateFromReservation(t *testing.T) {
 		})
 	}
 }
+
+func Test_Plugin_ResizeReservation(t *testing.T) {
+	gpuResources := func(n int64) corev1.ResourceList {
+		return corev1.ResourceList{
+			apiext.ResourceGPUCore:        *resource.NewQuantity(100*n, resource.DecimalSI),
+			apiext.ResourceGPUMemory:      *resource.NewQuantity(8*1024*1024*1024*n, resource.BinarySI),
+			apiext.ResourceGPUMemoryRatio: *resource.NewQuantity(100*n, resource.DecimalSI),
+		}
+	}
+	tests := []struct {
+		name            string
+		desired         corev1.ResourceList
+		assignedMinors  []int32
+		wantStatus      bool
+		wantMinors      []int32
+		wantAllocatable corev1.ResourceList
+	}{
+		{
+			name:            "devices not changed",
+			desired:         gpuResources(2),
+			wantStatus:      true,
+			wantMinors:      []int32{0, 1},
+			wantAllocatable: gpuResources(2),
+		},
+		{
+			name:            "grow devices",
+			desired:         gpuResources(3),
+			wantStatus:      true,
+			wantMinors:      []int32{0, 1, 2},
+			wantAllocatable: gpuResources(3),
+		},
+		{
+			name:            "shrink devices",
+			desired:         gpuResources(1),
+			wantStatus:      true,
+			wantMinors:      []int32{0},
+			wantAllocatable: gpuResources(1),
+		},
+		{
+			name:            "shrink devices and keep the devices allocated by owners",
+			desired:         gpuResources(1),
+			assignedMinors:  []int32{1},
+			wantStatus:      true,
+			wantMinors:      []int32{1},
+			wantAllocatable: gpuResources(1),
+		},
+		{
+			name:           "failed to shrink the devices allocated by owners",
+			desired:        gpuResources(1),
+			assignedMinors: []int32{0, 1},
+			wantStatus:     false,
+		},
+		{
+			name:       "failed to grow devices with insufficient devices",
+			desired:    gpuResources(5),
+			wantStatus: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suit := newPluginTestSuit(t, nil)
+			p, err := suit.proxyNew(getDefaultArgs(), suit.Framework)
+			assert.NoError(t, err)
+			pl := p.(*Plugin)
+
+			device := &schedulingv1alpha1.Device{}
+			for i := int32(0); i < 4; i++ {
+				device.Spec.Devices = append(device.Spec.Devices, schedulingv1alpha1.DeviceInfo{
+					Type:      schedulingv1alpha1.GPU,
+					Minor:     pointer.Int32(i),
+					Health:    true,
+					Resources: gpuResources(1),
+				})
+			}
+			pl.nodeDeviceCache.updateNodeDevice("test-node-1", device)
+			nd := pl.nodeDeviceCache.getNodeDevice("test-node-1", false)
+
+			reservation := &schedulingv1alpha1.Reservation{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:  uuid.NewUUID(),
+					Name: "reservation-1",
+				},
+				Spec: schedulingv1alpha1.ReservationSpec{
+					Template: &corev1.PodTemplateSpec{},
+				},
+				Status: schedulingv1alpha1.ReservationStatus{
+					Phase:       schedulingv1alpha1.ReservationAvailable,
+					NodeName:    "test-node-1",
+					Allocatable: gpuResources(2),
+				},
+			}
+			allocations := apiext.DeviceAllocations{
+				schedulingv1alpha1.GPU: {
+					{Minor: 0, Resources: gpuResources(1)},
+					{Minor: 1, Resources: gpuResources(1)},
+				},
+			}
+			assert.NoError(t, apiext.SetDeviceAllocations(reservation, allocations))
+			nd.updateCacheUsed(allocations, reservationutil.NewReservePod(reservation), true)
+
+			rInfo := frameworkext.NewReservationInfo(reservation)
+			if len(tt.assignedMinors) > 0 {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: uuid.NewUUID(), Namespace: "default", Name: "owner"}}
+				podAllocations := apiext.DeviceAllocations{}
+				for _, minor := range tt.assignedMinors {
+					podAllocations[schedulingv1alpha1.GPU] = append(podAllocations[schedulingv1alpha1.GPU], &apiext.DeviceAllocation{
+						Minor:     minor,
+						Resources: gpuResources(1),
+					})
+				}
+				nd.updateCacheUsed(podAllocations, pod, true)
+				rInfo.AddAssignedPod(pod)
+			}
+
+			resized := reservation.DeepCopy()
+			resized.Status.Allocatable = tt.desired
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"}}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			status := pl.ResizeReservation(context.TODO(), framework.NewCycleState(), resized, rInfo, nodeInfo)
+			assert.Equal(t, tt.wantStatus, status.IsSuccess(), status.Message())
+			if !tt.wantStatus {
+				return
+			}
+
+			got, err := apiext.GetDeviceAllocations(resized.Annotations)
+			assert.NoError(t, err)
+			var minors []int32
+			for _, alloc := range got[schedulingv1alpha1.GPU] {
+				minors = append(minors, alloc.Minor)
+			}
+			assert.ElementsMatch(t, tt.wantMinors, minors)
+			assert.True(t, quotav1.Equals(tt.wantAllocatable, resized.Status.Allocatable), "want %v, got %v", tt.wantAllocatable, resized.Status.Allocatable)
+		})
+	}
+}
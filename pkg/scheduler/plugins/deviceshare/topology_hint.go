@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/bitmask"
+)
+
+var _ topologymanager.NUMATopologyHintProvider = &Plugin{}
+
+func (p *Plugin) GetPodTopologyHints(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) (map[string][]topologymanager.NUMATopologyHint, *framework.Status) {
+	state, status := getPreFilterState(cycleState)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	if state.skip {
+		return nil, nil
+	}
+
+	nodeDeviceInfo := p.nodeDeviceCache.getNodeDevice(nodeName, false)
+	if nodeDeviceInfo == nil {
+		return nil, nil
+	}
+
+	preemptible := p.getPreemptibleForTopology(cycleState, state, nodeName)
+
+	nodeDeviceInfo.lock.RLock()
+	defer nodeDeviceInfo.lock.RUnlock()
+
+	hints := map[string][]topologymanager.NUMATopologyHint{}
+	for deviceType, supportedResourceNames := range DeviceResourceNames {
+		if quotav1.IsZero(quotav1.Mask(state.podRequests, supportedResourceNames)) {
+			continue
+		}
+		numaNodes := nodeDeviceInfo.getNUMANodes(deviceType)
+		if len(numaNodes) == 0 {
+			continue
+		}
+		hints[string(deviceType)] = p.generateDeviceHints(nodeName, pod, state.podRequests, nodeDeviceInfo, deviceType, numaNodes, preemptible)
+	}
+	return hints, nil
+}
+
+func (p *Plugin) generateDeviceHints(
+	nodeName string,
+	pod *corev1.Pod,
+	podRequests corev1.ResourceList,
+	nodeDeviceInfo *nodeDevice,
+	deviceType schedulingv1alpha1.DeviceType,
+	numaNodes []int,
+	preemptible map[schedulingv1alpha1.DeviceType]deviceResources,
+) []topologymanager.NUMATopologyHint {
+	minAffinitySize := len(numaNodes)
+	// an empty but non-nil hints means that no NUMA affinity can satisfy the request
+	hints := []topologymanager.NUMATopologyHint{}
+	bitmask.IterateBitMasks(numaNodes, func(mask bitmask.BitMask) {
+		required := map[schedulingv1alpha1.DeviceType]sets.Int{
+			deviceType: nodeDeviceInfo.getDeviceMinorsByNUMANodes(deviceType, mask.GetBits()),
+		}
+		result, err := p.allocator.Allocate(nodeName, pod, podRequests, nodeDeviceInfo, required, nil, nil, preemptible, nil)
+		if err != nil || len(result[deviceType]) == 0 {
+			return
+		}
+		if mask.Count() < minAffinitySize {
+			minAffinitySize = mask.Count()
+		}
+		hints = append(hints, topologymanager.NUMATopologyHint{
+			NUMANodeAffinity: mask,
+			Preferred:        false,
+		})
+	})
+
+	for i := range hints {
+		if hints[i].NUMANodeAffinity.Count() == minAffinitySize {
+			hints[i].Preferred = true
+		}
+	}
+	return hints
+}
+
+func (p *Plugin) Allocate(ctx context.Context, cycleState *framework.CycleState, affinity topologymanager.NUMATopologyHint, pod *corev1.Pod, nodeName string) *framework.Status {
+	state, status := getPreFilterState(cycleState)
+	if !status.IsSuccess() {
+		return status
+	}
+	if state.skip {
+		return nil
+	}
+
+	nodeDeviceInfo := p.nodeDeviceCache.getNodeDevice(nodeName, false)
+	if nodeDeviceInfo == nil {
+		return nil
+	}
+
+	preemptible := p.getPreemptibleForTopology(cycleState, state, nodeName)
+
+	nodeDeviceInfo.lock.RLock()
+	defer nodeDeviceInfo.lock.RUnlock()
+
+	required, err := nodeDeviceInfo.getRequiredDevicesByNUMAAffinity(state.podRequests, affinity)
+	if err != nil {
+		return framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices)
+	}
+	result, err := p.allocator.Allocate(nodeName, pod, state.podRequests, nodeDeviceInfo, required, nil, nil, preemptible, nil)
+	if err != nil || len(result) == 0 {
+		return framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices)
+	}
+	return nil
+}
+
+func (p *Plugin) getPreemptibleForTopology(cycleState *framework.CycleState, state *preFilterState, nodeName string) map[schedulingv1alpha1.DeviceType]deviceResources {
+	restoreState := getReservationRestoreState(cycleState).getNodeState(nodeName)
+	preemptible := appendAllocated(nil, restoreState.mergedUnmatchedUsed, state.preemptibleDevices[nodeName])
+	return appendAllocated(preemptible, restoreState.mergedMatchedAllocatable)
+}
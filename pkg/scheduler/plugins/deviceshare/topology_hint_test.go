@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/bitmask"
+)
+
+func generateFakeNUMADevice(nodeName string) *schedulingv1alpha1.Device {
+	device := &schedulingv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeName,
+		},
+	}
+	for i := 0; i < 4; i++ {
+		device.Spec.Devices = append(device.Spec.Devices, schedulingv1alpha1.DeviceInfo{
+			UUID:   string(uuid.NewUUID()),
+			Minor:  pointer.Int32(int32(i)),
+			Health: true,
+			Type:   schedulingv1alpha1.GPU,
+			Resources: corev1.ResourceList{
+				apiext.ResourceGPUCore:        resource.MustParse("100"),
+				apiext.ResourceGPUMemoryRatio: resource.MustParse("100"),
+				apiext.ResourceGPUMemory:      resource.MustParse("16Gi"),
+			},
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				SocketID: 0,
+				NodeID:   int32(i / 2),
+			},
+		})
+	}
+	return device
+}
+
+func newTestNUMANodeDeviceCache(nodeName string) *nodeDeviceCache {
+	deviceCache := newNodeDeviceCache()
+	deviceCache.updateNodeDevice(nodeName, generateFakeNUMADevice(nodeName))
+	// GPU 0 in NUMA Node 0 is fully used
+	usedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "used-pod",
+			UID:       uuid.NewUUID(),
+		},
+	}
+	deviceCache.getNodeDevice(nodeName, false).updateCacheUsed(apiext.DeviceAllocations{
+		schedulingv1alpha1.GPU: {
+			{
+				Minor: 0,
+				Resources: corev1.ResourceList{
+					apiext.ResourceGPUCore:        resource.MustParse("100"),
+					apiext.ResourceGPUMemoryRatio: resource.MustParse("100"),
+					apiext.ResourceGPUMemory:      resource.MustParse("16Gi"),
+				},
+			},
+		},
+	}, usedPod, true)
+	return deviceCache
+}
+
+func TestPlugin_GetPodTopologyHints(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     *preFilterState
+		wantHints map[string][]topologymanager.NUMATopologyHint
+	}{
+		{
+			name: "skip",
+			state: &preFilterState{
+				skip: true,
+			},
+		},
+		{
+			name: "request one GPU",
+			state: &preFilterState{
+				podRequests: corev1.ResourceList{
+					apiext.ResourceGPUCore:        resource.MustParse("100"),
+					apiext.ResourceGPUMemoryRatio: resource.MustParse("100"),
+				},
+			},
+			wantHints: map[string][]topologymanager.NUMATopologyHint{
+				string(schedulingv1alpha1.GPU): {
+					{NUMANodeAffinity: newTestBitMask(t, 0), Preferred: true},
+					{NUMANodeAffinity: newTestBitMask(t, 1), Preferred: true},
+					{NUMANodeAffinity: newTestBitMask(t, 0, 1), Preferred: false},
+				},
+			},
+		},
+		{
+			name: "request two GPUs",
+			state: &preFilterState{
+				podRequests: corev1.ResourceList{
+					apiext.ResourceGPUCore:        resource.MustParse("200"),
+					apiext.ResourceGPUMemoryRatio: resource.MustParse("200"),
+				},
+			},
+			wantHints: map[string][]topologymanager.NUMATopologyHint{
+				string(schedulingv1alpha1.GPU): {
+					{NUMANodeAffinity: newTestBitMask(t, 1), Preferred: true},
+					{NUMANodeAffinity: newTestBitMask(t, 0, 1), Preferred: false},
+				},
+			},
+		},
+		{
+			name: "request four GPUs",
+			state: &preFilterState{
+				podRequests: corev1.ResourceList{
+					apiext.ResourceGPUCore:        resource.MustParse("400"),
+					apiext.ResourceGPUMemoryRatio: resource.MustParse("400"),
+				},
+			},
+			wantHints: map[string][]topologymanager.NUMATopologyHint{
+				string(schedulingv1alpha1.GPU): {},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{nodeDeviceCache: newTestNUMANodeDeviceCache("test-node"), allocator: &defaultAllocator{}}
+			cycleState := framework.NewCycleState()
+			cycleState.Write(stateKey, tt.state)
+			hints, status := p.GetPodTopologyHints(context.TODO(), cycleState, &corev1.Pod{}, "test-node")
+			assert.True(t, status.IsSuccess())
+			for _, v := range hints {
+				sortNUMATopologyHints(v)
+			}
+			assert.Equal(t, tt.wantHints, hints)
+		})
+	}
+}
+
+func TestPlugin_Allocate(t *testing.T) {
+	tests := []struct {
+		name       string
+		affinity   topologymanager.NUMATopologyHint
+		wantStatus *framework.Status
+	}{
+		{
+			name:     "without affinity",
+			affinity: topologymanager.NUMATopologyHint{},
+		},
+		{
+			name:     "affinity with NUMA Node 1",
+			affinity: topologymanager.NUMATopologyHint{NUMANodeAffinity: newTestBitMask(t, 1)},
+		},
+		{
+			name:       "affinity with NUMA Node 0",
+			affinity:   topologymanager.NUMATopologyHint{NUMANodeAffinity: newTestBitMask(t, 0)},
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices),
+		},
+		{
+			name:       "affinity without any GPU",
+			affinity:   topologymanager.NUMATopologyHint{NUMANodeAffinity: newTestBitMask(t, 2)},
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{nodeDeviceCache: newTestNUMANodeDeviceCache("test-node"), allocator: &defaultAllocator{}}
+			cycleState := framework.NewCycleState()
+			cycleState.Write(stateKey, &preFilterState{
+				podRequests: corev1.ResourceList{
+					apiext.ResourceGPUCore:        resource.MustParse("200"),
+					apiext.ResourceGPUMemoryRatio: resource.MustParse("200"),
+				},
+			})
+			status := p.Allocate(context.TODO(), cycleState, tt.affinity, &corev1.Pod{}, "test-node")
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestPlugin_ReserveWithNUMAAffinity(t *testing.T) {
+	p := &Plugin{nodeDeviceCache: newTestNUMANodeDeviceCache("test-node"), allocator: &defaultAllocator{}}
+	cycleState := framework.NewCycleState()
+	state := &preFilterState{
+		podRequests: corev1.ResourceList{
+			apiext.ResourceGPUCore:        resource.MustParse("100"),
+			apiext.ResourceGPUMemoryRatio: resource.MustParse("100"),
+		},
+	}
+	cycleState.Write(stateKey, state)
+	topologymanager.InitStore(cycleState)
+	topologymanager.GetStore(cycleState).SetAffinity("test-node", topologymanager.NUMATopologyHint{NUMANodeAffinity: newTestBitMask(t, 1)})
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+			UID:       uuid.NewUUID(),
+		},
+	}
+	status := p.Reserve(context.TODO(), cycleState, pod, "test-node")
+	assert.True(t, status.IsSuccess())
+	allocations := state.allocationResult[schedulingv1alpha1.GPU]
+	assert.Len(t, allocations, 1)
+	assert.Contains(t, []int32{2, 3}, allocations[0].Minor)
+}
+
+func newTestBitMask(t *testing.T, bits ...int) bitmask.BitMask {
+	mask, err := bitmask.NewBitMask(bits...)
+	assert.NoError(t, err)
+	return mask
+}
+
+func sortNUMATopologyHints(hints []topologymanager.NUMATopologyHint) {
+	sort.Slice(hints, func(i, j int) bool {
+		return hints[i].NUMANodeAffinity.IsLessThan(hints[j].NUMANodeAffinity)
+	})
+}
@@ -19,7 +19,6 @@ package elasticquota
 import (
 	"context"
 	"fmt"
-	"sort"
 	"sync"
 	"time"
 
@@ -31,11 +30,11 @@ import (
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
-	k8sutil "k8s.io/kubernetes/pkg/scheduler/util"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/victim"
 )
 
 const (
@@ -102,7 +101,7 @@ func (monitor *QuotaOverUsedGroupMonitor) getToRevokePodList(quotaName string) [
 	// order pod from low priority -> high priority
 	priPodCache := quotaInfo.GetPodThatIsAssigned()
 
-	sort.Slice(priPodCache, func(i, j int) bool { return !k8sutil.MoreImportantPod(priPodCache[i], priPodCache[j]) })
+	victim.NewRanker().Sort(priPodCache)
 
 	// first try revoke all until used <= runtime
 	tryAssignBackPodCache := make([]*v1.Pod, 0)
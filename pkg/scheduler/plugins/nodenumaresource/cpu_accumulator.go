@@ -36,6 +36,7 @@ func takePreferredCPUs(
 	cpuBindPolicy schedulingconfig.CPUBindPolicy,
 	cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy,
 	numaAllocatedStrategy schedulingconfig.NUMAAllocateStrategy,
+	l3CacheAware bool,
 ) (cpuset.CPUSet, error) {
 	result := cpuset.CPUSet{}
 	preferredCPUs = availableCPUs.Intersection(preferredCPUs)
@@ -53,7 +54,8 @@ func takePreferredCPUs(
 			needed,
 			cpuBindPolicy,
 			cpuExclusivePolicy,
-			numaAllocatedStrategy)
+			numaAllocatedStrategy,
+			l3CacheAware)
 		if err != nil {
 			return result, err
 		}
@@ -70,7 +72,8 @@ func takePreferredCPUs(
 			numCPUsNeeded,
 			cpuBindPolicy,
 			cpuExclusivePolicy,
-			numaAllocatedStrategy)
+			numaAllocatedStrategy,
+			l3CacheAware)
 		if err != nil {
 			return cpuset.CPUSet{}, err
 		}
@@ -93,6 +96,7 @@ func takeCPUs(
 	cpuBindPolicy schedulingconfig.CPUBindPolicy,
 	cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy,
 	numaAllocatedStrategy schedulingconfig.NUMAAllocateStrategy,
+	l3CacheAware bool,
 ) (cpuset.CPUSet, error) {
 	acc := newCPUAccumulator(topology, maxRefCount, availableCPUs, allocatedCPUs, numCPUsNeeded, cpuExclusivePolicy, numaAllocatedStrategy)
 	if acc.isSatisfied() {
@@ -104,10 +108,24 @@ func takeCPUs(
 
 	fullPCPUs := cpuBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs
 	if fullPCPUs || acc.topology.CPUsPerCore() == 1 {
+		filterExclusiveArgs := []bool{true, false}
+		// If the L3 Cache aware allocation is enabled and the NUMA Node is split into multiple L3 Caches (e.g. the CCDs of AMD EPYC),
+		// try to allocate in the same L3 Cache according to the NUMA allocation strategy before spilling across them
+		if l3CacheAware && acc.topology.HasFinerL3Caches() && acc.numCPUsNeeded <= acc.topology.CPUsPerL3Cache() {
+			for _, filterExclusive := range filterExclusiveArgs {
+				freeCPUs := acc.freeCoresInL3Cache(filterExclusive)
+				for _, cpus := range freeCPUs {
+					if len(cpus) >= acc.numCPUsNeeded {
+						acc.take(cpus[:acc.numCPUsNeeded]...)
+						return acc.result, nil
+					}
+				}
+			}
+		}
+
 		// According to the NUMA allocation strategy,
 		// select the NUMA Node with the most remaining amount or the least amount remaining
 		// and the total amount of available CPUs in the NUMA Node is greater than or equal to the number of CPUs needed
-		filterExclusiveArgs := []bool{true, false}
 		if acc.numCPUsNeeded <= acc.topology.CPUsPerNode() {
 			for _, filterExclusive := range filterExclusiveArgs {
 				freeCPUs := acc.freeCoresInNode(true, filterExclusive)
@@ -460,6 +478,83 @@ func (a *cpuAccumulator) freeCoresInNode(filterFullFreeCore bool, filterExclusiv
 	return result
 }
 
+// freeCoresInL3Cache returns the logical cpus of the full free cores in L3 Caches that sorted
+func (a *cpuAccumulator) freeCoresInL3Cache(filterExclusive bool) [][]int {
+	allocatableCPUs := a.allocatableCPUs
+
+	nodeFreeScores := make(map[int]int)
+	cpusInCores := make(map[int][]int)
+	for _, cpuInfo := range allocatableCPUs {
+		if filterExclusive && a.isCPUExclusiveNUMANodeLevel(&cpuInfo) {
+			continue
+		}
+		cpus := cpusInCores[cpuInfo.CoreID]
+		if len(cpus) == 0 {
+			cpus = make([]int, 0, a.topology.CPUsPerCore())
+		}
+		cpus = append(cpus, cpuInfo.CPUID)
+		cpusInCores[cpuInfo.CoreID] = cpus
+		nodeFreeScores[cpuInfo.NodeID]++
+	}
+
+	coresInL3Caches := make(map[int][]int)
+	for core, cpus := range cpusInCores {
+		if len(cpus) != a.topology.CPUsPerCore() {
+			continue
+		}
+		info := allocatableCPUs[cpus[0]]
+		coresInL3Caches[info.L3CacheID] = append(coresInL3Caches[info.L3CacheID], core)
+	}
+
+	l3CacheIDs := make([]int, 0, len(coresInL3Caches))
+	cpusInL3Caches := make(map[int][]int)
+	for l3CacheID, cores := range coresInL3Caches {
+		l3CacheIDs = append(l3CacheIDs, l3CacheID)
+		a.sortCores(allocatableCPUs, cores, cpusInCores)
+		cpusInCore := make([]int, 0, len(cores)*a.topology.CPUsPerCore())
+		for _, c := range cores {
+			cpus := cpusInCores[c]
+			sort.Ints(cpus)
+			cpusInCore = append(cpusInCore, cpus...)
+		}
+		cpusInL3Caches[l3CacheID] = cpusInCore
+	}
+
+	sort.Slice(l3CacheIDs, func(i, j int) bool {
+		iCPUs := cpusInL3Caches[l3CacheIDs[i]]
+		jCPUs := cpusInL3Caches[l3CacheIDs[j]]
+
+		iL3CacheFreeScore := len(iCPUs)
+		jL3CacheFreeScore := len(jCPUs)
+		if iL3CacheFreeScore != jL3CacheFreeScore {
+			if a.numaAllocateStrategy == schedulingconfig.NUMAMostAllocated {
+				return iL3CacheFreeScore < jL3CacheFreeScore
+			} else {
+				return iL3CacheFreeScore > jL3CacheFreeScore
+			}
+		}
+
+		// each cpu's nodeId in same L3 Cache are same
+		iNodeFreeScore := nodeFreeScores[allocatableCPUs[iCPUs[0]].NodeID]
+		jNodeFreeScore := nodeFreeScores[allocatableCPUs[jCPUs[0]].NodeID]
+		if iNodeFreeScore != jNodeFreeScore {
+			if a.numaAllocateStrategy == schedulingconfig.NUMAMostAllocated {
+				return iNodeFreeScore < jNodeFreeScore
+			} else {
+				return iNodeFreeScore > jNodeFreeScore
+			}
+		}
+
+		return l3CacheIDs[i] < l3CacheIDs[j]
+	})
+
+	var result [][]int
+	for _, l3CacheID := range l3CacheIDs {
+		result = append(result, cpusInL3Caches[l3CacheID])
+	}
+	return result
+}
+
 // freeCoresInSocket returns the logical cpus of the free cores in sockets that sorted
 func (a *cpuAccumulator) freeCoresInSocket(filterFullFreeCore bool) [][]int {
 	allocatableCPUs := a.allocatableCPUs
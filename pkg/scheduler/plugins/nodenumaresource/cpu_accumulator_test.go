@@ -29,11 +29,12 @@ import (
 
 func buildCPUTopologyForTest(numSockets, nodesPerSocket, coresPerNode, cpusPerCore int) *CPUTopology {
 	topo := &CPUTopology{
-		NumSockets: numSockets,
-		NumNodes:   nodesPerSocket * numSockets,
-		NumCores:   coresPerNode * nodesPerSocket * numSockets,
-		NumCPUs:    cpusPerCore * coresPerNode * nodesPerSocket * numSockets,
-		CPUDetails: make(map[int]CPUInfo),
+		NumSockets:  numSockets,
+		NumNodes:    nodesPerSocket * numSockets,
+		NumL3Caches: nodesPerSocket * numSockets,
+		NumCores:    coresPerNode * nodesPerSocket * numSockets,
+		NumCPUs:     cpusPerCore * coresPerNode * nodesPerSocket * numSockets,
+		CPUDetails:  make(map[int]CPUInfo),
 	}
 	var nodeID, coreID, cpuID int
 	for s := 0; s < numSockets; s++ {
@@ -41,10 +42,11 @@ func buildCPUTopologyForTest(numSockets, nodesPerSocket, coresPerNode, cpusPerCo
 			for c := 0; c < coresPerNode; c++ {
 				for p := 0; p < cpusPerCore; p++ {
 					topo.CPUDetails[cpuID] = CPUInfo{
-						SocketID: s,
-						NodeID:   nodeID,
-						CoreID:   coreID,
-						CPUID:    cpuID,
+						SocketID:  s,
+						NodeID:    nodeID,
+						L3CacheID: nodeID << 16,
+						CoreID:    coreID,
+						CPUID:     cpuID,
 					}
 					cpuID++
 				}
@@ -159,7 +161,7 @@ func TestTakeFullPCPUs(t *testing.T) {
 			allocatedCPUsDetails := tt.topology.CPUDetails.KeepOnly(tt.allocatedCPUs)
 			result, err := takeCPUs(
 				tt.topology, tt.maxRefCount, availableCPUs, allocatedCPUsDetails,
-				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 			if tt.wantError && err == nil {
 				t.Fatal("expect error but got nil")
 			} else if !tt.wantError && err != nil {
@@ -275,7 +277,7 @@ func TestTakeFullPCPUsWithNUMALeastAllocated(t *testing.T) {
 			allocatedCPUsDetails := tt.topology.CPUDetails.KeepOnly(tt.allocatedCPUs)
 			result, err := takeCPUs(
 				tt.topology, tt.maxRefCount, availableCPUs, allocatedCPUsDetails,
-				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMALeastAllocated)
+				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMALeastAllocated, false)
 			if tt.wantError && err == nil {
 				t.Fatal("expect error but got nil")
 			} else if !tt.wantError && err != nil {
@@ -288,6 +290,114 @@ func TestTakeFullPCPUsWithNUMALeastAllocated(t *testing.T) {
 	}
 }
 
+func buildCPUTopologyWithL3CacheForTest(numSockets, nodesPerSocket, l3CachesPerNode, coresPerL3Cache, cpusPerCore int) *CPUTopology {
+	builder := NewCPUTopologyBuilder()
+	var nodeID, l3CacheID, coreID, cpuID int
+	for s := 0; s < numSockets; s++ {
+		for n := 0; n < nodesPerSocket; n++ {
+			for l := 0; l < l3CachesPerNode; l++ {
+				for c := 0; c < coresPerL3Cache; c++ {
+					for p := 0; p < cpusPerCore; p++ {
+						builder.AddCPUInfoWithL3Cache(s, nodeID, l3CacheID, coreID, cpuID)
+						cpuID++
+					}
+					coreID++
+				}
+				l3CacheID++
+			}
+			nodeID++
+		}
+	}
+	return builder.Result()
+}
+
+func TestCPUTopologyWithL3Cache(t *testing.T) {
+	topology := buildCPUTopologyWithL3CacheForTest(2, 1, 2, 4, 2)
+	assert.Equal(t, 32, topology.NumCPUs)
+	assert.Equal(t, 16, topology.NumCores)
+	assert.Equal(t, 4, topology.NumL3Caches)
+	assert.Equal(t, 2, topology.NumNodes)
+	assert.Equal(t, 2, topology.NumSockets)
+	assert.Equal(t, 8, topology.CPUsPerL3Cache())
+	assert.True(t, topology.HasFinerL3Caches())
+
+	topology = buildCPUTopologyWithL3CacheForTest(2, 2, 1, 4, 2)
+	assert.Equal(t, 4, topology.NumL3Caches)
+	assert.False(t, topology.HasFinerL3Caches())
+
+	// the CPUs without L3 Cache info are treated as one L3 Cache per NUMA Node
+	builder := NewCPUTopologyBuilder()
+	for cpuID := 0; cpuID < 8; cpuID++ {
+		builder.AddCPUInfo(0, cpuID/4, cpuID/2, cpuID)
+	}
+	topology = builder.Result()
+	assert.Equal(t, 2, topology.NumL3Caches)
+	assert.False(t, topology.HasFinerL3Caches())
+}
+
+func TestTakeFullPCPUsWithL3Cache(t *testing.T) {
+	tests := []struct {
+		name                 string
+		allocatedCPUs        cpuset.CPUSet
+		numCPUsNeeded        int
+		numaAllocateStrategy schedulingconfig.NUMAAllocateStrategy
+		l3CacheAware         bool
+		wantResult           cpuset.CPUSet
+	}{
+		{
+			name:                 "allocate in the most allocated L3 Cache",
+			allocatedCPUs:        cpuset.MustParse("0-3,8-9"),
+			numCPUsNeeded:        4,
+			l3CacheAware:         true,
+			numaAllocateStrategy: schedulingconfig.NUMAMostAllocated,
+			wantResult:           cpuset.MustParse("4-7"),
+		},
+		{
+			name:                 "allocate in the least allocated L3 Cache",
+			allocatedCPUs:        cpuset.MustParse("0-3,8-9"),
+			numCPUsNeeded:        4,
+			l3CacheAware:         true,
+			numaAllocateStrategy: schedulingconfig.NUMALeastAllocated,
+			wantResult:           cpuset.MustParse("16-19"),
+		},
+		{
+			name:                 "allocate in the L3 Cache which can satisfy the request",
+			allocatedCPUs:        cpuset.MustParse("0-3,8-9"),
+			numCPUsNeeded:        6,
+			l3CacheAware:         true,
+			numaAllocateStrategy: schedulingconfig.NUMAMostAllocated,
+			wantResult:           cpuset.MustParse("10-15"),
+		},
+		{
+			name:                 "allocate across L3 Caches in the same NUMA Node",
+			allocatedCPUs:        cpuset.MustParse("0-3,8-9"),
+			numCPUsNeeded:        10,
+			l3CacheAware:         true,
+			numaAllocateStrategy: schedulingconfig.NUMAMostAllocated,
+			wantResult:           cpuset.MustParse("4-7,10-15"),
+		},
+		{
+			name:                 "allocate in the NUMA Node without L3 Cache aware",
+			allocatedCPUs:        cpuset.MustParse("0-3,8-9"),
+			numCPUsNeeded:        6,
+			numaAllocateStrategy: schedulingconfig.NUMAMostAllocated,
+			wantResult:           cpuset.MustParse("4-7,10-11"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topology := buildCPUTopologyWithL3CacheForTest(2, 1, 2, 4, 2)
+			availableCPUs := topology.CPUDetails.CPUs().Difference(tt.allocatedCPUs)
+			allocatedCPUsDetails := topology.CPUDetails.KeepOnly(tt.allocatedCPUs)
+			result, err := takeCPUs(
+				topology, 1, availableCPUs, allocatedCPUsDetails,
+				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, tt.numaAllocateStrategy, tt.l3CacheAware)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantResult.String(), result.String())
+		})
+	}
+}
+
 func TestCPUSpreadByPCPUs(t *testing.T) {
 	topology := buildCPUTopologyForTest(2, 2, 4, 2)
 	acc := newCPUAccumulator(topology, 1, topology.CPUDetails.CPUs(), nil, 8, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
@@ -347,7 +457,7 @@ func TestTakeSpreadByPCPUs(t *testing.T) {
 			allocatedCPUsDetails := tt.topology.CPUDetails.KeepOnly(tt.allocatedCPUs)
 			result, err := takeCPUs(
 				tt.topology, tt.maxRefCount, availableCPUs, allocatedCPUsDetails,
-				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 			if tt.wantError && err == nil {
 				t.Fatal("expect error but got nil")
 			} else if !tt.wantError && err != nil {
@@ -419,7 +529,7 @@ func TestTakeSpreadByPCPUsWithNUMALeastAllocated(t *testing.T) {
 			allocatedCPUsDetails := tt.topology.CPUDetails.KeepOnly(tt.allocatedCPUs)
 			result, err := takeCPUs(
 				tt.topology, tt.maxRefCount, availableCPUs, allocatedCPUsDetails,
-				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMALeastAllocated)
+				tt.numCPUsNeeded, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMALeastAllocated, false)
 			if tt.wantError && err == nil {
 				t.Fatal("expect error but got nil")
 			} else if !tt.wantError && err != nil {
@@ -544,7 +654,7 @@ func TestTakeCPUsWithExclusivePolicy(t *testing.T) {
 
 			result, err := takeCPUs(
 				tt.topology, tt.maxRefCount, availableCPUs, allocatedCPUsDetails,
-				tt.numCPUsNeeded, tt.bindPolicy, tt.exclusivePolicy, schedulingconfig.NUMAMostAllocated)
+				tt.numCPUsNeeded, tt.bindPolicy, tt.exclusivePolicy, schedulingconfig.NUMAMostAllocated, false)
 			if tt.wantError && err == nil {
 				t.Fatal("expect error but got nil")
 			} else if !tt.wantError && err != nil {
@@ -572,7 +682,7 @@ func TestTakeCPUsWithMaxRefCount(t *testing.T) {
 	availableCPUs, allocatedCPUsDetails := allocationState.getAvailableCPUs(cpuTopology, 2, cpuset.NewCPUSet(), cpuset.NewCPUSet())
 	result, err := takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
-		4, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+		4, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.True(t, result.Equals(cpuset.MustParse("0-3")))
 	assert.NoError(t, err)
 	allocationState.addCPUs(cpuTopology, podUID, result, schedulingconfig.CPUExclusivePolicyPCPULevel)
@@ -582,7 +692,7 @@ func TestTakeCPUsWithMaxRefCount(t *testing.T) {
 	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, cpuset.NewCPUSet(), cpuset.NewCPUSet())
 	result, err = takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
-		5, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+		5, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.True(t, result.Equals(cpuset.MustParse("0,4-7")))
 	assert.NoError(t, err)
 	allocationState.addCPUs(cpuTopology, podUID, result, schedulingconfig.CPUExclusivePolicyPCPULevel)
@@ -592,7 +702,7 @@ func TestTakeCPUsWithMaxRefCount(t *testing.T) {
 	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, cpuset.NewCPUSet(), cpuset.NewCPUSet())
 	result, err = takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
-		4, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+		4, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.True(t, result.Equals(cpuset.MustParse("2-5")))
 	assert.NoError(t, err)
 	allocationState.addCPUs(cpuTopology, podUID, result, schedulingconfig.CPUExclusivePolicyPCPULevel)
@@ -613,7 +723,7 @@ func TestTakeCPUsSortByRefCount(t *testing.T) {
 	availableCPUs, allocatedCPUsDetails := allocationState.getAvailableCPUs(cpuTopology, 2, cpuset.NewCPUSet(), cpuset.NewCPUSet())
 	result, err := takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
-		16, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+		16, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.True(t, result.Equals(cpuset.MustParse("0,2,4,6,8,10,12,14,16,18,20,22,24,26,28,30")))
 	assert.NoError(t, err)
 	allocationState.addCPUs(cpuTopology, podUID, result, schedulingconfig.CPUExclusivePolicyPCPULevel)
@@ -623,7 +733,7 @@ func TestTakeCPUsSortByRefCount(t *testing.T) {
 	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, cpuset.NewCPUSet(), cpuset.NewCPUSet())
 	result, err = takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
-		16, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+		16, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.True(t, result.Equals(cpuset.MustParse("0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15")))
 	assert.NoError(t, err)
 	allocationState.addCPUs(cpuTopology, podUID, result, schedulingconfig.CPUExclusivePolicyPCPULevel)
@@ -633,7 +743,7 @@ func TestTakeCPUsSortByRefCount(t *testing.T) {
 	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, cpuset.NewCPUSet(), cpuset.NewCPUSet())
 	result, err = takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
-		16, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+		16, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.True(t, result.Equals(cpuset.MustParse("1,3,5,7,9,11,13,15,17,19,21,23,25,27,29,31")))
 	assert.NoError(t, err)
 	allocationState.addCPUs(cpuTopology, podUID, result, schedulingconfig.CPUExclusivePolicyPCPULevel)
@@ -643,7 +753,7 @@ func TestTakeCPUsSortByRefCount(t *testing.T) {
 	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, cpuset.NewCPUSet(), cpuset.NewCPUSet())
 	result, err = takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
-		16, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+		16, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.True(t, result.Equals(cpuset.MustParse("16-31")))
 	assert.NoError(t, err)
 	allocationState.addCPUs(cpuTopology, podUID, result, schedulingconfig.CPUExclusivePolicyPCPULevel)
@@ -694,7 +804,7 @@ func BenchmarkTakeCPUsWithSameCoreFirst(b *testing.B) {
 		b.Run(tt.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				_, err := takeCPUs(
-					topology, 1, cpus, nil, tt.numCPUsNeeded, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+					topology, 1, cpus, nil, tt.numCPUsNeeded, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -746,7 +856,7 @@ func BenchmarkTakeCPUsWithSpread(b *testing.B) {
 		b.Run(tt.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				_, err := takeCPUs(
-					topology, 1, cpus, nil, tt.numCPUsNeeded, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+					topology, 1, cpus, nil, tt.numCPUsNeeded, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -758,20 +868,20 @@ func BenchmarkTakeCPUsWithSpread(b *testing.B) {
 func TestTakePreferredCPUs(t *testing.T) {
 	topology := buildCPUTopologyForTest(2, 1, 16, 2)
 	cpus := topology.CPUDetails.CPUs()
-	result, err := takeCPUs(topology, 1, cpus, nil, 2, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+	result, err := takeCPUs(topology, 1, cpus, nil, 2, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.NoError(t, err)
 	assert.Equal(t, []int{0, 2}, result.ToSlice())
 
-	result, err = takePreferredCPUs(topology, 1, cpus, cpuset.NewCPUSet(0, 2), nil, 2, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+	result, err = takePreferredCPUs(topology, 1, cpus, cpuset.NewCPUSet(0, 2), nil, 2, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.NoError(t, err)
 	assert.Equal(t, []int{0, 2}, result.ToSlice())
 
-	result, err = takePreferredCPUs(topology, 1, cpus.Difference(result), cpuset.NewCPUSet(), nil, 2, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+	result, err = takePreferredCPUs(topology, 1, cpus.Difference(result), cpuset.NewCPUSet(), nil, 2, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.NoError(t, err)
 	assert.Equal(t, []int{1, 3}, result.ToSlice())
 
 	preferredCPUs := cpuset.NewCPUSet(11, 13, 15, 17)
-	result, err = takePreferredCPUs(topology, 1, cpus, preferredCPUs, nil, 2, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
+	result, err = takePreferredCPUs(topology, 1, cpus, preferredCPUs, nil, 2, schedulingconfig.CPUBindPolicySpreadByPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated, false)
 	assert.NoError(t, err)
 	assert.Equal(t, []int{11, 13}, result.ToSlice())
 }
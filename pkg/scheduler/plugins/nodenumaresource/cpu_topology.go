@@ -23,31 +23,42 @@ import (
 
 // CPUTopology contains details of node cpu
 type CPUTopology struct {
-	NumCPUs    int        `json:"numCPUs"`
-	NumCores   int        `json:"numCores"`
-	NumNodes   int        `json:"numNodes"`
-	NumSockets int        `json:"numSockets"`
-	CPUDetails CPUDetails `json:"cpuDetails"`
+	NumCPUs     int        `json:"numCPUs"`
+	NumCores    int        `json:"numCores"`
+	NumL3Caches int        `json:"numL3Caches"`
+	NumNodes    int        `json:"numNodes"`
+	NumSockets  int        `json:"numSockets"`
+	CPUDetails  CPUDetails `json:"cpuDetails"`
 }
 
 type CPUTopologyBuilder struct {
 	topologyTracker map[int] /*socket*/ map[int] /*node*/ map[int] /*core*/ struct{}
+	l3CacheTracker  map[int] /*l3Cache*/ struct{}
 	topology        CPUTopology
 }
 
 func NewCPUTopologyBuilder() *CPUTopologyBuilder {
 	return &CPUTopologyBuilder{
 		topologyTracker: map[int]map[int]map[int]struct{}{},
+		l3CacheTracker:  map[int]struct{}{},
 	}
 }
 
 func (b *CPUTopologyBuilder) AddCPUInfo(socketID, nodeID, coreID, cpuID int) *CPUTopologyBuilder {
+	return b.AddCPUInfoWithL3Cache(socketID, nodeID, 0, coreID, cpuID)
+}
+
+// AddCPUInfoWithL3Cache adds the CPU with the L3 Cache it belongs to.
+// The L3 Cache is tracked per NUMA Node, so a node without L3 Cache info is treated as one L3 Cache.
+func (b *CPUTopologyBuilder) AddCPUInfoWithL3Cache(socketID, nodeID, l3CacheID, coreID, cpuID int) *CPUTopologyBuilder {
 	coreID = socketID<<16 | coreID
+	l3CacheID = nodeID<<16 | l3CacheID
 	cpuInfo := &CPUInfo{
-		CPUID:    cpuID,
-		CoreID:   coreID,
-		NodeID:   nodeID,
-		SocketID: socketID,
+		CPUID:     cpuID,
+		CoreID:    coreID,
+		L3CacheID: l3CacheID,
+		NodeID:    nodeID,
+		SocketID:  socketID,
 	}
 	if b.topology.CPUDetails == nil {
 		b.topology.CPUDetails = NewCPUDetails()
@@ -65,6 +76,10 @@ func (b *CPUTopologyBuilder) AddCPUInfo(socketID, nodeID, coreID, cpuID int) *CP
 		b.topology.NumCores++
 		b.topologyTracker[cpuInfo.SocketID][nodeID][coreID] = struct{}{}
 	}
+	if _, ok := b.l3CacheTracker[l3CacheID]; !ok {
+		b.topology.NumL3Caches++
+		b.l3CacheTracker[l3CacheID] = struct{}{}
+	}
 	b.topology.NumCPUs = len(b.topology.CPUDetails)
 	return b
 }
@@ -86,6 +101,19 @@ func (topo *CPUTopology) CPUsPerCore() int {
 	return topo.NumCPUs / topo.NumCores
 }
 
+// CPUsPerL3Cache returns the number of logical CPUs are associated with each L3 Cache.
+func (topo *CPUTopology) CPUsPerL3Cache() int {
+	if topo.NumL3Caches == 0 {
+		return 0
+	}
+	return topo.NumCPUs / topo.NumL3Caches
+}
+
+// HasFinerL3Caches checks if the NUMA Nodes are split into multiple L3 Caches.
+func (topo *CPUTopology) HasFinerL3Caches() bool {
+	return topo.NumL3Caches > topo.NumNodes
+}
+
 // CPUsPerSocket returns the number of logical CPUs are associated with each socket.
 func (topo *CPUTopology) CPUsPerSocket() int {
 	if topo.NumSockets == 0 {
@@ -110,10 +138,11 @@ func NewCPUDetails() CPUDetails {
 	return CPUDetails{}
 }
 
-// CPUInfo contains the NUMA, socket, and core IDs associated with a CPU.
+// CPUInfo contains the NUMA, socket, L3 Cache and core IDs associated with a CPU.
 type CPUInfo struct {
 	CPUID           int                                 `json:"cpuID"`
 	CoreID          int                                 `json:"coreID"`
+	L3CacheID       int                                 `json:"l3CacheID"`
 	NodeID          int                                 `json:"nodeID"`
 	SocketID        int                                 `json:"socketID"`
 	RefCount        int                                 `json:"refCount"`
@@ -66,6 +66,7 @@ var (
 
 	_ frameworkext.ReservationRestorePlugin    = &Plugin{}
 	_ frameworkext.ReservationPreBindPlugin    = &Plugin{}
+	_ frameworkext.ReservationResizePlugin     = &Plugin{}
 	_ topologymanager.NUMATopologyHintProvider = &Plugin{}
 )
 
@@ -506,6 +507,7 @@ func (p *Plugin) getResourceOptions(cycleState *framework.CycleState, state *pre
 		reusableResources:     reusableResources,
 		hint:                  affinity,
 		topologyOptions:       topologyOptions,
+		l3CacheAware:          IsL3CacheAwareCPUAllocationEnabled(p.pluginArgs),
 	}
 	return options, nil
 }
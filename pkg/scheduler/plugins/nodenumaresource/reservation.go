@@ -18,11 +18,17 @@ package nodenumaresource
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
@@ -120,3 +126,191 @@ func (p *Plugin) FinalRestoreReservation(ctx context.Context, cycleState *framew
 	state.nodeToState = nodeToStates
 	return nil
 }
+
+// ResizeReservation shrinks or grows the CPUs bound by the reservation according to the desired allocatable.
+// When shrinking, the CPUs allocated by the owner Pods are kept. When growing, the free CPUs in the
+// NUMA Nodes of the reservation are preferred.
+func (p *Plugin) ResizeReservation(ctx context.Context, cycleState *framework.CycleState, reservation *schedulingv1alpha1.Reservation, reservationInfo *frameworkext.ReservationInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	resourceStatus, err := extension.GetResourceStatus(reservation.Annotations)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if cpus.IsEmpty() {
+		return nil
+	}
+	if len(resourceStatus.NUMANodeResources) > 0 {
+		oldMemory, newMemory := reservationInfo.Allocatable[corev1.ResourceMemory], reservation.Status.Allocatable[corev1.ResourceMemory]
+		if oldMemory.Cmp(newMemory) != 0 {
+			return framework.NewStatus(framework.Unschedulable, "resizing the memory of NUMA-aware reservation is not supported")
+		}
+	}
+
+	cpuQuantity := reservation.Status.Allocatable[corev1.ResourceCPU]
+	if cpuQuantity.MilliValue()%1000 != 0 {
+		return framework.NewStatus(framework.Unschedulable, "the CPUs of reservation with bound CPUs must be integer")
+	}
+	numCPUs := int(cpuQuantity.MilliValue() / 1000)
+	if numCPUs == cpus.Size() {
+		return nil
+	}
+
+	node := nodeInfo.Node()
+	topologyOptions := p.topologyOptionsManager.GetTopologyOptions(node.Name)
+	if topologyOptions.CPUTopology == nil {
+		return framework.NewStatus(framework.Unschedulable, ErrNotFoundCPUTopology)
+	}
+	if !topologyOptions.CPUTopology.IsValid() {
+		return framework.NewStatus(framework.Unschedulable, ErrInvalidCPUTopology)
+	}
+
+	if numCPUs < cpus.Size() {
+		cpus, err = p.shrinkReservedCPUs(node, cpus, numCPUs, reservation, reservationInfo, topologyOptions)
+	} else {
+		cpus, err = p.growReservedCPUs(node, cpus, numCPUs, reservation, topologyOptions)
+	}
+	if err != nil {
+		return framework.NewStatus(framework.Unschedulable, err.Error())
+	}
+
+	resourceStatus.CPUSet = cpus.String()
+	if len(resourceStatus.NUMANodeResources) > 0 {
+		resourceStatus.NUMANodeResources = resizeNUMANodeCPUs(resourceStatus.NUMANodeResources, cpus, topologyOptions.CPUTopology)
+	}
+	if err := extension.SetResourceStatus(reservation, resourceStatus); err != nil {
+		return framework.AsStatus(err)
+	}
+	return nil
+}
+
+// shrinkReservedCPUs keeps the CPUs allocated by the owners, and picks the rest from the unused CPUs of the
+// reservation with its CPU bind policy, preferring the NUMA Nodes where the owners are.
+func (p *Plugin) shrinkReservedCPUs(node *corev1.Node, cpus cpuset.CPUSet, numCPUs int, reservation *schedulingv1alpha1.Reservation, reservationInfo *frameworkext.ReservationInfo, topologyOptions TopologyOptions) (cpuset.CPUSet, error) {
+	used := cpuset.NewCPUSet()
+	for _, pod := range reservationInfo.AssignedPods {
+		podCPUs, ok := p.resourceManager.GetAllocatedCPUSet(node.Name, pod.UID)
+		if ok {
+			used = used.Union(podCPUs)
+		}
+	}
+	used = used.Intersection(cpus)
+	if used.Size() > numCPUs {
+		return cpus, fmt.Errorf("can not shrink the CPUs which have been allocated by owners")
+	}
+	numCPUsNeeded := numCPUs - used.Size()
+	if numCPUsNeeded == 0 {
+		return used, nil
+	}
+
+	resourceSpec, cpuBindPolicy, err := p.getReservationCPUBindPolicy(node, reservation)
+	if err != nil {
+		return cpus, err
+	}
+	cpuTopology := topologyOptions.CPUTopology
+	numaNodes := cpuTopology.CPUDetails.KeepOnly(used).NUMANodes().ToSlice()
+	preferredCPUs := cpuTopology.CPUDetails.CPUsInNUMANodes(numaNodes...)
+	// the unused CPUs of the reservation are all available to itself
+	kept, err := takePreferredCPUs(
+		cpuTopology,
+		topologyOptions.MaxRefCount,
+		cpus.Difference(used),
+		preferredCPUs,
+		nil,
+		numCPUsNeeded,
+		cpuBindPolicy,
+		resourceSpec.PreferredCPUExclusivePolicy,
+		GetNUMAAllocateStrategy(node, GetDefaultNUMAAllocateStrategy(p.pluginArgs)),
+		IsL3CacheAwareCPUAllocationEnabled(p.pluginArgs),
+	)
+	if err != nil {
+		return cpus, err
+	}
+	return used.Union(kept), nil
+}
+
+func (p *Plugin) growReservedCPUs(node *corev1.Node, cpus cpuset.CPUSet, numCPUs int, reservation *schedulingv1alpha1.Reservation, topologyOptions TopologyOptions) (cpuset.CPUSet, error) {
+	resourceSpec, cpuBindPolicy, err := p.getReservationCPUBindPolicy(node, reservation)
+	if err != nil {
+		return cpus, err
+	}
+	cpuTopology := topologyOptions.CPUTopology
+	numaNodes := cpuTopology.CPUDetails.KeepOnly(cpus).NUMANodes().ToSlice()
+	preferredCPUs := cpuTopology.CPUDetails.CPUsInNUMANodes(numaNodes...)
+
+	availableCPUs, allocatedCPUs, err := p.resourceManager.GetAvailableCPUs(node.Name, cpuset.NewCPUSet())
+	if err != nil {
+		return cpus, err
+	}
+	availableCPUs = availableCPUs.Difference(cpus)
+	numCPUsNeeded := numCPUs - cpus.Size()
+	if availableCPUs.Size() < numCPUsNeeded {
+		return cpus, errors.New("not enough cpus available to satisfy request")
+	}
+	increased, err := takePreferredCPUs(
+		cpuTopology,
+		topologyOptions.MaxRefCount,
+		availableCPUs,
+		preferredCPUs,
+		allocatedCPUs,
+		numCPUsNeeded,
+		cpuBindPolicy,
+		resourceSpec.PreferredCPUExclusivePolicy,
+		GetNUMAAllocateStrategy(node, GetDefaultNUMAAllocateStrategy(p.pluginArgs)),
+		IsL3CacheAwareCPUAllocationEnabled(p.pluginArgs),
+	)
+	if err != nil {
+		return cpus, err
+	}
+	return cpus.Union(increased), nil
+}
+
+func (p *Plugin) getReservationCPUBindPolicy(node *corev1.Node, reservation *schedulingv1alpha1.Reservation) (*extension.ResourceSpec, schedulingconfig.CPUBindPolicy, error) {
+	resourceSpec, err := extension.GetResourceSpec(reservation.Annotations)
+	if err != nil {
+		return nil, "", err
+	}
+	cpuBindPolicy, err := p.getPreferredCPUBindPolicy(node, schedulingconfig.CPUBindPolicy(resourceSpec.PreferredCPUBindPolicy))
+	if err != nil {
+		return nil, "", err
+	}
+	return resourceSpec, cpuBindPolicy, nil
+}
+
+// resizeNUMANodeCPUs updates the CPU resources of the NUMA Nodes according to the resized CPUs.
+func resizeNUMANodeCPUs(numaNodeResources []extension.NUMANodeResource, cpus cpuset.CPUSet, cpuTopology *CPUTopology) []extension.NUMANodeResource {
+	cpuDetails := cpuTopology.CPUDetails.KeepOnly(cpus)
+	var result []extension.NUMANodeResource
+	visited := map[int32]bool{}
+	for _, numaNodeRes := range numaNodeResources {
+		visited[numaNodeRes.Node] = true
+		numCPUs := cpuDetails.CPUsInNUMANodes(int(numaNodeRes.Node)).Size()
+		resources := numaNodeRes.Resources.DeepCopy()
+		if resources == nil {
+			resources = corev1.ResourceList{}
+		}
+		if numCPUs > 0 {
+			resources[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(numCPUs*1000), resource.DecimalSI)
+		} else {
+			delete(resources, corev1.ResourceCPU)
+		}
+		if len(resources) == 0 {
+			continue
+		}
+		result = append(result, extension.NUMANodeResource{Node: numaNodeRes.Node, Resources: resources})
+	}
+	for _, numaNode := range cpuDetails.NUMANodes().ToSlice() {
+		if visited[int32(numaNode)] {
+			continue
+		}
+		result = append(result, extension.NUMANodeResource{
+			Node: int32(numaNode),
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewMilliQuantity(int64(cpuDetails.CPUsInNUMANodes(numaNode).Size()*1000), resource.DecimalSI),
+			},
+		})
+	}
+	return result
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func TestResizeReservation(t *testing.T) {
+	tests := []struct {
+		name          string
+		reservedCPUs  string
+		desiredCPU    string
+		assignedCPUs  []int
+		allocatedCPUs []int
+		wantStatus    bool
+		wantCPUs      func(t *testing.T, cpus cpuset.CPUSet)
+	}{
+		{
+			name:         "reservation without bound CPUs",
+			desiredCPU:   "8",
+			wantStatus:   true,
+			reservedCPUs: "",
+			wantCPUs: func(t *testing.T, cpus cpuset.CPUSet) {
+				assert.True(t, cpus.IsEmpty())
+			},
+		},
+		{
+			name:         "shrink CPUs",
+			reservedCPUs: "0-3",
+			desiredCPU:   "2",
+			wantStatus:   true,
+			wantCPUs: func(t *testing.T, cpus cpuset.CPUSet) {
+				assert.Equal(t, "0-1", cpus.String())
+			},
+		},
+		{
+			name:         "shrink CPUs and keep the CPUs allocated by owners",
+			reservedCPUs: "0-3",
+			desiredCPU:   "2",
+			assignedCPUs: []int{2, 3},
+			wantStatus:   true,
+			wantCPUs: func(t *testing.T, cpus cpuset.CPUSet) {
+				assert.Equal(t, "2-3", cpus.String())
+			},
+		},
+		{
+			name:         "shrink CPUs and keep the full physical cores",
+			reservedCPUs: "1-4",
+			desiredCPU:   "2",
+			wantStatus:   true,
+			wantCPUs: func(t *testing.T, cpus cpuset.CPUSet) {
+				assert.Equal(t, "2-3", cpus.String())
+			},
+		},
+		{
+			name:         "shrink CPUs and keep the NUMA Node of owners",
+			reservedCPUs: "4-11",
+			desiredCPU:   "4",
+			assignedCPUs: []int{8, 9},
+			wantStatus:   true,
+			wantCPUs: func(t *testing.T, cpus cpuset.CPUSet) {
+				assert.Equal(t, "8-11", cpus.String())
+			},
+		},
+		{
+			name:         "failed to shrink the CPUs allocated by owners",
+			reservedCPUs: "0-3",
+			desiredCPU:   "2",
+			assignedCPUs: []int{1, 2, 3},
+			wantStatus:   false,
+		},
+		{
+			name:         "failed to resize with non-integer CPUs",
+			reservedCPUs: "0-3",
+			desiredCPU:   "2500m",
+			wantStatus:   false,
+		},
+		{
+			name:          "grow CPUs",
+			reservedCPUs:  "0-3",
+			desiredCPU:    "6",
+			allocatedCPUs: []int{4, 5},
+			wantStatus:    true,
+			wantCPUs: func(t *testing.T, cpus cpuset.CPUSet) {
+				assert.Equal(t, 6, cpus.Size())
+				assert.True(t, cpuset.NewCPUSet(0, 1, 2, 3).IsSubsetOf(cpus))
+				assert.False(t, cpus.Contains(4))
+				assert.False(t, cpus.Contains(5))
+			},
+		},
+		{
+			name:          "failed to grow CPUs with insufficient CPUs",
+			reservedCPUs:  "0-3",
+			desiredCPU:    "16",
+			allocatedCPUs: []int{4, 5},
+			wantStatus:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("16"),
+						corev1.ResourceMemory: resource.MustParse("32Gi"),
+					},
+				},
+			}
+			suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+			assert.NoError(t, err)
+			pl := p.(*Plugin)
+			cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+			pl.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *TopologyOptions) {
+				options.CPUTopology = cpuTopology
+			})
+
+			reservation := &schedulingv1alpha1.Reservation{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:         uuid.NewUUID(),
+					Name:        "test-reservation",
+					Annotations: map[string]string{},
+				},
+				Status: schedulingv1alpha1.ReservationStatus{
+					Phase:    schedulingv1alpha1.ReservationAvailable,
+					NodeName: node.Name,
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("4"),
+					},
+				},
+			}
+			if tt.reservedCPUs != "" {
+				assert.NoError(t, extension.SetResourceSpec(reservation, &extension.ResourceSpec{
+					PreferredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs,
+				}))
+				assert.NoError(t, extension.SetResourceStatus(reservation, &extension.ResourceStatus{CPUSet: tt.reservedCPUs}))
+				pl.resourceManager.Update(node.Name, &PodAllocation{
+					UID:    reservation.UID,
+					CPUSet: cpuset.MustParse(tt.reservedCPUs),
+				})
+			}
+			rInfo := frameworkext.NewReservationInfo(reservation)
+			if len(tt.assignedCPUs) > 0 {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: uuid.NewUUID(), Name: "owner", Namespace: "default"}}
+				rInfo.AddAssignedPod(pod)
+				pl.resourceManager.Update(node.Name, &PodAllocation{
+					UID:                pod.UID,
+					CPUSet:             cpuset.NewCPUSet(tt.assignedCPUs...),
+					CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+				})
+			}
+			if len(tt.allocatedCPUs) > 0 {
+				pl.resourceManager.Update(node.Name, &PodAllocation{
+					UID:    uuid.NewUUID(),
+					CPUSet: cpuset.NewCPUSet(tt.allocatedCPUs...),
+				})
+			}
+
+			resized := reservation.DeepCopy()
+			resized.Status.Allocatable[corev1.ResourceCPU] = resource.MustParse(tt.desiredCPU)
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			status := pl.ResizeReservation(context.TODO(), framework.NewCycleState(), resized, rInfo, nodeInfo)
+			assert.Equal(t, tt.wantStatus, status.IsSuccess(), status.Message())
+			if !tt.wantStatus {
+				return
+			}
+			resourceStatus, err := extension.GetResourceStatus(resized.Annotations)
+			assert.NoError(t, err)
+			cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+			assert.NoError(t, err)
+			tt.wantCPUs(t, cpus)
+		})
+	}
+}
+
+func TestResizeNUMANodeCPUs(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+	numaNodeResources := []extension.NUMANodeResource{
+		{
+			Node: 0,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		},
+	}
+	cpus := cpuTopology.CPUDetails.CPUsInNUMANodes(0).Union(cpuset.NewCPUSet(cpuTopology.CPUDetails.CPUsInNUMANodes(1).ToSlice()[:2]...))
+	got := resizeNUMANodeCPUs(numaNodeResources, cpus, cpuTopology)
+	expected := []extension.NUMANodeResource{
+		{
+			Node: 0,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(8000, resource.DecimalSI),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		},
+		{
+			Node: 1,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewMilliQuantity(2000, resource.DecimalSI),
+			},
+		},
+	}
+	assert.Equal(t, expected, got)
+}
@@ -62,6 +62,7 @@ type ResourceOptions struct {
 	hint                  topologymanager.NUMATopologyHint
 	topologyOptions       TopologyOptions
 	numaScorer            *resourceAllocationScorer
+	l3CacheAware          bool
 }
 
 type resourceManager struct {
@@ -317,6 +318,7 @@ func (c *resourceManager) allocateCPUSet(node *corev1.Node, pod *corev1.Pod, all
 				options.cpuBindPolicy,
 				options.cpuExclusivePolicy,
 				numaAllocateStrategy,
+				options.l3CacheAware,
 			)
 			if err != nil {
 				return empty, err
@@ -342,6 +344,7 @@ func (c *resourceManager) allocateCPUSet(node *corev1.Node, pod *corev1.Pod, all
 			options.cpuBindPolicy,
 			options.cpuExclusivePolicy,
 			numaAllocateStrategy,
+			options.l3CacheAware,
 		)
 		if err != nil {
 			return empty, err
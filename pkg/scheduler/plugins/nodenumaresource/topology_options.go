@@ -133,7 +133,7 @@ func NewTopologyOptions(nrt *nrtv1alpha1.NodeResourceTopology) TopologyOptions {
 		}
 	}
 
-	policy := convertToNUMATopologyPolicy(nrt)
+	policy := extension.GetNUMATopologyPolicyFromNRT(nrt)
 	numaNodeResources := extractNUMANodeResources(nrt)
 
 	amplificationRatios, err := extension.GetNodeResourceAmplificationRatios(nrt.Annotations)
@@ -173,7 +173,7 @@ func getPodAllocsCPUSet(podCPUAllocs extension.PodCPUAllocs) cpuset.CPUSet {
 func convertCPUTopology(reportedCPUTopology *extension.CPUTopology) *CPUTopology {
 	builder := NewCPUTopologyBuilder()
 	for _, info := range reportedCPUTopology.Detail {
-		builder.AddCPUInfo(int(info.Socket), int(info.Node), int(info.Core), int(info.ID))
+		builder.AddCPUInfoWithL3Cache(int(info.Socket), int(info.Node), int(info.L3), int(info.Core), int(info.ID))
 	}
 	return builder.Result()
 }
@@ -210,20 +210,6 @@ func extractNUMANodeResources(nrt *nrtv1alpha1.NodeResourceTopology) []NUMANodeR
 	return numaNodeResources
 }
 
-func convertToNUMATopologyPolicy(nrt *nrtv1alpha1.NodeResourceTopology) extension.NUMATopologyPolicy {
-	for _, policy := range nrt.TopologyPolicies {
-		switch nrtv1alpha1.TopologyManagerPolicy(policy) {
-		case nrtv1alpha1.BestEffort:
-			return extension.NUMATopologyPolicyBestEffort
-		case nrtv1alpha1.Restricted:
-			return extension.NUMATopologyPolicyRestricted
-		case nrtv1alpha1.SingleNUMANodePodLevel:
-			return extension.NUMATopologyPolicySingleNUMANode
-		}
-	}
-	return extension.NUMATopologyPolicyNone
-}
-
 func (opts *TopologyOptions) getNUMANodes() []int {
 	if len(opts.NUMANodeResources) == 0 {
 		return nil
@@ -31,6 +31,11 @@ func GetDefaultNUMAAllocateStrategy(pluginArgs *schedulingconfig.NodeNUMAResourc
 	return numaAllocateStrategy
 }
 
+// IsL3CacheAwareCPUAllocationEnabled checks if the FullPCPUs should be packed within one L3 Cache first.
+func IsL3CacheAwareCPUAllocationEnabled(pluginArgs *schedulingconfig.NodeNUMAResourceArgs) bool {
+	return pluginArgs != nil && pluginArgs.EnableL3CacheAwareCPUAllocation != nil && *pluginArgs.EnableL3CacheAwareCPUAllocation
+}
+
 func GetNUMAAllocateStrategy(node *corev1.Node, defaultNUMAtAllocateStrategy schedulingconfig.NUMAAllocateStrategy) schedulingconfig.NUMAAllocateStrategy {
 	numaAllocateStrategy := defaultNUMAtAllocateStrategy
 	if val := schedulingconfig.NUMAAllocateStrategy(node.Labels[extension.LabelNodeNUMAAllocateStrategy]); val != "" {
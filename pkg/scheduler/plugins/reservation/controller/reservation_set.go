@@ -0,0 +1,360 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	schedulinglister "github.com/koordinator-sh/koordinator/pkg/client/listers/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
+)
+
+const (
+	ReservationSetName = "reservationSetController"
+
+	// maxReservationsPerSync limits the number of Reservations created or deleted in one sync
+	// to avoid flooding the apiserver when the replicas is changed dramatically.
+	maxReservationsPerSync = 100
+)
+
+var reservationSetKind = schedulingv1alpha1.SchemeGroupVersion.WithKind("ReservationSet")
+
+var _ frameworkext.Controller = &ReservationSetController{}
+
+// ReservationSetController maintains the number of active Reservations of each ReservationSet.
+// The failed or expired Reservations are deleted and replaced with new ones.
+type ReservationSetController struct {
+	koordSharedInformerFactory koordinatorinformers.SharedInformerFactory
+	reservationLister          schedulinglister.ReservationLister
+	reservationSetLister       schedulinglister.ReservationSetLister
+	koordClientSet             koordclientset.Interface
+	queue                      workqueue.RateLimitingInterface
+	expectations               kubecontroller.ControllerExpectationsInterface
+	numWorker                  int
+}
+
+func NewReservationSetController(
+	koordSharedInformerFactory koordinatorinformers.SharedInformerFactory,
+	koordClientSet koordclientset.Interface,
+	numWorker int,
+) *ReservationSetController {
+	reservationLister := koordSharedInformerFactory.Scheduling().V1alpha1().Reservations().Lister()
+	reservationSetLister := koordSharedInformerFactory.Scheduling().V1alpha1().ReservationSets().Lister()
+
+	rateLimiter := workqueue.DefaultControllerRateLimiter()
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter, ReservationSetName)
+
+	if numWorker <= 0 {
+		numWorker = 1
+	}
+	return &ReservationSetController{
+		koordSharedInformerFactory: koordSharedInformerFactory,
+		reservationLister:          reservationLister,
+		reservationSetLister:       reservationSetLister,
+		koordClientSet:             koordClientSet,
+		queue:                      queue,
+		expectations:               kubecontroller.NewControllerExpectations(),
+		numWorker:                  numWorker,
+	}
+}
+
+func (c *ReservationSetController) Name() string { return ReservationSetName }
+
+func (c *ReservationSetController) Start() {
+	reservationSetInformer := c.koordSharedInformerFactory.Scheduling().V1alpha1().ReservationSets().Informer()
+	reservationSetInformer.AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.onReservationSetAdd,
+		UpdateFunc: c.onReservationSetUpdate,
+		DeleteFunc: c.onReservationSetDelete,
+	})
+
+	reservationInformer := c.koordSharedInformerFactory.Scheduling().V1alpha1().Reservations().Informer()
+	reservationInformer.AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.onReservationAdd,
+		UpdateFunc: c.onReservationUpdate,
+		DeleteFunc: c.onReservationDelete,
+	})
+
+	done := context.Background().Done()
+	c.koordSharedInformerFactory.Start(done)
+	c.koordSharedInformerFactory.WaitForCacheSync(done)
+
+	for i := 0; i < c.numWorker; i++ {
+		go c.worker()
+	}
+}
+
+func (c *ReservationSetController) worker() {
+	for c.processNextWorkItem() {
+
+	}
+}
+
+func (c *ReservationSetController) processNextWorkItem() bool {
+	req, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(req)
+
+	err := c.sync(req.(string))
+	if err != nil {
+		c.queue.AddRateLimited(req)
+		klog.ErrorS(err, "failed to sync ReservationSet", "reservationSet", req)
+	} else {
+		c.queue.Forget(req)
+	}
+	return true
+}
+
+func (c *ReservationSetController) sync(name string) error {
+	reservationSet, err := c.reservationSetLister.Get(name)
+	if errors.IsNotFound(err) {
+		// the Reservations are cleaned by the garbage collector through the owner references
+		c.expectations.DeleteExpectations(name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if reservationSet.DeletionTimestamp != nil {
+		return nil
+	}
+
+	reservations, err := c.getOwnedReservations(reservationSet)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if c.expectations.SatisfiedExpectations(name) {
+		errs = append(errs, c.manageReservations(reservationSet, reservations)...)
+	}
+
+	if err := c.syncReservationSetStatus(reservationSet, reservations); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (c *ReservationSetController) getOwnedReservations(reservationSet *schedulingv1alpha1.ReservationSet) ([]*schedulingv1alpha1.Reservation, error) {
+	selector := labels.SelectorFromSet(labels.Set{apiext.LabelReservationSetName: reservationSet.Name})
+	reservations, err := c.reservationLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]*schedulingv1alpha1.Reservation, 0, len(reservations))
+	for _, r := range reservations {
+		controllerRef := metav1.GetControllerOf(r)
+		if controllerRef != nil && controllerRef.UID == reservationSet.UID {
+			owned = append(owned, r)
+		}
+	}
+	return owned, nil
+}
+
+// manageReservations replaces the failed Reservations and scales the active Reservations to the desired replicas.
+func (c *ReservationSetController) manageReservations(reservationSet *schedulingv1alpha1.ReservationSet, reservations []*schedulingv1alpha1.Reservation) []error {
+	var active, failed []*schedulingv1alpha1.Reservation
+	for _, r := range reservations {
+		if r.DeletionTimestamp != nil {
+			continue
+		}
+		if reservationutil.IsReservationFailed(r) {
+			failed = append(failed, r)
+		} else if isReservationAlive(r) {
+			active = append(active, r)
+		}
+	}
+
+	diff := int(getReservationSetReplicas(reservationSet)) - len(active)
+	var toDelete []*schedulingv1alpha1.Reservation
+	if diff < 0 {
+		sort.Sort(activeReservationsToDelete(active))
+		toDelete = active[:-diff]
+	}
+	toDelete = append(toDelete, failed...)
+	if len(toDelete) > maxReservationsPerSync {
+		toDelete = toDelete[:maxReservationsPerSync]
+	}
+	if diff > maxReservationsPerSync {
+		diff = maxReservationsPerSync
+	}
+
+	key := reservationSet.Name
+	if diff > 0 {
+		_ = c.expectations.ExpectCreations(key, diff)
+	}
+	if len(toDelete) > 0 {
+		_ = c.expectations.ExpectDeletions(key, len(toDelete))
+	}
+
+	var errs []error
+	for i := 0; i < diff; i++ {
+		reservation := newReservationFromSet(reservationSet)
+		_, err := c.koordClientSet.SchedulingV1alpha1().Reservations().Create(context.TODO(), reservation, metav1.CreateOptions{})
+		if err != nil {
+			c.expectations.CreationObserved(key)
+			errs = append(errs, err)
+			continue
+		}
+		klog.V(4).InfoS("Successfully create Reservation for ReservationSet", "reservationSet", klog.KObj(reservationSet), "reservation", klog.KObj(reservation))
+	}
+	for _, r := range toDelete {
+		err := c.koordClientSet.SchedulingV1alpha1().Reservations().Delete(context.TODO(), r.Name, metav1.DeleteOptions{})
+		if err != nil {
+			c.expectations.DeletionObserved(key)
+			if !errors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		klog.V(4).InfoS("Successfully delete Reservation of ReservationSet", "reservationSet", klog.KObj(reservationSet), "reservation", klog.KObj(r), "phase", r.Status.Phase)
+	}
+	return errs
+}
+
+func (c *ReservationSetController) syncReservationSetStatus(reservationSet *schedulingv1alpha1.ReservationSet, reservations []*schedulingv1alpha1.Reservation) error {
+	status := calculateReservationSetStatus(reservationSet, reservations)
+	if status == reservationSet.Status {
+		return nil
+	}
+	reservationSet = reservationSet.DeepCopy()
+	reservationSet.Status = status
+	_, err := c.koordClientSet.SchedulingV1alpha1().ReservationSets().UpdateStatus(context.TODO(), reservationSet, metav1.UpdateOptions{})
+	if err == nil {
+		klog.V(4).InfoS("Successfully sync ReservationSet status", "reservationSet", klog.KObj(reservationSet))
+	}
+	return err
+}
+
+func calculateReservationSetStatus(reservationSet *schedulingv1alpha1.ReservationSet, reservations []*schedulingv1alpha1.Reservation) schedulingv1alpha1.ReservationSetStatus {
+	status := schedulingv1alpha1.ReservationSetStatus{
+		ObservedGeneration: reservationSet.Generation,
+	}
+	for _, r := range reservations {
+		if r.DeletionTimestamp != nil {
+			continue
+		}
+		if isReservationAlive(r) {
+			status.Replicas++
+		}
+		if reservationutil.IsReservationAvailable(r) {
+			status.AvailableReplicas++
+		}
+		if !reservationutil.IsReservationFailed(r) && len(r.Status.CurrentOwners) > 0 {
+			status.AllocatedReplicas++
+		}
+	}
+	return status
+}
+
+func newReservationFromSet(reservationSet *schedulingv1alpha1.ReservationSet) *schedulingv1alpha1.Reservation {
+	template := reservationSet.Spec.Template.DeepCopy()
+	reservation := &schedulingv1alpha1.Reservation{
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+	reservation.Name = fmt.Sprintf("%s-%s", reservationSet.Name, utilrand.String(5))
+	reservation.GenerateName = ""
+	reservation.Namespace = ""
+	reservation.ResourceVersion = ""
+	reservation.UID = ""
+	if reservation.Labels == nil {
+		reservation.Labels = map[string]string{}
+	}
+	reservation.Labels[apiext.LabelReservationSetName] = reservationSet.Name
+	reservation.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(reservationSet, reservationSetKind)}
+
+	if len(reservationSet.Spec.Owners) > 0 {
+		reservation.Spec.Owners = make([]schedulingv1alpha1.ReservationOwner, 0, len(reservationSet.Spec.Owners))
+		for i := range reservationSet.Spec.Owners {
+			reservation.Spec.Owners = append(reservation.Spec.Owners, *reservationSet.Spec.Owners[i].DeepCopy())
+		}
+	}
+
+	// The reserve pod inherits the labels of the Reservation,
+	// so the defaulted spreading constraints select the reserve pods of the set.
+	if len(reservationSet.Spec.TopologySpreadConstraints) > 0 {
+		if reservation.Spec.Template == nil {
+			reservation.Spec.Template = &corev1.PodTemplateSpec{}
+		}
+		for i := range reservationSet.Spec.TopologySpreadConstraints {
+			constraint := reservationSet.Spec.TopologySpreadConstraints[i].DeepCopy()
+			if constraint.LabelSelector == nil {
+				constraint.LabelSelector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{apiext.LabelReservationSetName: reservationSet.Name},
+				}
+			}
+			reservation.Spec.Template.Spec.TopologySpreadConstraints = append(reservation.Spec.Template.Spec.TopologySpreadConstraints, *constraint)
+		}
+	}
+	return reservation
+}
+
+func getReservationSetReplicas(reservationSet *schedulingv1alpha1.ReservationSet) int32 {
+	if reservationSet.Spec.Replicas == nil {
+		return 1
+	}
+	return *reservationSet.Spec.Replicas
+}
+
+// isReservationAlive checks if the Reservation is reserving resources or waiting to reserve,
+// i.e. it is neither failed nor consumed by the owners.
+func isReservationAlive(r *schedulingv1alpha1.Reservation) bool {
+	return !reservationutil.IsReservationFailed(r) && !reservationutil.IsReservationSucceeded(r)
+}
+
+// activeReservationsToDelete sorts the Reservations in the preferred order to delete when scaling down.
+// The unscheduled Reservations are deleted before the scheduled, the unallocated before the allocated,
+// and the newer before the older.
+type activeReservationsToDelete []*schedulingv1alpha1.Reservation
+
+func (s activeReservationsToDelete) Len() int      { return len(s) }
+func (s activeReservationsToDelete) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s activeReservationsToDelete) Less(i, j int) bool {
+	iScheduled, jScheduled := reservationutil.GetReservationNodeName(s[i]) != "", reservationutil.GetReservationNodeName(s[j]) != ""
+	if iScheduled != jScheduled {
+		return !iScheduled
+	}
+	iAllocated, jAllocated := len(s[i].Status.CurrentOwners) > 0, len(s[j].Status.CurrentOwners) > 0
+	if iAllocated != jAllocated {
+		return !iAllocated
+	}
+	if !s[i].CreationTimestamp.Equal(&s[j].CreationTimestamp) {
+		return s[j].CreationTimestamp.Before(&s[i].CreationTimestamp)
+	}
+	return s[i].Name > s[j].Name
+}
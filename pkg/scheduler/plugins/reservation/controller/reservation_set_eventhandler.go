@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func (c *ReservationSetController) onReservationSetAdd(obj interface{}) {
+	reservationSet, _ := obj.(*schedulingv1alpha1.ReservationSet)
+	if reservationSet != nil {
+		c.queue.Add(reservationSet.Name)
+	}
+}
+
+func (c *ReservationSetController) onReservationSetUpdate(oldObj, newObj interface{}) {
+	oldReservationSet, _ := oldObj.(*schedulingv1alpha1.ReservationSet)
+	newReservationSet, _ := newObj.(*schedulingv1alpha1.ReservationSet)
+	if oldReservationSet != nil && newReservationSet != nil {
+		if oldReservationSet.Generation != newReservationSet.Generation {
+			c.queue.Add(newReservationSet.Name)
+		}
+	}
+}
+
+func (c *ReservationSetController) onReservationSetDelete(obj interface{}) {
+	var reservationSet *schedulingv1alpha1.ReservationSet
+	switch t := obj.(type) {
+	case *schedulingv1alpha1.ReservationSet:
+		reservationSet = t
+	case cache.DeletedFinalStateUnknown:
+		reservationSet, _ = t.Obj.(*schedulingv1alpha1.ReservationSet)
+	}
+	if reservationSet != nil {
+		c.expectations.DeleteExpectations(reservationSet.Name)
+	}
+}
+
+func (c *ReservationSetController) onReservationAdd(obj interface{}) {
+	reservation, _ := obj.(*schedulingv1alpha1.Reservation)
+	if reservation == nil {
+		return
+	}
+	if name := getReservationSetName(reservation); name != "" {
+		c.expectations.CreationObserved(name)
+		c.queue.Add(name)
+	}
+}
+
+func (c *ReservationSetController) onReservationUpdate(oldObj, newObj interface{}) {
+	oldReservation, _ := oldObj.(*schedulingv1alpha1.Reservation)
+	newReservation, _ := newObj.(*schedulingv1alpha1.Reservation)
+	if oldReservation == nil || newReservation == nil {
+		return
+	}
+	if oldReservation.ResourceVersion == newReservation.ResourceVersion {
+		return
+	}
+	if name := getReservationSetName(newReservation); name != "" {
+		c.queue.Add(name)
+	}
+}
+
+func (c *ReservationSetController) onReservationDelete(obj interface{}) {
+	var reservation *schedulingv1alpha1.Reservation
+	switch t := obj.(type) {
+	case *schedulingv1alpha1.Reservation:
+		reservation = t
+	case cache.DeletedFinalStateUnknown:
+		reservation, _ = t.Obj.(*schedulingv1alpha1.Reservation)
+	}
+	if reservation == nil {
+		return
+	}
+	if name := getReservationSetName(reservation); name != "" {
+		c.expectations.DeletionObserved(name)
+		c.queue.Add(name)
+	}
+}
+
+func getReservationSetName(reservation *schedulingv1alpha1.Reservation) string {
+	controllerRef := metav1.GetControllerOf(reservation)
+	if controllerRef == nil || controllerRef.Kind != reservationSetKind.Kind ||
+		controllerRef.APIVersion != reservationSetKind.GroupVersion().String() {
+		return ""
+	}
+	return controllerRef.Name
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+)
+
+func newTestReservationSet(name string, replicas int32) *schedulingv1alpha1.ReservationSet {
+	return &schedulingv1alpha1.ReservationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:        uuid.NewUUID(),
+			Name:       name,
+			Generation: 1,
+		},
+		Spec: schedulingv1alpha1.ReservationSetSpec{
+			Replicas: pointer.Int32(replicas),
+			Template: schedulingv1alpha1.ReservationTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "test"},
+				},
+				Spec: schedulingv1alpha1.ReservationSpec{
+					Template: &corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+						},
+					},
+					Owners: []schedulingv1alpha1.ReservationOwner{
+						{
+							LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestReservationOfSet(reservationSet *schedulingv1alpha1.ReservationSet, name string, phase schedulingv1alpha1.ReservationPhase, nodeName string, creationTime time.Time) *schedulingv1alpha1.Reservation {
+	return &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:               uuid.NewUUID(),
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(creationTime),
+			Labels: map[string]string{
+				apiext.LabelReservationSetName: reservationSet.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(reservationSet, reservationSetKind)},
+		},
+		Status: schedulingv1alpha1.ReservationStatus{
+			Phase:    phase,
+			NodeName: nodeName,
+		},
+	}
+}
+
+func TestReservationSetSync(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		replicas     int32
+		reservations func(set *schedulingv1alpha1.ReservationSet) []*schedulingv1alpha1.Reservation
+		wantActive   int
+		wantDeleted  []string
+		wantStatus   schedulingv1alpha1.ReservationSetStatus
+	}{
+		{
+			name:       "create reservations",
+			replicas:   3,
+			wantActive: 3,
+			wantStatus: schedulingv1alpha1.ReservationSetStatus{ObservedGeneration: 1},
+		},
+		{
+			name:     "replace failed reservation",
+			replicas: 2,
+			reservations: func(set *schedulingv1alpha1.ReservationSet) []*schedulingv1alpha1.Reservation {
+				return []*schedulingv1alpha1.Reservation{
+					newTestReservationOfSet(set, "r-available", schedulingv1alpha1.ReservationAvailable, "node-1", now),
+					newTestReservationOfSet(set, "r-failed", schedulingv1alpha1.ReservationFailed, "node-1", now),
+				}
+			},
+			wantActive:  2,
+			wantDeleted: []string{"r-failed"},
+			wantStatus: schedulingv1alpha1.ReservationSetStatus{
+				ObservedGeneration: 1,
+				Replicas:           1,
+				AvailableReplicas:  1,
+			},
+		},
+		{
+			name:     "scale down prefers pending and unallocated reservations",
+			replicas: 1,
+			reservations: func(set *schedulingv1alpha1.ReservationSet) []*schedulingv1alpha1.Reservation {
+				allocated := newTestReservationOfSet(set, "r-allocated", schedulingv1alpha1.ReservationAvailable, "node-1", now)
+				allocated.Status.CurrentOwners = []corev1.ObjectReference{{Name: "pod-1", Namespace: "default"}}
+				return []*schedulingv1alpha1.Reservation{
+					allocated,
+					newTestReservationOfSet(set, "r-available", schedulingv1alpha1.ReservationAvailable, "node-2", now.Add(-time.Minute)),
+					newTestReservationOfSet(set, "r-pending", schedulingv1alpha1.ReservationPending, "", now.Add(-2*time.Minute)),
+				}
+			},
+			wantActive:  1,
+			wantDeleted: []string{"r-available", "r-pending"},
+			wantStatus: schedulingv1alpha1.ReservationSetStatus{
+				ObservedGeneration: 1,
+				Replicas:           3,
+				AvailableReplicas:  2,
+				AllocatedReplicas:  1,
+			},
+		},
+		{
+			name:     "succeeded reservations are replaced but counted as allocated",
+			replicas: 1,
+			reservations: func(set *schedulingv1alpha1.ReservationSet) []*schedulingv1alpha1.Reservation {
+				succeeded := newTestReservationOfSet(set, "r-succeeded", schedulingv1alpha1.ReservationSucceeded, "node-1", now)
+				succeeded.Status.CurrentOwners = []corev1.ObjectReference{{Name: "pod-1", Namespace: "default"}}
+				return []*schedulingv1alpha1.Reservation{succeeded}
+			},
+			wantActive: 1,
+			wantStatus: schedulingv1alpha1.ReservationSetStatus{
+				ObservedGeneration: 1,
+				AllocatedReplicas:  1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeKoordClientSet := koordfake.NewSimpleClientset()
+			koordSharedInformerFactory := koordinformers.NewSharedInformerFactory(fakeKoordClientSet, 0)
+
+			reservationSet := newTestReservationSet("test-set", tt.replicas)
+			_, err := fakeKoordClientSet.SchedulingV1alpha1().ReservationSets().Create(context.TODO(), reservationSet, metav1.CreateOptions{})
+			assert.NoError(t, err)
+			var reservations []*schedulingv1alpha1.Reservation
+			if tt.reservations != nil {
+				reservations = tt.reservations(reservationSet)
+			}
+			for _, r := range reservations {
+				_, err = fakeKoordClientSet.SchedulingV1alpha1().Reservations().Create(context.TODO(), r, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+
+			c := NewReservationSetController(koordSharedInformerFactory, fakeKoordClientSet, 1)
+			koordSharedInformerFactory.Start(nil)
+			koordSharedInformerFactory.WaitForCacheSync(nil)
+
+			assert.NoError(t, c.sync(reservationSet.Name))
+
+			reservationList, err := fakeKoordClientSet.SchedulingV1alpha1().Reservations().List(context.TODO(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			existing := map[string]bool{}
+			active := 0
+			for i := range reservationList.Items {
+				r := &reservationList.Items[i]
+				existing[r.Name] = true
+				assert.Equal(t, reservationSet.Name, getReservationSetName(r))
+				assert.Equal(t, reservationSet.Name, r.Labels[apiext.LabelReservationSetName])
+				if isReservationAlive(r) {
+					active++
+				}
+			}
+			assert.Equal(t, tt.wantActive, active)
+			for _, name := range tt.wantDeleted {
+				assert.False(t, existing[name], "reservation %s should be deleted", name)
+			}
+
+			got, err := fakeKoordClientSet.SchedulingV1alpha1().ReservationSets().Get(context.TODO(), reservationSet.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+		})
+	}
+}
+
+func TestNewReservationFromSet(t *testing.T) {
+	reservationSet := newTestReservationSet("test-set", 1)
+	reservationSet.Spec.Owners = []schedulingv1alpha1.ReservationOwner{
+		{
+			Controller: &schedulingv1alpha1.ReservationControllerReference{
+				OwnerReference: metav1.OwnerReference{Kind: "Deployment", Name: "test"},
+				Namespace:      "default",
+			},
+		},
+	}
+	reservationSet.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+		},
+		{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelHostname,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+		},
+	}
+
+	reservation := newReservationFromSet(reservationSet)
+	assert.Contains(t, reservation.Name, "test-set-")
+	assert.Equal(t, map[string]string{"app": "test", apiext.LabelReservationSetName: "test-set"}, reservation.Labels)
+	assert.Equal(t, reservationSet.Name, getReservationSetName(reservation))
+	assert.Equal(t, reservationSet.Spec.Owners, reservation.Spec.Owners)
+	expectedConstraints := []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{apiext.LabelReservationSetName: "test-set"}},
+		},
+		reservationSet.Spec.TopologySpreadConstraints[1],
+	}
+	assert.Equal(t, expectedConstraints, reservation.Spec.Template.Spec.TopologySpreadConstraints)
+	// the template of the set is not changed
+	assert.Nil(t, reservationSet.Spec.Template.Spec.Template.Spec.TopologySpreadConstraints)
+}
+
+func TestActiveReservationsToDelete(t *testing.T) {
+	now := time.Now()
+	reservationSet := newTestReservationSet("test-set", 1)
+	allocated := newTestReservationOfSet(reservationSet, "allocated", schedulingv1alpha1.ReservationAvailable, "node-1", now)
+	allocated.Status.CurrentOwners = []corev1.ObjectReference{{Name: "pod-1"}}
+	reservations := []*schedulingv1alpha1.Reservation{
+		allocated,
+		newTestReservationOfSet(reservationSet, "available-old", schedulingv1alpha1.ReservationAvailable, "node-1", now.Add(-time.Hour)),
+		newTestReservationOfSet(reservationSet, "available-new", schedulingv1alpha1.ReservationAvailable, "node-1", now),
+		newTestReservationOfSet(reservationSet, "pending", schedulingv1alpha1.ReservationPending, "", now.Add(-time.Hour)),
+	}
+	sort.Sort(activeReservationsToDelete(reservations))
+	var names []string
+	for _, r := range reservations {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"pending", "available-new", "available-old", "allocated"}, names)
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
 	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -35,6 +36,7 @@ import (
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	clientschedulingv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
 	listerschedulingv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/listers/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/reservation/controller"
@@ -84,6 +86,7 @@ type Plugin struct {
 	rLister          listerschedulingv1alpha1.ReservationLister
 	client           clientschedulingv1alpha1.SchedulingV1alpha1Interface
 	reservationCache *reservationCache
+	resizeTracker    *resizeTracker
 }
 
 func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
@@ -114,6 +117,7 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		rLister:          reservationLister,
 		client:           extendedHandle.KoordinatorClientSet().SchedulingV1alpha1(),
 		reservationCache: cache,
+		resizeTracker:    newResizeTracker(),
 	}
 
 	return p, nil
@@ -127,7 +131,18 @@ func (pl *Plugin) NewControllers() ([]frameworkext.Controller, error) {
 		pl.handle.KoordinatorSharedInformerFactory(),
 		pl.handle.KoordinatorClientSet(),
 		1)
-	return []frameworkext.Controller{reservationController}, nil
+	controllers := []frameworkext.Controller{reservationController}
+	if k8sfeature.DefaultFeatureGate.Enabled(features.ReservationSet) {
+		reservationSetController := controller.NewReservationSetController(
+			pl.handle.KoordinatorSharedInformerFactory(),
+			pl.handle.KoordinatorClientSet(),
+			1)
+		controllers = append(controllers, reservationSetController)
+	}
+	if k8sfeature.DefaultFeatureGate.Enabled(features.ReservationResize) {
+		controllers = append(controllers, newResizeController(pl.handle, pl.reservationCache, pl.resizeTracker))
+	}
+	return controllers, nil
 }
 
 func (pl *Plugin) EventsToRegister() []framework.ClusterEvent {
@@ -535,7 +550,15 @@ func (pl *Plugin) FilterReservation(ctx context.Context, cycleState *framework.C
 }
 
 func (pl *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	// The resizeController grows or shrinks reservations concurrently, so the reserved resources are checked
+	// against the resizing reservations under the same lock.
+	pl.resizeTracker.lock.Lock()
+	defer pl.resizeTracker.lock.Unlock()
+
 	if reservationutil.IsReservePod(pod) {
+		if status := pl.checkPendingResizes(nodeName, nil); !status.IsSuccess() {
+			return status
+		}
 		rName := reservationutil.GetReservationNameFromReservePod(pod)
 		assumedReservation, err := pl.rLister.Get(rName)
 		if err != nil {
@@ -557,7 +580,7 @@ func (pl *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 		}
 		if nominatedReservation == nil {
 			klog.V(5).Infof("Skip reserve with reservation since there are no matched reservations, pod %v, node: %v", klog.KObj(pod), nodeName)
-			return nil
+			return pl.checkPendingResizes(nodeName, nil)
 		}
 		frameworkext.SetNominatedReservation(cycleState, map[string]*frameworkext.ReservationInfo{nodeName: nominatedReservation})
 	}
@@ -567,6 +590,10 @@ func (pl *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 		klog.ErrorS(err, "Failed to assume pod in reservationCache", "pod", klog.KObj(pod), "reservation", klog.KObj(nominatedReservation))
 		return framework.AsStatus(err)
 	}
+	if status := pl.checkPendingResizes(nodeName, pl.reservationCache.getReservationInfoByUID(nominatedReservation.UID())); !status.IsSuccess() {
+		pl.reservationCache.forgetPod(nominatedReservation.UID(), pod)
+		return status
+	}
 
 	state := getStateData(cycleState)
 	state.assumed = nominatedReservation.Clone()
@@ -0,0 +1,380 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	clientschedulingv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
+	listerschedulingv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/listers/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
+)
+
+const (
+	ResizeControllerName = "reservationResizeController"
+
+	ReasonReservationResized      = "Resized"
+	ReasonReservationResizeFailed = "ResizeFailed"
+)
+
+var _ frameworkext.Controller = &resizeController{}
+
+// resizeController shrinks or grows the Available Reservations in place according to the
+// AnnotationReservationResizeSpec. The increased resources must fit in the free resources of the node,
+// and the fine-grained resources such as CPU Cores and GPU Devices are adjusted by the ReservationResizePlugins.
+type resizeController struct {
+	handle           frameworkext.ExtendedHandle
+	rLister          listerschedulingv1alpha1.ReservationLister
+	client           clientschedulingv1alpha1.SchedulingV1alpha1Interface
+	reservationCache *reservationCache
+	resizeTracker    *resizeTracker
+	queue            workqueue.RateLimitingInterface
+}
+
+func newResizeController(handle frameworkext.ExtendedHandle, reservationCache *reservationCache, resizeTracker *resizeTracker) *resizeController {
+	return &resizeController{
+		handle:           handle,
+		rLister:          handle.KoordinatorSharedInformerFactory().Scheduling().V1alpha1().Reservations().Lister(),
+		client:           handle.KoordinatorClientSet().SchedulingV1alpha1(),
+		reservationCache: reservationCache,
+		resizeTracker:    resizeTracker,
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ResizeControllerName),
+	}
+}
+
+// resizeTracker records the desired allocatable of the reservations being resized until the scheduler cache
+// observes them. The Reserve of pods and the resizeController check the resources under its lock, so that
+// the resized reservations and the concurrently reserved pods never overcommit the node or the reservation.
+type resizeTracker struct {
+	lock    sync.Mutex
+	pending map[string]map[types.UID]corev1.ResourceList
+}
+
+func newResizeTracker() *resizeTracker {
+	return &resizeTracker{
+		pending: map[string]map[types.UID]corev1.ResourceList{},
+	}
+}
+
+func (t *resizeTracker) add(nodeName string, uid types.UID, desired corev1.ResourceList) {
+	pending := t.pending[nodeName]
+	if pending == nil {
+		pending = map[types.UID]corev1.ResourceList{}
+		t.pending[nodeName] = pending
+	}
+	pending[uid] = desired
+}
+
+func (t *resizeTracker) remove(nodeName string, uid types.UID) {
+	pending := t.pending[nodeName]
+	delete(pending, uid)
+	if len(pending) == 0 {
+		delete(t.pending, nodeName)
+	}
+}
+
+func (t *resizeTracker) getDesired(nodeName string, uid types.UID) corev1.ResourceList {
+	return t.pending[nodeName][uid]
+}
+
+func (t *resizeTracker) hasPending(nodeName string) bool {
+	return len(t.pending[nodeName]) > 0
+}
+
+// pendingIncreased returns the resources that the resizing reservations on the node except the given one
+// will increase, and forgets the reservations whose desired allocatable have been applied to the reserve pods.
+func (t *resizeTracker) pendingIncreased(nodeName string, nodeInfo *framework.NodeInfo, except types.UID) corev1.ResourceList {
+	pending := t.pending[nodeName]
+	if len(pending) == 0 {
+		return nil
+	}
+	reservePods := map[types.UID]*corev1.Pod{}
+	for _, podInfo := range nodeInfo.Pods {
+		if _, ok := pending[podInfo.Pod.UID]; ok {
+			reservePods[podInfo.Pod.UID] = podInfo.Pod
+		}
+	}
+	increased := corev1.ResourceList{}
+	for uid, desired := range pending {
+		reservePod := reservePods[uid]
+		if reservePod == nil {
+			t.remove(nodeName, uid)
+			continue
+		}
+		current := util.GetPodRequest(reservePod)
+		if quotav1.Equals(quotav1.Mask(current, quotav1.ResourceNames(desired)), desired) {
+			t.remove(nodeName, uid)
+			continue
+		}
+		if uid != except {
+			increased = quotav1.Add(increased, quotav1.SubtractWithNonNegativeResult(desired, current))
+		}
+	}
+	return increased
+}
+
+func (c *resizeController) Name() string { return ResizeControllerName }
+
+func (c *resizeController) Start() {
+	koordSharedInformerFactory := c.handle.KoordinatorSharedInformerFactory()
+	reservationInformer := koordSharedInformerFactory.Scheduling().V1alpha1().Reservations().Informer()
+	reservationInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			r, ok := obj.(*schedulingv1alpha1.Reservation)
+			return ok && needResize(r)
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: c.enqueue,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				c.enqueue(newObj)
+			},
+		},
+	})
+
+	done := context.Background().Done()
+	koordSharedInformerFactory.Start(done)
+	koordSharedInformerFactory.WaitForCacheSync(done)
+
+	go c.worker()
+}
+
+func (c *resizeController) enqueue(obj interface{}) {
+	r, ok := obj.(*schedulingv1alpha1.Reservation)
+	if !ok {
+		return
+	}
+	c.queue.Add(r.Name)
+}
+
+func (c *resizeController) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *resizeController) processNextWorkItem() bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(key.(string)); err != nil {
+		c.queue.AddRateLimited(key)
+		klog.ErrorS(err, "Failed to resize reservation", "reservation", key)
+	} else {
+		c.queue.Forget(key)
+	}
+	return true
+}
+
+func needResize(r *schedulingv1alpha1.Reservation) bool {
+	if !reservationutil.IsReservationAvailable(r) {
+		return false
+	}
+	_, ok := r.Annotations[apiext.AnnotationReservationResizeSpec]
+	return ok
+}
+
+// getDesiredAllocatable returns the desired allocatable of the reservation, or nil if it does not need to resize.
+func getDesiredAllocatable(r *schedulingv1alpha1.Reservation) (corev1.ResourceList, error) {
+	resize, err := apiext.GetReservationResizeSpec(r.Annotations)
+	if err != nil || resize == nil || len(resize.Resources) == 0 {
+		return nil, err
+	}
+	desired := r.Status.Allocatable.DeepCopy()
+	if desired == nil {
+		desired = corev1.ResourceList{}
+	}
+	for resourceName, quantity := range resize.Resources {
+		if quantity.IsZero() {
+			delete(desired, resourceName)
+			continue
+		}
+		desired[resourceName] = quantity
+	}
+	if quotav1.Equals(desired, r.Status.Allocatable) {
+		return nil, nil
+	}
+	return desired, nil
+}
+
+func (c *resizeController) sync(name string) error {
+	reservation, err := c.rLister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !needResize(reservation) {
+		return nil
+	}
+	desired, err := getDesiredAllocatable(reservation)
+	if err != nil {
+		c.handle.EventRecorder().Eventf(reservation, nil, corev1.EventTypeWarning, ReasonReservationResizeFailed, "Resizing", "Invalid resize annotation: %v", err)
+		return nil
+	}
+	if desired == nil {
+		return nil
+	}
+
+	nodeName := reservation.Status.NodeName
+	resized, err := c.assumeResize(reservation, desired)
+	if resized == nil || err != nil {
+		return err
+	}
+
+	// The fine-grained allocations recorded in annotations are updated before the allocatable, so that
+	// a retry after a failed status update can reuse the allocations and only update the allocatable.
+	if !apiequality.Semantic.DeepEqual(resized.Annotations, reservation.Annotations) {
+		allocatable := resized.Status.Allocatable
+		resized, err = c.client.Reservations().Update(context.TODO(), resized, metav1.UpdateOptions{})
+		if err != nil {
+			c.forgetResize(reservation)
+			return err
+		}
+		resized = resized.DeepCopy()
+		resized.Status.Allocatable = allocatable
+	}
+	_, err = c.client.Reservations().UpdateStatus(context.TODO(), resized, metav1.UpdateOptions{})
+	if err != nil {
+		c.forgetResize(reservation)
+		return err
+	}
+	klog.V(4).InfoS("Successfully resized reservation", "reservation", klog.KObj(reservation), "node", nodeName)
+	c.handle.EventRecorder().Eventf(reservation, nil, corev1.EventTypeNormal, ReasonReservationResized, "Resizing",
+		"Resized the allocatable from %v to %v", util.DumpJSON(reservation.Status.Allocatable), util.DumpJSON(resized.Status.Allocatable))
+	return nil
+}
+
+// assumeResize checks the resized reservation against the owners and the live resources of the node under the lock
+// of the resizeTracker, and records the desired allocatable so that the Reserve of pods respects it before
+// the scheduler cache observes the updated reservation. It returns nil if the reservation can not be resized.
+func (c *resizeController) assumeResize(reservation *schedulingv1alpha1.Reservation, desired corev1.ResourceList) (*schedulingv1alpha1.Reservation, error) {
+	c.resizeTracker.lock.Lock()
+	defer c.resizeTracker.lock.Unlock()
+
+	rInfo := c.reservationCache.getReservationInfoByUID(reservation.UID)
+	if rInfo == nil {
+		return nil, fmt.Errorf("reservation %s is not found in cache", reservation.Name)
+	}
+	if satisfied, exceeded := quotav1.LessThanOrEqual(quotav1.Mask(rInfo.Allocated, rInfo.ResourceNames), desired); !satisfied {
+		c.handle.EventRecorder().Eventf(reservation, nil, corev1.EventTypeWarning, ReasonReservationResizeFailed, "Resizing",
+			"Can not shrink the resources %v which have been allocated by owners", exceeded)
+		return nil, nil
+	}
+
+	nodeName := reservation.Status.NodeName
+	nodeInfo, err := c.handle.Scheduler().GetCache().GetNodeInfo(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	increased := quotav1.SubtractWithNonNegativeResult(desired, reservation.Status.Allocatable)
+	increased = quotav1.Add(increased, c.resizeTracker.pendingIncreased(nodeName, nodeInfo, reservation.UID))
+	if !quotav1.IsZero(increased) {
+		if insufficient := insufficientNodeResources(framework.NewResource(increased), nodeInfo); len(insufficient) > 0 {
+			c.handle.EventRecorder().Eventf(reservation, nil, corev1.EventTypeWarning, ReasonReservationResizeFailed, "Resizing",
+				"Insufficient resources %v on node %s", insufficient, nodeName)
+			return nil, fmt.Errorf("insufficient resources %v on node %s", insufficient, nodeName)
+		}
+	}
+
+	resized := reservation.DeepCopy()
+	resized.Status.Allocatable = desired
+	if extender, ok := c.handle.(frameworkext.FrameworkExtender); ok {
+		status := extender.RunReservationResizePlugins(context.TODO(), framework.NewCycleState(), resized, rInfo, nodeInfo)
+		if !status.IsSuccess() {
+			c.handle.EventRecorder().Eventf(reservation, nil, corev1.EventTypeWarning, ReasonReservationResizeFailed, "Resizing", status.Message())
+			return nil, status.AsError()
+		}
+	}
+	c.resizeTracker.add(nodeName, reservation.UID, desired)
+	return resized, nil
+}
+
+func (c *resizeController) forgetResize(reservation *schedulingv1alpha1.Reservation) {
+	c.resizeTracker.lock.Lock()
+	defer c.resizeTracker.lock.Unlock()
+	c.resizeTracker.remove(reservation.Status.NodeName, reservation.UID)
+}
+
+// checkPendingResizes checks whether the pods reserved on the node overcommit the resources of the resizing
+// reservations. It must be called with the lock of the resizeTracker held, after the pod has been assumed.
+func (pl *Plugin) checkPendingResizes(nodeName string, rInfo *frameworkext.ReservationInfo) *framework.Status {
+	if !pl.resizeTracker.hasPending(nodeName) {
+		return nil
+	}
+	if rInfo != nil {
+		desired := pl.resizeTracker.getDesired(nodeName, rInfo.UID())
+		if desired == nil {
+			return nil
+		}
+		if satisfied, exceeded := quotav1.LessThanOrEqual(quotav1.Mask(rInfo.Allocated, quotav1.ResourceNames(desired)), desired); !satisfied {
+			return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Insufficient resources %v in resizing reservation", exceeded))
+		}
+		return nil
+	}
+	nodeInfo, err := pl.handle.Scheduler().GetCache().GetNodeInfo(nodeName)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	increased := pl.resizeTracker.pendingIncreased(nodeName, nodeInfo, "")
+	if quotav1.IsZero(increased) {
+		return nil
+	}
+	if insufficient := insufficientNodeResources(framework.NewResource(increased), nodeInfo); len(insufficient) > 0 {
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Insufficient resources %v on node with resizing reservations", insufficient))
+	}
+	return nil
+}
+
+// insufficientNodeResources returns the names of resources whose requests exceed the free resources of the node.
+func insufficientNodeResources(request *framework.Resource, nodeInfo *framework.NodeInfo) []corev1.ResourceName {
+	var insufficient []corev1.ResourceName
+	if request.MilliCPU > nodeInfo.Allocatable.MilliCPU-nodeInfo.Requested.MilliCPU {
+		insufficient = append(insufficient, corev1.ResourceCPU)
+	}
+	if request.Memory > nodeInfo.Allocatable.Memory-nodeInfo.Requested.Memory {
+		insufficient = append(insufficient, corev1.ResourceMemory)
+	}
+	if request.EphemeralStorage > nodeInfo.Allocatable.EphemeralStorage-nodeInfo.Requested.EphemeralStorage {
+		insufficient = append(insufficient, corev1.ResourceEphemeralStorage)
+	}
+	for rName, rQuant := range request.ScalarResources {
+		if rQuant > nodeInfo.Allocatable.ScalarResources[rName]-nodeInfo.Requested.ScalarResources[rName] {
+			insufficient = append(insufficient, rName)
+		}
+	}
+	return insufficient
+}
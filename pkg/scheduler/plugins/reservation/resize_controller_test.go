@@ -0,0 +1,317 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
+)
+
+func TestGetDesiredAllocatable(t *testing.T) {
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	tests := []struct {
+		name    string
+		resize  string
+		want    corev1.ResourceList
+		wantErr bool
+	}{
+		{
+			name: "no resize annotation",
+		},
+		{
+			name:    "invalid resize annotation",
+			resize:  "invalid",
+			wantErr: true,
+		},
+		{
+			name:   "same as allocatable",
+			resize: `{"resources":{"cpu":"4"}}`,
+		},
+		{
+			name:   "shrink cpu",
+			resize: `{"resources":{"cpu":"2"}}`,
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+		{
+			name:   "grow memory and release cpu",
+			resize: `{"resources":{"cpu":"0","memory":"16Gi"}}`,
+			want: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &schedulingv1alpha1.Reservation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-r",
+					Annotations: map[string]string{},
+				},
+				Status: schedulingv1alpha1.ReservationStatus{
+					Phase:       schedulingv1alpha1.ReservationAvailable,
+					Allocatable: allocatable,
+				},
+			}
+			if tt.resize != "" {
+				r.Annotations[apiext.AnnotationReservationResizeSpec] = tt.resize
+			}
+			got, err := getDesiredAllocatable(r)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.True(t, quotav1.Equals(tt.want, got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
+
+func TestResizeControllerSync(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			},
+		},
+	}
+	tests := []struct {
+		name            string
+		resize          *apiext.ReservationResizeSpec
+		allocatedPod    *corev1.Pod
+		wantErr         bool
+		wantAllocatable corev1.ResourceList
+	}{
+		{
+			name: "shrink reservation",
+			resize: &apiext.ReservationResizeSpec{
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			},
+			wantAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+		{
+			name: "grow reservation",
+			resize: &apiext.ReservationResizeSpec{
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+			},
+			wantAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+		{
+			name: "failed to grow reservation with insufficient node resources",
+			resize: &apiext.ReservationResizeSpec{
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("20")},
+			},
+			wantErr: true,
+			wantAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+		{
+			name: "failed to shrink the allocated resources",
+			resize: &apiext.ReservationResizeSpec{
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+			allocatedPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:       uuid.NewUUID(),
+					Name:      "test-pod",
+					Namespace: "default",
+				},
+				Spec: corev1.PodSpec{
+					NodeName: node.Name,
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("2"),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reservation := &schedulingv1alpha1.Reservation{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:         uuid.NewUUID(),
+					Name:        "test-reservation",
+					Annotations: map[string]string{apiext.AnnotationReservationResizeSpec: util.DumpJSON(tt.resize)},
+				},
+				Spec: schedulingv1alpha1.ReservationSpec{
+					Template: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Resources: corev1.ResourceRequirements{
+										Requests: corev1.ResourceList{
+											corev1.ResourceCPU:    resource.MustParse("4"),
+											corev1.ResourceMemory: resource.MustParse("8Gi"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			assert.NoError(t, reservationutil.SetReservationAvailable(reservation, node.Name))
+
+			reservePod := reservationutil.NewReservePod(reservation)
+			suit := newPluginTestSuitWith(t, []*corev1.Pod{reservePod}, []*corev1.Node{node})
+			p, err := suit.pluginFactory()
+			assert.NoError(t, err)
+			pl := p.(*Plugin)
+			nodeInfo := framework.NewNodeInfo(reservePod)
+			nodeInfo.SetNode(node)
+			pl.handle.Scheduler().(*frameworkext.FakeScheduler).NodeInfos[node.Name] = nodeInfo
+			_, err = suit.extenderFactory.KoordinatorClientSet().SchedulingV1alpha1().Reservations().Create(context.TODO(), reservation, metav1.CreateOptions{})
+			assert.NoError(t, err)
+			suit.start()
+			if tt.allocatedPod != nil {
+				assert.NoError(t, pl.reservationCache.addPod(reservation.UID, tt.allocatedPod))
+			}
+
+			c := newResizeController(pl.handle, pl.reservationCache, pl.resizeTracker)
+			err = c.sync(reservation.Name)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+
+			got, err := suit.extenderFactory.KoordinatorClientSet().SchedulingV1alpha1().Reservations().Get(context.TODO(), reservation.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.True(t, quotav1.Equals(tt.wantAllocatable, got.Status.Allocatable), "want %v, got %v", tt.wantAllocatable, got.Status.Allocatable)
+		})
+	}
+}
+
+func TestCheckPendingResizes(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			},
+		},
+	}
+	reservation := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:  uuid.NewUUID(),
+			Name: "test-reservation",
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("4"),
+									corev1.ResourceMemory: resource.MustParse("8Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, reservationutil.SetReservationAvailable(reservation, node.Name))
+	reservePod := reservationutil.NewReservePod(reservation)
+	newPod := func(name, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:       uuid.NewUUID(),
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				NodeName: node.Name,
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse(cpu),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	suit := newPluginTestSuitWith(t, []*corev1.Pod{reservePod}, []*corev1.Node{node})
+	p, err := suit.pluginFactory()
+	assert.NoError(t, err)
+	pl := p.(*Plugin)
+	nodeInfo := framework.NewNodeInfo(reservePod, newPod("pod-1", "8"))
+	nodeInfo.SetNode(node)
+	pl.handle.Scheduler().(*frameworkext.FakeScheduler).NodeInfos[node.Name] = nodeInfo
+
+	desired := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("8"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	pl.resizeTracker.add(node.Name, reservation.UID, desired)
+	assert.True(t, pl.checkPendingResizes(node.Name, nil).IsSuccess())
+
+	// the assumed pod fits in the free resources of the node but not with the growing reservation
+	nodeInfo.AddPod(newPod("pod-2", "2"))
+	status := pl.checkPendingResizes(node.Name, nil)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+
+	// the pending resize is forgotten once the reserve pod in the cache has the desired allocatable
+	resized := reservation.DeepCopy()
+	resized.Status.Allocatable = desired
+	assert.NoError(t, nodeInfo.RemovePod(reservePod))
+	nodeInfo.AddPod(reservationutil.NewReservePod(resized))
+	assert.True(t, pl.checkPendingResizes(node.Name, nil).IsSuccess())
+	assert.False(t, pl.resizeTracker.hasPending(node.Name))
+}
@@ -19,6 +19,7 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"sync"
 
@@ -46,6 +47,9 @@ var _ handler.EventHandler = &ColocationHandlerForConfigMapEvent{}
 
 type ColocationCfgCache interface {
 	GetCfgCopy() *configuration.ColocationCfg
+	// GetCfgCopyForNode returns the config revision which the node should apply, e.g. the canary revision during
+	// the rollout.
+	GetCfgCopyForNode(node *corev1.Node) *configuration.ColocationCfg
 	IsCfgAvailable() bool
 	IsErrorStatus() bool
 }
@@ -55,6 +59,10 @@ type colocationCfgCache struct {
 	colocationCfg configuration.ColocationCfg
 	available     bool
 	errorStatus   bool
+
+	// the states of the staged rollout, which is driven by the nodeslo controller
+	rolloutCfg configuration.RolloutCfg
+	canaryCfg  *configuration.ColocationCfg
 }
 
 type ColocationHandlerForConfigMapEvent struct {
@@ -68,6 +76,7 @@ type ColocationHandlerForConfigMapEvent struct {
 func NewColocationHandlerForConfigMapEvent(client client.Client, initCfg configuration.ColocationCfg, recorder record.EventRecorder) *ColocationHandlerForConfigMapEvent {
 	colocationHandler := &ColocationHandlerForConfigMapEvent{cfgCache: colocationCfgCache{colocationCfg: initCfg}, Client: client, recorder: recorder}
 	colocationHandler.SyncCacheIfChanged = colocationHandler.syncColocationCfgIfChanged
+	colocationHandler.SyncRolloutIfChanged = colocationHandler.syncColocationRolloutIfChanged
 	colocationHandler.EnqueueRequest = colocationHandler.triggerAllNodeEnqueue
 	return colocationHandler
 }
@@ -81,31 +90,124 @@ func (p *ColocationHandlerForConfigMapEvent) syncColocationCfgIfChanged(configMa
 	return p.syncConfig(configMap)
 }
 
+// syncColocationRolloutIfChanged is a locked version of syncConfig for the changes of the rollout revisions
+func (p *ColocationHandlerForConfigMapEvent) syncColocationRolloutIfChanged(_ *corev1.ConfigMap) bool {
+	p.cfgCache.lock.Lock()
+	defer p.cfgCache.lock.Unlock()
+	if !p.cfgCache.available || !IsRolloutEnabled(&p.cfgCache.rolloutCfg) {
+		return false
+	}
+	changed, _ := p.syncRolloutConfig()
+	return changed
+}
+
 // syncConfig syncs valid colocation config from the configmap request
 func (p *ColocationHandlerForConfigMapEvent) syncConfig(configMap *corev1.ConfigMap) bool {
 	// get co-location config from the configmap
 	// if the configmap does not exist, use the default
 	if configMap == nil {
 		klog.Errorf("configmap is deleted!,use default config")
-		return p.updateCacheIfChanged(sloconfig.NewDefaultColocationCfg(), true)
+		p.cfgCache.rolloutCfg = DefaultRolloutCfg()
+		canaryChanged := p.resetCanaryCfg()
+		return p.updateCacheIfChanged(sloconfig.NewDefaultColocationCfg(), true) || canaryChanged
 	}
 
-	newCfg := &configuration.ColocationCfg{}
+	p.cfgCache.rolloutCfg = CalculateRolloutCfgMerged(p.cfgCache.rolloutCfg, configMap)
+	if IsRolloutEnabled(&p.cfgCache.rolloutCfg) {
+		if changed, ok := p.syncRolloutConfig(); ok {
+			return changed
+		}
+		if p.cfgCache.available {
+			// the config change is applied after the nodeslo controller records its revision
+			klog.V(4).Infof("colocation config is waiting for the slo config revision to roll out")
+			return false
+		}
+	}
+	canaryChanged := p.resetCanaryCfg()
+
 	configStr := configMap.Data[configuration.ColocationConfigKey]
 	if configStr == "" {
 		klog.Warningf("colocation config is empty!,use default config")
-		return p.updateCacheIfChanged(sloconfig.NewDefaultColocationCfg(), false)
+		return p.updateCacheIfChanged(sloconfig.NewDefaultColocationCfg(), false) || canaryChanged
 	}
 
-	err := json.Unmarshal([]byte(configStr), &newCfg)
+	newCfg, err := parseColocationCfg(configStr)
 	if err != nil {
 		//if controller restart ,cache will unavailable, else use old cfg
 		klog.Errorf("syncConfig failed since parse colocation error, use old Cfg ,configmap %s/%s, err: %s",
 			sloconfig.ConfigNameSpace, sloconfig.SLOCtrlConfigMap, err)
 		p.recorder.Eventf(configMap, "Warning", ReasonColocationConfigUnmarshalFailed, "failed to unmarshal colocation config, err: %s", err)
 		p.cfgCache.errorStatus = true
+		return canaryChanged
+	}
+
+	changed := p.updateCacheIfChanged(newCfg, false)
+	return changed || canaryChanged
+}
+
+// syncRolloutConfig applies the stable revision to all nodes and the canary revision to the canary nodes.
+// It returns false if the revisions are not recorded.
+func (p *ColocationHandlerForConfigMapEvent) syncRolloutConfig() (changed bool, ok bool) {
+	configMap := &corev1.ConfigMap{}
+	err := p.Client.Get(context.TODO(), types.NamespacedName{Namespace: sloconfig.ConfigNameSpace, Name: RolloutRevisionsConfigMap}, configMap)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Warningf("failed to get slo config revisions, err: %v", err)
+		}
+		return false, false
+	}
+	status, revisions, err := ParseRolloutRevisions(configMap)
+	if err != nil {
+		klog.Warningf("failed to parse slo config revisions, err: %v", err)
+		return false, false
+	}
+	stableRevision := GetRevision(revisions, status.StableRevision)
+	if stableRevision == nil {
+		return false, false
+	}
+
+	var canaryCfg *configuration.ColocationCfg
+	if canaryRevision := GetRevision(revisions, status.CanaryRevision); status.Phase == RolloutPhaseBaking && canaryRevision != nil {
+		if canaryCfg, err = parseRevisionColocationCfg(canaryRevision); err != nil {
+			klog.Warningf("failed to parse colocation config of the canary revision %v, err: %v", canaryRevision.Revision, err)
+			canaryCfg = nil
+		}
+	}
+	canaryChanged := !reflect.DeepEqual(p.cfgCache.canaryCfg, canaryCfg)
+	p.cfgCache.canaryCfg = canaryCfg
+
+	stableCfg, err := parseRevisionColocationCfg(stableRevision)
+	if err != nil {
+		klog.Errorf("failed to parse colocation config of the stable revision %v, use old Cfg, err: %v", stableRevision.Revision, err)
+		p.cfgCache.errorStatus = true
+		return canaryChanged, true
+	}
+	return p.updateCacheIfChanged(stableCfg, false) || canaryChanged, true
+}
+
+func (p *ColocationHandlerForConfigMapEvent) resetCanaryCfg() bool {
+	if p.cfgCache.canaryCfg == nil {
 		return false
 	}
+	p.cfgCache.canaryCfg = nil
+	return true
+}
+
+func parseRevisionColocationCfg(revision *SLOCfgRevision) (*configuration.ColocationCfg, error) {
+	configStr := revision.Data[configuration.ColocationConfigKey]
+	if configStr == "" {
+		return sloconfig.NewDefaultColocationCfg(), nil
+	}
+	return parseColocationCfg(configStr)
+}
+
+// parseColocationCfg parses the colocation config and merges it with the default config.
+func parseColocationCfg(configStr string) (*configuration.ColocationCfg, error) {
+	newCfg := &configuration.ColocationCfg{}
+	err := json.Unmarshal([]byte(configStr), &newCfg)
+	if err != nil {
+		return nil, err
+	}
 
 	defaultCfg := sloconfig.NewDefaultColocationCfg()
 	// merge default cluster strategy
@@ -114,10 +216,7 @@ func (p *ColocationHandlerForConfigMapEvent) syncConfig(configMap *corev1.Config
 	newCfg.ColocationStrategy = *(mergedInterface.(*configuration.ColocationStrategy))
 
 	if !sloconfig.IsColocationStrategyValid(&newCfg.ColocationStrategy) {
-		//if controller restart ,cache will unavailable, else use old cfg
-		klog.Errorf("syncConfig failed since the cluster config is invalid, %+v", newCfg.ColocationStrategy)
-		p.cfgCache.errorStatus = true
-		return false
+		return nil, fmt.Errorf("the cluster config is invalid, %+v", newCfg.ColocationStrategy)
 	}
 
 	for index, nodeStrategy := range newCfg.NodeConfigs {
@@ -132,9 +231,7 @@ func (p *ColocationHandlerForConfigMapEvent) syncConfig(configMap *corev1.Config
 			newCfg.NodeConfigs[index].ColocationStrategy = newNodeStrategy
 		}
 	}
-
-	changed := p.updateCacheIfChanged(newCfg, false)
-	return changed
+	return newCfg, nil
 }
 
 func (p *ColocationHandlerForConfigMapEvent) updateCacheIfChanged(newCfg *configuration.ColocationCfg, errorStatus bool) bool {
@@ -170,6 +267,15 @@ func (p *ColocationHandlerForConfigMapEvent) GetCfgCopy() *configuration.Colocat
 	return p.cfgCache.colocationCfg.DeepCopy()
 }
 
+func (p *ColocationHandlerForConfigMapEvent) GetCfgCopyForNode(node *corev1.Node) *configuration.ColocationCfg {
+	p.cfgCache.lock.RLock()
+	defer p.cfgCache.lock.RUnlock()
+	if p.cfgCache.canaryCfg != nil && IsCanaryNode(node, &p.cfgCache.rolloutCfg) {
+		return p.cfgCache.canaryCfg.DeepCopy()
+	}
+	return p.cfgCache.colocationCfg.DeepCopy()
+}
+
 func (p *ColocationHandlerForConfigMapEvent) IsErrorStatus() bool {
 	p.cfgCache.lock.RLock()
 	defer p.cfgCache.lock.RUnlock()
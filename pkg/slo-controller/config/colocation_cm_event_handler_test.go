@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,6 +30,7 @@ import (
 
 	"github.com/koordinator-sh/koordinator/apis/configuration"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
 
@@ -776,3 +778,86 @@ func Test_IsCfgAvailable(t *testing.T) {
 		})
 	}
 }
+
+func TestColocationCfgRollout(t *testing.T) {
+	newConfigData := func(cpuReclaimThresholdPercent string) map[string]string {
+		return map[string]string{
+			configuration.ColocationConfigKey: `{"enable":true,"cpuReclaimThresholdPercent":` + cpuReclaimThresholdPercent + `}`,
+		}
+	}
+	sloConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sloconfig.SLOCtrlConfigMap,
+			Namespace: sloconfig.ConfigNameSpace,
+		},
+		Data: newConfigData("70"),
+	}
+	sloConfigMap.Data[configuration.RolloutConfigKey] = `{"enable":true,"canaryNodeSelector":{"matchLabels":{"pool":"canary"}},"canaryPercent":100}`
+	newRevisionsConfigMap := func(status SLOCfgRolloutStatus) *corev1.ConfigMap {
+		revisions := []SLOCfgRevision{
+			{Revision: 1, Data: newConfigData("60")},
+			{Revision: 2, Data: newConfigData("70")},
+		}
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      RolloutRevisionsConfigMap,
+				Namespace: sloconfig.ConfigNameSpace,
+			},
+			Data: map[string]string{
+				RolloutStatusKey:    util.DumpJSON(status),
+				RolloutRevisionsKey: util.DumpJSON(revisions),
+			},
+		}
+	}
+	canaryNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "canary-node",
+			Labels: map[string]string{"pool": "canary"},
+		},
+	}
+	stableNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "stable-node",
+		},
+	}
+	getThreshold := func(p *ColocationHandlerForConfigMapEvent, node *corev1.Node) int64 {
+		return *p.GetCfgCopyForNode(node).CPUReclaimThresholdPercent
+	}
+
+	// the canary revision is applied to the canary nodes
+	revisionsConfigMap := newRevisionsConfigMap(SLOCfgRolloutStatus{
+		Phase:          RolloutPhaseBaking,
+		StableRevision: 1,
+		CanaryRevision: 2,
+	})
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(revisionsConfigMap).Build()
+	p := NewColocationHandlerForConfigMapEvent(fakeClient, *sloconfig.NewDefaultColocationCfg(), &record.FakeRecorder{})
+	assert.True(t, p.syncColocationCfgIfChanged(sloConfigMap))
+	assert.Equal(t, int64(70), getThreshold(p, canaryNode))
+	assert.Equal(t, int64(60), getThreshold(p, stableNode))
+	assert.Equal(t, int64(60), *p.GetCfgCopy().CPUReclaimThresholdPercent)
+
+	// the canary revision progresses to all nodes
+	revisionsConfigMap = newRevisionsConfigMap(SLOCfgRolloutStatus{
+		Phase:          RolloutPhaseStable,
+		StableRevision: 2,
+		CanaryRevision: 2,
+	})
+	assert.NoError(t, fakeClient.Update(context.TODO(), revisionsConfigMap))
+	assert.True(t, p.syncColocationRolloutIfChanged(revisionsConfigMap))
+	assert.Equal(t, int64(70), getThreshold(p, canaryNode))
+	assert.Equal(t, int64(70), getThreshold(p, stableNode))
+	assert.False(t, p.syncColocationRolloutIfChanged(revisionsConfigMap))
+
+	// the config change waits for its revision to be recorded
+	newSLOConfigMap := sloConfigMap.DeepCopy()
+	newSLOConfigMap.Data[configuration.ColocationConfigKey] = newConfigData("80")[configuration.ColocationConfigKey]
+	assert.False(t, p.syncColocationCfgIfChanged(newSLOConfigMap))
+	assert.Equal(t, int64(70), getThreshold(p, canaryNode))
+
+	// apply to all nodes when the rollout is disabled
+	newSLOConfigMap.Data[configuration.RolloutConfigKey] = `{"enable":false}`
+	assert.True(t, p.syncColocationCfgIfChanged(newSLOConfigMap))
+	assert.Equal(t, int64(80), getThreshold(p, canaryNode))
+	assert.Equal(t, int64(80), getThreshold(p, stableNode))
+}
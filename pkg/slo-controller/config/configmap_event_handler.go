@@ -32,6 +32,8 @@ var _ handler.EventHandler = &EnqueueRequestForConfigMap{}
 type EnqueueRequestForConfigMap struct {
 	EnqueueRequest     func(q *workqueue.RateLimitingInterface)
 	SyncCacheIfChanged func(configMap *corev1.ConfigMap) bool
+	// SyncRolloutIfChanged syncs the cache with the revisions of the slo-controller-config if it is set.
+	SyncRolloutIfChanged func(configMap *corev1.ConfigMap) bool
 }
 
 func (p *EnqueueRequestForConfigMap) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
@@ -39,6 +41,12 @@ func (p *EnqueueRequestForConfigMap) Create(evt event.CreateEvent, q workqueue.R
 	if !ok {
 		return
 	}
+	if p.isRolloutRevisions(configMap) {
+		if p.SyncRolloutIfChanged(configMap) {
+			p.EnqueueRequest(&q)
+		}
+		return
+	}
 	if configMap.Namespace != sloconfig.ConfigNameSpace || configMap.Name != sloconfig.SLOCtrlConfigMap {
 		return
 	}
@@ -61,6 +69,12 @@ func (p *EnqueueRequestForConfigMap) Update(evt event.UpdateEvent, q workqueue.R
 	if reflect.DeepEqual(newConfigMap.Data, oldConfigMap.Data) {
 		return
 	}
+	if p.isRolloutRevisions(newConfigMap) {
+		if p.SyncRolloutIfChanged(newConfigMap) {
+			p.EnqueueRequest(&q)
+		}
+		return
+	}
 	if newConfigMap.Namespace != sloconfig.ConfigNameSpace || newConfigMap.Name != sloconfig.SLOCtrlConfigMap {
 		return
 	}
@@ -70,3 +84,8 @@ func (p *EnqueueRequestForConfigMap) Update(evt event.UpdateEvent, q workqueue.R
 
 	p.EnqueueRequest(&q)
 }
+
+func (p *EnqueueRequestForConfigMap) isRolloutRevisions(configMap *corev1.ConfigMap) bool {
+	return p.SyncRolloutIfChanged != nil && configMap.Namespace == sloconfig.ConfigNameSpace &&
+		configMap.Name == RolloutRevisionsConfigMap
+}
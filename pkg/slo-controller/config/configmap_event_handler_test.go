@@ -49,8 +49,9 @@ func enqueueRequest(q *workqueue.RateLimitingInterface) {
 
 func Test_common_Create(t *testing.T) {
 	type args struct {
-		evt                event.CreateEvent
-		cacheChangedReturn func(configMap *corev1.ConfigMap) bool
+		evt                  event.CreateEvent
+		cacheChangedReturn   func(configMap *corev1.ConfigMap) bool
+		rolloutChangedReturn func(configMap *corev1.ConfigMap) bool
 	}
 	type want struct {
 		objs []interface{}
@@ -114,6 +115,42 @@ func Test_common_Create(t *testing.T) {
 				objs: []interface{}{},
 			},
 		},
+		{
+			name: "rollout-revisions-changed",
+			args: args{
+				evt: event.CreateEvent{
+					Object: client.Object(&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: sloconfig.ConfigNameSpace,
+							Name:      RolloutRevisionsConfigMap,
+						},
+					}),
+				},
+				cacheChangedReturn:   cacheChangedFalse,
+				rolloutChangedReturn: cacheChangedTrue,
+			},
+			want: want{
+				objs: []interface{}{
+					reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				},
+			},
+		},
+		{
+			name: "rollout-revisions-not-watched",
+			args: args{
+				evt: event.CreateEvent{
+					Object: client.Object(&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: sloconfig.ConfigNameSpace,
+							Name:      RolloutRevisionsConfigMap,
+						},
+					}),
+				},
+			},
+			want: want{
+				objs: []interface{}{},
+			},
+		},
 		{
 			name: "no-update-1",
 			args: args{
@@ -137,6 +174,7 @@ func Test_common_Create(t *testing.T) {
 			if tt.args.cacheChangedReturn != nil {
 				p.SyncCacheIfChanged = tt.args.cacheChangedReturn
 			}
+			p.SyncRolloutIfChanged = tt.args.rolloutChangedReturn
 			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
 			p.Create(tt.args.evt, q)
 
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// RolloutRevisionsConfigMap keeps the revision history and the rollout status of the slo-controller-config.
+	RolloutRevisionsConfigMap = "slo-controller-config-revisions"
+
+	RolloutStatusKey    = "status"
+	RolloutRevisionsKey = "revisions"
+)
+
+type RolloutPhase string
+
+const (
+	// RolloutPhaseStable means all nodes apply the stable revision.
+	RolloutPhaseStable RolloutPhase = "Stable"
+	// RolloutPhaseBaking means the canary nodes apply the canary revision, and their health is being watched.
+	RolloutPhaseBaking RolloutPhase = "Baking"
+	// RolloutPhaseReverted means the canary revision is reverted since the canary nodes are unhealthy.
+	RolloutPhaseReverted RolloutPhase = "Reverted"
+)
+
+// SLOCfgRevision is a snapshot of the slo-controller-config.
+type SLOCfgRevision struct {
+	Revision   int64       `json:"revision"`
+	Hash       string      `json:"hash"`
+	CreateTime metav1.Time `json:"createTime"`
+	// Data is kept for only the stable and the canary revisions.
+	Data map[string]string `json:"data,omitempty"`
+}
+
+type SLOCfgRolloutStatus struct {
+	Phase           RolloutPhase `json:"phase,omitempty"`
+	StableRevision  int64        `json:"stableRevision,omitempty"`
+	CanaryRevision  int64        `json:"canaryRevision,omitempty"`
+	CanaryStartTime *metav1.Time `json:"canaryStartTime,omitempty"`
+	Message         string       `json:"message,omitempty"`
+}
+
+func DefaultRolloutCfg() configuration.RolloutCfg {
+	return configuration.RolloutCfg{
+		Enable:                   pointer.Bool(false),
+		CanaryPercent:            pointer.Int64(10),
+		BakeTimeSeconds:          pointer.Int64(600),
+		NodeMetricExpiredSeconds: pointer.Int64(300),
+		MaxUnhealthyPercent:      pointer.Int64(10),
+		RevisionHistoryLimit:     pointer.Int64(10),
+	}
+}
+
+func CalculateRolloutCfgMerged(oldCfg configuration.RolloutCfg, configMap *corev1.ConfigMap) configuration.RolloutCfg {
+	cfgStr, ok := configMap.Data[configuration.RolloutConfigKey]
+	if !ok {
+		return DefaultRolloutCfg()
+	}
+
+	cfg := configuration.RolloutCfg{}
+	if err := json.Unmarshal([]byte(cfgStr), &cfg); err != nil {
+		klog.Errorf("failed to unmarshal config %s, err: %s", configuration.RolloutConfigKey, err)
+		return oldCfg
+	}
+	mergedCfg := DefaultRolloutCfg()
+	mergedInterface, _ := util.MergeCfg(&mergedCfg, &cfg)
+	return *mergedInterface.(*configuration.RolloutCfg)
+}
+
+func IsRolloutEnabled(cfg *configuration.RolloutCfg) bool {
+	return cfg.Enable != nil && *cfg.Enable
+}
+
+// IsCanaryNode checks if the node matches the canary selector and falls into the canary percentage.
+// The percentage is calculated with the hash of the node name, so the canary nodes are stable among the revisions.
+func IsCanaryNode(node *corev1.Node, cfg *configuration.RolloutCfg) bool {
+	if node == nil || cfg.CanaryPercent == nil {
+		return false
+	}
+	if cfg.CanaryNodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(cfg.CanaryNodeSelector)
+		if err != nil {
+			klog.Errorf("failed to parse canary node selector %v, err: %v", cfg.CanaryNodeSelector, err)
+			return false
+		}
+		if !selector.Matches(labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(node.Name))
+	return int64(h.Sum32()%100) < *cfg.CanaryPercent
+}
+
+// ParseRolloutRevisions parses the rollout status and the revision history from the revisions configmap.
+func ParseRolloutRevisions(configMap *corev1.ConfigMap) (*SLOCfgRolloutStatus, []SLOCfgRevision, error) {
+	var revisions []SLOCfgRevision
+	if err := json.Unmarshal([]byte(configMap.Data[RolloutRevisionsKey]), &revisions); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal slo config revisions, err: %w", err)
+	}
+	status := &SLOCfgRolloutStatus{}
+	if err := json.Unmarshal([]byte(configMap.Data[RolloutStatusKey]), status); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal slo config rollout status, err: %w", err)
+	}
+	return status, revisions, nil
+}
+
+// GetRevision returns the revision in the history, or nil if it is not found.
+func GetRevision(revisions []SLOCfgRevision, revision int64) *SLOCfgRevision {
+	for i := range revisions {
+		if revisions[i].Revision == revision {
+			return &revisions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+)
+
+func Test_IsCanaryNode(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{"pool": "canary"},
+		},
+	}
+	tests := []struct {
+		name string
+		cfg  *configuration.RolloutCfg
+		want bool
+	}{
+		{
+			name: "all candidate nodes are canary",
+			cfg:  &configuration.RolloutCfg{CanaryPercent: pointer.Int64(100)},
+			want: true,
+		},
+		{
+			name: "no canary node",
+			cfg:  &configuration.RolloutCfg{CanaryPercent: pointer.Int64(0)},
+			want: false,
+		},
+		{
+			name: "node matches the selector",
+			cfg: &configuration.RolloutCfg{
+				CanaryNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "canary"}},
+				CanaryPercent:      pointer.Int64(100),
+			},
+			want: true,
+		},
+		{
+			name: "node does not match the selector",
+			cfg: &configuration.RolloutCfg{
+				CanaryNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "stable"}},
+				CanaryPercent:      pointer.Int64(100),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsCanaryNode(node, tt.cfg))
+		})
+	}
+}
+
+func Test_CalculateRolloutCfgMerged(t *testing.T) {
+	got := CalculateRolloutCfgMerged(configuration.RolloutCfg{}, &corev1.ConfigMap{})
+	assert.Equal(t, DefaultRolloutCfg(), got)
+
+	got = CalculateRolloutCfgMerged(configuration.RolloutCfg{}, &corev1.ConfigMap{
+		Data: map[string]string{
+			configuration.RolloutConfigKey: `{"enable":true,"canaryPercent":50}`,
+		},
+	})
+	want := DefaultRolloutCfg()
+	want.Enable = pointer.Bool(true)
+	want.CanaryPercent = pointer.Int64(50)
+	assert.Equal(t, want, got)
+
+	oldCfg := DefaultRolloutCfg()
+	oldCfg.Enable = pointer.Bool(true)
+	got = CalculateRolloutCfgMerged(oldCfg, &corev1.ConfigMap{
+		Data: map[string]string{
+			configuration.RolloutConfigKey: `invalid`,
+		},
+	})
+	assert.Equal(t, oldCfg, got)
+}
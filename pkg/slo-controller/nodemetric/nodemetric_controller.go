@@ -152,7 +152,7 @@ func (r *NodeMetricReconciler) getNodeMetricSpec(node *corev1.Node, oldSpec *slo
 
 	nodeMetricSpec := getDefaultSpec()
 
-	cfg := r.cfgCache.GetCfgCopy()
+	cfg := r.cfgCache.GetCfgCopyForNode(node)
 	mergedStrategy := sloconfig.GetNodeColocationStrategy(cfg, node)
 
 	nodeMetricCollectPolicy, err := getNodeMetricCollectPolicy(mergedStrategy)
@@ -59,7 +59,7 @@ func (r *NodeResourceReconciler) updateDeviceResources(node *corev1.Node) error
 }
 
 func (r *NodeResourceReconciler) isGPUResourceNeedSync(new, old *corev1.Node) bool {
-	strategy := sloconfig.GetNodeColocationStrategy(r.cfgCache.GetCfgCopy(), new)
+	strategy := sloconfig.GetNodeColocationStrategy(r.cfgCache.GetCfgCopyForNode(new), new)
 
 	lastUpdatedTime, ok := r.GPUSyncContext.Load(util.GenerateNodeKey(&new.ObjectMeta))
 	if !ok || r.Clock.Since(lastUpdatedTime) > time.Duration(*strategy.UpdateTimeThresholdSeconds)*time.Second {
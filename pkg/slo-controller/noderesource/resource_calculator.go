@@ -37,7 +37,7 @@ import (
 )
 
 func (r *NodeResourceReconciler) isColocationCfgDisabled(node *corev1.Node) bool {
-	cfg := r.cfgCache.GetCfgCopy()
+	cfg := r.cfgCache.GetCfgCopyForNode(node)
 	if cfg.Enable == nil || !*cfg.Enable {
 		return true
 	}
@@ -63,7 +63,7 @@ func (r *NodeResourceReconciler) calculateNodeResource(node *corev1.Node,
 		NodeMetric: nodeMetric,
 	}
 
-	strategy := sloconfig.GetNodeColocationStrategy(r.cfgCache.GetCfgCopy(), node)
+	strategy := sloconfig.GetNodeColocationStrategy(r.cfgCache.GetCfgCopyForNode(node), node)
 	framework.RunResourceCalculateExtenders(nr, strategy, node, podList, resourceMetrics)
 
 	return nr
@@ -71,7 +71,7 @@ func (r *NodeResourceReconciler) calculateNodeResource(node *corev1.Node,
 
 func (r *NodeResourceReconciler) updateNodeResource(node *corev1.Node, nr *framework.NodeResource) error {
 	nodeCopy := node.DeepCopy() // avoid overwriting the cache
-	strategy := sloconfig.GetNodeColocationStrategy(r.cfgCache.GetCfgCopy(), node)
+	strategy := sloconfig.GetNodeColocationStrategy(r.cfgCache.GetCfgCopyForNode(node), node)
 
 	// pre-update once
 	framework.RunNodePreUpdateExtenders(strategy, node, nr)
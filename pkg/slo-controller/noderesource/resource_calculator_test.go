@@ -57,6 +57,10 @@ func (f *FakeCfgCache) GetCfgCopy() *configuration.ColocationCfg {
 	return &f.cfg
 }
 
+func (f *FakeCfgCache) GetCfgCopyForNode(_ *corev1.Node) *configuration.ColocationCfg {
+	return &f.cfg
+}
+
 func (f *FakeCfgCache) IsCfgAvailable() bool {
 	return f.available
 }
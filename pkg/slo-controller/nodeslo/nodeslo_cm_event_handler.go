@@ -28,6 +28,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -41,6 +42,9 @@ var _ handler.EventHandler = &SLOCfgHandlerForConfigMapEvent{}
 
 type SLOCfgCache interface {
 	GetCfgCopy() *SLOCfg
+	// GetCfgCopyForNode returns the config revision which the node should apply, e.g. the canary revision during
+	// the rollout.
+	GetCfgCopyForNode(node *corev1.Node) *SLOCfg
 	IsCfgAvailable() bool
 }
 
@@ -69,6 +73,16 @@ type sLOCfgCache struct {
 	// Config could be concurrently used by the Reconciliation and EventHandler
 	sloCfg    SLOCfg
 	available bool
+
+	// the states of the staged rollout
+	rolloutCfg     configuration.RolloutCfg
+	canaryCfg      *SLOCfg
+	rolloutStatus  config.SLOCfgRolloutStatus
+	revisions      []config.SLOCfgRevision
+	configData     map[string]string // the config data of the latest sync
+	lastConfigData map[string]string // the config data of the previous sync
+	// rolloutSnapshot is the rollout states to save into the revisions configmap
+	rolloutSnapshot map[string]string
 }
 
 func DefaultSLOCfg() SLOCfg {
@@ -85,13 +99,24 @@ func DefaultSLOCfg() SLOCfg {
 type SLOCfgHandlerForConfigMapEvent struct {
 	config.EnqueueRequestForConfigMap
 
-	Client   client.Client
-	cfgCache sLOCfgCache
+	Client client.Client
+	// EventReader reads the events without the informer cache. Client is used if it is not set.
+	EventReader client.Reader
+	cfgCache    sLOCfgCache
+	// saveLock serializes the writes of the revisions configmap
+	saveLock sync.Mutex
 	recorder record.EventRecorder
+	// rolloutTrigger notifies to enqueue all nodes when the rollout progresses or reverts
+	rolloutTrigger chan event.GenericEvent
 }
 
 func NewSLOCfgHandlerForConfigMapEvent(client client.Client, initCfg SLOCfg, recorder record.EventRecorder) *SLOCfgHandlerForConfigMapEvent {
-	sloHandler := &SLOCfgHandlerForConfigMapEvent{cfgCache: sLOCfgCache{sloCfg: initCfg}, Client: client, recorder: recorder}
+	sloHandler := &SLOCfgHandlerForConfigMapEvent{
+		cfgCache:       sLOCfgCache{sloCfg: initCfg},
+		Client:         client,
+		recorder:       recorder,
+		rolloutTrigger: make(chan event.GenericEvent, 1),
+	}
 	sloHandler.SyncCacheIfChanged = sloHandler.syncNodeSLOSpecIfChanged
 	sloHandler.EnqueueRequest = sloHandler.triggerAllNodeEnqueue
 	return sloHandler
@@ -113,18 +138,34 @@ func (p *SLOCfgHandlerForConfigMapEvent) triggerAllNodeEnqueue(q *workqueue.Rate
 
 func (p *SLOCfgHandlerForConfigMapEvent) syncNodeSLOSpecIfChanged(configMap *corev1.ConfigMap) bool {
 	p.cfgCache.lock.Lock()
-	defer p.cfgCache.lock.Unlock()
-	return p.syncConfig(configMap)
+	changed := p.syncConfig(configMap)
+	p.cfgCache.lock.Unlock()
+	p.saveRollout()
+	return changed
 }
 
 func (p *SLOCfgHandlerForConfigMapEvent) syncConfig(configMap *corev1.ConfigMap) bool {
 	if configMap == nil {
 		klog.Warningf("config map is deleted!,use default config")
+		p.cfgCache.rolloutCfg = config.DefaultRolloutCfg()
+		p.cfgCache.lastConfigData, p.cfgCache.configData = p.cfgCache.configData, nil
 		return p.updateCacheIfChanged(DefaultSLOCfg())
 	}
 
+	p.cfgCache.rolloutCfg = config.CalculateRolloutCfgMerged(p.cfgCache.rolloutCfg, configMap)
+	p.cfgCache.lastConfigData, p.cfgCache.configData = p.cfgCache.configData, getRevisionData(configMap)
+	newSLOCfg := p.calculateSLOCfg(&p.cfgCache.sloCfg, configMap)
+	if !p.cfgCache.available && config.IsRolloutEnabled(&p.cfgCache.rolloutCfg) {
+		// recover the stable revision and the rollout status at the first sync
+		p.restoreRollout()
+	}
+	return p.updateCacheIfChanged(newSLOCfg)
+}
+
+// calculateSLOCfg parses the config from the configmap, and it falls back to the old config for any invalid item.
+func (p *SLOCfgHandlerForConfigMapEvent) calculateSLOCfg(oldSLOCfg *SLOCfg, configMap *corev1.ConfigMap) SLOCfg {
 	var newSLOCfg SLOCfg
-	oldSLOCfgCopy := p.cfgCache.sloCfg.DeepCopy()
+	oldSLOCfgCopy := oldSLOCfg.DeepCopy()
 	var err error
 	newSLOCfg.ThresholdCfgMerged, err = calculateResourceThresholdCfgMerged(oldSLOCfgCopy.ThresholdCfgMerged, configMap)
 	if err != nil {
@@ -152,10 +193,15 @@ func (p *SLOCfgHandlerForConfigMapEvent) syncConfig(configMap *corev1.ConfigMap)
 		p.recorder.Eventf(configMap, "Warning", config.ReasonSLOConfigUnmarshalFailed, "failed to unmarshal HostApplicationCfg, err: %s", err)
 	}
 	newSLOCfg.ExtensionCfgMerged = calculateExtensionsCfgMerged(oldSLOCfgCopy.ExtensionCfgMerged, configMap, p.recorder)
-	return p.updateCacheIfChanged(newSLOCfg)
+	return newSLOCfg
 }
 
 func (p *SLOCfgHandlerForConfigMapEvent) updateCacheIfChanged(newSLOCfg SLOCfg) bool {
+	if p.cfgCache.available && config.IsRolloutEnabled(&p.cfgCache.rolloutCfg) {
+		return p.rolloutIfChanged(newSLOCfg)
+	}
+	// apply to all nodes at once when the rollout is disabled
+	canceled := p.cancelRolloutIfNeed()
 	changed := !reflect.DeepEqual(p.cfgCache.sloCfg, newSLOCfg)
 
 	if changed {
@@ -166,7 +212,7 @@ func (p *SLOCfgHandlerForConfigMapEvent) updateCacheIfChanged(newSLOCfg SLOCfg)
 	}
 	// set the available flag and never change it
 	p.cfgCache.available = true
-	return changed
+	return changed || canceled
 }
 
 func (p *SLOCfgHandlerForConfigMapEvent) GetCfgCopy() *SLOCfg {
@@ -175,7 +221,18 @@ func (p *SLOCfgHandlerForConfigMapEvent) GetCfgCopy() *SLOCfg {
 	return p.cfgCache.sloCfg.DeepCopy()
 }
 
+func (p *SLOCfgHandlerForConfigMapEvent) GetCfgCopyForNode(node *corev1.Node) *SLOCfg {
+	p.cfgCache.lock.RLock()
+	defer p.cfgCache.lock.RUnlock()
+	if p.cfgCache.canaryCfg != nil && config.IsCanaryNode(node, &p.cfgCache.rolloutCfg) {
+		return p.cfgCache.canaryCfg.DeepCopy()
+	}
+	return p.cfgCache.sloCfg.DeepCopy()
+}
+
 func (p *SLOCfgHandlerForConfigMapEvent) IsCfgAvailable() bool {
+	// save the rollout states restored by the first sync after the lock is released
+	defer p.saveRollout()
 	p.cfgCache.lock.RLock()
 	defer p.cfgCache.lock.RUnlock()
 	// if config is available, just return
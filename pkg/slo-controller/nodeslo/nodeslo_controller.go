@@ -24,11 +24,15 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -71,7 +75,7 @@ func (r *NodeSLOReconciler) getNodeSLOSpec(node *corev1.Node, oldSpec *slov1alph
 		nodeSLOSpec = oldSpec.DeepCopy()
 	}
 
-	sloCfg := r.sloCfgCache.GetCfgCopy()
+	sloCfg := r.sloCfgCache.GetCfgCopyForNode(node)
 
 	var err error
 	nodeSLOSpec.ResourceUsedThresholdWithBE, err = getResourceThresholdSpec(node, &sloCfg.ThresholdCfgMerged)
@@ -122,6 +126,7 @@ func (r *NodeSLOReconciler) getNodeSLOSpec(node *corev1.Node, oldSpec *slov1alph
 
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=list
 // +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos/status,verbs=get;update;patch
 
@@ -232,13 +237,22 @@ func Add(mgr ctrl.Manager) error {
 
 func (r *NodeSLOReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	configMapCacheHandler := NewSLOCfgHandlerForConfigMapEvent(r.Client, DefaultSLOCfg(), r.Recorder)
+	configMapCacheHandler.EventReader = mgr.GetAPIReader()
 	r.sloCfgCache = configMapCacheHandler
+	if err := mgr.Add(manager.RunnableFunc(configMapCacheHandler.runRolloutChecker)); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&slov1alpha1.NodeSLO{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&source.Kind{Type: &corev1.Node{}}, &nodemetric.EnqueueRequestForNode{
 			Client: r.Client,
 		}).
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, configMapCacheHandler).
+		Watches(&source.Channel{Source: configMapCacheHandler.rolloutTrigger}, handler.Funcs{
+			GenericFunc: func(_ event.GenericEvent, q workqueue.RateLimitingInterface) {
+				configMapCacheHandler.triggerAllNodeEnqueue(&q)
+			},
+		}).
 		Named(Name).
 		Complete(r)
 }
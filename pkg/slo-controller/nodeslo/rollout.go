@@ -0,0 +1,509 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+const (
+	// koordletEvictPodReason is the event reason which koordlet records when it evicts a pod.
+	koordletEvictPodReason = "evictPodSuccess"
+	// koordletQOSManagerComponent is the event source component of koordlet qos manager.
+	koordletQOSManagerComponent = "koordlet-qosManager"
+	// eventListLimit is the page size to list the eviction events.
+	eventListLimit = 500
+
+	rolloutCheckInterval = 30 * time.Second
+
+	ReasonSLOConfigRolloutStarted    = "SLOCfgRolloutStarted"
+	ReasonSLOConfigRolloutProgressed = "SLOCfgRolloutProgressed"
+	ReasonSLOConfigRolloutReverted   = "SLOCfgRolloutReverted"
+)
+
+// hashSLOCfg hashes the NodeSLO config along with the colocation config, since the colocation config is rolled out
+// with the same revisions by the noderesource controller.
+func hashSLOCfg(cfg *SLOCfg, data map[string]string) string {
+	h := fnv.New64a()
+	h.Write([]byte(util.DumpJSON(cfg)))
+	h.Write([]byte(data[configuration.ColocationConfigKey]))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// stableHash returns the hash of the stable revision.
+func (p *SLOCfgHandlerForConfigMapEvent) stableHash() string {
+	data := p.cfgCache.lastConfigData
+	if stableRevision := p.getRevision(p.cfgCache.rolloutStatus.StableRevision); stableRevision != nil {
+		data = stableRevision.Data
+	}
+	return hashSLOCfg(&p.cfgCache.sloCfg, data)
+}
+
+// rolloutIfChanged starts a canary for the changed config instead of applying it to all nodes.
+func (p *SLOCfgHandlerForConfigMapEvent) rolloutIfChanged(newSLOCfg SLOCfg) bool {
+	newHash := hashSLOCfg(&newSLOCfg, p.cfgCache.configData)
+	if newHash == p.stableHash() {
+		if p.cfgCache.canaryCfg == nil {
+			return false
+		}
+		// the config is changed back to the stable revision
+		p.cfgCache.canaryCfg = nil
+		p.cfgCache.rolloutStatus.Phase = config.RolloutPhaseStable
+		p.cfgCache.rolloutStatus.CanaryStartTime = nil
+		p.cfgCache.rolloutStatus.Message = "canary canceled since the config is changed back to the stable revision"
+		p.snapshotRollout()
+		klog.V(4).Infof("slo config rollout canceled, revision %v", p.cfgCache.rolloutStatus.CanaryRevision)
+		return true
+	}
+	if canaryRevision := p.getRevision(p.cfgCache.rolloutStatus.CanaryRevision); canaryRevision != nil &&
+		canaryRevision.Hash == newHash && p.cfgCache.rolloutStatus.Phase != config.RolloutPhaseStable {
+		// the canary is baking or has been reverted
+		if p.cfgCache.canaryCfg == nil && p.cfgCache.rolloutStatus.Phase == config.RolloutPhaseBaking {
+			p.cfgCache.canaryCfg = &newSLOCfg
+			return true
+		}
+		return false
+	}
+
+	revision := p.addRevision(newHash)
+	now := metav1.Now()
+	p.cfgCache.canaryCfg = &newSLOCfg
+	p.cfgCache.rolloutStatus = config.SLOCfgRolloutStatus{
+		Phase:           config.RolloutPhaseBaking,
+		StableRevision:  p.cfgCache.rolloutStatus.StableRevision,
+		CanaryRevision:  revision.Revision,
+		CanaryStartTime: &now,
+		Message:         "canary started",
+	}
+	p.snapshotRollout()
+	p.recordRolloutEvent(corev1.EventTypeNormal, ReasonSLOConfigRolloutStarted,
+		fmt.Sprintf("slo config revision %v started the canary", revision.Revision))
+	return true
+}
+
+// cancelRolloutIfNeed applies the canary revision to all nodes when the rollout is disabled.
+func (p *SLOCfgHandlerForConfigMapEvent) cancelRolloutIfNeed() bool {
+	if p.cfgCache.canaryCfg == nil {
+		return false
+	}
+	p.cfgCache.canaryCfg = nil
+	// the stable revision is recorded again when the rollout is enabled next time
+	p.cfgCache.rolloutStatus.Phase = config.RolloutPhaseStable
+	p.cfgCache.rolloutStatus.StableRevision = 0
+	p.cfgCache.rolloutStatus.CanaryStartTime = nil
+	p.cfgCache.rolloutStatus.Message = "rollout disabled"
+	p.snapshotRollout()
+	return true
+}
+
+// restoreRollout recovers the stable revision from the revision history, so that the config changed during the
+// controller restarts is still rolled out with canary.
+func (p *SLOCfgHandlerForConfigMapEvent) restoreRollout() {
+	configMap := &corev1.ConfigMap{}
+	err := p.Client.Get(context.TODO(), types.NamespacedName{Namespace: sloconfig.ConfigNameSpace, Name: config.RolloutRevisionsConfigMap}, configMap)
+	if errors.IsNotFound(err) {
+		klog.V(4).Infof("slo config revisions not found, start the rollout with the current config")
+		return
+	} else if err != nil {
+		klog.Warningf("failed to get slo config revisions, err: %v", err)
+		return
+	}
+	status, revisions, err := config.ParseRolloutRevisions(configMap)
+	if err != nil {
+		klog.Warningf("failed to restore slo config rollout, err: %v", err)
+		return
+	}
+	p.cfgCache.rolloutStatus, p.cfgCache.revisions = *status, revisions
+	stableRevision := p.getRevision(p.cfgCache.rolloutStatus.StableRevision)
+	if stableRevision == nil {
+		klog.Warningf("stable slo config revision %v not found", p.cfgCache.rolloutStatus.StableRevision)
+		return
+	}
+	stableCfg := p.calculateSLOCfg(&p.cfgCache.sloCfg, &corev1.ConfigMap{Data: stableRevision.Data})
+	p.cfgCache.sloCfg = stableCfg
+	p.cfgCache.available = true
+	klog.V(4).Infof("restore slo config rollout, stable revision %v, status %v",
+		stableRevision.Revision, util.DumpJSON(p.cfgCache.rolloutStatus))
+}
+
+func (p *SLOCfgHandlerForConfigMapEvent) getRevision(revision int64) *config.SLOCfgRevision {
+	return config.GetRevision(p.cfgCache.revisions, revision)
+}
+
+func (p *SLOCfgHandlerForConfigMapEvent) addRevision(hash string) *config.SLOCfgRevision {
+	var revision int64 = 1
+	if n := len(p.cfgCache.revisions); n > 0 {
+		revision = p.cfgCache.revisions[n-1].Revision + 1
+	}
+	if p.cfgCache.rolloutStatus.StableRevision <= 0 {
+		// keep the current config as the first stable revision
+		p.cfgCache.revisions = append(p.cfgCache.revisions, config.SLOCfgRevision{
+			Revision:   revision,
+			Hash:       hashSLOCfg(&p.cfgCache.sloCfg, p.cfgCache.lastConfigData),
+			CreateTime: metav1.Now(),
+			Data:       p.cfgCache.lastConfigData,
+		})
+		p.cfgCache.rolloutStatus.StableRevision = revision
+		revision++
+	}
+	p.cfgCache.revisions = append(p.cfgCache.revisions, config.SLOCfgRevision{
+		Revision:   revision,
+		Hash:       hash,
+		CreateTime: metav1.Now(),
+		Data:       p.cfgCache.configData,
+	})
+
+	// prune the oldest revisions except the stable one
+	limit := int(*config.DefaultRolloutCfg().RevisionHistoryLimit)
+	if p.cfgCache.rolloutCfg.RevisionHistoryLimit != nil {
+		limit = int(*p.cfgCache.rolloutCfg.RevisionHistoryLimit)
+	}
+	for len(p.cfgCache.revisions) > limit && len(p.cfgCache.revisions) > 2 {
+		if p.cfgCache.revisions[0].Revision == p.cfgCache.rolloutStatus.StableRevision {
+			p.cfgCache.revisions = append(p.cfgCache.revisions[:1], p.cfgCache.revisions[2:]...)
+		} else {
+			p.cfgCache.revisions = p.cfgCache.revisions[1:]
+		}
+	}
+	return &p.cfgCache.revisions[len(p.cfgCache.revisions)-1]
+}
+
+// getRevisionData returns the config data of the revision, which excludes the rollout config itself.
+func getRevisionData(configMap *corev1.ConfigMap) map[string]string {
+	if configMap == nil {
+		return nil
+	}
+	data := map[string]string{}
+	for k, v := range configMap.Data {
+		if k == configuration.RolloutConfigKey {
+			continue
+		}
+		data[k] = v
+	}
+	return data
+}
+
+// compactRevisions keeps the config data of only the stable and the canary revisions, which are the last known-good
+// config and the config being verified, while the other revisions keep only their hashes as the history. So the
+// revisions configmap holds at most two copies of the slo-controller-config no matter how many revisions are retained,
+// and it keeps within the object size limit as the slo-controller-config does.
+func (p *SLOCfgHandlerForConfigMapEvent) compactRevisions() {
+	for i := range p.cfgCache.revisions {
+		revision := &p.cfgCache.revisions[i]
+		if revision.Revision != p.cfgCache.rolloutStatus.StableRevision &&
+			revision.Revision != p.cfgCache.rolloutStatus.CanaryRevision {
+			revision.Data = nil
+		}
+	}
+}
+
+// snapshotRollout records the revision history and the rollout status to save, and it should be called with the
+// cache lock held. The snapshot is written into the configmap by saveRollout after the lock is released.
+func (p *SLOCfgHandlerForConfigMapEvent) snapshotRollout() {
+	p.compactRevisions()
+	p.cfgCache.rolloutSnapshot = map[string]string{
+		config.RolloutStatusKey:    util.DumpJSON(p.cfgCache.rolloutStatus),
+		config.RolloutRevisionsKey: util.DumpJSON(p.cfgCache.revisions),
+	}
+}
+
+// saveRollout writes the latest snapshot of the rollout into the configmap. It should be called without the cache
+// lock held, and the writes are serialized to keep the latest snapshot in the configmap.
+func (p *SLOCfgHandlerForConfigMapEvent) saveRollout() {
+	p.saveLock.Lock()
+	defer p.saveLock.Unlock()
+	p.cfgCache.lock.Lock()
+	data := p.cfgCache.rolloutSnapshot
+	p.cfgCache.rolloutSnapshot = nil
+	p.cfgCache.lock.Unlock()
+	if data == nil {
+		return
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := p.Client.Get(context.TODO(), types.NamespacedName{Namespace: sloconfig.ConfigNameSpace, Name: config.RolloutRevisionsConfigMap}, configMap)
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: sloconfig.ConfigNameSpace,
+				Name:      config.RolloutRevisionsConfigMap,
+			},
+			Data: data,
+		}
+		err = p.Client.Create(context.TODO(), configMap)
+	} else if err == nil {
+		configMap.Data = data
+		err = p.Client.Update(context.TODO(), configMap)
+	}
+	if err != nil {
+		klog.Warningf("failed to save slo config revisions, status %v, err: %v", data[config.RolloutStatusKey], err)
+	}
+}
+
+func (p *SLOCfgHandlerForConfigMapEvent) recordRolloutEvent(eventType, reason, message string) {
+	klog.V(4).Infof("slo config rollout event %s: %s", reason, message)
+	if p.recorder == nil {
+		return
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sloconfig.ConfigNameSpace,
+			Name:      sloconfig.SLOCtrlConfigMap,
+		},
+	}
+	p.recorder.Event(configMap, eventType, reason, message)
+}
+
+// GetRolloutStatus returns the status of the staged rollout.
+func (p *SLOCfgHandlerForConfigMapEvent) GetRolloutStatus() config.SLOCfgRolloutStatus {
+	p.cfgCache.lock.RLock()
+	defer p.cfgCache.lock.RUnlock()
+	status := p.cfgCache.rolloutStatus
+	return status
+}
+
+// runRolloutChecker periodically checks the health of the canary nodes to progress or revert the rollout.
+func (p *SLOCfgHandlerForConfigMapEvent) runRolloutChecker(ctx context.Context) error {
+	wait.Until(p.checkRollout, rolloutCheckInterval, ctx.Done())
+	return nil
+}
+
+func (p *SLOCfgHandlerForConfigMapEvent) checkRollout() {
+	p.cfgCache.lock.RLock()
+	if p.cfgCache.canaryCfg == nil || p.cfgCache.rolloutStatus.CanaryStartTime == nil {
+		p.cfgCache.lock.RUnlock()
+		return
+	}
+	rolloutCfg := *p.cfgCache.rolloutCfg.DeepCopy()
+	canaryRevision := p.cfgCache.rolloutStatus.CanaryRevision
+	startTime := p.cfgCache.rolloutStatus.CanaryStartTime.Time
+	p.cfgCache.lock.RUnlock()
+
+	nodeList := &corev1.NodeList{}
+	if err := p.Client.List(context.TODO(), nodeList); err != nil {
+		klog.Warningf("failed to list nodes for slo config rollout, err: %v", err)
+		return
+	}
+	var canaryNodes []*corev1.Node
+	for i := range nodeList.Items {
+		if config.IsCanaryNode(&nodeList.Items[i], &rolloutCfg) {
+			canaryNodes = append(canaryNodes, &nodeList.Items[i])
+		}
+	}
+
+	healthy, message, err := p.checkCanaryHealth(canaryNodes, &rolloutCfg, startTime)
+	if err != nil {
+		klog.Warningf("failed to check the canary health for slo config revision %v, err: %v", canaryRevision, err)
+		return
+	}
+	if !healthy {
+		p.finishRollout(canaryRevision, false, message)
+		return
+	}
+	bakeTime := time.Duration(*config.DefaultRolloutCfg().BakeTimeSeconds) * time.Second
+	if rolloutCfg.BakeTimeSeconds != nil {
+		bakeTime = time.Duration(*rolloutCfg.BakeTimeSeconds) * time.Second
+	}
+	if time.Since(startTime) < bakeTime {
+		klog.V(5).Infof("slo config revision %v is baking, %s", canaryRevision, message)
+		return
+	}
+	p.finishRollout(canaryRevision, true, message)
+}
+
+// checkCanaryHealth checks the NodeMetric freshness, the strategy enforcement errors and the pod evictions of the
+// canary nodes since the canary started.
+func (p *SLOCfgHandlerForConfigMapEvent) checkCanaryHealth(canaryNodes []*corev1.Node, cfg *configuration.RolloutCfg,
+	startTime time.Time) (bool, string, error) {
+	if len(canaryNodes) <= 0 {
+		// the canary revision is never verified, so it should not progress to all nodes
+		return false, "no canary node is selected", nil
+	}
+
+	var unhealthyMessages []string
+	canaryNodeNames := map[string]struct{}{}
+	for _, node := range canaryNodes {
+		canaryNodeNames[node.Name] = struct{}{}
+		reason, err := p.checkCanaryNode(node.Name, cfg, startTime)
+		if err != nil {
+			return false, "", err
+		}
+		if reason != "" {
+			unhealthyMessages = append(unhealthyMessages, fmt.Sprintf("%s: %s", node.Name, reason))
+		}
+	}
+	maxUnhealthyPercent := *config.DefaultRolloutCfg().MaxUnhealthyPercent
+	if cfg.MaxUnhealthyPercent != nil {
+		maxUnhealthyPercent = *cfg.MaxUnhealthyPercent
+	}
+	message := fmt.Sprintf("%d/%d canary nodes are unhealthy", len(unhealthyMessages), len(canaryNodes))
+	if len(unhealthyMessages) > 0 {
+		message = fmt.Sprintf("%s, %s", message, strings.Join(unhealthyMessages, "; "))
+	}
+	if int64(len(unhealthyMessages))*100 > maxUnhealthyPercent*int64(len(canaryNodes)) {
+		return false, message, nil
+	}
+
+	if cfg.MaxEvictionCount != nil {
+		evictionCount, err := p.countCanaryEvictions(canaryNodeNames, startTime)
+		if err != nil {
+			return false, "", err
+		}
+		if evictionCount > *cfg.MaxEvictionCount {
+			return false, fmt.Sprintf("%d pods are evicted on canary nodes, exceeds %d", evictionCount, *cfg.MaxEvictionCount), nil
+		}
+		message = fmt.Sprintf("%s, %d pods are evicted", message, evictionCount)
+	}
+	return true, message, nil
+}
+
+// checkCanaryNode returns the unhealthy reason of the canary node, or empty if it is healthy.
+func (p *SLOCfgHandlerForConfigMapEvent) checkCanaryNode(nodeName string, cfg *configuration.RolloutCfg, startTime time.Time) (string, error) {
+	nodeMetric := &slov1alpha1.NodeMetric{}
+	err := p.Client.Get(context.TODO(), types.NamespacedName{Name: nodeName}, nodeMetric)
+	if errors.IsNotFound(err) {
+		return "NodeMetric not found", nil
+	} else if err != nil {
+		return "", err
+	}
+	expiredSeconds := *config.DefaultRolloutCfg().NodeMetricExpiredSeconds
+	if cfg.NodeMetricExpiredSeconds != nil {
+		expiredSeconds = *cfg.NodeMetricExpiredSeconds
+	}
+	if nodeMetric.Status.UpdateTime == nil ||
+		time.Since(nodeMetric.Status.UpdateTime.Time) > time.Duration(expiredSeconds)*time.Second {
+		return "NodeMetric expired", nil
+	}
+	if cfg.MaxLSCPUPressurePercent != nil && nodeMetric.Status.NodeMetric != nil &&
+		nodeMetric.Status.NodeMetric.LSCPUPressurePercent != nil &&
+		*nodeMetric.Status.NodeMetric.LSCPUPressurePercent > *cfg.MaxLSCPUPressurePercent {
+		return fmt.Sprintf("ls cpu pressure %d%% exceeds %d%%",
+			*nodeMetric.Status.NodeMetric.LSCPUPressurePercent, *cfg.MaxLSCPUPressurePercent), nil
+	}
+
+	nodeSLO := &slov1alpha1.NodeSLO{}
+	err = p.Client.Get(context.TODO(), types.NamespacedName{Name: nodeName}, nodeSLO)
+	if errors.IsNotFound(err) {
+		return "NodeSLO not found", nil
+	} else if err != nil {
+		return "", err
+	}
+	for _, condition := range nodeSLO.Status.Conditions {
+		if condition.Type == slov1alpha1.StrategyConditionError && !condition.LastTransitionTime.Time.Before(startTime) {
+			return fmt.Sprintf("strategy %s failed, %s", condition.Strategy, condition.Message), nil
+		}
+	}
+	return "", nil
+}
+
+// countCanaryEvictions counts the pods evicted by koordlet on the canary nodes since the canary started.
+// The events are filtered by the field selectors on the server side and listed in pages.
+func (p *SLOCfgHandlerForConfigMapEvent) countCanaryEvictions(canaryNodeNames map[string]struct{}, startTime time.Time) (int64, error) {
+	reader := p.EventReader
+	if reader == nil {
+		reader = p.Client
+	}
+	fieldSelector := fields.SelectorFromSet(fields.Set{
+		"reason": koordletEvictPodReason,
+		"source": koordletQOSManagerComponent,
+		"type":   corev1.EventTypeWarning,
+	})
+	var count int64
+	continueToken := ""
+	for {
+		eventList := &corev1.EventList{}
+		if err := reader.List(context.TODO(), eventList, client.MatchingFieldsSelector{Selector: fieldSelector},
+			client.Limit(eventListLimit), client.Continue(continueToken)); err != nil {
+			return 0, err
+		}
+		for i := range eventList.Items {
+			e := &eventList.Items[i]
+			if e.Reason != koordletEvictPodReason || e.LastTimestamp.Time.Before(startTime) {
+				continue
+			}
+			if _, ok := canaryNodeNames[e.Source.Host]; !ok {
+				continue
+			}
+			if e.Count > 0 {
+				count += int64(e.Count)
+			} else {
+				count++
+			}
+		}
+		continueToken = eventList.Continue
+		if continueToken == "" {
+			return count, nil
+		}
+	}
+}
+
+// finishRollout progresses the canary revision to all nodes or reverts it.
+func (p *SLOCfgHandlerForConfigMapEvent) finishRollout(canaryRevision int64, progress bool, message string) {
+	p.cfgCache.lock.Lock()
+	if p.cfgCache.canaryCfg == nil || p.cfgCache.rolloutStatus.CanaryRevision != canaryRevision {
+		// the canary has been changed during the check
+		p.cfgCache.lock.Unlock()
+		return
+	}
+	if progress {
+		p.cfgCache.sloCfg = *p.cfgCache.canaryCfg
+		p.cfgCache.rolloutStatus.Phase = config.RolloutPhaseStable
+		p.cfgCache.rolloutStatus.StableRevision = canaryRevision
+	} else {
+		p.cfgCache.rolloutStatus.Phase = config.RolloutPhaseReverted
+	}
+	p.cfgCache.canaryCfg = nil
+	p.cfgCache.rolloutStatus.CanaryStartTime = nil
+	p.cfgCache.rolloutStatus.Message = message
+	p.snapshotRollout()
+	p.cfgCache.lock.Unlock()
+	p.saveRollout()
+
+	if progress {
+		p.recordRolloutEvent(corev1.EventTypeNormal, ReasonSLOConfigRolloutProgressed,
+			fmt.Sprintf("slo config revision %v progressed to all nodes, %s", canaryRevision, message))
+	} else {
+		p.recordRolloutEvent(corev1.EventTypeWarning, ReasonSLOConfigRolloutReverted,
+			fmt.Sprintf("slo config revision %v reverted, %s", canaryRevision, message))
+	}
+	// enqueue all nodes to apply the new stable revision
+	select {
+	case p.rolloutTrigger <- event.GenericEvent{}:
+	default:
+	}
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func TestSLOCfgRollout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	slov1alpha1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	rolloutCfg := `{"enable":true,"canaryNodeSelector":{"matchLabels":{"pool":"canary"}},"canaryPercent":100,"bakeTimeSeconds":0}`
+	newConfigMap := func(cpuSuppressThresholdPercent string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sloconfig.SLOCtrlConfigMap,
+				Namespace: sloconfig.ConfigNameSpace,
+			},
+			Data: map[string]string{
+				configuration.ResourceThresholdConfigKey: `{"clusterStrategy":{"enable":true,"cpuSuppressThresholdPercent":` + cpuSuppressThresholdPercent + `}}`,
+				configuration.RolloutConfigKey:           rolloutCfg,
+			},
+		}
+	}
+	canaryNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "canary-node",
+			Labels: map[string]string{"pool": "canary"},
+		},
+	}
+	stableNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "stable-node",
+		},
+	}
+	assert.NoError(t, fakeClient.Create(context.TODO(), canaryNode))
+	assert.NoError(t, fakeClient.Create(context.TODO(), stableNode))
+	nodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: canaryNode.Name},
+		Status: slov1alpha1.NodeMetricStatus{
+			UpdateTime: &metav1.Time{Time: time.Now()},
+		},
+	}
+	assert.NoError(t, fakeClient.Create(context.TODO(), nodeMetric))
+	assert.NoError(t, fakeClient.Create(context.TODO(), &slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: canaryNode.Name}}))
+	getThreshold := func(p *SLOCfgHandlerForConfigMapEvent, node *corev1.Node) int64 {
+		return *p.GetCfgCopyForNode(node).ThresholdCfgMerged.ClusterStrategy.CPUSuppressThresholdPercent
+	}
+	getSavedStatus := func() config.SLOCfgRolloutStatus {
+		configMap := &corev1.ConfigMap{}
+		err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: sloconfig.ConfigNameSpace, Name: config.RolloutRevisionsConfigMap}, configMap)
+		assert.NoError(t, err)
+		status := config.SLOCfgRolloutStatus{}
+		assert.NoError(t, json.Unmarshal([]byte(configMap.Data[config.RolloutStatusKey]), &status))
+		return status
+	}
+
+	p := NewSLOCfgHandlerForConfigMapEvent(fakeClient, DefaultSLOCfg(), &record.FakeRecorder{})
+
+	// the first config is applied to all nodes
+	assert.True(t, p.SyncCacheIfChanged(newConfigMap("60")))
+	assert.Equal(t, int64(60), getThreshold(p, canaryNode))
+	assert.Equal(t, int64(60), getThreshold(p, stableNode))
+
+	// the changed config is applied to the canary nodes
+	assert.True(t, p.SyncCacheIfChanged(newConfigMap("70")))
+	assert.Equal(t, int64(70), getThreshold(p, canaryNode))
+	assert.Equal(t, int64(60), getThreshold(p, stableNode))
+	assert.Equal(t, config.RolloutPhaseBaking, p.GetRolloutStatus().Phase)
+	assert.Equal(t, int64(1), p.GetRolloutStatus().StableRevision)
+	assert.Equal(t, int64(2), p.GetRolloutStatus().CanaryRevision)
+	assert.Equal(t, config.RolloutPhaseBaking, getSavedStatus().Phase)
+	assert.False(t, p.SyncCacheIfChanged(newConfigMap("70")))
+
+	// progress since the canary nodes are healthy
+	p.checkRollout()
+	assert.Equal(t, int64(70), getThreshold(p, canaryNode))
+	assert.Equal(t, int64(70), getThreshold(p, stableNode))
+	assert.Equal(t, config.RolloutPhaseStable, p.GetRolloutStatus().Phase)
+	assert.Equal(t, int64(2), p.GetRolloutStatus().StableRevision)
+	assert.Equal(t, 1, len(p.rolloutTrigger))
+	<-p.rolloutTrigger
+
+	// revert since the NodeMetric of the canary node is expired
+	assert.True(t, p.SyncCacheIfChanged(newConfigMap("80")))
+	assert.Equal(t, int64(80), getThreshold(p, canaryNode))
+	nodeMetric.Status.UpdateTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	assert.NoError(t, fakeClient.Status().Update(context.TODO(), nodeMetric))
+	p.checkRollout()
+	assert.Equal(t, int64(70), getThreshold(p, canaryNode))
+	assert.Equal(t, int64(70), getThreshold(p, stableNode))
+	assert.Equal(t, config.RolloutPhaseReverted, p.GetRolloutStatus().Phase)
+	assert.Equal(t, int64(2), p.GetRolloutStatus().StableRevision)
+	assert.Equal(t, int64(3), p.GetRolloutStatus().CanaryRevision)
+	assert.Equal(t, config.RolloutPhaseReverted, getSavedStatus().Phase)
+	// the reverted config is not rolled out again
+	assert.False(t, p.SyncCacheIfChanged(newConfigMap("80")))
+	assert.Equal(t, int64(70), getThreshold(p, canaryNode))
+
+	// restore the stable revision after the controller restarts
+	p1 := NewSLOCfgHandlerForConfigMapEvent(fakeClient, DefaultSLOCfg(), &record.FakeRecorder{})
+	p1.SyncCacheIfChanged(newConfigMap("80"))
+	assert.Equal(t, int64(70), getThreshold(p1, canaryNode))
+	assert.Equal(t, int64(70), getThreshold(p1, stableNode))
+	assert.Equal(t, config.RolloutPhaseReverted, p1.GetRolloutStatus().Phase)
+
+	// a new config starts the canary again
+	assert.True(t, p1.SyncCacheIfChanged(newConfigMap("90")))
+	assert.Equal(t, int64(90), getThreshold(p1, canaryNode))
+	assert.Equal(t, int64(70), getThreshold(p1, stableNode))
+	assert.Equal(t, int64(4), p1.GetRolloutStatus().CanaryRevision)
+
+	// a colocation config change starts the canary, though the NodeSLO config is unchanged
+	nodeMetric.Status.UpdateTime = &metav1.Time{Time: time.Now()}
+	assert.NoError(t, fakeClient.Status().Update(context.TODO(), nodeMetric))
+	p1.checkRollout()
+	assert.Equal(t, config.RolloutPhaseStable, p1.GetRolloutStatus().Phase)
+	colocationConfigMap := newConfigMap("90")
+	colocationConfigMap.Data[configuration.ColocationConfigKey] = `{"enable":true}`
+	assert.True(t, p1.SyncCacheIfChanged(colocationConfigMap))
+	assert.Equal(t, config.RolloutPhaseBaking, p1.GetRolloutStatus().Phase)
+	assert.Equal(t, int64(5), p1.GetRolloutStatus().CanaryRevision)
+	assert.Equal(t, `{"enable":true}`, p1.getRevision(5).Data[configuration.ColocationConfigKey])
+	// only the stable and the canary revisions keep the config data
+	revisionsConfigMap := &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: sloconfig.ConfigNameSpace, Name: config.RolloutRevisionsConfigMap}, revisionsConfigMap))
+	_, savedRevisions, err := config.ParseRolloutRevisions(revisionsConfigMap)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(savedRevisions))
+	for _, revision := range savedRevisions {
+		if revision.Revision == 4 || revision.Revision == 5 {
+			assert.NotEmpty(t, revision.Data, revision.Revision)
+		} else {
+			assert.Empty(t, revision.Data, revision.Revision)
+			assert.NotEmpty(t, revision.Hash, revision.Revision)
+		}
+	}
+
+	// apply to all nodes when the rollout is disabled
+	rolloutCfg = `{"enable":false}`
+	assert.True(t, p1.SyncCacheIfChanged(newConfigMap("90")))
+	assert.Equal(t, int64(90), getThreshold(p1, canaryNode))
+	assert.Equal(t, int64(90), getThreshold(p1, stableNode))
+	assert.Equal(t, config.RolloutPhaseStable, p1.GetRolloutStatus().Phase)
+}
+
+func Test_checkCanaryHealth(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	slov1alpha1.AddToScheme(scheme)
+	startTime := time.Now().Add(-time.Minute)
+	canaryNodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	}
+	newNodeMetric := func(name string) *slov1alpha1.NodeMetric {
+		return &slov1alpha1.NodeMetric{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: slov1alpha1.NodeMetricStatus{
+				UpdateTime: &metav1.Time{Time: time.Now()},
+			},
+		}
+	}
+	newEvictEvent := func(name, host string, count int32, lastTimestamp time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Namespace: "default", Name: name},
+			Reason:        koordletEvictPodReason,
+			Source:        corev1.EventSource{Component: "koordlet-qosManager", Host: host},
+			Count:         count,
+			LastTimestamp: metav1.Time{Time: lastTimestamp},
+		}
+	}
+	tests := []struct {
+		name        string
+		canaryNodes []*corev1.Node
+		cfg         *configuration.RolloutCfg
+		objs        []client.Object
+		wantHealthy bool
+	}{
+		{
+			name:        "no canary node",
+			canaryNodes: []*corev1.Node{},
+			cfg:         &configuration.RolloutCfg{},
+			wantHealthy: false,
+		},
+		{
+			name: "all canary nodes are healthy",
+			cfg:  &configuration.RolloutCfg{},
+			objs: []client.Object{
+				newNodeMetric("node-0"), newNodeMetric("node-1"),
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}},
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+			},
+			wantHealthy: true,
+		},
+		{
+			name: "strategy failed on the canary node",
+			cfg:  &configuration.RolloutCfg{MaxUnhealthyPercent: pointer.Int64(0)},
+			objs: []client.Object{
+				newNodeMetric("node-0"), newNodeMetric("node-1"),
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}},
+				&slov1alpha1.NodeSLO{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+					Status: slov1alpha1.NodeSLOStatus{
+						Conditions: []slov1alpha1.StrategyCondition{
+							{
+								Strategy:           "CPUSuppress",
+								Type:               slov1alpha1.StrategyConditionError,
+								LastTransitionTime: metav1.Now(),
+							},
+						},
+					},
+				},
+			},
+			wantHealthy: false,
+		},
+		{
+			name: "unhealthy nodes are tolerated",
+			cfg:  &configuration.RolloutCfg{MaxUnhealthyPercent: pointer.Int64(50)},
+			objs: []client.Object{
+				newNodeMetric("node-0"),
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}},
+			},
+			wantHealthy: true,
+		},
+		{
+			name: "ls cpu pressure exceeds on the canary node",
+			cfg:  &configuration.RolloutCfg{MaxUnhealthyPercent: pointer.Int64(0), MaxLSCPUPressurePercent: pointer.Int64(20)},
+			objs: []client.Object{
+				newNodeMetric("node-0"),
+				&slov1alpha1.NodeMetric{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+					Status: slov1alpha1.NodeMetricStatus{
+						UpdateTime: &metav1.Time{Time: time.Now()},
+						NodeMetric: &slov1alpha1.NodeMetricInfo{LSCPUPressurePercent: pointer.Int64(30)},
+					},
+				},
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}},
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+			},
+			wantHealthy: false,
+		},
+		{
+			name: "too many evictions on the canary nodes",
+			cfg:  &configuration.RolloutCfg{MaxEvictionCount: pointer.Int64(2)},
+			objs: []client.Object{
+				newNodeMetric("node-0"), newNodeMetric("node-1"),
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}},
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+				newEvictEvent("evict-0", "node-0", 2, time.Now()),
+				newEvictEvent("evict-1", "node-1", 1, time.Now()),
+				newEvictEvent("evict-2", "node-2", 5, time.Now()),
+			},
+			wantHealthy: false,
+		},
+		{
+			name: "evictions before the canary are ignored",
+			cfg:  &configuration.RolloutCfg{MaxEvictionCount: pointer.Int64(2)},
+			objs: []client.Object{
+				newNodeMetric("node-0"), newNodeMetric("node-1"),
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}},
+				&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+				newEvictEvent("evict-0", "node-0", 2, time.Now()),
+				newEvictEvent("evict-1", "node-1", 3, startTime.Add(-time.Minute)),
+			},
+			wantHealthy: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objs...).Build()
+			p := NewSLOCfgHandlerForConfigMapEvent(fakeClient, DefaultSLOCfg(), &record.FakeRecorder{})
+			nodes := canaryNodes
+			if tt.canaryNodes != nil {
+				nodes = tt.canaryNodes
+			}
+			gotHealthy, _, err := p.checkCanaryHealth(nodes, tt.cfg, startTime)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHealthy, gotHealthy)
+		})
+	}
+}
@@ -123,3 +123,9 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 	}
 	return item.object, true
 }
+
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
@@ -56,6 +56,11 @@ func Test_Cache_Set(t *testing.T) {
 	assert.True(t, !found, "value not found", "checkSet")
 	assert.Nil(t, value, "value must be nil", "checkSet")
 
+	_ = cache.SetDefault("key", "value")
+	cache.Delete("key")
+	value, found = cache.Get("key")
+	assert.True(t, !found, "value not found", "checkDelete")
+	assert.Nil(t, value, "value must be nil", "checkDelete")
 }
 
 func Test_gcExpiredCache(t *testing.T) {
@@ -60,3 +60,19 @@ func GetContainerBatchMemoryByteRequest(c *corev1.Container) int64 {
 func GetContainerBatchMemoryByteLimit(c *corev1.Container) int64 {
 	return GetBatchMemoryFromResourceList(c.Resources.Limits)
 }
+
+func GetMidMilliCPUFromResourceList(r corev1.ResourceList) int64 {
+	// assert r != nil
+	if milliCPU, ok := r[extension.MidCPU]; ok {
+		return milliCPU.Value()
+	}
+	return -1
+}
+
+func GetMidMemoryFromResourceList(r corev1.ResourceList) int64 {
+	// assert r != nil
+	if memory, ok := r[extension.MidMemory]; ok {
+		return memory.Value()
+	}
+	return -1
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+// GetPodBatchRequest returns the batch resources requested by the Pod.
+func GetPodBatchRequest(pod *corev1.Pod) corev1.ResourceList {
+	return util.GetPodRequest(pod, extension.BatchResourceNames...)
+}
+
+// GetNamespaceBatchUsed returns the batch resources requested by the active Pods in the namespace.
+func GetNamespaceBatchUsed(ctx context.Context, c client.Client, namespace string) (corev1.ResourceList, error) {
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, client.InNamespace(namespace), utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	return sumPodsBatchRequest(podList.Items, nil), nil
+}
+
+// GetElasticQuotaBatchUsed returns the batch resources requested by the active Pods of the ElasticQuota,
+// which includes the Pods labeled with the quota name and the unlabeled Pods in the namespaces of the quota.
+func GetElasticQuotaBatchUsed(ctx context.Context, c client.Client, quota *v1alpha1.ElasticQuota) (corev1.ResourceList, error) {
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("label.quotaName", quota.Name),
+	}, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	used := sumPodsBatchRequest(podList.Items, func(pod *corev1.Pod) bool {
+		return extension.GetQuotaName(pod) == quota.Name
+	})
+
+	namespaces := extension.GetAnnotationQuotaNamespaces(quota)
+	if quota.Name == quota.Namespace {
+		namespaces = append(namespaces, quota.Namespace)
+	}
+	visited := map[string]bool{}
+	for _, namespace := range namespaces {
+		if visited[namespace] {
+			continue
+		}
+		visited[namespace] = true
+		podList := &corev1.PodList{}
+		if err := c.List(ctx, podList, client.InNamespace(namespace), utilclient.DisableDeepCopy); err != nil {
+			return nil, err
+		}
+		used = quotav1.Add(used, sumPodsBatchRequest(podList.Items, func(pod *corev1.Pod) bool {
+			return extension.GetQuotaName(pod) == ""
+		}))
+	}
+	return used, nil
+}
+
+func sumPodsBatchRequest(pods []corev1.Pod, filter func(pod *corev1.Pod) bool) corev1.ResourceList {
+	used := corev1.ResourceList{}
+	for i := range pods {
+		pod := &pods[i]
+		if util.IsPodTerminated(pod) || (filter != nil && !filter(pod)) {
+			continue
+		}
+		used = quotav1.Add(used, GetPodBatchRequest(pod))
+	}
+	return used
+}
+
+// CheckBatchResourceQuota returns an error if the request exceeds the batch resource quota.
+func CheckBatchResourceQuota(quota, used, request corev1.ResourceList) error {
+	var exceeded []string
+	for resourceName, limit := range quota {
+		requested, ok := request[resourceName]
+		if !ok || requested.IsZero() {
+			continue
+		}
+		usedQuantity := used[resourceName]
+		total := usedQuantity.DeepCopy()
+		total.Add(requested)
+		if total.Cmp(limit) > 0 {
+			exceeded = append(exceeded, fmt.Sprintf("%s=%s (used: %s, limited: %s)",
+				resourceName, requested.String(), usedQuantity.String(), limit.String()))
+		}
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+	sort.Strings(exceeded)
+	return fmt.Errorf("requested: %s", strings.Join(exceeded, ", "))
+}
@@ -294,6 +294,43 @@ func NoneResctrlQOS() *slov1alpha1.ResctrlQOS {
 	}
 }
 
+// DefaultResctrlAdaptiveCfg returns the default configuration for the adaptive resctrl policy.
+func DefaultResctrlAdaptiveCfg() *slov1alpha1.ResctrlAdaptiveCfg {
+	return &slov1alpha1.ResctrlAdaptiveCfg{
+		Enable:                         pointer.Bool(false),
+		CATRangeEndMinPercent:          pointer.Int64(10),
+		MBAMinPercent:                  pointer.Int64(30),
+		StepPercent:                    pointer.Int64(10),
+		CPIDegradationThresholdPercent: pointer.Int64(20),
+		MemoryBandwidthDegradationThresholdPercent: pointer.Int64(20),
+		WindowSeconds:         pointer.Int64(60),
+		BaselineWindowSeconds: pointer.Int64(600),
+	}
+}
+
+// DefaultCPUSuppressLatencyConfig returns the default configuration for the lsLatency cpu suppress policy.
+func DefaultCPUSuppressLatencyConfig() *slov1alpha1.CPUSuppressLatencyConfig {
+	return &slov1alpha1.CPUSuppressLatencyConfig{
+		LSCPUPressureTargetPercent:          pointer.Int64(5),
+		LSRunQueueLatencyTargetMicroSeconds: pointer.Int64(2000),
+		ProportionalGainPercent:             pointer.Int64(20),
+		IntegralGainPercent:                 pointer.Int64(10),
+		DerivativeGainPercent:               pointer.Int64(0),
+	}
+}
+
+// DefaultBlkIODynamicCfg returns the default configuration for the dynamic blkio throttling.
+func DefaultBlkIODynamicCfg() *slov1alpha1.BlkIODynamicCfg {
+	return &slov1alpha1.BlkIODynamicCfg{
+		Enable:                       pointer.Bool(false),
+		TargetLatency:                pointer.Int64(10000),
+		UtilizationThresholdPercent:  pointer.Int64(50),
+		LSIOPressureThresholdPercent: pointer.Int64(10),
+		MinBPSPercent:                pointer.Int64(10),
+		StepPercent:                  pointer.Int64(20),
+	}
+}
+
 // NoneMemoryQOS returns the all-disabled configuration for memory qos strategy.
 func NoneMemoryQOS() *slov1alpha1.MemoryQOS {
 	return &slov1alpha1.MemoryQOS{
@@ -336,10 +373,12 @@ func DefaultCPUBurstStrategy() *slov1alpha1.CPUBurstStrategy {
 
 func DefaultCPUBurstConfig() slov1alpha1.CPUBurstConfig {
 	return slov1alpha1.CPUBurstConfig{
-		Policy:                     slov1alpha1.CPUBurstNone,
-		CPUBurstPercent:            pointer.Int64(1000),
-		CFSQuotaBurstPercent:       pointer.Int64(300),
-		CFSQuotaBurstPeriodSeconds: pointer.Int64(-1),
+		Policy:                         slov1alpha1.CPUBurstNone,
+		CPUBurstPercent:                pointer.Int64(1000),
+		CFSQuotaBurstPercent:           pointer.Int64(300),
+		CFSQuotaBurstPeriodSeconds:     pointer.Int64(-1),
+		ThrottledRatioThresholdPercent: pointer.Int64(10),
+		HistoryWindowSeconds:           pointer.Int64(300),
 	}
 }
 
@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package victim
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	policyv1lister "k8s.io/client-go/listers/policy/v1"
+	schedulingcorev1helper "k8s.io/component-helpers/scheduling/corev1"
+	apiscorehelper "k8s.io/kubernetes/pkg/apis/core/helper"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+var koordPriorityClassOrder = map[extension.PriorityClass]int{
+	extension.PriorityNone:  5,
+	extension.PriorityProd:  4,
+	extension.PriorityMid:   3,
+	extension.PriorityBatch: 2,
+	extension.PriorityFree:  1,
+}
+
+var koordQoSClassOrder = map[extension.QoSClass]int{
+	extension.QoSNone:   5,
+	extension.QoSSystem: 4,
+	extension.QoSLSE:    4,
+	extension.QoSLSR:    3,
+	extension.QoSLS:     2,
+	extension.QoSBE:     1,
+}
+
+var k8sQoSClassOrder = map[corev1.PodQOSClass]int{
+	corev1.PodQOSGuaranteed: 3,
+	corev1.PodQOSBurstable:  2,
+	corev1.PodQOSBestEffort: 1,
+}
+
+func cmpInt64(a, b int64) int {
+	if a == b {
+		return 0
+	}
+	if a > b {
+		return 1
+	}
+	return -1
+}
+
+// Priority compares pods by Priority
+func Priority(p1, p2 *corev1.Pod) int {
+	return cmpInt64(int64(schedulingcorev1helper.PodPriority(p1)), int64(schedulingcorev1helper.PodPriority(p2)))
+}
+
+// KubernetesQoSClass compares pods by Kubernetes QosClass
+func KubernetesQoSClass(p1, p2 *corev1.Pod) int {
+	return cmpInt64(int64(k8sQoSClassOrder[util.GetKubeQosClass(p1)]), int64(k8sQoSClassOrder[util.GetKubeQosClass(p2)]))
+}
+
+// KoordinatorQoSClass compares pods by the Koordinator QoSClass
+func KoordinatorQoSClass(p1, p2 *corev1.Pod) int {
+	qos1 := koordQoSClassOrder[extension.GetPodQoSClassWithDefault(p1)]
+	qos2 := koordQoSClassOrder[extension.GetPodQoSClassWithDefault(p2)]
+	return cmpInt64(int64(qos1), int64(qos2))
+}
+
+// KoordinatorPriorityClass compares pods by the Koordinator PriorityClass
+func KoordinatorPriorityClass(p1, p2 *corev1.Pod) int {
+	priorityClass1 := koordPriorityClassOrder[extension.GetPodPriorityClassWithDefault(p1)]
+	priorityClass2 := koordPriorityClassOrder[extension.GetPodPriorityClassWithDefault(p2)]
+	return cmpInt64(int64(priorityClass1), int64(priorityClass2))
+}
+
+// DeletionCost compares pods by the annotation `controller.kubernetes.io/pod-deletion-cost`
+func DeletionCost(p1, p2 *corev1.Pod) int {
+	p1DeletionCost, _ := apiscorehelper.GetDeletionCostFromPodAnnotations(p1.Annotations)
+	p2DeletionCost, _ := apiscorehelper.GetDeletionCostFromPodAnnotations(p2.Annotations)
+	return cmpInt64(int64(p1DeletionCost), int64(p2DeletionCost))
+}
+
+// EvictionCost compares pods by the annotation `scheduling.koordinator.sh/eviction-cost`
+func EvictionCost(p1, p2 *corev1.Pod) int {
+	p1EvictionCost, _ := extension.GetEvictionCost(p1.Annotations)
+	p2EvictionCost, _ := extension.GetEvictionCost(p2.Annotations)
+	return cmpInt64(int64(p1EvictionCost), int64(p2EvictionCost))
+}
+
+// DisruptionBudget compares pods by the disruptions allowed for their workloads.
+// The pods allowing more disruptions are preferred, and the pods without disruption budget are the most preferred.
+func DisruptionBudget(disruptionsAllowed DisruptionsAllowedFunc) CompareFn {
+	return func(p1, p2 *corev1.Pod) int {
+		allowed1, found1 := disruptionsAllowed(p1)
+		allowed2, found2 := disruptionsAllowed(p2)
+		if !found1 || !found2 {
+			if found1 == found2 {
+				return 0
+			}
+			if !found1 {
+				return -1
+			}
+			return 1
+		}
+		return cmpInt64(int64(allowed2), int64(allowed1))
+	}
+}
+
+// Restarts compares pods by the restart count of containers, the pods restarted more times are preferred.
+func Restarts(p1, p2 *corev1.Pod) int {
+	return cmpInt64(int64(restartCount(p2)), int64(restartCount(p1)))
+}
+
+func restartCount(pod *corev1.Pod) int32 {
+	var count int32
+	for _, status := range pod.Status.ContainerStatuses {
+		count += status.RestartCount
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		count += status.RestartCount
+	}
+	return count
+}
+
+// Age compares the pods by the creation timestamp, the younger pods are preferred.
+func Age(p1, p2 *corev1.Pod) int {
+	if p1.CreationTimestamp.Equal(&p2.CreationTimestamp) {
+		return 0
+	}
+	if p1.CreationTimestamp.Before(&p2.CreationTimestamp) {
+		return 1
+	}
+	return -1
+}
+
+// PDBDisruptionsAllowed returns a DisruptionsAllowedFunc that reads the PodDisruptionBudgets from the lister.
+// If the pod matches multiple PodDisruptionBudgets, the minimum disruptions allowed is returned.
+func PDBDisruptionsAllowed(lister policyv1lister.PodDisruptionBudgetLister) DisruptionsAllowedFunc {
+	return func(pod *corev1.Pod) (int32, bool) {
+		pdbs, err := lister.PodDisruptionBudgets(pod.Namespace).List(labels.Everything())
+		if err != nil {
+			return 0, false
+		}
+		var allowed int32
+		found := false
+		for _, pdb := range pdbs {
+			if !matchPDB(pdb, pod) {
+				continue
+			}
+			if !found || pdb.Status.DisruptionsAllowed < allowed {
+				allowed = pdb.Status.DisruptionsAllowed
+			}
+			found = true
+		}
+		return allowed, found
+	}
+}
+
+func matchPDB(pdb *policyv1.PodDisruptionBudget, pod *corev1.Pod) bool {
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	// an empty selector matches nothing in policy/v1 for the eviction API
+	if selector.Empty() {
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
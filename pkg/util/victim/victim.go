@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package victim ranks the victim pods for all the eviction paths, e.g. descheduling, migration arbitration,
+// the koordlet evictions and the quota revocation, so that the victims are chosen in the same way.
+package victim
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Factor is a factor to rank the victims.
+type Factor string
+
+const (
+	// FactorPriority prefers the pods with lower Koordinator PriorityClass and lower Priority.
+	FactorPriority Factor = "Priority"
+	// FactorQoSClass prefers the pods with lower Kubernetes QoSClass and lower Koordinator QoSClass.
+	FactorQoSClass Factor = "QoSClass"
+	// FactorDeletionCost prefers the pods with lower `controller.kubernetes.io/pod-deletion-cost`.
+	FactorDeletionCost Factor = "DeletionCost"
+	// FactorEvictionCost prefers the pods with lower `scheduling.koordinator.sh/eviction-cost`.
+	FactorEvictionCost Factor = "EvictionCost"
+	// FactorDisruptionBudget prefers the pods whose workloads allow more disruptions.
+	FactorDisruptionBudget Factor = "DisruptionBudget"
+	// FactorUsage prefers the pods according to the resource usage compared by the eviction path.
+	FactorUsage Factor = "Usage"
+	// FactorRestarts prefers the pods restarted more times.
+	FactorRestarts Factor = "Restarts"
+	// FactorAge prefers the younger pods.
+	FactorAge Factor = "Age"
+)
+
+// DefaultFactors is the default order of factors to rank the victims.
+var DefaultFactors = []Factor{
+	FactorPriority,
+	FactorQoSClass,
+	FactorDeletionCost,
+	FactorEvictionCost,
+	FactorDisruptionBudget,
+	FactorUsage,
+	FactorRestarts,
+	FactorAge,
+}
+
+var factors = factorList(DefaultFactors)
+
+var factorsUsage = fmt.Sprintf("The ordered factors to rank the victims of evictions, separated by comma. Supported factors: %s.", factorList(DefaultFactors).String())
+
+// AddFlags adds the flags of victim ranking to the pflag.FlagSet of the component.
+func AddFlags(fs *pflag.FlagSet) {
+	fs.Var(&factors, "victim-ranking-factors", factorsUsage)
+}
+
+// AddGoFlags adds the flags of victim ranking to the flag.FlagSet of the component, e.g. the koordlet.
+func AddGoFlags(fs *flag.FlagSet) {
+	fs.Var(&factors, "victim-ranking-factors", factorsUsage)
+}
+
+type factorList []Factor
+
+func (l factorList) String() string {
+	var s []string
+	for _, f := range l {
+		s = append(s, string(f))
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *factorList) Type() string {
+	return "strings"
+}
+
+func (l *factorList) Set(value string) error {
+	parsed, err := ParseFactors(value)
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// ParseFactors parses the factors separated by comma.
+func ParseFactors(value string) ([]Factor, error) {
+	var parsed []Factor
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		f := Factor(s)
+		if !isValidFactor(f) {
+			return nil, fmt.Errorf("unsupported victim ranking factor %q", s)
+		}
+		parsed = append(parsed, f)
+	}
+	return parsed, nil
+}
+
+func isValidFactor(f Factor) bool {
+	for _, v := range DefaultFactors {
+		if v == f {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareFn compares p1 and p2 and returns:
+//
+//	-1 if p1 is preferred to be evicted before p2
+//	 0 if p1 and p2 are equal
+//	+1 if p2 is preferred to be evicted before p1
+type CompareFn func(p1, p2 *corev1.Pod) int
+
+// DisruptionsAllowedFunc returns the number of disruptions allowed for the workload of the pod,
+// and false if the workload of the pod has no disruption budget.
+type DisruptionsAllowedFunc func(pod *corev1.Pod) (int32, bool)
+
+// Ranker ranks the victims by the configured factors.
+type Ranker struct {
+	factors            []Factor
+	usage              []CompareFn
+	disruptionsAllowed DisruptionsAllowedFunc
+	comparators        []CompareFn
+}
+
+type Option func(r *Ranker)
+
+// WithFactors overrides the factors configured by the flag.
+func WithFactors(factors ...Factor) Option {
+	return func(r *Ranker) {
+		r.factors = factors
+	}
+}
+
+// WithUsage sets the comparators of FactorUsage, e.g. the pods with higher usage are preferred.
+func WithUsage(usage ...CompareFn) Option {
+	return func(r *Ranker) {
+		r.usage = usage
+	}
+}
+
+// WithDisruptionsAllowed sets the function to get the disruption budget of FactorDisruptionBudget.
+func WithDisruptionsAllowed(fn DisruptionsAllowedFunc) Option {
+	return func(r *Ranker) {
+		r.disruptionsAllowed = fn
+	}
+}
+
+// NewRanker creates a Ranker with the factors configured by the flag.
+func NewRanker(opts ...Option) *Ranker {
+	r := &Ranker{
+		factors: factors,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.comparators = r.buildComparators()
+	return r
+}
+
+// Comparators returns the comparators of the factors in order.
+func (r *Ranker) Comparators() []CompareFn {
+	return r.comparators
+}
+
+func (r *Ranker) buildComparators() []CompareFn {
+	var comparators []CompareFn
+	for _, f := range r.factors {
+		switch f {
+		case FactorPriority:
+			comparators = append(comparators, KoordinatorPriorityClass, Priority)
+		case FactorQoSClass:
+			comparators = append(comparators, KubernetesQoSClass, KoordinatorQoSClass)
+		case FactorDeletionCost:
+			comparators = append(comparators, DeletionCost)
+		case FactorEvictionCost:
+			comparators = append(comparators, EvictionCost)
+		case FactorDisruptionBudget:
+			if r.disruptionsAllowed != nil {
+				comparators = append(comparators, DisruptionBudget(r.disruptionsAllowed))
+			}
+		case FactorUsage:
+			comparators = append(comparators, r.usage...)
+		case FactorRestarts:
+			comparators = append(comparators, Restarts)
+		case FactorAge:
+			comparators = append(comparators, Age)
+		}
+	}
+	return comparators
+}
+
+// Compare returns a negative value if p1 is preferred to be evicted before p2,
+// a positive value if p2 is preferred, and 0 if they are equal.
+func (r *Ranker) Compare(p1, p2 *corev1.Pod) int {
+	for _, cmp := range r.comparators {
+		if result := cmp(p1, p2); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// Sort stably sorts the pods so that the preferred victims come first.
+func (r *Ranker) Sort(pods []*corev1.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return r.Compare(pods[i], pods[j]) < 0
+	})
+}
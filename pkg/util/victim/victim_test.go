@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package victim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policyv1lister "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestParseFactors(t *testing.T) {
+	got, err := ParseFactors("EvictionCost, Priority,,Age")
+	assert.NoError(t, err)
+	assert.Equal(t, []Factor{FactorEvictionCost, FactorPriority, FactorAge}, got)
+
+	_, err = ParseFactors("Priority,Unknown")
+	assert.Error(t, err)
+
+	var l factorList
+	assert.NoError(t, l.Set("Restarts,Usage"))
+	assert.Equal(t, "Restarts,Usage", l.String())
+}
+
+func newTestPod(name string, mutate func(pod *corev1.Pod)) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+	if mutate != nil {
+		mutate(pod)
+	}
+	return pod
+}
+
+func podNames(pods []*corev1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestRankerSort(t *testing.T) {
+	pods := []*corev1.Pod{
+		newTestPod("prod", func(pod *corev1.Pod) {
+			pod.Labels = map[string]string{extension.LabelPodPriorityClass: string(extension.PriorityProd)}
+		}),
+		newTestPod("high-eviction-cost", func(pod *corev1.Pod) {
+			pod.Annotations = map[string]string{extension.AnnotationEvictionCost: "100"}
+		}),
+		newTestPod("low-priority", func(pod *corev1.Pod) {
+			pod.Spec.Priority = pointer.Int32(-1)
+		}),
+		newTestPod("protected-by-pdb", func(pod *corev1.Pod) {
+			pod.Labels = map[string]string{"app": "protected"}
+		}),
+		newTestPod("old", func(pod *corev1.Pod) {
+			pod.CreationTimestamp = metav1.NewTime(pod.CreationTimestamp.Add(-time.Hour))
+		}),
+		newTestPod("restarted", func(pod *corev1.Pod) {
+			pod.CreationTimestamp = metav1.NewTime(pod.CreationTimestamp.Add(-time.Hour))
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{RestartCount: 3}}
+		}),
+		newTestPod("young", nil),
+	}
+	disruptionsAllowed := func(pod *corev1.Pod) (int32, bool) {
+		if pod.Labels["app"] == "protected" {
+			return 0, true
+		}
+		return 0, false
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "default factors",
+			opts: []Option{WithDisruptionsAllowed(disruptionsAllowed)},
+			want: []string{"low-priority", "restarted", "young", "old", "protected-by-pdb", "high-eviction-cost", "prod"},
+		},
+		{
+			name: "ignore disruption budget without disruptions allowed func",
+			want: []string{"low-priority", "restarted", "protected-by-pdb", "young", "old", "high-eviction-cost", "prod"},
+		},
+		{
+			name: "custom factors",
+			opts: []Option{WithFactors(FactorAge, FactorPriority)},
+			want: []string{"low-priority", "high-eviction-cost", "protected-by-pdb", "young", "prod", "old", "restarted"},
+		},
+		{
+			name: "usage factor",
+			opts: []Option{
+				WithFactors(FactorUsage, FactorAge),
+				WithUsage(func(p1, p2 *corev1.Pod) int {
+					// prefer the pod named restarted
+					if p1.Name == p2.Name {
+						return 0
+					}
+					if p1.Name == "restarted" {
+						return -1
+					}
+					if p2.Name == "restarted" {
+						return 1
+					}
+					return 0
+				}),
+			},
+			want: []string{"restarted", "prod", "high-eviction-cost", "low-priority", "protected-by-pdb", "young", "old"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]*corev1.Pod, len(pods))
+			copy(got, pods)
+			NewRanker(tt.opts...).Sort(got)
+			assert.Equal(t, tt.want, podNames(got))
+		})
+	}
+}
+
+func TestPDBDisruptionsAllowed(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	pdbs := []*policyv1.PodDisruptionBudget{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pdb-1"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}}},
+			Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 2},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pdb-2"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}}},
+			Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pdb-empty-selector"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{}},
+		},
+	}
+	for _, pdb := range pdbs {
+		assert.NoError(t, indexer.Add(pdb))
+	}
+	fn := PDBDisruptionsAllowed(policyv1lister.NewPodDisruptionBudgetLister(indexer))
+
+	allowed, found := fn(newTestPod("pod-1", func(pod *corev1.Pod) {
+		pod.Labels = map[string]string{"app": "test"}
+	}))
+	assert.True(t, found)
+	assert.Equal(t, int32(2), allowed)
+
+	allowed, found = fn(newTestPod("pod-2", func(pod *corev1.Pod) {
+		pod.Labels = map[string]string{"app": "test", "tier": "web"}
+	}))
+	assert.True(t, found)
+	assert.Equal(t, int32(1), allowed)
+
+	_, found = fn(newTestPod("pod-3", nil))
+	assert.False(t, found)
+
+	_, found = fn(newTestPod("pod-4", func(pod *corev1.Pod) {
+		pod.Namespace = "other"
+		pod.Labels = map[string]string{"app": "test"}
+	}))
+	assert.False(t, found)
+}
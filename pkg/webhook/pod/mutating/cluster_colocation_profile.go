@@ -21,18 +21,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
@@ -49,6 +55,8 @@ var (
 
 // +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=config.koordinator.sh,resources=clustercolocationprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
 
 func (h *PodMutatingHandler) clusterColocationProfileMutatingPod(ctx context.Context, req admission.Request, pod *corev1.Pod) error {
 	if req.Operation != admissionv1.Create {
@@ -66,6 +74,7 @@ func (h *PodMutatingHandler) clusterColocationProfileMutatingPod(ctx context.Con
 	}
 
 	var matchedProfiles []*configv1alpha1.ClusterColocationProfile
+	var workloads []metav1.OwnerReference
 	for i := range profileList.Items {
 		profile := &profileList.Items[i]
 		if profile.Spec.NamespaceSelector != nil {
@@ -80,36 +89,108 @@ func (h *PodMutatingHandler) clusterColocationProfileMutatingPod(ctx context.Con
 				continue
 			}
 		}
+		if len(profile.Spec.WorkloadSelectors) > 0 {
+			if workloads == nil {
+				workloads = h.getPodWorkloads(ctx, pod)
+			}
+			if !matchWorkloadSelectors(workloads, profile.Spec.WorkloadSelectors) {
+				continue
+			}
+		}
 		matchedProfiles = append(matchedProfiles, profile)
 	}
+	matchedProfiles = filterProfilesByOrder(matchedProfiles)
 	if len(matchedProfiles) == 0 {
 		return nil
 	}
+	recorder := h.getProfileStatsRecorder()
 	skipUpdateResourceFromProfile := false
+	mutatingProfiles := 0
 	for _, profile := range matchedProfiles {
-		if extension.ShouldSkipUpdateResource(profile) {
-			skipUpdateResourceFromProfile = true
-		}
+		recorder.RecordMatched(profile.Name)
 		skip, err := shouldSkipProfile(profile)
 		if err != nil {
+			recorder.RecordError(profile.Name, err)
+			if profile.Spec.DryRun {
+				continue
+			}
 			return err
 		}
+		if profile.Spec.DryRun {
+			if !skip {
+				h.dryRunMutateByColocationProfile(ctx, pod, profile)
+			} else {
+				recorder.RecordSkipped(profile.Name)
+			}
+			continue
+		}
+		mutatingProfiles++
+		if extension.ShouldSkipUpdateResource(profile) {
+			skipUpdateResourceFromProfile = true
+		}
 		if skip {
+			recorder.RecordSkipped(profile.Name)
 			klog.V(4).Infof("skip mutate Pod %s/%s by clusterColocationProfile %s", pod.Namespace, pod.Name, profile.Name)
 			continue
 		}
 		err = h.doMutateByColocationProfile(ctx, pod, profile)
 		if err != nil {
+			recorder.RecordError(profile.Name, err)
 			return err
 		}
+		recorder.RecordMutated(profile.Name)
 		klog.V(4).Infof("mutate Pod %s/%s by clusterColocationProfile %s", pod.Namespace, pod.Name, profile.Name)
 	}
-	if skipUpdateResourceFromProfile || utilfeature.DefaultFeatureGate.Enabled(features.ColocationProfileSkipMutatingResources) {
+	if mutatingProfiles == 0 || skipUpdateResourceFromProfile || utilfeature.DefaultFeatureGate.Enabled(features.ColocationProfileSkipMutatingResources) {
 		return nil
 	}
 	return h.mutatePodResourceSpec(pod)
 }
 
+// dryRunMutateByColocationProfile runs the full mutation pipeline of the profile on a copy of the Pod, including the
+// resource spec mutation, and records the would-be patch without changing the Pod.
+func (h *PodMutatingHandler) dryRunMutateByColocationProfile(ctx context.Context, pod *corev1.Pod, profile *configv1alpha1.ClusterColocationProfile) {
+	recorder := h.getProfileStatsRecorder()
+	mutated := pod.DeepCopy()
+	err := h.doMutateByColocationProfile(ctx, mutated, profile)
+	if err == nil && !extension.ShouldSkipUpdateResource(profile) &&
+		!utilfeature.DefaultFeatureGate.Enabled(features.ColocationProfileSkipMutatingResources) {
+		err = h.mutatePodResourceSpec(mutated)
+	}
+	if err != nil {
+		klog.V(4).Infof("failed to dry-run mutate Pod %s/%s by clusterColocationProfile %s, err: %v", pod.Namespace, pod.Name, profile.Name, err)
+		recorder.RecordError(profile.Name, err)
+		return
+	}
+	patch, err := createPodPatch(pod, mutated)
+	if err != nil {
+		recorder.RecordError(profile.Name, err)
+		return
+	}
+	podName := pod.Name
+	if podName == "" {
+		podName = pod.GenerateName
+	}
+	recorder.RecordDryRun(profile.Name, &configv1alpha1.ColocationProfileMutation{
+		Pod:   fmt.Sprintf("%s/%s", pod.Namespace, podName),
+		Patch: string(patch),
+		Time:  metav1.Now(),
+	})
+	klog.V(4).Infof("dry-run mutate Pod %s/%s by clusterColocationProfile %s, patch: %s", pod.Namespace, podName, profile.Name, string(patch))
+}
+
+func createPodPatch(original, modified *corev1.Pod) ([]byte, error) {
+	originalBytes, err := json.Marshal(original)
+	if err != nil {
+		return nil, err
+	}
+	modifiedBytes, err := json.Marshal(modified)
+	if err != nil {
+		return nil, err
+	}
+	return strategicpatch.CreateTwoWayMergePatch(originalBytes, modifiedBytes, &corev1.Pod{})
+}
+
 func (h *PodMutatingHandler) matchNamespaceSelector(ctx context.Context, namespaceName string, namespaceSelector *metav1.LabelSelector) (bool, error) {
 	selector, err := util.GetFastLabelSelector(namespaceSelector)
 	if err != nil {
@@ -142,6 +223,91 @@ func (h *PodMutatingHandler) matchObjectSelector(pod, oldPod *corev1.Pod, object
 	return matched, nil
 }
 
+// getPodWorkloads resolves the workloads owning the Pod through the controller references,
+// e.g. ReplicaSet -> Deployment, Job -> CronJob.
+// The owners are read through the APIReader, so that no cluster-wide informer is started for them
+// and the just-created owners can be found.
+func (h *PodMutatingHandler) getPodWorkloads(ctx context.Context, pod *corev1.Pod) []metav1.OwnerReference {
+	var reader client.Reader = h.Client
+	if h.APIReader != nil {
+		reader = h.APIReader
+	}
+	workloads := []metav1.OwnerReference{}
+	ownerRef := metav1.GetControllerOf(pod)
+	for ownerRef != nil {
+		workloads = append(workloads, *ownerRef)
+		var owner client.Object
+		switch {
+		case ownerRef.Kind == "ReplicaSet" && isGroupOf(ownerRef.APIVersion, appsv1.GroupName):
+			owner = &appsv1.ReplicaSet{}
+		case ownerRef.Kind == "Job" && isGroupOf(ownerRef.APIVersion, batchv1.GroupName):
+			owner = &batchv1.Job{}
+		default:
+			return workloads
+		}
+		err := reader.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: ownerRef.Name}, owner)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				klog.Warningf("failed to get %s %s/%s of Pod %s/%s, err: %v", ownerRef.Kind, pod.Namespace, ownerRef.Name, pod.Namespace, pod.Name, err)
+			}
+			return workloads
+		}
+		if owner.GetUID() != ownerRef.UID {
+			return workloads
+		}
+		ownerRef = metav1.GetControllerOfNoCopy(owner)
+	}
+	return workloads
+}
+
+func isGroupOf(apiVersion, group string) bool {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	return err == nil && gv.Group == group
+}
+
+func matchWorkloadSelectors(workloads []metav1.OwnerReference, selectors []configv1alpha1.WorkloadSelector) bool {
+	for _, selector := range selectors {
+		for _, workload := range workloads {
+			if workload.Kind != selector.Kind {
+				continue
+			}
+			if selector.APIVersion != "" && workload.APIVersion != selector.APIVersion {
+				continue
+			}
+			if len(selector.Names) == 0 {
+				return true
+			}
+			for _, name := range selector.Names {
+				if workload.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// filterProfilesByOrder keeps the profiles with the highest order, sorted by names.
+// The dry-run profiles are always kept since they do not change the Pod.
+func filterProfilesByOrder(profiles []*configv1alpha1.ClusterColocationProfile) []*configv1alpha1.ClusterColocationProfile {
+	var maxOrder *int32
+	for _, profile := range profiles {
+		if !profile.Spec.DryRun && (maxOrder == nil || profile.Spec.Order > *maxOrder) {
+			maxOrder = pointer.Int32(profile.Spec.Order)
+		}
+	}
+	var filtered []*configv1alpha1.ClusterColocationProfile
+	for _, profile := range profiles {
+		if profile.Spec.DryRun || profile.Spec.Order == *maxOrder {
+			filtered = append(filtered, profile)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Name < filtered[j].Name
+	})
+	return filtered
+}
+
 func shouldSkipProfile(profile *configv1alpha1.ClusterColocationProfile) (bool, error) {
 	percent := 100
 	if profile.Spec.Probability != nil {
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=config.koordinator.sh,resources=clustercolocationprofiles/status,verbs=get;update;patch
+
+const (
+	profileStatusSyncInterval = 10 * time.Second
+)
+
+var (
+	defaultProfileStatsRecorder = newProfileStatsRecorder()
+)
+
+// profileStats is the admission statistics of a ClusterColocationProfile since the last sync.
+type profileStats struct {
+	matched            int64
+	mutated            int64
+	skipped            int64
+	dryRun             int64
+	lastError          string
+	lastErrorTime      *metav1.Time
+	lastDryRunMutation *configv1alpha1.ColocationProfileMutation
+}
+
+func (s *profileStats) merge(other *profileStats) {
+	s.matched += other.matched
+	s.mutated += other.mutated
+	s.skipped += other.skipped
+	s.dryRun += other.dryRun
+	if other.lastErrorTime != nil && (s.lastErrorTime == nil || s.lastErrorTime.Before(other.lastErrorTime)) {
+		s.lastError = other.lastError
+		s.lastErrorTime = other.lastErrorTime
+	}
+	if other.lastDryRunMutation != nil &&
+		(s.lastDryRunMutation == nil || s.lastDryRunMutation.Time.Before(&other.lastDryRunMutation.Time)) {
+		s.lastDryRunMutation = other.lastDryRunMutation
+	}
+}
+
+func (s *profileStats) applyTo(status *configv1alpha1.ClusterColocationProfileStatus) {
+	status.MatchedPods += s.matched
+	status.MutatedPods += s.mutated
+	status.SkippedPods += s.skipped
+	status.DryRunPods += s.dryRun
+	if s.lastErrorTime != nil {
+		status.LastError = s.lastError
+		status.LastErrorTime = s.lastErrorTime.DeepCopy()
+	}
+	if s.lastDryRunMutation != nil {
+		status.LastDryRunMutation = s.lastDryRunMutation.DeepCopy()
+	}
+}
+
+// profileStatsRecorder accumulates the admission statistics of the ClusterColocationProfiles in memory,
+// which are synced to the status of the profiles periodically.
+type profileStatsRecorder struct {
+	lock  sync.Mutex
+	stats map[string]*profileStats
+}
+
+func newProfileStatsRecorder() *profileStatsRecorder {
+	return &profileStatsRecorder{
+		stats: map[string]*profileStats{},
+	}
+}
+
+func (r *profileStatsRecorder) update(profileName string, fn func(s *profileStats)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	s := r.stats[profileName]
+	if s == nil {
+		s = &profileStats{}
+		r.stats[profileName] = s
+	}
+	fn(s)
+}
+
+func (r *profileStatsRecorder) RecordMatched(profileName string) {
+	r.update(profileName, func(s *profileStats) { s.matched++ })
+}
+
+func (r *profileStatsRecorder) RecordMutated(profileName string) {
+	r.update(profileName, func(s *profileStats) { s.mutated++ })
+}
+
+func (r *profileStatsRecorder) RecordSkipped(profileName string) {
+	r.update(profileName, func(s *profileStats) { s.skipped++ })
+}
+
+func (r *profileStatsRecorder) RecordDryRun(profileName string, mutation *configv1alpha1.ColocationProfileMutation) {
+	r.update(profileName, func(s *profileStats) {
+		s.dryRun++
+		s.lastDryRunMutation = mutation
+	})
+}
+
+func (r *profileStatsRecorder) RecordError(profileName string, err error) {
+	now := metav1.Now()
+	r.update(profileName, func(s *profileStats) {
+		s.lastError = err.Error()
+		s.lastErrorTime = &now
+	})
+}
+
+// drain returns the accumulated statistics and resets the recorder.
+func (r *profileStatsRecorder) drain() map[string]*profileStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	stats := r.stats
+	r.stats = map[string]*profileStats{}
+	return stats
+}
+
+// restore merges back the statistics failed to sync.
+func (r *profileStatsRecorder) restore(profileName string, stats *profileStats) {
+	r.update(profileName, func(s *profileStats) { s.merge(stats) })
+}
+
+var _ manager.Runnable = &PodMutatingHandler{}
+
+// Start syncs the admission statistics of the ClusterColocationProfiles to their status periodically.
+func (h *PodMutatingHandler) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, h.syncProfileStatus, profileStatusSyncInterval)
+	// flush the rest statistics before exiting
+	h.syncProfileStatus(context.Background())
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The webhook serves on all replicas,
+// so every replica has to sync the statistics it accumulates.
+func (h *PodMutatingHandler) NeedLeaderElection() bool {
+	return false
+}
+
+func (h *PodMutatingHandler) syncProfileStatus(ctx context.Context) {
+	recorder := h.getProfileStatsRecorder()
+	for profileName, stats := range recorder.drain() {
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			profile := &configv1alpha1.ClusterColocationProfile{}
+			if err := h.Client.Get(ctx, types.NamespacedName{Name: profileName}, profile); err != nil {
+				return err
+			}
+			stats.applyTo(&profile.Status)
+			return h.Client.Status().Update(ctx, profile)
+		})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			klog.Warningf("failed to update status of clusterColocationProfile %s, err: %v", profileName, err)
+			recorder.restore(profileName, stats)
+		}
+	}
+}
+
+func (h *PodMutatingHandler) getProfileStatsRecorder() *profileStatsRecorder {
+	if h.profileStatsRecorder != nil {
+		return h.profileStatsRecorder
+	}
+	return defaultProfileStatsRecorder
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestClusterColocationProfileDryRunAndStatus(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	decoder, _ := admission.NewDecoder(scheme.Scheme)
+	handler := &PodMutatingHandler{
+		Client:               client,
+		Decoder:              decoder,
+		profileStatsRecorder: newProfileStatsRecorder(),
+	}
+
+	zeroPercent := intstr.FromInt(0)
+	profiles := []*configv1alpha1.ClusterColocationProfile{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "dry-run-profile"},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"koordinator-colocation-pod": "true"},
+				},
+				QoSClass: string(extension.QoSBE),
+				Labels:   map[string]string{"dry-run": "true"},
+				DryRun:   true,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "skipped-profile"},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"koordinator-colocation-pod": "true"},
+				},
+				Probability: &zeroPercent,
+				Labels:      map[string]string{"skipped": "true"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "failed-profile"},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"koordinator-colocation-pod": "true"},
+				},
+				PriorityClassName: "not-exist",
+				DryRun:            true,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mutating-profile"},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"koordinator-colocation-pod": "true"},
+				},
+				Labels: map[string]string{"mutated": "true"},
+			},
+		},
+	}
+	for _, profile := range profiles {
+		assert.NoError(t, client.Create(context.TODO(), profile))
+	}
+
+	for i := 0; i < 2; i++ {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      fmt.Sprintf("test-pod-%d", i),
+				Labels:    map[string]string{"koordinator-colocation-pod": "true"},
+			},
+		}
+		req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+		err := handler.clusterColocationProfileMutatingPod(context.TODO(), req, pod)
+		assert.NoError(t, err)
+		expectedLabels := map[string]string{
+			"koordinator-colocation-pod": "true",
+			"mutated":                    "true",
+		}
+		assert.Equal(t, expectedLabels, pod.Labels)
+	}
+
+	handler.syncProfileStatus(context.TODO())
+	assert.Empty(t, handler.profileStatsRecorder.drain())
+
+	getStatus := func(name string) configv1alpha1.ClusterColocationProfileStatus {
+		profile := &configv1alpha1.ClusterColocationProfile{}
+		assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: name}, profile))
+		return profile.Status
+	}
+
+	dryRunStatus := getStatus("dry-run-profile")
+	assert.Equal(t, int64(2), dryRunStatus.MatchedPods)
+	assert.Equal(t, int64(2), dryRunStatus.DryRunPods)
+	assert.Equal(t, int64(0), dryRunStatus.MutatedPods)
+	assert.NotNil(t, dryRunStatus.LastDryRunMutation)
+	assert.Equal(t, "default/test-pod-1", dryRunStatus.LastDryRunMutation.Pod)
+	assert.Equal(t, `{"metadata":{"labels":{"dry-run":"true","koordinator.sh/qosClass":"BE"}}}`, dryRunStatus.LastDryRunMutation.Patch)
+
+	skippedStatus := getStatus("skipped-profile")
+	assert.Equal(t, int64(2), skippedStatus.MatchedPods)
+	assert.Equal(t, int64(2), skippedStatus.SkippedPods)
+	assert.Equal(t, int64(0), skippedStatus.MutatedPods)
+
+	failedStatus := getStatus("failed-profile")
+	assert.Equal(t, int64(2), failedStatus.MatchedPods)
+	assert.Equal(t, int64(0), failedStatus.DryRunPods)
+	assert.NotEmpty(t, failedStatus.LastError)
+	assert.NotNil(t, failedStatus.LastErrorTime)
+
+	mutatingStatus := getStatus("mutating-profile")
+	assert.Equal(t, int64(2), mutatingStatus.MatchedPods)
+	assert.Equal(t, int64(2), mutatingStatus.MutatedPods)
+
+	// statistics are accumulated onto the existing status
+	handler.profileStatsRecorder.RecordMutated("mutating-profile")
+	handler.profileStatsRecorder.RecordMutated("deleted-profile")
+	handler.syncProfileStatus(context.TODO())
+	assert.Equal(t, int64(3), getStatus("mutating-profile").MutatedPods)
+	assert.Empty(t, handler.profileStatsRecorder.drain())
+}
+
+func TestClusterColocationProfileDryRunResourceSpec(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	decoder, _ := admission.NewDecoder(scheme.Scheme)
+	handler := &PodMutatingHandler{
+		Client:               client,
+		Decoder:              decoder,
+		profileStatsRecorder: newProfileStatsRecorder(),
+	}
+	assert.NoError(t, client.Create(context.TODO(), &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "koordinator-batch"},
+		Value:      extension.PriorityBatchValueMax,
+	}))
+	assert.NoError(t, client.Create(context.TODO(), &configv1alpha1.ClusterColocationProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "dry-run-profile"},
+		Spec: configv1alpha1.ClusterColocationProfileSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"koordinator-colocation-pod": "true"},
+			},
+			PriorityClassName: "koordinator-batch",
+			DryRun:            true,
+		},
+	}))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+			Labels:    map[string]string{"koordinator-colocation-pod": "true"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test-container",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+			},
+		},
+	}
+	originalPod := pod.DeepCopy()
+	req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+	assert.NoError(t, handler.clusterColocationProfileMutatingPod(context.TODO(), req, pod))
+	assert.Equal(t, originalPod, pod)
+
+	handler.syncProfileStatus(context.TODO())
+	profile := &configv1alpha1.ClusterColocationProfile{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "dry-run-profile"}, profile))
+	assert.Equal(t, int64(1), profile.Status.DryRunPods)
+	assert.NotNil(t, profile.Status.LastDryRunMutation)
+	// the resource spec mutation is included in the dry-run patch
+	assert.Contains(t, profile.Status.LastDryRunMutation.Patch, string(extension.BatchCPU))
+}
+
+func TestProfileStatsMerge(t *testing.T) {
+	t1 := metav1.Unix(100, 0)
+	t2 := metav1.Unix(200, 0)
+	s := &profileStats{matched: 1, lastError: "new", lastErrorTime: &t2}
+	s.merge(&profileStats{matched: 2, mutated: 1, lastError: "old", lastErrorTime: &t1})
+	assert.Equal(t, &profileStats{matched: 3, mutated: 1, lastError: "new", lastErrorTime: &t2}, s)
+}
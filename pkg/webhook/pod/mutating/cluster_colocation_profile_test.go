@@ -22,6 +22,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -1264,3 +1266,169 @@ func TestClusterColocationProfileMutatingPod(t *testing.T) {
 
 	}
 }
+
+func TestClusterColocationProfileMatchWorkloads(t *testing.T) {
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-deployment-5d7f8b",
+			UID:       "rs-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "test-deployment", UID: "deployment-uid", Controller: pointer.Bool(true)},
+			},
+		},
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-cronjob-27777",
+			UID:       "job-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "batch/v1", Kind: "CronJob", Name: "test-cronjob", UID: "cronjob-uid", Controller: pointer.Bool(true)},
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		ownerRef  *metav1.OwnerReference
+		selectors []configv1alpha1.WorkloadSelector
+		want      bool
+	}{
+		{
+			name:      "no owner",
+			selectors: []configv1alpha1.WorkloadSelector{{Kind: "Deployment"}},
+			want:      false,
+		},
+		{
+			name:      "match deployment through replicaset",
+			ownerRef:  &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test-deployment-5d7f8b", UID: "rs-uid", Controller: pointer.Bool(true)},
+			selectors: []configv1alpha1.WorkloadSelector{{APIVersion: "apps/v1", Kind: "Deployment", Names: []string{"test-deployment"}}},
+			want:      true,
+		},
+		{
+			name:      "deployment name not matched",
+			ownerRef:  &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test-deployment-5d7f8b", UID: "rs-uid", Controller: pointer.Bool(true)},
+			selectors: []configv1alpha1.WorkloadSelector{{Kind: "Deployment", Names: []string{"other-deployment"}}},
+			want:      false,
+		},
+		{
+			name:      "replicaset uid mismatched",
+			ownerRef:  &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test-deployment-5d7f8b", UID: "other-uid", Controller: pointer.Bool(true)},
+			selectors: []configv1alpha1.WorkloadSelector{{Kind: "Deployment"}},
+			want:      false,
+		},
+		{
+			name:      "match cronjob through job",
+			ownerRef:  &metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "test-cronjob-27777", UID: "job-uid", Controller: pointer.Bool(true)},
+			selectors: []configv1alpha1.WorkloadSelector{{Kind: "StatefulSet"}, {Kind: "CronJob"}},
+			want:      true,
+		},
+		{
+			name:      "match job directly",
+			ownerRef:  &metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "test-cronjob-27777", UID: "job-uid", Controller: pointer.Bool(true)},
+			selectors: []configv1alpha1.WorkloadSelector{{Kind: "Job"}},
+			want:      true,
+		},
+		{
+			name:      "match argo workflow",
+			ownerRef:  &metav1.OwnerReference{APIVersion: "argoproj.io/v1alpha1", Kind: "Workflow", Name: "test-workflow", UID: "workflow-uid", Controller: pointer.Bool(true)},
+			selectors: []configv1alpha1.WorkloadSelector{{APIVersion: "argoproj.io/v1alpha1", Kind: "Workflow"}},
+			want:      true,
+		},
+		{
+			name:      "apiVersion mismatched",
+			ownerRef:  &metav1.OwnerReference{APIVersion: "apps.kruise.io/v1beta1", Kind: "StatefulSet", Name: "test-sts", UID: "sts-uid", Controller: pointer.Bool(true)},
+			selectors: []configv1alpha1.WorkloadSelector{{APIVersion: "apps/v1", Kind: "StatefulSet"}},
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the owners are missing from the cache and can only be read from the API server
+			client := fake.NewClientBuilder().Build()
+			apiReader := fake.NewClientBuilder().WithObjects(replicaSet, job).Build()
+			handler := &PodMutatingHandler{
+				Client:               client,
+				APIReader:            apiReader,
+				profileStatsRecorder: newProfileStatsRecorder(),
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+				},
+			}
+			if tt.ownerRef != nil {
+				pod.OwnerReferences = []metav1.OwnerReference{*tt.ownerRef}
+			}
+			profile := &configv1alpha1.ClusterColocationProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-profile"},
+				Spec: configv1alpha1.ClusterColocationProfileSpec{
+					WorkloadSelectors: tt.selectors,
+					Labels:            map[string]string{"mutated": "true"},
+				},
+			}
+			assert.NoError(t, client.Create(context.TODO(), profile))
+
+			req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+			err := handler.clusterColocationProfileMutatingPod(context.TODO(), req, pod)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, pod.Labels["mutated"] == "true")
+		})
+	}
+}
+
+func TestClusterColocationProfileOrder(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	handler := &PodMutatingHandler{
+		Client:               client,
+		profileStatsRecorder: newProfileStatsRecorder(),
+	}
+	profiles := []*configv1alpha1.ClusterColocationProfile{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile-a"},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Labels: map[string]string{"profile": "a", "profile-a": "true"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile-c"},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Order:  10,
+				Labels: map[string]string{"profile": "c"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile-b"},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Order:  10,
+				Labels: map[string]string{"profile": "b", "profile-b": "true"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile-d"},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Order:  100,
+				DryRun: true,
+				Labels: map[string]string{"profile": "d"},
+			},
+		},
+	}
+	for _, profile := range profiles {
+		assert.NoError(t, client.Create(context.TODO(), profile))
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+		},
+	}
+	req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+	err := handler.clusterColocationProfileMutatingPod(context.TODO(), req, pod)
+	assert.NoError(t, err)
+	expectedLabels := map[string]string{
+		"profile":   "c",
+		"profile-b": "true",
+	}
+	assert.Equal(t, expectedLabels, pod.Labels)
+}
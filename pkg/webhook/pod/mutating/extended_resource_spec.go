@@ -38,7 +38,7 @@ func (h *PodMutatingHandler) extendedResourceSpecMutatingPod(ctx context.Context
 }
 
 func (h *PodMutatingHandler) mutateByExtendedResources(pod *corev1.Pod) error {
-	// dump batch-resource of pod.spec.containers[*].resources.requests/limits into ExtendedResourceSpec{}
+	// dump batch-resource and mid-resource of pod.spec.containers[*].resources.requests/limits into ExtendedResourceSpec{}
 	extendedResourceSpec := &extension.ExtendedResourceSpec{}
 	containersSpec := map[string]extension.ExtendedResourceContainerSpec{}
 
@@ -48,6 +48,8 @@ func (h *PodMutatingHandler) mutateByExtendedResources(pod *corev1.Pod) error {
 		r := getContainerExtendedResourcesRequirement(container, []corev1.ResourceName{
 			extension.BatchCPU,
 			extension.BatchMemory,
+			extension.MidCPU,
+			extension.MidMemory,
 		})
 		if r == nil {
 			continue
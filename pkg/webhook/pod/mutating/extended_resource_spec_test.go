@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 func TestExtendedResourceSpecMutatingPod(t *testing.T) {
@@ -137,3 +138,52 @@ func TestExtendedResourceSpecMutatingPod(t *testing.T) {
 	}
 	assert.Equal(expectPod, pod)
 }
+
+func TestExtendedResourceSpecMutatingMidPod(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	decoder, _ := admission.NewDecoder(scheme.Scheme)
+	handler := &PodMutatingHandler{
+		Client:  client,
+		Decoder: decoder,
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod-1",
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSLS),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test-container-a",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							extension.MidCPU:    resource.MustParse("2000"),
+							extension.MidMemory: resource.MustParse("4Gi"),
+						},
+						Requests: corev1.ResourceList{
+							extension.MidCPU:    resource.MustParse("1000"),
+							extension.MidMemory: resource.MustParse("4Gi"),
+						},
+					},
+				},
+			},
+			Priority: pointer.Int32(extension.PriorityMidValueMax),
+		},
+	}
+
+	req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+	err := handler.extendedResourceSpecMutatingPod(context.TODO(), req, pod)
+	assert.NoError(t, err)
+
+	spec, err := extension.GetExtendedResourceSpec(pod.Annotations)
+	assert.NoError(t, err)
+	assert.NotNil(t, spec)
+	containerSpec := spec.Containers["test-container-a"]
+	assert.Equal(t, int64(2000), util.GetMidMilliCPUFromResourceList(containerSpec.Limits))
+	assert.Equal(t, int64(1000), util.GetMidMilliCPUFromResourceList(containerSpec.Requests))
+	assert.Equal(t, int64(4<<30), util.GetMidMemoryFromResourceList(containerSpec.Limits))
+	assert.Equal(t, int64(4<<30), util.GetMidMemoryFromResourceList(containerSpec.Requests))
+}
@@ -34,8 +34,16 @@ import (
 type PodMutatingHandler struct {
 	Client client.Client
 
+	// APIReader reads objects from the API server directly. It is used for the objects that are
+	// not watched by the manager cache, e.g. the workloads owning the Pods.
+	APIReader client.Reader
+
 	// Decoder decodes objects
 	Decoder *admission.Decoder
+
+	// profileStatsRecorder records the admission statistics of ClusterColocationProfiles,
+	// defaultProfileStatsRecorder is used if it is nil.
+	profileStatsRecorder *profileStatsRecorder
 }
 
 var _ admission.Handler = &PodMutatingHandler{}
@@ -124,6 +132,14 @@ func (h *PodMutatingHandler) InjectClient(c client.Client) error {
 	return nil
 }
 
+var _ inject.APIReader = &PodMutatingHandler{}
+
+// InjectAPIReader injects the APIReader into the PodMutatingHandler
+func (h *PodMutatingHandler) InjectAPIReader(r client.Reader) error {
+	h.APIReader = r
+	return nil
+}
+
 var _ admission.DecoderInjector = &PodMutatingHandler{}
 
 // InjectDecoder injects the decoder into the PodMutatingHandler
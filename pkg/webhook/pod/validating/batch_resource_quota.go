@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	utilquota "github.com/koordinator-sh/koordinator/pkg/util/quota"
+	"github.com/koordinator-sh/koordinator/pkg/webhook/elasticquota"
+)
+
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.sigs.k8s.io,resources=elasticquotas,verbs=get;list;watch
+
+// batchResourceQuotaValidatingPod rejects the Pod if its batch resources requests exceed the batch resource quota
+// of the Namespace or the ElasticQuota. The usage is calculated with the cached Pods, so the quota may be slightly
+// exceeded when Pods are created concurrently.
+func (h *PodValidatingHandler) batchResourceQuotaValidatingPod(ctx context.Context, req admission.Request) (bool, string, error) {
+	if req.Operation != admissionv1.Create {
+		return true, "", nil
+	}
+
+	pod := &corev1.Pod{}
+	if err := h.Decoder.DecodeRaw(req.Object, pod); err != nil {
+		return false, "", err
+	}
+	if pod.Namespace == "" {
+		pod.Namespace = req.Namespace
+	}
+	request := utilquota.GetPodBatchRequest(pod)
+	if quotav1.IsZero(request) {
+		return true, "", nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := h.Client.Get(ctx, types.NamespacedName{Name: pod.Namespace}, namespace); err != nil && !errors.IsNotFound(err) {
+		return false, "", err
+	}
+	namespaceQuota, err := extension.GetBatchResourceQuota(namespace.Annotations)
+	if err != nil {
+		klog.Warningf("failed to parse batch resource quota of namespace %s, err: %v", namespace.Name, err)
+	} else if namespaceQuota != nil {
+		used, err := utilquota.GetNamespaceBatchUsed(ctx, h.Client, namespace.Name)
+		if err != nil {
+			return false, "", err
+		}
+		if err = utilquota.CheckBatchResourceQuota(namespaceQuota, used, request); err != nil {
+			return false, fmt.Sprintf("exceeded batch resource quota of namespace %s, %v", namespace.Name, err), nil
+		}
+	}
+
+	quotaName := elasticquota.GetQuotaName(pod, h.Client)
+	if quotaName == "" || quotaName == extension.DefaultQuotaName {
+		return true, "", nil
+	}
+	quota, err := h.getElasticQuota(ctx, quotaName)
+	if err != nil {
+		return false, "", err
+	}
+	if quota == nil {
+		return true, "", nil
+	}
+	elasticQuotaQuota, err := extension.GetBatchResourceQuota(quota.Annotations)
+	if err != nil {
+		klog.Warningf("failed to parse batch resource quota of elasticQuota %s, err: %v", quota.Name, err)
+		return true, "", nil
+	}
+	if elasticQuotaQuota == nil {
+		return true, "", nil
+	}
+	used, err := utilquota.GetElasticQuotaBatchUsed(ctx, h.Client, quota)
+	if err != nil {
+		return false, "", err
+	}
+	if err = utilquota.CheckBatchResourceQuota(elasticQuotaQuota, used, request); err != nil {
+		return false, fmt.Sprintf("exceeded batch resource quota of elasticQuota %s, %v", quota.Name, err), nil
+	}
+	return true, "", nil
+}
+
+func (h *PodValidatingHandler) getElasticQuota(ctx context.Context, quotaName string) (*v1alpha1.ElasticQuota, error) {
+	quotaList := &v1alpha1.ElasticQuotaList{}
+	if err := h.Client.List(ctx, quotaList, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	for i := range quotaList.Items {
+		if quotaList.Items[i].Name == quotaName {
+			return &quotaList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
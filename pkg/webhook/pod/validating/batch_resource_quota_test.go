@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func makeBatchPod(namespace, name, quotaName string, batchCPU int64, phase corev1.PodPhase) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSBE),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							extension.BatchCPU:    *resource.NewQuantity(batchCPU, resource.DecimalSI),
+							extension.BatchMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+	if quotaName != "" {
+		pod.Labels[extension.LabelQuotaName] = quotaName
+	}
+	return pod
+}
+
+func TestBatchResourceQuotaValidatingPod(t *testing.T) {
+	tests := []struct {
+		name           string
+		namespaceQuota string
+		elasticQuota   string
+		pod            *corev1.Pod
+		operation      admissionv1.Operation
+		wantAllowed    bool
+		wantReason     string
+	}{
+		{
+			name:           "skip update",
+			namespaceQuota: `{"kubernetes.io/batch-cpu":"1000"}`,
+			pod:            makeBatchPod("default", "test-pod", "", 4000, corev1.PodPending),
+			operation:      admissionv1.Update,
+			wantAllowed:    true,
+		},
+		{
+			name:        "no batch resource quota",
+			pod:         makeBatchPod("default", "test-pod", "", 4000, corev1.PodPending),
+			operation:   admissionv1.Create,
+			wantAllowed: true,
+		},
+		{
+			name:           "invalid batch resource quota",
+			namespaceQuota: `invalid`,
+			pod:            makeBatchPod("default", "test-pod", "", 4000, corev1.PodPending),
+			operation:      admissionv1.Create,
+			wantAllowed:    true,
+		},
+		{
+			name:           "within namespace quota",
+			namespaceQuota: `{"kubernetes.io/batch-cpu":"6000","kubernetes.io/batch-memory":"10Gi"}`,
+			pod:            makeBatchPod("default", "test-pod", "", 2000, corev1.PodPending),
+			operation:      admissionv1.Create,
+			wantAllowed:    true,
+		},
+		{
+			name:           "exceed namespace quota",
+			namespaceQuota: `{"kubernetes.io/batch-cpu":"5000"}`,
+			pod:            makeBatchPod("default", "test-pod", "", 2000, corev1.PodPending),
+			operation:      admissionv1.Create,
+			wantAllowed:    false,
+			wantReason:     "exceeded batch resource quota of namespace default, requested: kubernetes.io/batch-cpu=2k (used: 4k, limited: 5k)",
+		},
+		{
+			name:         "exceed elastic quota",
+			elasticQuota: `{"kubernetes.io/batch-memory":"2Gi"}`,
+			pod:          makeBatchPod("default", "test-pod", "test-quota", 1000, corev1.PodPending),
+			operation:    admissionv1.Create,
+			wantAllowed:  false,
+			wantReason:   "exceeded batch resource quota of elasticQuota test-quota, requested: kubernetes.io/batch-memory=1Gi (used: 2Gi, limited: 2Gi)",
+		},
+		{
+			name:         "within elastic quota",
+			elasticQuota: `{"kubernetes.io/batch-memory":"3Gi"}`,
+			pod:          makeBatchPod("default", "test-pod", "test-quota", 1000, corev1.PodPending),
+			operation:    admissionv1.Create,
+			wantAllowed:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = v1alpha1.AddToScheme(scheme)
+
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+			if tt.namespaceQuota != "" {
+				namespace.Annotations = map[string]string{extension.AnnotationBatchResourceQuota: tt.namespaceQuota}
+			}
+			quota := &v1alpha1.ElasticQuota{ObjectMeta: metav1.ObjectMeta{Namespace: "quota-ns", Name: "test-quota"}}
+			if tt.elasticQuota != "" {
+				quota.Annotations = map[string]string{extension.AnnotationBatchResourceQuota: tt.elasticQuota}
+			}
+			objs := []client.Object{
+				namespace,
+				quota,
+				// 4 cores and 2Gi in namespace default, 1 core and 1Gi in quota test-quota
+				makeBatchPod("default", "running-pod-1", "", 3000, corev1.PodRunning),
+				makeBatchPod("default", "running-pod-2", "test-quota", 1000, corev1.PodRunning),
+				makeBatchPod("default", "succeeded-pod", "", 3000, corev1.PodSucceeded),
+				// 1 core and 1Gi in quota test-quota
+				makeBatchPod("other", "running-pod-3", "test-quota", 1000, corev1.PodRunning),
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			decoder, _ := admission.NewDecoder(scheme)
+			h := &PodValidatingHandler{
+				Client:  c,
+				Decoder: decoder,
+			}
+
+			raw, err := json.Marshal(tt.pod)
+			assert.NoError(t, err)
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  gvr("pods"),
+					Operation: tt.operation,
+					Object:    runtime.RawExtension{Raw: raw},
+					OldObject: runtime.RawExtension{Raw: raw},
+				},
+			}
+			allowed, reason, err := h.batchResourceQuotaValidatingPod(context.TODO(), req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, allowed)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
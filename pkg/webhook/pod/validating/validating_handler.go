@@ -65,6 +65,9 @@ func (h *PodValidatingHandler) validatingPodFn(ctx context.Context, req admissio
 			return false, "", err
 		}
 	}
+	if err == nil && allowed {
+		allowed, reason, err = h.batchResourceQuotaValidatingPod(ctx, req)
+	}
 	return
 }
 
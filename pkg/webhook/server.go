@@ -88,6 +88,12 @@ func SetupWithManager(mgr manager.Manager) error {
 	for path, handler := range HandlerMap {
 		server.Register(path, &webhook.Admission{Handler: handler})
 		klog.V(3).Infof("Registered webhook handler %s", path)
+		// some handlers run background tasks, e.g. syncing statistics
+		if runnable, ok := handler.(manager.Runnable); ok {
+			if err := mgr.Add(runnable); err != nil {
+				return err
+			}
+		}
 	}
 
 	// register conversion webhook